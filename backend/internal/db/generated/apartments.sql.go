@@ -23,7 +23,7 @@ INSERT INTO apartments (
     updated_at
   ) VALUES ($1, $2, $3, $4, $5, $6, now(), now())
 
-RETURNING id, unit_number, building_id, price, size, management_id, availability, lease_id, updated_at, created_at
+RETURNING id, unit_number, building_id, price, size, management_id, availability, lease_id, updated_at, created_at, bedrooms, unit_type
 `
 
 type CreateApartmentParams struct {
@@ -56,6 +56,8 @@ func (q *Queries) CreateApartment(ctx context.Context, arg CreateApartmentParams
 		&i.LeaseID,
 		&i.UpdatedAt,
 		&i.CreatedAt,
+		&i.Bedrooms,
+		&i.UnitType,
 	)
 	return i, err
 }
@@ -76,7 +78,10 @@ SELECT id,
   price,
   size,
   management_id,
-  availability
+  availability,
+  building_id,
+  bedrooms,
+  unit_type
 FROM apartments
 WHERE id = $1
 LIMIT 1
@@ -89,6 +94,9 @@ type GetApartmentRow struct {
 	Size         pgtype.Int2    `json:"size"`
 	ManagementID int64          `json:"management_id"`
 	Availability bool           `json:"availability"`
+	BuildingID   int64          `json:"building_id"`
+	Bedrooms     pgtype.Int2    `json:"bedrooms"`
+	UnitType     pgtype.Text    `json:"unit_type"`
 }
 
 func (q *Queries) GetApartment(ctx context.Context, id int64) (GetApartmentRow, error) {
@@ -101,6 +109,9 @@ func (q *Queries) GetApartment(ctx context.Context, id int64) (GetApartmentRow,
 		&i.Size,
 		&i.ManagementID,
 		&i.Availability,
+		&i.BuildingID,
+		&i.Bedrooms,
+		&i.UnitType,
 	)
 	return i, err
 }
@@ -125,12 +136,15 @@ SELECT
   price,
   size,
   management_id,
-  availability
+  availability,
+  building_id,
+  bedrooms,
+  unit_type
 FROM apartments
 WHERE 
   id NOT IN (
     SELECT apartment_id FROM leases 
-    WHERE status = 'active'
+    WHERE status IN ('active', 'month_to_month')
   )
   AND availability = true
 ORDER BY unit_number ASC
@@ -143,6 +157,9 @@ type GetApartmentsWithoutLeaseRow struct {
 	Size         pgtype.Int2    `json:"size"`
 	ManagementID int64          `json:"management_id"`
 	Availability bool           `json:"availability"`
+	BuildingID   int64          `json:"building_id"`
+	Bedrooms     pgtype.Int2    `json:"bedrooms"`
+	UnitType     pgtype.Text    `json:"unit_type"`
 }
 
 func (q *Queries) GetApartmentsWithoutLease(ctx context.Context) ([]GetApartmentsWithoutLeaseRow, error) {
@@ -161,6 +178,9 @@ func (q *Queries) GetApartmentsWithoutLease(ctx context.Context) ([]GetApartment
 			&i.Size,
 			&i.ManagementID,
 			&i.Availability,
+			&i.BuildingID,
+			&i.Bedrooms,
+			&i.UnitType,
 		); err != nil {
 			return nil, err
 		}
@@ -178,7 +198,10 @@ SELECT id,
   price,
   size,
   management_id,
-  availability
+  availability,
+  building_id,
+  bedrooms,
+  unit_type
 FROM apartments
 ORDER BY unit_number DESC
 `
@@ -190,6 +213,9 @@ type ListApartmentsRow struct {
 	Size         pgtype.Int2    `json:"size"`
 	ManagementID int64          `json:"management_id"`
 	Availability bool           `json:"availability"`
+	BuildingID   int64          `json:"building_id"`
+	Bedrooms     pgtype.Int2    `json:"bedrooms"`
+	UnitType     pgtype.Text    `json:"unit_type"`
 }
 
 func (q *Queries) ListApartments(ctx context.Context) ([]ListApartmentsRow, error) {
@@ -208,6 +234,9 @@ func (q *Queries) ListApartments(ctx context.Context) ([]ListApartmentsRow, erro
 			&i.Size,
 			&i.ManagementID,
 			&i.Availability,
+			&i.BuildingID,
+			&i.Bedrooms,
+			&i.UnitType,
 		); err != nil {
 			return nil, err
 		}
@@ -300,3 +329,23 @@ func (q *Queries) UpdateApartment(ctx context.Context, arg UpdateApartmentParams
 	)
 	return err
 }
+
+const updateApartmentLayout = `-- name: UpdateApartmentLayout :exec
+UPDATE apartments
+SET bedrooms = $2,
+  unit_type = $3,
+  updated_at = now()
+WHERE id = $1
+`
+
+type UpdateApartmentLayoutParams struct {
+	ID       int64       `json:"id"`
+	Bedrooms pgtype.Int2 `json:"bedrooms"`
+	UnitType pgtype.Text `json:"unit_type"`
+}
+
+// The bedroom count and unit type waitlist entries are matched on
+func (q *Queries) UpdateApartmentLayout(ctx context.Context, arg UpdateApartmentLayoutParams) error {
+	_, err := q.db.Exec(ctx, updateApartmentLayout, arg.ID, arg.Bedrooms, arg.UnitType)
+	return err
+}
@@ -19,7 +19,7 @@ INSERT INTO buildings (
     created_at,
     updated_at
   ) VALUES ($1, $2, $3, now(), now())
-RETURNING id, parking_total, per_user_parking, management_id, created_at, updated_at
+RETURNING id, parking_total, per_user_parking, management_id, created_at, updated_at, rules, proration_method, holdover_policy, holdover_premium_percent
 `
 
 type CreateBuildingParams struct {
@@ -38,12 +38,30 @@ func (q *Queries) CreateBuilding(ctx context.Context, arg CreateBuildingParams)
 		&i.ManagementID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Rules,
+		&i.ProrationMethod,
+		&i.HoldoverPolicy,
+		&i.HoldoverPremiumPercent,
 	)
 	return i, err
 }
 
+const getApartmentProrationMethod = `-- name: GetApartmentProrationMethod :one
+SELECT b.proration_method
+FROM apartments a
+JOIN buildings b ON b.id = a.building_id
+WHERE a.id = $1
+`
+
+func (q *Queries) GetApartmentProrationMethod(ctx context.Context, id int64) (ProrationMethod, error) {
+	row := q.db.QueryRow(ctx, getApartmentProrationMethod, id)
+	var proration_method ProrationMethod
+	err := row.Scan(&proration_method)
+	return proration_method, err
+}
+
 const getBuilding = `-- name: GetBuilding :one
-SELECT id, parking_total, per_user_parking, management_id, created_at, updated_at
+SELECT id, parking_total, per_user_parking, management_id, created_at, updated_at, rules, proration_method, holdover_policy, holdover_premium_percent
 FROM buildings
 WHERE id = $1
 LIMIT 1
@@ -59,6 +77,10 @@ func (q *Queries) GetBuilding(ctx context.Context, id int64) (Building, error) {
 		&i.ManagementID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Rules,
+		&i.ProrationMethod,
+		&i.HoldoverPolicy,
+		&i.HoldoverPremiumPercent,
 	)
 	return i, err
 }
@@ -81,3 +103,73 @@ func (q *Queries) UpdateBuilding(ctx context.Context, arg UpdateBuildingParams)
 	_, err := q.db.Exec(ctx, updateBuilding, arg.ID, arg.ParkingTotal, arg.PerUserParking)
 	return err
 }
+
+const updateBuildingRules = `-- name: UpdateBuildingRules :exec
+UPDATE buildings
+SET rules = $2,
+    updated_at = now()
+WHERE id = $1
+`
+
+type UpdateBuildingRulesParams struct {
+	ID    int64       `json:"id"`
+	Rules pgtype.Text `json:"rules"`
+}
+
+func (q *Queries) UpdateBuildingRules(ctx context.Context, arg UpdateBuildingRulesParams) error {
+	_, err := q.db.Exec(ctx, updateBuildingRules, arg.ID, arg.Rules)
+	return err
+}
+
+const updateBuildingHoldoverPolicy = `-- name: UpdateBuildingHoldoverPolicy :exec
+UPDATE buildings
+SET holdover_policy = $2,
+    holdover_premium_percent = $3,
+    updated_at = now()
+WHERE id = $1
+`
+
+type UpdateBuildingHoldoverPolicyParams struct {
+	ID                     int64          `json:"id"`
+	HoldoverPolicy         HoldoverPolicy `json:"holdover_policy"`
+	HoldoverPremiumPercent pgtype.Numeric `json:"holdover_premium_percent"`
+}
+
+func (q *Queries) UpdateBuildingHoldoverPolicy(ctx context.Context, arg UpdateBuildingHoldoverPolicyParams) error {
+	_, err := q.db.Exec(ctx, updateBuildingHoldoverPolicy, arg.ID, arg.HoldoverPolicy, arg.HoldoverPremiumPercent)
+	return err
+}
+
+const updateBuildingManagement = `-- name: UpdateBuildingManagement :exec
+UPDATE buildings
+SET management_id = $2,
+    updated_at = now()
+WHERE id = $1
+`
+
+type UpdateBuildingManagementParams struct {
+	ID           int64 `json:"id"`
+	ManagementID int64 `json:"management_id"`
+}
+
+func (q *Queries) UpdateBuildingManagement(ctx context.Context, arg UpdateBuildingManagementParams) error {
+	_, err := q.db.Exec(ctx, updateBuildingManagement, arg.ID, arg.ManagementID)
+	return err
+}
+
+const updateBuildingProrationMethod = `-- name: UpdateBuildingProrationMethod :exec
+UPDATE buildings
+SET proration_method = $2,
+    updated_at = now()
+WHERE id = $1
+`
+
+type UpdateBuildingProrationMethodParams struct {
+	ID              int64           `json:"id"`
+	ProrationMethod ProrationMethod `json:"proration_method"`
+}
+
+func (q *Queries) UpdateBuildingProrationMethod(ctx context.Context, arg UpdateBuildingProrationMethodParams) error {
+	_, err := q.db.Exec(ctx, updateBuildingProrationMethod, arg.ID, arg.ProrationMethod)
+	return err
+}
@@ -11,6 +11,34 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const convertLeaseToMonthToMonth = `-- name: ConvertLeaseToMonthToMonth :execrows
+WITH converted AS (
+    UPDATE leases
+    SET status = 'month_to_month',
+        holdover_rent = $2,
+        updated_at = now()
+    WHERE id = $1
+      AND status = 'active'
+    RETURNING id
+)
+INSERT INTO lease_status_history (lease_id, from_status, to_status, event, reason)
+SELECT id, 'active', 'month_to_month', 'holdover', 'Lease end date passed under a holdover policy'
+FROM converted
+`
+
+type ConvertLeaseToMonthToMonthParams struct {
+	ID           int64          `json:"id"`
+	HoldoverRent pgtype.Numeric `json:"holdover_rent"`
+}
+
+func (q *Queries) ConvertLeaseToMonthToMonth(ctx context.Context, arg ConvertLeaseToMonthToMonthParams) (int64, error) {
+	result, err := q.db.Exec(ctx, convertLeaseToMonthToMonth, arg.ID, arg.HoldoverRent)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createLease = `-- name: CreateLease :one
 INSERT INTO leases (
   lease_number, external_doc_id, lease_pdf_s3,
@@ -25,7 +53,7 @@ INSERT INTO leases (
   $10, $11, $12,
   $13, $14, $15
 )
-RETURNING id, lease_number, external_doc_id, lease_pdf_s3, tenant_id, landlord_id, apartment_id, lease_start_date, lease_end_date, rent_amount, status, created_by, updated_by, created_at, updated_at, previous_lease_id, tenant_signing_url, landlord_signing_url
+RETURNING id, lease_number, external_doc_id, lease_pdf_s3, tenant_id, landlord_id, apartment_id, lease_start_date, lease_end_date, rent_amount, status, created_by, updated_by, created_at, updated_at, previous_lease_id, tenant_signing_url, landlord_signing_url, lease_template_id, document_type, rent_escalation, notice_period_days, early_termination_fee, holdover_policy, holdover_premium_percent, holdover_rent, generated_pdf_key, signed_pdf_key
 `
 
 type CreateLeaseParams struct {
@@ -84,6 +112,16 @@ func (q *Queries) CreateLease(ctx context.Context, arg CreateLeaseParams) (Lease
 		&i.PreviousLeaseID,
 		&i.TenantSigningUrl,
 		&i.LandlordSigningUrl,
+		&i.LeaseTemplateID,
+		&i.DocumentType,
+		&i.RentEscalation,
+		&i.NoticePeriodDays,
+		&i.EarlyTerminationFee,
+		&i.HoldoverPolicy,
+		&i.HoldoverPremiumPercent,
+		&i.HoldoverRent,
+		&i.GeneratedPdfKey,
+		&i.SignedPdfKey,
 	)
 	return i, err
 }
@@ -94,6 +132,10 @@ WITH expired_leases AS (
     SET status = 'expired', updated_at = NOW()
     WHERE status = 'active' AND lease_end_date <= CURRENT_DATE
     RETURNING id
+), history AS (
+    INSERT INTO lease_status_history (lease_id, from_status, to_status, event, reason)
+    SELECT id, 'active', 'expired', 'expired', 'Lease end date passed'
+    FROM expired_leases
 )
 SELECT 
     COUNT(*) as expired_count,
@@ -118,9 +160,9 @@ func (q *Queries) ExpireLeasesEndingToday(ctx context.Context) (ExpireLeasesEndi
 }
 
 const getActiveLeasesByTenant = `-- name: GetActiveLeasesByTenant :many
-SELECT id, lease_number, external_doc_id, lease_pdf_s3, tenant_id, landlord_id, apartment_id, lease_start_date, lease_end_date, rent_amount, status, created_by, updated_by, created_at, updated_at, previous_lease_id, tenant_signing_url, landlord_signing_url FROM leases
+SELECT id, lease_number, external_doc_id, lease_pdf_s3, tenant_id, landlord_id, apartment_id, lease_start_date, lease_end_date, rent_amount, status, created_by, updated_by, created_at, updated_at, previous_lease_id, tenant_signing_url, landlord_signing_url, lease_template_id, document_type, rent_escalation, notice_period_days, early_termination_fee, holdover_policy, holdover_premium_percent, holdover_rent, generated_pdf_key, signed_pdf_key FROM leases
 WHERE tenant_id = $1
-AND status IN ('active', 'draft', 'pending_approval')
+AND status IN ('active', 'month_to_month', 'draft', 'pending_approval')
 ORDER BY id DESC
 `
 
@@ -152,6 +194,16 @@ func (q *Queries) GetActiveLeasesByTenant(ctx context.Context, tenantID int64) (
 			&i.PreviousLeaseID,
 			&i.TenantSigningUrl,
 			&i.LandlordSigningUrl,
+			&i.LeaseTemplateID,
+			&i.DocumentType,
+			&i.RentEscalation,
+			&i.NoticePeriodDays,
+			&i.EarlyTerminationFee,
+			&i.HoldoverPolicy,
+			&i.HoldoverPremiumPercent,
+			&i.HoldoverRent,
+			&i.GeneratedPdfKey,
+			&i.SignedPdfKey,
 		); err != nil {
 			return nil, err
 		}
@@ -449,28 +501,40 @@ SELECT id,
     updated_by,
     previous_lease_id,
     tenant_signing_url,
-    landlord_signing_url
+    landlord_signing_url,
+    rent_escalation,
+    notice_period_days,
+    early_termination_fee,
+    holdover_policy,
+    holdover_premium_percent,
+    holdover_rent
 FROM leases
 WHERE id = $1
 `
 
 type GetLeaseByIDRow struct {
-	ID                 int64          `json:"id"`
-	LeaseNumber        int64          `json:"lease_number"`
-	ExternalDocID      string         `json:"external_doc_id"`
-	LeasePdfS3         pgtype.Text    `json:"lease_pdf_s3"`
-	TenantID           int64          `json:"tenant_id"`
-	LandlordID         int64          `json:"landlord_id"`
-	ApartmentID        int64          `json:"apartment_id"`
-	LeaseStartDate     pgtype.Date    `json:"lease_start_date"`
-	LeaseEndDate       pgtype.Date    `json:"lease_end_date"`
-	RentAmount         pgtype.Numeric `json:"rent_amount"`
-	Status             LeaseStatus    `json:"status"`
-	CreatedBy          int64          `json:"created_by"`
-	UpdatedBy          int64          `json:"updated_by"`
-	PreviousLeaseID    pgtype.Int8    `json:"previous_lease_id"`
-	TenantSigningUrl   pgtype.Text    `json:"tenant_signing_url"`
-	LandlordSigningUrl pgtype.Text    `json:"landlord_signing_url"`
+	ID                     int64              `json:"id"`
+	LeaseNumber            int64              `json:"lease_number"`
+	ExternalDocID          string             `json:"external_doc_id"`
+	LeasePdfS3             pgtype.Text        `json:"lease_pdf_s3"`
+	TenantID               int64              `json:"tenant_id"`
+	LandlordID             int64              `json:"landlord_id"`
+	ApartmentID            int64              `json:"apartment_id"`
+	LeaseStartDate         pgtype.Date        `json:"lease_start_date"`
+	LeaseEndDate           pgtype.Date        `json:"lease_end_date"`
+	RentAmount             pgtype.Numeric     `json:"rent_amount"`
+	Status                 LeaseStatus        `json:"status"`
+	CreatedBy              int64              `json:"created_by"`
+	UpdatedBy              int64              `json:"updated_by"`
+	PreviousLeaseID        pgtype.Int8        `json:"previous_lease_id"`
+	TenantSigningUrl       pgtype.Text        `json:"tenant_signing_url"`
+	LandlordSigningUrl     pgtype.Text        `json:"landlord_signing_url"`
+	RentEscalation         []byte             `json:"rent_escalation"`
+	NoticePeriodDays       int32              `json:"notice_period_days"`
+	EarlyTerminationFee    pgtype.Numeric     `json:"early_termination_fee"`
+	HoldoverPolicy         NullHoldoverPolicy `json:"holdover_policy"`
+	HoldoverPremiumPercent pgtype.Numeric     `json:"holdover_premium_percent"`
+	HoldoverRent           pgtype.Numeric     `json:"holdover_rent"`
 }
 
 func (q *Queries) GetLeaseByID(ctx context.Context, id int64) (GetLeaseByIDRow, error) {
@@ -493,6 +557,12 @@ func (q *Queries) GetLeaseByID(ctx context.Context, id int64) (GetLeaseByIDRow,
 		&i.PreviousLeaseID,
 		&i.TenantSigningUrl,
 		&i.LandlordSigningUrl,
+		&i.RentEscalation,
+		&i.NoticePeriodDays,
+		&i.EarlyTerminationFee,
+		&i.HoldoverPolicy,
+		&i.HoldoverPremiumPercent,
+		&i.HoldoverRent,
 	)
 	return i, err
 }
@@ -558,6 +628,24 @@ func (q *Queries) GetLeaseForAmending(ctx context.Context, arg GetLeaseForAmendi
 	return i, err
 }
 
+const getLeasePdfKeys = `-- name: GetLeasePdfKeys :one
+SELECT generated_pdf_key, signed_pdf_key
+FROM leases
+WHERE id = $1
+`
+
+type GetLeasePdfKeysRow struct {
+	GeneratedPdfKey pgtype.Text `json:"generated_pdf_key"`
+	SignedPdfKey    pgtype.Text `json:"signed_pdf_key"`
+}
+
+func (q *Queries) GetLeasePdfKeys(ctx context.Context, id int64) (GetLeasePdfKeysRow, error) {
+	row := q.db.QueryRow(ctx, getLeasePdfKeys, id)
+	var i GetLeasePdfKeysRow
+	err := row.Scan(&i.GeneratedPdfKey, &i.SignedPdfKey)
+	return i, err
+}
+
 const getMaxLeaseNumber = `-- name: GetMaxLeaseNumber :one
 SELECT COALESCE(MAX(lease_number), 0) FROM leases 
 where tenant_id = $1
@@ -584,10 +672,12 @@ func (q *Queries) GetSignedLeasePdfS3URL(ctx context.Context, id int64) (pgtype.
 }
 
 const getTenantLeaseStatusAndURLByUserID = `-- name: GetTenantLeaseStatusAndURLByUserID :one
-SELECT status,tenant_signing_url, lease_number
-FROM leases
-WHERE tenant_id = $1
-ORDER BY lease_number DESC
+SELECT l.status, COALESCE(ls.signing_url, l.tenant_signing_url) AS tenant_signing_url, l.lease_number
+FROM leases l
+JOIN lease_tenants lt ON lt.lease_id = l.id
+LEFT JOIN lease_signers ls ON ls.lease_id = l.id AND ls.user_id = lt.tenant_id
+WHERE lt.tenant_id = $1
+ORDER BY l.id DESC
 LIMIT 1
 `
 
@@ -724,28 +814,115 @@ func (q *Queries) ListLeases(ctx context.Context) ([]ListLeasesRow, error) {
 	return items, nil
 }
 
-const markLeaseAsSignedBothParties = `-- name: MarkLeaseAsSignedBothParties :exec
-UPDATE leases
-SET status = 'active', updated_at = now()
-WHERE id = $1
-RETURNING lease_number,
-    external_doc_id,
-    lease_pdf_s3,
-    tenant_id,
-    landlord_id,
-    apartment_id,
-    lease_start_date,
-    lease_end_date,
-    rent_amount,
-    status,
-    created_by,
-    updated_by,
-    previous_lease_id
+const listLeasesDueForHoldover = `-- name: ListLeasesDueForHoldover :many
+SELECT l.id, l.tenant_id, l.landlord_id, l.apartment_id, l.lease_start_date, l.lease_end_date,
+    l.rent_amount, l.rent_escalation,
+    COALESCE(l.holdover_premium_percent, b.holdover_premium_percent)::NUMERIC AS holdover_premium_percent,
+    b.proration_method
+FROM leases l
+JOIN apartments a ON a.id = l.apartment_id
+JOIN buildings b ON b.id = a.building_id
+WHERE l.status = 'active'
+  AND l.lease_end_date <= CURRENT_DATE
+  AND COALESCE(l.holdover_policy, b.holdover_policy) = 'month_to_month'
+  AND NOT EXISTS (
+    SELECT 1 FROM leases nl
+    WHERE nl.previous_lease_id = l.id
+      AND nl.status IN ('draft', 'pending_approval', 'active')
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM lease_vacate_notices n
+    WHERE n.lease_id = l.id
+      AND n.status IN ('pending_signature', 'scheduled')
+  )
+ORDER BY l.id
 `
 
-func (q *Queries) MarkLeaseAsSignedBothParties(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, markLeaseAsSignedBothParties, id)
-	return err
+type ListLeasesDueForHoldoverRow struct {
+	ID                     int64           `json:"id"`
+	TenantID               int64           `json:"tenant_id"`
+	LandlordID             int64           `json:"landlord_id"`
+	ApartmentID            int64           `json:"apartment_id"`
+	LeaseStartDate         pgtype.Date     `json:"lease_start_date"`
+	LeaseEndDate           pgtype.Date     `json:"lease_end_date"`
+	RentAmount             pgtype.Numeric  `json:"rent_amount"`
+	RentEscalation         []byte          `json:"rent_escalation"`
+	HoldoverPremiumPercent pgtype.Numeric  `json:"holdover_premium_percent"`
+	ProrationMethod        ProrationMethod `json:"proration_method"`
+}
+
+func (q *Queries) ListLeasesDueForHoldover(ctx context.Context) ([]ListLeasesDueForHoldoverRow, error) {
+	rows, err := q.db.Query(ctx, listLeasesDueForHoldover)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLeasesDueForHoldoverRow
+	for rows.Next() {
+		var i ListLeasesDueForHoldoverRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.LandlordID,
+			&i.ApartmentID,
+			&i.LeaseStartDate,
+			&i.LeaseEndDate,
+			&i.RentAmount,
+			&i.RentEscalation,
+			&i.HoldoverPremiumPercent,
+			&i.ProrationMethod,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLeasesForDocumentReconciliation = `-- name: ListLeasesForDocumentReconciliation :many
+SELECT id, external_doc_id, status, landlord_id
+FROM leases
+WHERE external_doc_id <> ''
+  AND (status IN ('draft', 'pending_approval')
+    OR (status IN ('active', 'month_to_month', 'expired', 'terminated', 'renewed', 'superseded') AND signed_pdf_key IS NULL))
+ORDER BY id
+`
+
+type ListLeasesForDocumentReconciliationRow struct {
+	ID            int64       `json:"id"`
+	ExternalDocID string      `json:"external_doc_id"`
+	Status        LeaseStatus `json:"status"`
+	LandlordID    int64       `json:"landlord_id"`
+}
+
+// Leases that still depend on their document at the e-sign provider: unsigned ones waiting for signatures and
+// signed ones we have not kept our own copy of yet.
+func (q *Queries) ListLeasesForDocumentReconciliation(ctx context.Context) ([]ListLeasesForDocumentReconciliationRow, error) {
+	rows, err := q.db.Query(ctx, listLeasesForDocumentReconciliation)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLeasesForDocumentReconciliationRow
+	for rows.Next() {
+		var i ListLeasesForDocumentReconciliationRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalDocID,
+			&i.Status,
+			&i.LandlordID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const renewLease = `-- name: RenewLease :one
@@ -808,162 +985,253 @@ func (q *Queries) RenewLease(ctx context.Context, arg RenewLeaseParams) (RenewLe
 	return i, err
 }
 
-const storeGeneratedLeasePDFURL = `-- name: StoreGeneratedLeasePDFURL :exec
-UPDATE leases
-SET lease_pdf_s3 = $1, external_doc_id = $2, updated_at = now()
-WHERE id = $3
-RETURNING lease_pdf_s3
+const searchLeases = `-- name: SearchLeases :many
+SELECT l.id, l.lease_number, l.external_doc_id, l.tenant_id, l.apartment_id,
+    l.lease_start_date, l.lease_end_date, l.rent_amount, l.status, l.created_at,
+    u.first_name AS tenant_first_name, u.last_name AS tenant_last_name, u.email AS tenant_email,
+    a.unit_number, a.building_id
+FROM leases l
+JOIN users u ON u.id = l.tenant_id
+JOIN apartments a ON a.id = l.apartment_id
+WHERE ($1::"Lease_Status" IS NULL OR l.status = $1)
+  AND ($2::BIGINT IS NULL OR l.tenant_id = $2
+    OR EXISTS (SELECT 1 FROM lease_tenants lt WHERE lt.lease_id = l.id AND lt.tenant_id = $2))
+  AND ($3::TEXT IS NULL
+    OR (u.first_name || ' ' || u.last_name) ILIKE '%' || $3 || '%' ESCAPE '\'
+    OR u.email ILIKE '%' || $3 || '%' ESCAPE '\')
+  AND ($4::BIGINT IS NULL OR l.apartment_id = $4)
+  AND ($5::BIGINT IS NULL OR a.building_id = $5)
+  AND ($6::DATE IS NULL OR l.lease_start_date >= $6)
+  AND ($7::DATE IS NULL OR l.lease_start_date <= $7)
+  AND ($8::DATE IS NULL OR l.lease_end_date >= $8)
+  AND ($9::DATE IS NULL OR l.lease_end_date <= $9)
+  AND ($10::NUMERIC IS NULL OR l.rent_amount >= $10)
+  AND ($11::NUMERIC IS NULL OR l.rent_amount <= $11)
+  AND ($12::BIGINT IS NULL OR CASE $13::TEXT
+    WHEN 'start_date' THEN CASE WHEN $14::TEXT = 'asc'
+      THEN (l.lease_start_date, l.id) > ($15::DATE, $12)
+      ELSE (l.lease_start_date, l.id) < ($15::DATE, $12) END
+    WHEN 'end_date' THEN CASE WHEN $14::TEXT = 'asc'
+      THEN (l.lease_end_date, l.id) > ($15::DATE, $12)
+      ELSE (l.lease_end_date, l.id) < ($15::DATE, $12) END
+    WHEN 'rent' THEN CASE WHEN $14::TEXT = 'asc'
+      THEN (l.rent_amount, l.id) > ($16::NUMERIC, $12)
+      ELSE (l.rent_amount, l.id) < ($16::NUMERIC, $12) END
+    ELSE CASE WHEN $14::TEXT = 'asc'
+      THEN l.id > $12
+      ELSE l.id < $12 END
+  END)
+ORDER BY
+  CASE WHEN $13::TEXT = 'start_date' AND $14::TEXT = 'asc' THEN l.lease_start_date END ASC,
+  CASE WHEN $13::TEXT = 'start_date' AND $14::TEXT = 'desc' THEN l.lease_start_date END DESC,
+  CASE WHEN $13::TEXT = 'end_date' AND $14::TEXT = 'asc' THEN l.lease_end_date END ASC,
+  CASE WHEN $13::TEXT = 'end_date' AND $14::TEXT = 'desc' THEN l.lease_end_date END DESC,
+  CASE WHEN $13::TEXT = 'rent' AND $14::TEXT = 'asc' THEN l.rent_amount END ASC,
+  CASE WHEN $13::TEXT = 'rent' AND $14::TEXT = 'desc' THEN l.rent_amount END DESC,
+  CASE WHEN $14::TEXT = 'asc' THEN l.id END ASC,
+  CASE WHEN $14::TEXT = 'desc' THEN l.id END DESC
+LIMIT $17;
 `
 
-type StoreGeneratedLeasePDFURLParams struct {
-	LeasePdfS3    pgtype.Text `json:"lease_pdf_s3"`
-	ExternalDocID string      `json:"external_doc_id"`
-	ID            int64       `json:"id"`
-}
-
-func (q *Queries) StoreGeneratedLeasePDFURL(ctx context.Context, arg StoreGeneratedLeasePDFURLParams) error {
-	_, err := q.db.Exec(ctx, storeGeneratedLeasePDFURL, arg.LeasePdfS3, arg.ExternalDocID, arg.ID)
-	return err
+type SearchLeasesParams struct {
+	Status       NullLeaseStatus `json:"status"`
+	TenantID     pgtype.Int8     `json:"tenant_id"`
+	TenantSearch pgtype.Text     `json:"tenant_search"`
+	ApartmentID  pgtype.Int8     `json:"apartment_id"`
+	BuildingID   pgtype.Int8     `json:"building_id"`
+	StartFrom    pgtype.Date     `json:"start_from"`
+	StartTo      pgtype.Date     `json:"start_to"`
+	EndFrom      pgtype.Date     `json:"end_from"`
+	EndTo        pgtype.Date     `json:"end_to"`
+	RentMin      pgtype.Numeric  `json:"rent_min"`
+	RentMax      pgtype.Numeric  `json:"rent_max"`
+	CursorID     pgtype.Int8     `json:"cursor_id"`
+	SortBy       string          `json:"sort_by"`
+	SortDir      string          `json:"sort_dir"`
+	CursorDate   pgtype.Date     `json:"cursor_date"`
+	CursorRent   pgtype.Numeric  `json:"cursor_rent"`
+	RowLimit     int32           `json:"row_limit"`
 }
 
-const terminateLease = `-- name: TerminateLease :one
-UPDATE leases
-SET
-    status = 'terminated', 
-    updated_by = $1, 
-    updated_at = now()
-WHERE id = $2
-RETURNING id, lease_number, external_doc_id, tenant_id, landlord_id, apartment_id, 
-    lease_start_date, lease_end_date, rent_amount, status, 
-    updated_by, updated_at, previous_lease_id
-`
-
-type TerminateLeaseParams struct {
-	UpdatedBy int64 `json:"updated_by"`
-	ID        int64 `json:"id"`
-}
-
-type TerminateLeaseRow struct {
+type SearchLeasesRow struct {
 	ID              int64            `json:"id"`
 	LeaseNumber     int64            `json:"lease_number"`
 	ExternalDocID   string           `json:"external_doc_id"`
 	TenantID        int64            `json:"tenant_id"`
-	LandlordID      int64            `json:"landlord_id"`
 	ApartmentID     int64            `json:"apartment_id"`
 	LeaseStartDate  pgtype.Date      `json:"lease_start_date"`
 	LeaseEndDate    pgtype.Date      `json:"lease_end_date"`
 	RentAmount      pgtype.Numeric   `json:"rent_amount"`
 	Status          LeaseStatus      `json:"status"`
-	UpdatedBy       int64            `json:"updated_by"`
-	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
-	PreviousLeaseID pgtype.Int8      `json:"previous_lease_id"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	TenantFirstName string           `json:"tenant_first_name"`
+	TenantLastName  string           `json:"tenant_last_name"`
+	TenantEmail     string           `json:"tenant_email"`
+	UnitNumber      pgtype.Int8      `json:"unit_number"`
+	BuildingID      int64            `json:"building_id"`
 }
 
-func (q *Queries) TerminateLease(ctx context.Context, arg TerminateLeaseParams) (TerminateLeaseRow, error) {
-	row := q.db.QueryRow(ctx, terminateLease, arg.UpdatedBy, arg.ID)
-	var i TerminateLeaseRow
-	err := row.Scan(
-		&i.ID,
-		&i.LeaseNumber,
-		&i.ExternalDocID,
-		&i.TenantID,
-		&i.LandlordID,
-		&i.ApartmentID,
-		&i.LeaseStartDate,
-		&i.LeaseEndDate,
-		&i.RentAmount,
-		&i.Status,
-		&i.UpdatedBy,
-		&i.UpdatedAt,
-		&i.PreviousLeaseID,
+// Admin lease list. Every filter is optional; rows come back in sort_by/sort_dir order and continue after
+// the cursor, which is the sort value and id of the last row of the previous page.
+func (q *Queries) SearchLeases(ctx context.Context, arg SearchLeasesParams) ([]SearchLeasesRow, error) {
+	rows, err := q.db.Query(ctx, searchLeases,
+		arg.Status,
+		arg.TenantID,
+		arg.TenantSearch,
+		arg.ApartmentID,
+		arg.BuildingID,
+		arg.StartFrom,
+		arg.StartTo,
+		arg.EndFrom,
+		arg.EndTo,
+		arg.RentMin,
+		arg.RentMax,
+		arg.CursorID,
+		arg.SortBy,
+		arg.SortDir,
+		arg.CursorDate,
+		arg.CursorRent,
+		arg.RowLimit,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchLeasesRow
+	for rows.Next() {
+		var i SearchLeasesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.LeaseNumber,
+			&i.ExternalDocID,
+			&i.TenantID,
+			&i.ApartmentID,
+			&i.LeaseStartDate,
+			&i.LeaseEndDate,
+			&i.RentAmount,
+			&i.Status,
+			&i.CreatedAt,
+			&i.TenantFirstName,
+			&i.TenantLastName,
+			&i.TenantEmail,
+			&i.UnitNumber,
+			&i.BuildingID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const updateLease = `-- name: UpdateLease :exec
+const setLeaseGeneratedPdfKey = `-- name: SetLeaseGeneratedPdfKey :exec
 UPDATE leases
-SET 
-    tenant_id = $1,
-    status = $2,
-    status = $2,
-    lease_start_date = $3,
-    lease_end_date = $4,
-    rent_amount = $5,
-    updated_by = $6,
+SET generated_pdf_key = $2,
     updated_at = now()
-WHERE id = $7
-RETURNING lease_number,
-    external_doc_id,
-    lease_pdf_s3,
-    tenant_id,
-    landlord_id,
-    apartment_id,
-    lease_start_date,
-    lease_end_date,
-    rent_amount,
-    status,
-    created_by,
-    updated_by,
-    previous_lease_id
+WHERE id = $1
 `
 
-type UpdateLeaseParams struct {
-	TenantID       int64          `json:"tenant_id"`
-	Status         LeaseStatus    `json:"status"`
-	LeaseStartDate pgtype.Date    `json:"lease_start_date"`
-	LeaseEndDate   pgtype.Date    `json:"lease_end_date"`
-	RentAmount     pgtype.Numeric `json:"rent_amount"`
-	UpdatedBy      int64          `json:"updated_by"`
-	ID             int64          `json:"id"`
+type SetLeaseGeneratedPdfKeyParams struct {
+	ID              int64       `json:"id"`
+	GeneratedPdfKey pgtype.Text `json:"generated_pdf_key"`
 }
 
-func (q *Queries) UpdateLease(ctx context.Context, arg UpdateLeaseParams) error {
-	_, err := q.db.Exec(ctx, updateLease,
-		arg.TenantID,
-		arg.Status,
-		arg.LeaseStartDate,
-		arg.LeaseEndDate,
-		arg.RentAmount,
-		arg.UpdatedBy,
-		arg.ID,
-	)
+func (q *Queries) SetLeaseGeneratedPdfKey(ctx context.Context, arg SetLeaseGeneratedPdfKeyParams) error {
+	_, err := q.db.Exec(ctx, setLeaseGeneratedPdfKey, arg.ID, arg.GeneratedPdfKey)
 	return err
 }
 
-const updateLeaseAndApartmentOnDocumentCompletion = `-- name: UpdateLeaseAndApartmentOnDocumentCompletion :one
-WITH updated_lease AS (
-    UPDATE leases
-    SET 
-        status = 'active',
-        updated_at = now(),
-        updated_by = $2
-    WHERE external_doc_id = $1
-    RETURNING id, apartment_id, rent_amount, landlord_id
-)
-UPDATE apartments
-SET 
-    availability = false,
-    lease_id = (SELECT id FROM updated_lease),
+const setLeaseHoldoverPolicy = `-- name: SetLeaseHoldoverPolicy :exec
+UPDATE leases
+SET holdover_policy = $2,
+    holdover_premium_percent = $3,
+    updated_at = now()
+WHERE id = $1
+`
+
+type SetLeaseHoldoverPolicyParams struct {
+	ID                     int64              `json:"id"`
+	HoldoverPolicy         NullHoldoverPolicy `json:"holdover_policy"`
+	HoldoverPremiumPercent pgtype.Numeric     `json:"holdover_premium_percent"`
+}
+
+func (q *Queries) SetLeaseHoldoverPolicy(ctx context.Context, arg SetLeaseHoldoverPolicyParams) error {
+	_, err := q.db.Exec(ctx, setLeaseHoldoverPolicy, arg.ID, arg.HoldoverPolicy, arg.HoldoverPremiumPercent)
+	return err
+}
+
+const setLeaseRentEscalation = `-- name: SetLeaseRentEscalation :exec
+UPDATE leases
+SET rent_escalation = $2,
+    updated_at = now()
+WHERE id = $1
+`
+
+type SetLeaseRentEscalationParams struct {
+	ID             int64  `json:"id"`
+	RentEscalation []byte `json:"rent_escalation"`
+}
+
+func (q *Queries) SetLeaseRentEscalation(ctx context.Context, arg SetLeaseRentEscalationParams) error {
+	_, err := q.db.Exec(ctx, setLeaseRentEscalation, arg.ID, arg.RentEscalation)
+	return err
+}
+
+const setLeaseSignedPdfKey = `-- name: SetLeaseSignedPdfKey :exec
+UPDATE leases
+SET signed_pdf_key = $2,
+    updated_at = now()
+WHERE id = $1
+`
+
+type SetLeaseSignedPdfKeyParams struct {
+	ID           int64       `json:"id"`
+	SignedPdfKey pgtype.Text `json:"signed_pdf_key"`
+}
+
+func (q *Queries) SetLeaseSignedPdfKey(ctx context.Context, arg SetLeaseSignedPdfKeyParams) error {
+	_, err := q.db.Exec(ctx, setLeaseSignedPdfKey, arg.ID, arg.SignedPdfKey)
+	return err
+}
+
+const setLeaseTerminationTerms = `-- name: SetLeaseTerminationTerms :exec
+UPDATE leases
+SET notice_period_days = $2,
+    early_termination_fee = $3,
     updated_at = now()
-WHERE id = (SELECT apartment_id FROM updated_lease)
-RETURNING 
-    (SELECT json_build_object(
-        'lease_id', ul.id,
-        'apartment_id', ul.apartment_id,
-        'success', true
-    ) FROM updated_lease ul)
+WHERE id = $1
 `
 
-type UpdateLeaseAndApartmentOnDocumentCompletionParams struct {
-	ExternalDocID string `json:"external_doc_id"`
-	UpdatedBy     int64  `json:"updated_by"`
+type SetLeaseTerminationTermsParams struct {
+	ID                  int64          `json:"id"`
+	NoticePeriodDays    int32          `json:"notice_period_days"`
+	EarlyTerminationFee pgtype.Numeric `json:"early_termination_fee"`
 }
 
-func (q *Queries) UpdateLeaseAndApartmentOnDocumentCompletion(ctx context.Context, arg UpdateLeaseAndApartmentOnDocumentCompletionParams) ([]byte, error) {
-	row := q.db.QueryRow(ctx, updateLeaseAndApartmentOnDocumentCompletion, arg.ExternalDocID, arg.UpdatedBy)
-	var json_build_object []byte
-	err := row.Scan(&json_build_object)
-	return json_build_object, err
+func (q *Queries) SetLeaseTerminationTerms(ctx context.Context, arg SetLeaseTerminationTermsParams) error {
+	_, err := q.db.Exec(ctx, setLeaseTerminationTerms, arg.ID, arg.NoticePeriodDays, arg.EarlyTerminationFee)
+	return err
+}
+
+const storeGeneratedLeasePDFURL = `-- name: StoreGeneratedLeasePDFURL :exec
+UPDATE leases
+SET lease_pdf_s3 = $1, external_doc_id = $2, updated_at = now()
+WHERE id = $3
+RETURNING lease_pdf_s3
+`
+
+type StoreGeneratedLeasePDFURLParams struct {
+	LeasePdfS3    pgtype.Text `json:"lease_pdf_s3"`
+	ExternalDocID string      `json:"external_doc_id"`
+	ID            int64       `json:"id"`
+}
+
+func (q *Queries) StoreGeneratedLeasePDFURL(ctx context.Context, arg StoreGeneratedLeasePDFURLParams) error {
+	_, err := q.db.Exec(ctx, storeGeneratedLeasePDFURL, arg.LeasePdfS3, arg.ExternalDocID, arg.ID)
+	return err
 }
 
 const updateLeasePDF = `-- name: UpdateLeasePDF :exec
@@ -986,60 +1254,6 @@ func (q *Queries) UpdateLeasePDF(ctx context.Context, arg UpdateLeasePDFParams)
 	return err
 }
 
-const updateLeaseStatus = `-- name: UpdateLeaseStatus :one
-UPDATE leases
-SET status = $2, updated_by = $3, updated_at = NOW()
-WHERE id = $1
-RETURNING id, lease_number, external_doc_id, tenant_id, landlord_id, apartment_id, 
-    lease_start_date, lease_end_date, rent_amount, status, created_by, 
-    updated_by, updated_at, previous_lease_id
-`
-
-type UpdateLeaseStatusParams struct {
-	ID        int64       `json:"id"`
-	Status    LeaseStatus `json:"status"`
-	UpdatedBy int64       `json:"updated_by"`
-}
-
-type UpdateLeaseStatusRow struct {
-	ID              int64            `json:"id"`
-	LeaseNumber     int64            `json:"lease_number"`
-	ExternalDocID   string           `json:"external_doc_id"`
-	TenantID        int64            `json:"tenant_id"`
-	LandlordID      int64            `json:"landlord_id"`
-	ApartmentID     int64            `json:"apartment_id"`
-	LeaseStartDate  pgtype.Date      `json:"lease_start_date"`
-	LeaseEndDate    pgtype.Date      `json:"lease_end_date"`
-	RentAmount      pgtype.Numeric   `json:"rent_amount"`
-	Status          LeaseStatus      `json:"status"`
-	CreatedBy       int64            `json:"created_by"`
-	UpdatedBy       int64            `json:"updated_by"`
-	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
-	PreviousLeaseID pgtype.Int8      `json:"previous_lease_id"`
-}
-
-func (q *Queries) UpdateLeaseStatus(ctx context.Context, arg UpdateLeaseStatusParams) (UpdateLeaseStatusRow, error) {
-	row := q.db.QueryRow(ctx, updateLeaseStatus, arg.ID, arg.Status, arg.UpdatedBy)
-	var i UpdateLeaseStatusRow
-	err := row.Scan(
-		&i.ID,
-		&i.LeaseNumber,
-		&i.ExternalDocID,
-		&i.TenantID,
-		&i.LandlordID,
-		&i.ApartmentID,
-		&i.LeaseStartDate,
-		&i.LeaseEndDate,
-		&i.RentAmount,
-		&i.Status,
-		&i.CreatedBy,
-		&i.UpdatedBy,
-		&i.UpdatedAt,
-		&i.PreviousLeaseID,
-	)
-	return i, err
-}
-
 const updateSignedLeasePdfS3URL = `-- name: UpdateSignedLeasePdfS3URL :exec
 UPDATE leases
 SET lease_pdf_s3 = $2,
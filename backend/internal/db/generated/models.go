@@ -54,6 +54,50 @@ func (ns NullAccountStatus) Value() (driver.Value, error) {
 	return string(ns.AccountStatus), nil
 }
 
+type ApplicationStatus string
+
+const (
+	ApplicationStatusSubmitted   ApplicationStatus = "submitted"
+	ApplicationStatusConditional ApplicationStatus = "conditional"
+	ApplicationStatusApproved    ApplicationStatus = "approved"
+	ApplicationStatusDenied      ApplicationStatus = "denied"
+)
+
+func (e *ApplicationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ApplicationStatus(s)
+	case string:
+		*e = ApplicationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ApplicationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullApplicationStatus struct {
+	ApplicationStatus ApplicationStatus `json:"Application_Status"`
+	Valid             bool              `json:"valid"` // Valid is true if ApplicationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullApplicationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ApplicationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ApplicationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullApplicationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ApplicationStatus), nil
+}
+
 type ComplaintCategory string
 
 const (
@@ -148,15 +192,191 @@ func (ns NullComplianceStatus) Value() (driver.Value, error) {
 	return string(ns.ComplianceStatus), nil
 }
 
+type DepositDispositionStatus string
+
+const (
+	DepositDispositionStatusPendingDisposition DepositDispositionStatus = "pending_disposition"
+	DepositDispositionStatusIssued             DepositDispositionStatus = "issued"
+)
+
+func (e *DepositDispositionStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DepositDispositionStatus(s)
+	case string:
+		*e = DepositDispositionStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DepositDispositionStatus: %T", src)
+	}
+	return nil
+}
+
+type NullDepositDispositionStatus struct {
+	DepositDispositionStatus DepositDispositionStatus `json:"Deposit_Disposition_Status"`
+	Valid                    bool                     `json:"valid"` // Valid is true if DepositDispositionStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDepositDispositionStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.DepositDispositionStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DepositDispositionStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDepositDispositionStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DepositDispositionStatus), nil
+}
+
+type DocumentCategory string
+
+const (
+	DocumentCategoryLease            DocumentCategory = "lease"
+	DocumentCategoryAmendment        DocumentCategory = "amendment"
+	DocumentCategoryNotice           DocumentCategory = "notice"
+	DocumentCategoryReceipt          DocumentCategory = "receipt"
+	DocumentCategoryInspectionReport DocumentCategory = "inspection_report"
+	DocumentCategoryStatement        DocumentCategory = "statement"
+	DocumentCategoryOther            DocumentCategory = "other"
+)
+
+func (e *DocumentCategory) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DocumentCategory(s)
+	case string:
+		*e = DocumentCategory(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DocumentCategory: %T", src)
+	}
+	return nil
+}
+
+type NullDocumentCategory struct {
+	DocumentCategory DocumentCategory `json:"Document_Category"`
+	Valid            bool             `json:"valid"` // Valid is true if DocumentCategory is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDocumentCategory) Scan(value interface{}) error {
+	if value == nil {
+		ns.DocumentCategory, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DocumentCategory.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDocumentCategory) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DocumentCategory), nil
+}
+
+type HoldoverPolicy string
+
+const (
+	HoldoverPolicyExpire       HoldoverPolicy = "expire"
+	HoldoverPolicyMonthToMonth HoldoverPolicy = "month_to_month"
+)
+
+func (e *HoldoverPolicy) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = HoldoverPolicy(s)
+	case string:
+		*e = HoldoverPolicy(s)
+	default:
+		return fmt.Errorf("unsupported scan type for HoldoverPolicy: %T", src)
+	}
+	return nil
+}
+
+type NullHoldoverPolicy struct {
+	HoldoverPolicy HoldoverPolicy `json:"Holdover_Policy"`
+	Valid          bool           `json:"valid"` // Valid is true if HoldoverPolicy is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullHoldoverPolicy) Scan(value interface{}) error {
+	if value == nil {
+		ns.HoldoverPolicy, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.HoldoverPolicy.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullHoldoverPolicy) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.HoldoverPolicy), nil
+}
+
+type LeaseSignerRole string
+
+const (
+	LeaseSignerRoleTenant    LeaseSignerRole = "tenant"
+	LeaseSignerRoleLandlord  LeaseSignerRole = "landlord"
+	LeaseSignerRoleGuarantor LeaseSignerRole = "guarantor"
+)
+
+func (e *LeaseSignerRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LeaseSignerRole(s)
+	case string:
+		*e = LeaseSignerRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LeaseSignerRole: %T", src)
+	}
+	return nil
+}
+
+type NullLeaseSignerRole struct {
+	LeaseSignerRole LeaseSignerRole `json:"Lease_Signer_Role"`
+	Valid           bool            `json:"valid"` // Valid is true if LeaseSignerRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLeaseSignerRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.LeaseSignerRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LeaseSignerRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLeaseSignerRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LeaseSignerRole), nil
+}
+
 type LeaseStatus string
 
 const (
 	LeaseStatusDraft           LeaseStatus = "draft"
 	LeaseStatusPendingApproval LeaseStatus = "pending_approval"
 	LeaseStatusActive          LeaseStatus = "active"
+	LeaseStatusMonthToMonth    LeaseStatus = "month_to_month"
 	LeaseStatusExpired         LeaseStatus = "expired"
 	LeaseStatusTerminated      LeaseStatus = "terminated"
 	LeaseStatusRenewed         LeaseStatus = "renewed"
+	LeaseStatusSuperseded      LeaseStatus = "superseded"
 	LeaseStatusCanceled        LeaseStatus = "canceled"
 )
 
@@ -195,6 +415,222 @@ func (ns NullLeaseStatus) Value() (driver.Value, error) {
 	return string(ns.LeaseStatus), nil
 }
 
+type LedgerEntryType string
+
+const (
+	LedgerEntryTypeCharge  LedgerEntryType = "charge"
+	LedgerEntryTypePayment LedgerEntryType = "payment"
+	LedgerEntryTypeCredit  LedgerEntryType = "credit"
+)
+
+func (e *LedgerEntryType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LedgerEntryType(s)
+	case string:
+		*e = LedgerEntryType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LedgerEntryType: %T", src)
+	}
+	return nil
+}
+
+type NullLedgerEntryType struct {
+	LedgerEntryType LedgerEntryType `json:"Ledger_Entry_Type"`
+	Valid           bool            `json:"valid"` // Valid is true if LedgerEntryType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLedgerEntryType) Scan(value interface{}) error {
+	if value == nil {
+		ns.LedgerEntryType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LedgerEntryType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLedgerEntryType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LedgerEntryType), nil
+}
+
+type NoticeInitiator string
+
+const (
+	NoticeInitiatorTenant   NoticeInitiator = "tenant"
+	NoticeInitiatorLandlord NoticeInitiator = "landlord"
+)
+
+func (e *NoticeInitiator) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NoticeInitiator(s)
+	case string:
+		*e = NoticeInitiator(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NoticeInitiator: %T", src)
+	}
+	return nil
+}
+
+type NullNoticeInitiator struct {
+	NoticeInitiator NoticeInitiator `json:"Notice_Initiator"`
+	Valid           bool            `json:"valid"` // Valid is true if NoticeInitiator is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNoticeInitiator) Scan(value interface{}) error {
+	if value == nil {
+		ns.NoticeInitiator, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NoticeInitiator.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNoticeInitiator) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NoticeInitiator), nil
+}
+
+type NoticeStatus string
+
+const (
+	NoticeStatusPendingSignature NoticeStatus = "pending_signature"
+	NoticeStatusScheduled        NoticeStatus = "scheduled"
+	NoticeStatusCompleted        NoticeStatus = "completed"
+	NoticeStatusCancelled        NoticeStatus = "cancelled"
+)
+
+func (e *NoticeStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NoticeStatus(s)
+	case string:
+		*e = NoticeStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NoticeStatus: %T", src)
+	}
+	return nil
+}
+
+type NullNoticeStatus struct {
+	NoticeStatus NoticeStatus `json:"Notice_Status"`
+	Valid        bool         `json:"valid"` // Valid is true if NoticeStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNoticeStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.NoticeStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NoticeStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNoticeStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NoticeStatus), nil
+}
+
+type ProrationMethod string
+
+const (
+	ProrationMethodActualDays ProrationMethod = "actual_days"
+	ProrationMethodThirtyDay  ProrationMethod = "thirty_day"
+)
+
+func (e *ProrationMethod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProrationMethod(s)
+	case string:
+		*e = ProrationMethod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProrationMethod: %T", src)
+	}
+	return nil
+}
+
+type NullProrationMethod struct {
+	ProrationMethod ProrationMethod `json:"proration_method"`
+	Valid           bool            `json:"valid"` // Valid is true if ProrationMethod is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProrationMethod) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProrationMethod, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProrationMethod.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProrationMethod) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProrationMethod), nil
+}
+
+type RenewalOfferStatus string
+
+const (
+	RenewalOfferStatusPending   RenewalOfferStatus = "pending"
+	RenewalOfferStatusAccepted  RenewalOfferStatus = "accepted"
+	RenewalOfferStatusDeclined  RenewalOfferStatus = "declined"
+	RenewalOfferStatusExpired   RenewalOfferStatus = "expired"
+	RenewalOfferStatusWithdrawn RenewalOfferStatus = "withdrawn"
+)
+
+func (e *RenewalOfferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RenewalOfferStatus(s)
+	case string:
+		*e = RenewalOfferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RenewalOfferStatus: %T", src)
+	}
+	return nil
+}
+
+type NullRenewalOfferStatus struct {
+	RenewalOfferStatus RenewalOfferStatus `json:"Renewal_Offer_Status"`
+	Valid              bool               `json:"valid"` // Valid is true if RenewalOfferStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRenewalOfferStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.RenewalOfferStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RenewalOfferStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRenewalOfferStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.RenewalOfferStatus), nil
+}
+
 type Role string
 
 const (
@@ -237,6 +673,92 @@ func (ns NullRole) Value() (driver.Value, error) {
 	return string(ns.Role), nil
 }
 
+type ScreeningRecommendation string
+
+const (
+	ScreeningRecommendationPass   ScreeningRecommendation = "pass"
+	ScreeningRecommendationReview ScreeningRecommendation = "review"
+	ScreeningRecommendationFail   ScreeningRecommendation = "fail"
+)
+
+func (e *ScreeningRecommendation) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScreeningRecommendation(s)
+	case string:
+		*e = ScreeningRecommendation(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScreeningRecommendation: %T", src)
+	}
+	return nil
+}
+
+type NullScreeningRecommendation struct {
+	ScreeningRecommendation ScreeningRecommendation `json:"Screening_Recommendation"`
+	Valid                   bool                    `json:"valid"` // Valid is true if ScreeningRecommendation is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScreeningRecommendation) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScreeningRecommendation, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScreeningRecommendation.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScreeningRecommendation) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScreeningRecommendation), nil
+}
+
+type SigningStatus string
+
+const (
+	SigningStatusPending  SigningStatus = "pending"
+	SigningStatusSigned   SigningStatus = "signed"
+	SigningStatusRejected SigningStatus = "rejected"
+)
+
+func (e *SigningStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SigningStatus(s)
+	case string:
+		*e = SigningStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SigningStatus: %T", src)
+	}
+	return nil
+}
+
+type NullSigningStatus struct {
+	SigningStatus SigningStatus `json:"Signing_Status"`
+	Valid         bool          `json:"valid"` // Valid is true if SigningStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSigningStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.SigningStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SigningStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSigningStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SigningStatus), nil
+}
+
 type Status string
 
 const (
@@ -326,6 +848,140 @@ func (ns NullType) Value() (driver.Value, error) {
 	return string(ns.Type), nil
 }
 
+type WaitlistEntryStatus string
+
+const (
+	WaitlistEntryStatusWaiting   WaitlistEntryStatus = "waiting"
+	WaitlistEntryStatusOffered   WaitlistEntryStatus = "offered"
+	WaitlistEntryStatusAccepted  WaitlistEntryStatus = "accepted"
+	WaitlistEntryStatusWithdrawn WaitlistEntryStatus = "withdrawn"
+)
+
+func (e *WaitlistEntryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WaitlistEntryStatus(s)
+	case string:
+		*e = WaitlistEntryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WaitlistEntryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWaitlistEntryStatus struct {
+	WaitlistEntryStatus WaitlistEntryStatus `json:"waitlist_entry_status"`
+	Valid               bool                `json:"valid"` // Valid is true if WaitlistEntryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWaitlistEntryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WaitlistEntryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WaitlistEntryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWaitlistEntryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WaitlistEntryStatus), nil
+}
+
+type WaitlistOfferStatus string
+
+const (
+	WaitlistOfferStatusPending   WaitlistOfferStatus = "pending"
+	WaitlistOfferStatusAccepted  WaitlistOfferStatus = "accepted"
+	WaitlistOfferStatusDeclined  WaitlistOfferStatus = "declined"
+	WaitlistOfferStatusExpired   WaitlistOfferStatus = "expired"
+	WaitlistOfferStatusWithdrawn WaitlistOfferStatus = "withdrawn"
+)
+
+func (e *WaitlistOfferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WaitlistOfferStatus(s)
+	case string:
+		*e = WaitlistOfferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WaitlistOfferStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWaitlistOfferStatus struct {
+	WaitlistOfferStatus WaitlistOfferStatus `json:"waitlist_offer_status"`
+	Valid               bool                `json:"valid"` // Valid is true if WaitlistOfferStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWaitlistOfferStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WaitlistOfferStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WaitlistOfferStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWaitlistOfferStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WaitlistOfferStatus), nil
+}
+
+type WebhookEventStatus string
+
+const (
+	WebhookEventStatusReceived   WebhookEventStatus = "received"
+	WebhookEventStatusProcessing WebhookEventStatus = "processing"
+	WebhookEventStatusProcessed  WebhookEventStatus = "processed"
+	WebhookEventStatusFailed     WebhookEventStatus = "failed"
+	WebhookEventStatusRejected   WebhookEventStatus = "rejected"
+)
+
+func (e *WebhookEventStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookEventStatus(s)
+	case string:
+		*e = WebhookEventStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookEventStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookEventStatus struct {
+	WebhookEventStatus WebhookEventStatus `json:"Webhook_Event_Status"`
+	Valid              bool               `json:"valid"` // Valid is true if WebhookEventStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookEventStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookEventStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookEventStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookEventStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookEventStatus), nil
+}
+
 type WorkCategory string
 
 const (
@@ -383,6 +1039,10 @@ type Apartment struct {
 	LeaseID      pgtype.Int8      `json:"lease_id"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	// number of bedrooms, 0 for a studio
+	Bedrooms pgtype.Int2 `json:"bedrooms"`
+	// kind of unit prospects can wait for, like studio, loft or townhouse
+	UnitType pgtype.Text `json:"unit_type"`
 }
 
 type AppConfig struct {
@@ -396,12 +1056,21 @@ type AppConfig struct {
 }
 
 type Building struct {
-	ID             int64            `json:"id"`
-	ParkingTotal   pgtype.Int8      `json:"parking_total"`
-	PerUserParking pgtype.Int8      `json:"per_user_parking"`
-	ManagementID   int64            `json:"management_id"`
-	CreatedAt      pgtype.Timestamp `json:"created_at"`
-	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+	ID             int64       `json:"id"`
+	ParkingTotal   pgtype.Int8 `json:"parking_total"`
+	PerUserParking pgtype.Int8 `json:"per_user_parking"`
+	// landlord managing the building
+	ManagementID int64            `json:"management_id"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	// house rules printed on leases for units in this building
+	Rules pgtype.Text `json:"rules"`
+	// daily rate for partial months: actual days in the month or a 30-day month
+	ProrationMethod ProrationMethod `json:"proration_method"`
+	// expire leases at their end date or convert them to month-to-month
+	HoldoverPolicy HoldoverPolicy `json:"holdover_policy"`
+	// percent added to the last rent while a tenant holds over month-to-month
+	HoldoverPremiumPercent pgtype.Numeric `json:"holdover_premium_percent"`
 }
 
 type Complaint struct {
@@ -416,6 +1085,71 @@ type Complaint struct {
 	CreatedAt   pgtype.Timestamp  `json:"created_at"`
 }
 
+type DepositDisposition struct {
+	ID             int64          `json:"id"`
+	LeaseID        int64          `json:"lease_id"`
+	DepositAmount  pgtype.Numeric `json:"deposit_amount"`
+	InterestAmount pgtype.Numeric `json:"interest_amount"`
+	// itemized deductions as a list of {category, description, amount}
+	Deductions      []byte         `json:"deductions"`
+	TotalDeductions pgtype.Numeric `json:"total_deductions"`
+	// deposit plus interest less deductions; negative when the tenant still owes
+	RefundAmount pgtype.Numeric `json:"refund_amount"`
+	MoveOutDate  pgtype.Date    `json:"move_out_date"`
+	// statutory deadline for sending the statement to the tenant
+	DueBy pgtype.Date `json:"due_by"`
+	// pending_disposition until an admin records the deductions or the deadline arrives; amounts are final once issued
+	Status    DepositDispositionStatus `json:"status"`
+	SentTo    pgtype.Text              `json:"sent_to"`
+	SentAt    pgtype.Timestamp         `json:"sent_at"`
+	CreatedBy pgtype.Int8              `json:"created_by"`
+	CreatedAt pgtype.Timestamp         `json:"created_at"`
+}
+
+type Document struct {
+	ID          int64            `json:"id"`
+	Category    DocumentCategory `json:"category"`
+	Title       string           `json:"title"`
+	FileName    string           `json:"file_name"`
+	ContentType string           `json:"content_type"`
+	SizeBytes   int64            `json:"size_bytes"`
+	StorageKey  string           `json:"storage_key"`
+	// only this tenant sees the document
+	TenantID pgtype.Int8 `json:"tenant_id"`
+	// every tenant on this lease sees the document
+	LeaseID pgtype.Int8 `json:"lease_id"`
+	// current tenants of the unit see the document when it is not tied to a tenant or lease
+	ApartmentID pgtype.Int8 `json:"apartment_id"`
+	// admin who uploaded the document; NULL when the system filed it
+	UploadedBy pgtype.Int8      `json:"uploaded_by"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type DocumentDownload struct {
+	ID           int64            `json:"id"`
+	DocumentID   int64            `json:"document_id"`
+	UserID       pgtype.Int8      `json:"user_id"`
+	IpAddress    string           `json:"ip_address"`
+	UserAgent    string           `json:"user_agent"`
+	DownloadedAt pgtype.Timestamp `json:"downloaded_at"`
+}
+
+type Landlord struct {
+	ID int64 `json:"id"`
+	// name the lease is made in, like a person or a management company
+	LegalName string `json:"legal_name"`
+	// address for notices, printed on leases
+	Address string `json:"address"`
+	// property management or broker license, where one is required
+	LicenseNumber pgtype.Text `json:"license_number"`
+	// admin user who signs for the landlord; NULL when the signer has no account
+	SignerUserID pgtype.Int8      `json:"signer_user_id"`
+	SignerName   string           `json:"signer_name"`
+	SignerEmail  string           `json:"signer_email"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+}
+
 type Lease struct {
 	ID                 int64            `json:"id"`
 	LeaseNumber        int64            `json:"lease_number"`
@@ -435,6 +1169,177 @@ type Lease struct {
 	PreviousLeaseID    pgtype.Int8      `json:"previous_lease_id"`
 	TenantSigningUrl   pgtype.Text      `json:"tenant_signing_url"`
 	LandlordSigningUrl pgtype.Text      `json:"landlord_signing_url"`
+	LeaseTemplateID    pgtype.Int8      `json:"lease_template_id"`
+	DocumentType       Type             `json:"document_type"`
+	// rent step-ups as {percent, interval_months} or {steps: [{month, amount}]}
+	RentEscalation []byte `json:"rent_escalation"`
+	// days of written notice required before moving out
+	NoticePeriodDays int32 `json:"notice_period_days"`
+	// fee owed when the tenant moves out before the lease end date
+	EarlyTerminationFee pgtype.Numeric `json:"early_termination_fee"`
+	// overrides the building holdover policy when set
+	HoldoverPolicy NullHoldoverPolicy `json:"holdover_policy"`
+	// overrides the building holdover premium when set
+	HoldoverPremiumPercent pgtype.Numeric `json:"holdover_premium_percent"`
+	// monthly rent charged since the lease went month-to-month
+	HoldoverRent pgtype.Numeric `json:"holdover_rent"`
+	// storage key of the lease PDF as generated, before signing
+	GeneratedPdfKey pgtype.Text `json:"generated_pdf_key"`
+	// storage key of the fully signed lease PDF
+	SignedPdfKey pgtype.Text `json:"signed_pdf_key"`
+}
+
+type LeaseAddenda struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	// addendum text; may use the lease template placeholders
+	Body string `json:"body"`
+	// archived addenda stay on existing leases but cannot be attached to new ones
+	Archived  bool             `json:"archived"`
+	CreatedBy pgtype.Int8      `json:"created_by"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type LeaseAmendment struct {
+	ID int64 `json:"id"`
+	// lease that was amended
+	LeaseID int64 `json:"lease_id"`
+	// new lease version carrying the amended terms
+	AmendedLeaseID int64 `json:"amended_lease_id"`
+	Version        int32 `json:"version"`
+	// changed terms as a list of {field, from, to}
+	Changes       []byte           `json:"changes"`
+	ExternalDocID string           `json:"external_doc_id"`
+	CreatedBy     int64            `json:"created_by"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+type LeaseAttachedAddenda struct {
+	LeaseID    int64 `json:"lease_id"`
+	AddendumID int64 `json:"addendum_id"`
+	// order the addendum pages appear in the lease PDF
+	Position  int32            `json:"position"`
+	Title     string           `json:"title"`
+	Body      string           `json:"body"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type LeaseDeposit struct {
+	ID         int64          `json:"id"`
+	LeaseID    int64          `json:"lease_id"`
+	Amount     pgtype.Numeric `json:"amount"`
+	ReceivedOn pgtype.Date    `json:"received_on"`
+	// bank account or trust account the deposit is held in
+	HoldingAccount string `json:"holding_account"`
+	// annual simple interest owed to the tenant in percent, where required by law
+	InterestRate pgtype.Numeric   `json:"interest_rate"`
+	CreatedBy    pgtype.Int8      `json:"created_by"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+}
+
+type LeaseGuarantor struct {
+	ID      int64  `json:"id"`
+	LeaseID int64  `json:"lease_id"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	// relationship to the tenants, e.g. parent
+	Relationship string           `json:"relationship"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+type LeaseRenewalOffer struct {
+	ID      int64 `json:"id"`
+	LeaseID int64 `json:"lease_id"`
+	// secret used in the link emailed to the tenant
+	Token  string             `json:"token"`
+	Status RenewalOfferStatus `json:"status"`
+	// proposed terms as a list of {term_months, rent_amount}
+	Options []byte `json:"options"`
+	// index into options chosen by the tenant
+	AcceptedOption pgtype.Int4 `json:"accepted_option"`
+	RenewedLeaseID pgtype.Int8 `json:"renewed_lease_id"`
+	// last reminder stage emailed; reminders escalate as the lease end date approaches
+	NoticeStage    int32            `json:"notice_stage"`
+	LastNotifiedAt pgtype.Timestamp `json:"last_notified_at"`
+	RespondedAt    pgtype.Timestamp `json:"responded_at"`
+	// the offer can no longer be accepted from this time, the day after the lease ends
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	CreatedBy pgtype.Int8      `json:"created_by"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type LeaseSigner struct {
+	ID      int64           `json:"id"`
+	LeaseID int64           `json:"lease_id"`
+	UserID  pgtype.Int8     `json:"user_id"`
+	Role    LeaseSignerRole `json:"role"`
+	Name    string          `json:"name"`
+	// recipient email on the e-sign document, used to match webhook recipients
+	Email           string           `json:"email"`
+	SigningUrl      pgtype.Text      `json:"signing_url"`
+	Status          SigningStatus    `json:"status"`
+	SignedAt        pgtype.Timestamp `json:"signed_at"`
+	RejectionReason pgtype.Text      `json:"rejection_reason"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+}
+
+type LeaseStatusHistory struct {
+	ID      int64 `json:"id"`
+	LeaseID int64 `json:"lease_id"`
+	// NULL when the lease was created
+	FromStatus NullLeaseStatus `json:"from_status"`
+	ToStatus   LeaseStatus     `json:"to_status"`
+	// what caused the change, like sent, signed, expired or holdover
+	Event  string      `json:"event"`
+	Reason pgtype.Text `json:"reason"`
+	// user who made the change; NULL for scheduled jobs
+	ChangedBy pgtype.Int8      `json:"changed_by"`
+	ChangedAt pgtype.Timestamp `json:"changed_at"`
+}
+
+type LeaseTemplate struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Version int32  `json:"version"`
+	// title, ordered clause blocks with placeholders and the guaranty addendum text
+	Content   []byte           `json:"content"`
+	CreatedBy pgtype.Int8      `json:"created_by"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type LeaseTenant struct {
+	LeaseID  int64 `json:"lease_id"`
+	TenantID int64 `json:"tenant_id"`
+	// true for the tenant stored on leases.tenant_id
+	IsPrimary bool             `json:"is_primary"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type LeaseVacateNotice struct {
+	ID          int64           `json:"id"`
+	LeaseID     int64           `json:"lease_id"`
+	InitiatedBy NoticeInitiator `json:"initiated_by"`
+	RequestedBy pgtype.Int8     `json:"requested_by"`
+	NoticeDate  pgtype.Date     `json:"notice_date"`
+	// day the lease is terminated; never earlier than the notice period allows
+	MoveOutDate      pgtype.Date `json:"move_out_date"`
+	EarlyTermination bool        `json:"early_termination"`
+	// early termination fee charged to the ledger when the lease ends
+	TerminationFee pgtype.Numeric `json:"termination_fee"`
+	Reason         string         `json:"reason"`
+	Status         NoticeStatus   `json:"status"`
+	ExternalDocID  pgtype.Text    `json:"external_doc_id"`
+	// e-sign link of each signer keyed by lowercase email
+	SigningUrls []byte           `json:"signing_urls"`
+	SignedAt    pgtype.Timestamp `json:"signed_at"`
+	CompletedAt pgtype.Timestamp `json:"completed_at"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
 }
 
 type Locker struct {
@@ -456,6 +1361,94 @@ type ParkingPermit struct {
 	ExpiresAt pgtype.Timestamp `json:"expires_at"`
 }
 
+type RentLedgerEntry struct {
+	ID          int64           `json:"id"`
+	LeaseID     int64           `json:"lease_id"`
+	TenantID    int64           `json:"tenant_id"`
+	EntryType   LedgerEntryType `json:"entry_type"`
+	Amount      pgtype.Numeric  `json:"amount"`
+	Description string          `json:"description"`
+	// first day of the billed month for rent charges
+	PeriodStart   pgtype.Date      `json:"period_start"`
+	DueDate       pgtype.Date      `json:"due_date"`
+	EntryDate     pgtype.Date      `json:"entry_date"`
+	PaymentMethod pgtype.Text      `json:"payment_method"`
+	Reference     pgtype.Text      `json:"reference"`
+	CreatedBy     pgtype.Int8      `json:"created_by"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+type RentalApplication struct {
+	ID            int64             `json:"id"`
+	ApartmentID   int64             `json:"apartment_id"`
+	Status        ApplicationStatus `json:"status"`
+	FirstName     string            `json:"first_name"`
+	LastName      string            `json:"last_name"`
+	Email         string            `json:"email"`
+	Phone         string            `json:"phone"`
+	DesiredMoveIn pgtype.Date       `json:"desired_move_in"`
+	// gross monthly income of the applicant
+	MonthlyIncome pgtype.Numeric `json:"monthly_income"`
+	Employer      string         `json:"employer"`
+	// anything the applicant wants the landlord to know
+	Message        string `json:"message"`
+	DecisionReason string `json:"decision_reason"`
+	// what the applicant still has to provide when conditionally approved
+	Conditions string           `json:"conditions"`
+	DecidedBy  pgtype.Int8      `json:"decided_by"`
+	DecidedAt  pgtype.Timestamp `json:"decided_at"`
+	// account the applicant was given on approval
+	TenantID pgtype.Int8 `json:"tenant_id"`
+	// draft lease created on approval
+	LeaseID   pgtype.Int8      `json:"lease_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	// screening the decision was made on
+	ScreeningID pgtype.Int8 `json:"screening_id"`
+}
+
+type RentalApplicationDocument struct {
+	ID            int64            `json:"id"`
+	ApplicationID int64            `json:"application_id"`
+	FileName      string           `json:"file_name"`
+	ContentType   string           `json:"content_type"`
+	SizeBytes     int64            `json:"size_bytes"`
+	StorageKey    string           `json:"storage_key"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+type RentalApplicationMember struct {
+	ID            int64          `json:"id"`
+	ApplicationID int64          `json:"application_id"`
+	Name          string         `json:"name"`
+	Relationship  string         `json:"relationship"`
+	IsAdult       bool           `json:"is_adult"`
+	MonthlyIncome pgtype.Numeric `json:"monthly_income"`
+}
+
+type RentalApplicationReference struct {
+	ID            int64  `json:"id"`
+	ApplicationID int64  `json:"application_id"`
+	Name          string `json:"name"`
+	Relationship  string `json:"relationship"`
+	Phone         string `json:"phone"`
+	Email         string `json:"email"`
+}
+
+type RentalApplicationScreening struct {
+	ID            int64  `json:"id"`
+	ApplicationID int64  `json:"application_id"`
+	Provider      string `json:"provider"`
+	// the provider's ID for the report
+	Reference string `json:"reference"`
+	// least favourable result of the checks
+	Recommendation ScreeningRecommendation `json:"recommendation"`
+	// results of each check as returned by the provider
+	Report      []byte           `json:"report"`
+	RequestedBy pgtype.Int8      `json:"requested_by"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
 type User struct {
 	ID int64 `json:"id"`
 	// provided by Clerk
@@ -470,6 +1463,54 @@ type User struct {
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
+type WaitlistEntry struct {
+	ID         int64               `json:"id"`
+	FirstName  string              `json:"first_name"`
+	LastName   string              `json:"last_name"`
+	Email      string              `json:"email"`
+	Phone      string              `json:"phone"`
+	BuildingID pgtype.Int8         `json:"building_id"`
+	Bedrooms   pgtype.Int2         `json:"bedrooms"`
+	UnitType   pgtype.Text         `json:"unit_type"`
+	Status     WaitlistEntryStatus `json:"status"`
+	// secret in the links emailed to the prospect, lets them leave the waitlist
+	Token     string           `json:"token"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type WaitlistOffer struct {
+	ID          int64 `json:"id"`
+	EntryID     int64 `json:"entry_id"`
+	ApartmentID int64 `json:"apartment_id"`
+	// secret used in the link emailed to the prospect
+	Token  string              `json:"token"`
+	Status WaitlistOfferStatus `json:"status"`
+	// the offer passes to the next prospect if not answered by then
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	// rental application submitted when the offer was accepted
+	ApplicationID pgtype.Int8      `json:"application_id"`
+	RespondedAt   pgtype.Timestamp `json:"responded_at"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+}
+
+type WebhookEvent struct {
+	ID     int64  `json:"id"`
+	Source string `json:"source"`
+	// svix-id for Clerk, SHA-256 of the body for Documenso
+	DeliveryID  string             `json:"delivery_id"`
+	EventType   string             `json:"event_type"`
+	Headers     []byte             `json:"headers"`
+	Body        string             `json:"body"`
+	Status      WebhookEventStatus `json:"status"`
+	Attempts    int32              `json:"attempts"`
+	LastError   pgtype.Text        `json:"last_error"`
+	ProcessedAt pgtype.Timestamp   `json:"processed_at"`
+	UpdatedAt   pgtype.Timestamp   `json:"updated_at"`
+	CreatedAt   pgtype.Timestamp   `json:"created_at"`
+}
+
 type WorkOrder struct {
 	ID          int64            `json:"id"`
 	CreatedBy   int64            `json:"created_by"`
@@ -11,6 +11,109 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimApplicantUser = `-- name: ClaimApplicantUser :one
+UPDATE users
+SET clerk_id   = $2,
+    first_name = $3,
+    last_name  = $4,
+    email      = $5,
+    status     = 'active',
+    updated_at = now()
+WHERE id = $1
+  AND clerk_id LIKE 'application\_%'
+RETURNING id, clerk_id, first_name, last_name, email, phone, role, created_at
+`
+
+type ClaimApplicantUserParams struct {
+	ID        int64  `json:"id"`
+	ClerkID   string `json:"clerk_id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+}
+
+type ClaimApplicantUserRow struct {
+	ID        int64            `json:"id"`
+	ClerkID   string           `json:"clerk_id"`
+	FirstName string           `json:"first_name"`
+	LastName  string           `json:"last_name"`
+	Email     string           `json:"email"`
+	Phone     pgtype.Text      `json:"phone"`
+	Role      Role             `json:"role"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+func (q *Queries) ClaimApplicantUser(ctx context.Context, arg ClaimApplicantUserParams) (ClaimApplicantUserRow, error) {
+	row := q.db.QueryRow(ctx, claimApplicantUser,
+		arg.ID,
+		arg.ClerkID,
+		arg.FirstName,
+		arg.LastName,
+		arg.Email,
+	)
+	var i ClaimApplicantUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.ClerkID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Phone,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createApplicantUser = `-- name: CreateApplicantUser :one
+INSERT INTO users (clerk_id, first_name, last_name, email, phone, role, status, updated_at)
+VALUES ($1, $2, $3, $4, $5, 'tenant', 'inactive', now())
+RETURNING id, clerk_id, first_name, last_name, email, phone, role, status
+`
+
+type CreateApplicantUserParams struct {
+	ClerkID   string      `json:"clerk_id"`
+	FirstName string      `json:"first_name"`
+	LastName  string      `json:"last_name"`
+	Email     string      `json:"email"`
+	Phone     pgtype.Text `json:"phone"`
+}
+
+type CreateApplicantUserRow struct {
+	ID        int64         `json:"id"`
+	ClerkID   string        `json:"clerk_id"`
+	FirstName string        `json:"first_name"`
+	LastName  string        `json:"last_name"`
+	Email     string        `json:"email"`
+	Phone     pgtype.Text   `json:"phone"`
+	Role      Role          `json:"role"`
+	Status    AccountStatus `json:"status"`
+}
+
+// A tenant account for an approved applicant who has not signed up yet. clerk_id holds a placeholder until
+// the applicant accepts their invitation and the Clerk webhook claims the account.
+func (q *Queries) CreateApplicantUser(ctx context.Context, arg CreateApplicantUserParams) (CreateApplicantUserRow, error) {
+	row := q.db.QueryRow(ctx, createApplicantUser,
+		arg.ClerkID,
+		arg.FirstName,
+		arg.LastName,
+		arg.Email,
+		arg.Phone,
+	)
+	var i CreateApplicantUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.ClerkID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Phone,
+		&i.Role,
+		&i.Status,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     clerk_id,
@@ -68,6 +171,18 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 	return i, err
 }
 
+const deleteApplicantUser = `-- name: DeleteApplicantUser :exec
+DELETE
+FROM users
+WHERE id = $1
+  AND clerk_id LIKE 'application\_%'
+`
+
+func (q *Queries) DeleteApplicantUser(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deleteApplicantUser, id)
+	return err
+}
+
 const deleteUser = `-- name: DeleteUser :exec
 DELETE
 FROM users
@@ -79,6 +194,42 @@ func (q *Queries) DeleteUser(ctx context.Context, clerkID string) error {
 	return err
 }
 
+const getTenantByEmail = `-- name: GetTenantByEmail :one
+SELECT id, clerk_id, first_name, last_name, email, phone, role, status
+FROM users
+WHERE lower(email) = lower($1)
+  AND role = 'tenant'
+ORDER BY id
+LIMIT 1
+`
+
+type GetTenantByEmailRow struct {
+	ID        int64         `json:"id"`
+	ClerkID   string        `json:"clerk_id"`
+	FirstName string        `json:"first_name"`
+	LastName  string        `json:"last_name"`
+	Email     string        `json:"email"`
+	Phone     pgtype.Text   `json:"phone"`
+	Role      Role          `json:"role"`
+	Status    AccountStatus `json:"status"`
+}
+
+func (q *Queries) GetTenantByEmail(ctx context.Context, lower string) (GetTenantByEmailRow, error) {
+	row := q.db.QueryRow(ctx, getTenantByEmail, lower)
+	var i GetTenantByEmailRow
+	err := row.Scan(
+		&i.ID,
+		&i.ClerkID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Phone,
+		&i.Role,
+		&i.Status,
+	)
+	return i, err
+}
+
 const getTenantsWithNoLease = `-- name: GetTenantsWithNoLease :many
 SELECT id, clerk_id, first_name, last_name, email, phone, role, status
 FROM users
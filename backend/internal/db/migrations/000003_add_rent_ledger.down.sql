@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "rent_ledger_entries";
+DROP TYPE IF EXISTS "Ledger_Entry_Type";
@@ -0,0 +1,30 @@
+CREATE TYPE "Ledger_Entry_Type" AS ENUM (
+    'charge',
+    'payment',
+    'credit'
+    );
+
+-- Rent ledger: charges increase what the tenant owes, payments and credits reduce it
+CREATE TABLE IF NOT EXISTS "rent_ledger_entries"
+(
+    "id"             BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "lease_id"       BIGINT              NOT NULL REFERENCES leases (id) ON DELETE CASCADE,
+    "tenant_id"      BIGINT              NOT NULL REFERENCES users (id),
+    "entry_type"     "Ledger_Entry_Type" NOT NULL,
+    "amount"         NUMERIC(10, 2)      NOT NULL CHECK ("amount" >= 0),
+    "description"    TEXT                NOT NULL DEFAULT '',
+    "period_start"   DATE                NULL,
+    "due_date"       DATE                NULL,
+    "entry_date"     DATE                NOT NULL DEFAULT CURRENT_DATE,
+    "payment_method" VARCHAR             NULL,
+    "reference"      VARCHAR             NULL,
+    "created_by"     BIGINT              NULL REFERENCES users (id),
+    "created_at"     TIMESTAMP(0)                 DEFAULT now()
+);
+
+COMMENT ON COLUMN "rent_ledger_entries"."period_start" IS 'first day of the billed month for rent charges';
+CREATE INDEX "rent_ledger_lease_id_index" ON "rent_ledger_entries" ("lease_id");
+CREATE INDEX "rent_ledger_tenant_id_index" ON "rent_ledger_entries" ("tenant_id");
+-- One monthly rent charge per lease and period, so charge generation can be re-run safely
+CREATE UNIQUE INDEX "rent_ledger_monthly_charge_unique" ON "rent_ledger_entries" ("lease_id", "period_start")
+    WHERE "entry_type" = 'charge' AND "period_start" IS NOT NULL;
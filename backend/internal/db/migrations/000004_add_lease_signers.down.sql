@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "lease_signers";
+DROP TYPE IF EXISTS "Signing_Status";
+DROP TYPE IF EXISTS "Lease_Signer_Role";
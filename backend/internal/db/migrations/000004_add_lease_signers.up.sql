@@ -0,0 +1,56 @@
+CREATE TYPE "Lease_Signer_Role" AS ENUM (
+    'tenant',
+    'landlord'
+    );
+CREATE TYPE "Signing_Status" AS ENUM (
+    'pending',
+    'signed',
+    'rejected'
+    );
+
+-- Per-recipient signing state for a lease document, kept in sync from the Documenso webhooks
+CREATE TABLE IF NOT EXISTS "lease_signers"
+(
+    "id"               BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "lease_id"         BIGINT              NOT NULL REFERENCES leases (id) ON DELETE CASCADE,
+    "user_id"          BIGINT              NULL REFERENCES users (id),
+    "role"             "Lease_Signer_Role" NOT NULL,
+    "name"             TEXT                NOT NULL,
+    "email"            TEXT                NOT NULL,
+    "signing_url"      TEXT                NULL,
+    "status"           "Signing_Status"    NOT NULL DEFAULT 'pending',
+    "signed_at"        TIMESTAMP(0)        NULL,
+    "rejection_reason" TEXT                NULL,
+    "updated_at"       TIMESTAMP(0)                 DEFAULT now(),
+    "created_at"       TIMESTAMP(0)                 DEFAULT now()
+);
+
+COMMENT ON COLUMN "lease_signers"."email" IS 'recipient email on the e-sign document, used to match webhook recipients';
+CREATE UNIQUE INDEX "lease_signers_lease_email_unique" ON "lease_signers" ("lease_id", "email");
+
+-- Backfill tenant and landlord signers for existing leases
+INSERT INTO "lease_signers" ("lease_id", "user_id", "role", "name", "email", "signing_url", "status", "signed_at")
+SELECT l.id,
+       u.id,
+       'tenant',
+       u.first_name || ' ' || u.last_name,
+       u.email,
+       l.tenant_signing_url,
+       CASE WHEN l.status IN ('active', 'expired', 'renewed', 'terminated') THEN 'signed' ELSE 'pending' END::"Signing_Status",
+       CASE WHEN l.status IN ('active', 'expired', 'renewed', 'terminated') THEN l.updated_at END
+FROM leases l
+         JOIN users u ON u.id = l.tenant_id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "lease_signers" ("lease_id", "user_id", "role", "name", "email", "signing_url", "status", "signed_at")
+SELECT l.id,
+       u.id,
+       'landlord',
+       u.first_name || ' ' || u.last_name,
+       u.email,
+       l.landlord_signing_url,
+       CASE WHEN l.status IN ('active', 'expired', 'renewed', 'terminated') THEN 'signed' ELSE 'pending' END::"Signing_Status",
+       CASE WHEN l.status IN ('active', 'expired', 'renewed', 'terminated') THEN l.updated_at END
+FROM leases l
+         JOIN users u ON u.id = l.landlord_id
+ON CONFLICT DO NOTHING;
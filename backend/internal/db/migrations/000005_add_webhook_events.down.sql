@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "webhook_events";
+DROP TYPE IF EXISTS "Webhook_Event_Status";
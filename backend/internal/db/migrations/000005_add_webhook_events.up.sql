@@ -0,0 +1,28 @@
+CREATE TYPE "Webhook_Event_Status" AS ENUM (
+    'received',
+    'processing',
+    'processed',
+    'failed',
+    'rejected'
+    );
+
+-- Every inbound webhook delivery, kept so failed deliveries can be inspected and replayed
+CREATE TABLE IF NOT EXISTS "webhook_events"
+(
+    "id"           BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "source"       VARCHAR                NOT NULL,
+    "delivery_id"  VARCHAR                NOT NULL,
+    "event_type"   VARCHAR                NOT NULL DEFAULT '',
+    "headers"      JSONB                  NOT NULL DEFAULT '{}',
+    "body"         TEXT                   NOT NULL,
+    "status"       "Webhook_Event_Status" NOT NULL DEFAULT 'received',
+    "attempts"     INTEGER                NOT NULL DEFAULT 0,
+    "last_error"   TEXT                   NULL,
+    "processed_at" TIMESTAMP(0)           NULL,
+    "updated_at"   TIMESTAMP(0)                    DEFAULT now(),
+    "created_at"   TIMESTAMP(0)                    DEFAULT now()
+);
+
+COMMENT ON COLUMN "webhook_events"."delivery_id" IS 'svix-id for Clerk, SHA-256 of the body for Documenso; prefixed with rejected: for deliveries that failed verification';
+CREATE UNIQUE INDEX "webhook_events_source_delivery_unique" ON "webhook_events" ("source", "delivery_id");
+CREATE INDEX "webhook_events_status_index" ON "webhook_events" ("status");
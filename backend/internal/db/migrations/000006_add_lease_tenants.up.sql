@@ -0,0 +1,24 @@
+-- Every adult on a lease. leases.tenant_id stays as the primary tenant.
+-- Occupants go with their lease. A user who is an occupant cannot be deleted while the lease exists, the
+-- same as leases.tenant_id, so nobody silently drops off a lease they signed.
+CREATE TABLE IF NOT EXISTS "lease_tenants"
+(
+    "lease_id"  BIGINT NOT NULL,
+    "tenant_id" BIGINT NOT NULL,
+    PRIMARY KEY ("lease_id", "tenant_id"),
+    FOREIGN KEY ("lease_id") REFERENCES "leases" ("id") ON DELETE CASCADE,
+    FOREIGN KEY ("tenant_id") REFERENCES "users" ("id") ON DELETE RESTRICT
+);
+
+ALTER TABLE "lease_tenants"
+    ADD COLUMN IF NOT EXISTS "is_primary" BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS "created_at" TIMESTAMP(0) DEFAULT now();
+
+COMMENT ON COLUMN "lease_tenants"."is_primary" IS 'true for the tenant stored on leases.tenant_id';
+CREATE INDEX IF NOT EXISTS "lease_tenants_tenant_id_index" ON "lease_tenants" ("tenant_id");
+
+-- Backfill the primary tenant of existing leases
+INSERT INTO "lease_tenants" ("lease_id", "tenant_id", "is_primary")
+SELECT id, tenant_id, true
+FROM leases
+ON CONFLICT ("lease_id", "tenant_id") DO UPDATE SET "is_primary" = true;
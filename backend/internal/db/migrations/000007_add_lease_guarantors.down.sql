@@ -0,0 +1,3 @@
+-- Postgres cannot drop an enum value, so only remove the rows that use it
+DELETE FROM "lease_signers" WHERE "role" = 'guarantor';
+DROP TABLE IF EXISTS "lease_guarantors";
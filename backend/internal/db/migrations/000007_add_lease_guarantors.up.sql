@@ -0,0 +1,15 @@
+ALTER TYPE "Lease_Signer_Role" ADD VALUE IF NOT EXISTS 'guarantor';
+
+-- Guarantors sign a guaranty addendum on the lease. They are not tenants and have no user account.
+CREATE TABLE IF NOT EXISTS "lease_guarantors"
+(
+    "id"           BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "lease_id"     BIGINT       NOT NULL REFERENCES leases (id) ON DELETE CASCADE,
+    "name"         TEXT         NOT NULL,
+    "email"        TEXT         NOT NULL,
+    "relationship" TEXT         NOT NULL,
+    "created_at"   TIMESTAMP(0) DEFAULT now()
+);
+
+COMMENT ON COLUMN "lease_guarantors"."relationship" IS 'relationship to the tenants, e.g. parent';
+CREATE UNIQUE INDEX "lease_guarantors_lease_email_unique" ON "lease_guarantors" ("lease_id", "email");
@@ -0,0 +1,3 @@
+ALTER TABLE "buildings" DROP COLUMN IF EXISTS "rules";
+ALTER TABLE "leases" DROP COLUMN IF EXISTS "lease_template_id";
+DROP TABLE IF EXISTS "lease_templates";
@@ -0,0 +1,21 @@
+-- Admin-managed lease templates. Saving a template creates a new version; old versions are kept
+-- so every lease can point at the exact text it was generated from.
+CREATE TABLE IF NOT EXISTS "lease_templates"
+(
+    "id"         BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "name"       TEXT         NOT NULL,
+    "version"    INTEGER      NOT NULL,
+    "content"    JSONB        NOT NULL,
+    "created_by" BIGINT       NULL REFERENCES users (id),
+    "created_at" TIMESTAMP(0) DEFAULT now()
+);
+
+COMMENT ON COLUMN "lease_templates"."content" IS 'title, ordered clause blocks with placeholders and the guaranty addendum text';
+CREATE UNIQUE INDEX "lease_templates_name_version_unique" ON "lease_templates" ("name", "version");
+
+ALTER TABLE "leases"
+    ADD COLUMN IF NOT EXISTS "lease_template_id" BIGINT NULL REFERENCES lease_templates (id);
+
+ALTER TABLE "buildings"
+    ADD COLUMN IF NOT EXISTS "rules" TEXT NULL;
+COMMENT ON COLUMN "buildings"."rules" IS 'house rules printed on leases for units in this building';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "lease_attached_addenda";
+DROP TABLE IF EXISTS "lease_addenda";
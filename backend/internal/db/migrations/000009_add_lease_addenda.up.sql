@@ -0,0 +1,46 @@
+-- Library of addenda admins can attach to a lease, e.g. a pet or parking addendum
+CREATE TABLE IF NOT EXISTS "lease_addenda"
+(
+    "id"         BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "name"       TEXT         NOT NULL UNIQUE,
+    "title"      TEXT         NOT NULL,
+    "body"       TEXT         NOT NULL,
+    "archived"   BOOLEAN      NOT NULL DEFAULT false,
+    "created_by" BIGINT       NULL REFERENCES users (id),
+    "created_at" TIMESTAMP(0) DEFAULT now(),
+    "updated_at" TIMESTAMP(0) DEFAULT now()
+);
+
+COMMENT ON COLUMN "lease_addenda"."body" IS 'addendum text; may use the lease template placeholders';
+COMMENT ON COLUMN "lease_addenda"."archived" IS 'archived addenda stay on existing leases but cannot be attached to new ones';
+
+-- Addenda attached to a lease. Title and body are copied so later library edits do not change signed leases.
+CREATE TABLE IF NOT EXISTS "lease_attached_addenda"
+(
+    "lease_id"    BIGINT       NOT NULL REFERENCES leases (id) ON DELETE CASCADE,
+    "addendum_id" BIGINT       NOT NULL REFERENCES lease_addenda (id),
+    "position"    INTEGER      NOT NULL,
+    "title"       TEXT         NOT NULL,
+    "body"        TEXT         NOT NULL,
+    "created_at"  TIMESTAMP(0) DEFAULT now(),
+    PRIMARY KEY ("lease_id", "addendum_id")
+);
+
+COMMENT ON COLUMN "lease_attached_addenda"."position" IS 'order the addendum pages appear in the lease PDF';
+
+INSERT INTO "lease_addenda" ("name", "title", "body")
+VALUES ('pet', 'Pet Addendum',
+        'Tenant may keep the pets approved in writing by {{landlord_name}} at {{property_address}}. ' ||
+        'Tenant is responsible for any damage caused by the pets and shall keep them leashed in common areas. ' ||
+        'Landlord may revoke permission for a pet that disturbs other residents.'),
+       ('parking', 'Parking Addendum',
+        'Tenant is assigned parking at {{property_address}} for the lease term from {{start_date}} to {{end_date}}. ' ||
+        'Vehicles must be registered with the Landlord and parked only in assigned spaces. ' ||
+        'Unregistered or inoperable vehicles may be towed at the owner''s expense.'),
+       ('lead_paint', 'Lead-Based Paint Disclosure',
+        'Housing built before 1978 may contain lead-based paint. Lead from paint, paint chips and dust can pose health hazards if not managed properly. ' ||
+        'Landlord has disclosed any known lead-based paint and hazards at {{property_address}} and Tenant has received the pamphlet "Protect Your Family From Lead in Your Home".'),
+       ('smoking', 'Smoking Policy',
+        'Smoking of any kind, including e-cigarettes, is prohibited inside unit {{unit_number}} and in all common areas of {{property_address}}. ' ||
+        'Tenant is responsible for the cost of removing smoke damage.')
+ON CONFLICT ("name") DO NOTHING;
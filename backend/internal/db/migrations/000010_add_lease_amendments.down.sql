@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "lease_amendments";
+ALTER TABLE "leases" DROP COLUMN IF EXISTS "document_type";
@@ -0,0 +1,21 @@
+ALTER TABLE "leases"
+    ADD COLUMN IF NOT EXISTS "document_type" "Type" NOT NULL DEFAULT 'lease_agreement';
+
+-- One row per amendment. The amended terms live on a new lease row that points back through
+-- previous_lease_id and goes through its own signing round; this row keeps the version and the diff.
+CREATE TABLE IF NOT EXISTS "lease_amendments"
+(
+    "id"               BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "lease_id"         BIGINT       NOT NULL REFERENCES leases (id) ON DELETE CASCADE,
+    "amended_lease_id" BIGINT       NOT NULL UNIQUE REFERENCES leases (id) ON DELETE CASCADE,
+    "version"          INTEGER      NOT NULL,
+    "changes"          JSONB        NOT NULL,
+    "external_doc_id"  TEXT         NOT NULL,
+    "created_by"       BIGINT       NOT NULL REFERENCES users (id),
+    "created_at"       TIMESTAMP(0) DEFAULT now()
+);
+
+COMMENT ON COLUMN "lease_amendments"."lease_id" IS 'lease that was amended';
+COMMENT ON COLUMN "lease_amendments"."amended_lease_id" IS 'new lease version carrying the amended terms';
+COMMENT ON COLUMN "lease_amendments"."changes" IS 'changed terms as a list of {field, from, to}';
+CREATE INDEX "lease_amendments_lease_id_index" ON "lease_amendments" ("lease_id");
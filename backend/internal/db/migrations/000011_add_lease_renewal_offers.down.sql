@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "lease_renewal_offers";
+DROP TYPE IF EXISTS "Renewal_Offer_Status";
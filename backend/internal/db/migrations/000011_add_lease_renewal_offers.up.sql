@@ -0,0 +1,33 @@
+CREATE TYPE "Renewal_Offer_Status" AS ENUM (
+    'pending',
+    'accepted',
+    'declined',
+    'expired',
+    'withdrawn'
+    );
+
+-- Renewal offers sent to tenants as their lease nears its end date
+CREATE TABLE IF NOT EXISTS "lease_renewal_offers"
+(
+    "id"               BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "lease_id"         BIGINT                 NOT NULL REFERENCES leases (id) ON DELETE CASCADE,
+    "token"            TEXT                   NOT NULL UNIQUE,
+    "status"           "Renewal_Offer_Status" NOT NULL DEFAULT 'pending',
+    "options"          JSONB                  NOT NULL,
+    "accepted_option"  INTEGER                NULL,
+    "renewed_lease_id" BIGINT                 NULL REFERENCES leases (id),
+    "notice_stage"     INTEGER                NOT NULL DEFAULT 0,
+    "last_notified_at" TIMESTAMP(0)           NULL,
+    "responded_at"     TIMESTAMP(0)           NULL,
+    "expires_at"       TIMESTAMP(0)           NOT NULL,
+    "created_by"       BIGINT                 NULL REFERENCES users (id),
+    "created_at"       TIMESTAMP(0)           DEFAULT now(),
+    "updated_at"       TIMESTAMP(0)           DEFAULT now()
+);
+
+COMMENT ON COLUMN "lease_renewal_offers"."token" IS 'secret used in the link emailed to the tenant';
+COMMENT ON COLUMN "lease_renewal_offers"."options" IS 'proposed terms as a list of {term_months, rent_amount}';
+COMMENT ON COLUMN "lease_renewal_offers"."accepted_option" IS 'index into options chosen by the tenant';
+COMMENT ON COLUMN "lease_renewal_offers"."expires_at" IS 'the offer can no longer be accepted from this time, the day after the lease ends';
+COMMENT ON COLUMN "lease_renewal_offers"."notice_stage" IS 'last reminder stage emailed; reminders escalate as the lease end date approaches';
+CREATE UNIQUE INDEX "lease_renewal_offers_open_unique" ON "lease_renewal_offers" ("lease_id") WHERE status = 'pending';
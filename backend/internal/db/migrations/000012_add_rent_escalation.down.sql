@@ -0,0 +1,2 @@
+ALTER TABLE "leases"
+    DROP COLUMN IF EXISTS "rent_escalation";
@@ -0,0 +1,4 @@
+ALTER TABLE "leases"
+    ADD COLUMN IF NOT EXISTS "rent_escalation" JSONB NULL;
+
+COMMENT ON COLUMN "leases"."rent_escalation" IS 'rent step-ups as {percent, interval_months} or {steps: [{month, amount}]}';
@@ -0,0 +1,3 @@
+ALTER TABLE "buildings"
+    DROP COLUMN IF EXISTS "proration_method";
+DROP TYPE IF EXISTS "Proration_Method";
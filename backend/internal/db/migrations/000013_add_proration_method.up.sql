@@ -0,0 +1,8 @@
+CREATE TYPE "Proration_Method" AS ENUM (
+    'actual_days',
+    'thirty_day'
+    );
+
+ALTER TABLE "buildings"
+    ADD COLUMN IF NOT EXISTS "proration_method" "Proration_Method" NOT NULL DEFAULT 'actual_days';
+COMMENT ON COLUMN "buildings"."proration_method" IS 'daily rate for partial months: actual days in the month or a 30-day month';
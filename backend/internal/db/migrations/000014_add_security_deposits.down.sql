@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "deposit_dispositions";
+DROP TYPE IF EXISTS "Deposit_Disposition_Status";
+DROP TABLE IF EXISTS "lease_deposits";
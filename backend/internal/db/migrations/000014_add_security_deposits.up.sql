@@ -0,0 +1,45 @@
+-- Security deposit held against a lease
+CREATE TABLE IF NOT EXISTS "lease_deposits"
+(
+    "id"              BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "lease_id"        BIGINT         NOT NULL UNIQUE REFERENCES leases (id) ON DELETE CASCADE,
+    "amount"          NUMERIC(10, 2) NOT NULL CHECK ("amount" >= 0),
+    "received_on"     DATE           NOT NULL,
+    "holding_account" TEXT           NOT NULL DEFAULT '',
+    "interest_rate"   NUMERIC(6, 3)  NULL,
+    "created_by"      BIGINT         NULL REFERENCES users (id),
+    "created_at"      TIMESTAMP(0) DEFAULT now(),
+    "updated_at"      TIMESTAMP(0) DEFAULT now()
+);
+
+COMMENT ON COLUMN "lease_deposits"."holding_account" IS 'bank account or trust account the deposit is held in';
+COMMENT ON COLUMN "lease_deposits"."interest_rate" IS 'annual simple interest owed to the tenant in percent, where required by law';
+
+CREATE TYPE "Deposit_Disposition_Status" AS ENUM (
+    'pending_disposition',
+    'issued'
+    );
+
+-- Itemized statement of what happened to the deposit after the lease ended
+CREATE TABLE IF NOT EXISTS "deposit_dispositions"
+(
+    "id"               BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "lease_id"         BIGINT         NOT NULL UNIQUE REFERENCES leases (id) ON DELETE CASCADE,
+    "deposit_amount"   NUMERIC(10, 2) NOT NULL,
+    "interest_amount"  NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    "deductions"       JSONB          NOT NULL,
+    "total_deductions" NUMERIC(10, 2) NOT NULL,
+    "refund_amount"    NUMERIC(10, 2) NOT NULL,
+    "move_out_date"    DATE           NOT NULL,
+    "due_by"           DATE           NOT NULL,
+    "status"           "Deposit_Disposition_Status" NOT NULL DEFAULT 'issued',
+    "sent_to"          TEXT           NULL,
+    "sent_at"          TIMESTAMP(0)   NULL,
+    "created_by"       BIGINT         NULL REFERENCES users (id),
+    "created_at"       TIMESTAMP(0) DEFAULT now()
+);
+
+COMMENT ON COLUMN "deposit_dispositions"."deductions" IS 'itemized deductions as a list of {category, description, amount}';
+COMMENT ON COLUMN "deposit_dispositions"."refund_amount" IS 'deposit plus interest less deductions; negative when the tenant still owes';
+COMMENT ON COLUMN "deposit_dispositions"."due_by" IS 'statutory deadline for sending the statement to the tenant';
+COMMENT ON COLUMN "deposit_dispositions"."status" IS 'pending_disposition until an admin records the deductions or the deadline arrives; amounts are final once issued';
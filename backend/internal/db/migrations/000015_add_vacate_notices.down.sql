@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "lease_vacate_notices";
+DROP TYPE IF EXISTS "Notice_Status";
+DROP TYPE IF EXISTS "Notice_Initiator";
+ALTER TABLE "leases"
+    DROP COLUMN IF EXISTS "early_termination_fee",
+    DROP COLUMN IF EXISTS "notice_period_days";
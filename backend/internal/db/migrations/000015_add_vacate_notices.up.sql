@@ -0,0 +1,46 @@
+-- Terms that govern ending a lease before its end date
+ALTER TABLE "leases"
+    ADD COLUMN IF NOT EXISTS "notice_period_days" INTEGER NOT NULL DEFAULT 30 CHECK ("notice_period_days" >= 0),
+    ADD COLUMN IF NOT EXISTS "early_termination_fee" NUMERIC(10, 2) NULL CHECK ("early_termination_fee" >= 0);
+
+COMMENT ON COLUMN "leases"."notice_period_days" IS 'days of written notice required before moving out';
+COMMENT ON COLUMN "leases"."early_termination_fee" IS 'fee owed when the tenant moves out before the lease end date';
+
+CREATE TYPE "Notice_Initiator" AS ENUM (
+    'tenant',
+    'landlord'
+    );
+
+CREATE TYPE "Notice_Status" AS ENUM (
+    'pending_signature',
+    'scheduled',
+    'completed',
+    'cancelled'
+    );
+
+-- Notices to vacate; the lease is terminated on the move-out date once every party has signed
+CREATE TABLE IF NOT EXISTS "lease_vacate_notices"
+(
+    "id"                 BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "lease_id"           BIGINT             NOT NULL REFERENCES leases (id) ON DELETE CASCADE,
+    "initiated_by"       "Notice_Initiator" NOT NULL,
+    "requested_by"       BIGINT             NULL REFERENCES users (id),
+    "notice_date"        DATE               NOT NULL,
+    "move_out_date"      DATE               NOT NULL,
+    "early_termination"  BOOLEAN            NOT NULL DEFAULT false,
+    "termination_fee"    NUMERIC(10, 2)     NOT NULL DEFAULT 0,
+    "reason"             TEXT               NOT NULL DEFAULT '',
+    "status"             "Notice_Status"    NOT NULL DEFAULT 'pending_signature',
+    "external_doc_id"    TEXT               NULL,
+    "signing_urls"       JSONB              NOT NULL DEFAULT '{}',
+    "signed_at"          TIMESTAMP(0)       NULL,
+    "completed_at"       TIMESTAMP(0)       NULL,
+    "created_at"         TIMESTAMP(0) DEFAULT now(),
+    "updated_at"         TIMESTAMP(0) DEFAULT now()
+);
+
+COMMENT ON COLUMN "lease_vacate_notices"."move_out_date" IS 'day the lease is terminated; never earlier than the notice period allows';
+COMMENT ON COLUMN "lease_vacate_notices"."termination_fee" IS 'early termination fee charged to the ledger when the lease ends';
+COMMENT ON COLUMN "lease_vacate_notices"."signing_urls" IS 'e-sign link of each signer keyed by lowercase email';
+CREATE UNIQUE INDEX "lease_vacate_notices_open_unique" ON "lease_vacate_notices" ("lease_id") WHERE status IN ('pending_signature', 'scheduled');
+CREATE INDEX "lease_vacate_notices_external_doc_id_idx" ON "lease_vacate_notices" ("external_doc_id");
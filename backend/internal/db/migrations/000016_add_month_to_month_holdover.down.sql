@@ -0,0 +1,10 @@
+-- Postgres cannot drop an enum value, so month_to_month leases are expired and the value is left in place
+UPDATE "leases" SET "status" = 'expired' WHERE "status" = 'month_to_month';
+ALTER TABLE "leases"
+    DROP COLUMN IF EXISTS "holdover_rent",
+    DROP COLUMN IF EXISTS "holdover_premium_percent",
+    DROP COLUMN IF EXISTS "holdover_policy";
+ALTER TABLE "buildings"
+    DROP COLUMN IF EXISTS "holdover_premium_percent",
+    DROP COLUMN IF EXISTS "holdover_policy";
+DROP TYPE IF EXISTS "Holdover_Policy";
@@ -0,0 +1,23 @@
+ALTER TYPE "Lease_Status" ADD VALUE IF NOT EXISTS 'month_to_month' AFTER 'active';
+
+CREATE TYPE "Holdover_Policy" AS ENUM (
+    'expire',
+    'month_to_month'
+    );
+
+-- What happens when a tenant stays past the lease end date; leases fall back to their building's policy
+ALTER TABLE "buildings"
+    ADD COLUMN IF NOT EXISTS "holdover_policy" "Holdover_Policy" NOT NULL DEFAULT 'expire',
+    ADD COLUMN IF NOT EXISTS "holdover_premium_percent" NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK ("holdover_premium_percent" >= 0);
+
+COMMENT ON COLUMN "buildings"."holdover_policy" IS 'expire leases at their end date or convert them to month-to-month';
+COMMENT ON COLUMN "buildings"."holdover_premium_percent" IS 'percent added to the last rent while a tenant holds over month-to-month';
+
+ALTER TABLE "leases"
+    ADD COLUMN IF NOT EXISTS "holdover_policy" "Holdover_Policy" NULL,
+    ADD COLUMN IF NOT EXISTS "holdover_premium_percent" NUMERIC(5, 2) NULL CHECK ("holdover_premium_percent" >= 0),
+    ADD COLUMN IF NOT EXISTS "holdover_rent" NUMERIC(10, 2) NULL;
+
+COMMENT ON COLUMN "leases"."holdover_policy" IS 'overrides the building holdover policy when set';
+COMMENT ON COLUMN "leases"."holdover_premium_percent" IS 'overrides the building holdover premium when set';
+COMMENT ON COLUMN "leases"."holdover_rent" IS 'monthly rent charged since the lease went month-to-month';
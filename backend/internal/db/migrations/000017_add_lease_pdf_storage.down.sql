@@ -0,0 +1,3 @@
+ALTER TABLE "leases"
+    DROP COLUMN IF EXISTS "signed_pdf_key",
+    DROP COLUMN IF EXISTS "generated_pdf_key";
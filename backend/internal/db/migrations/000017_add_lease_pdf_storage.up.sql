@@ -0,0 +1,7 @@
+-- Our own copies of lease PDFs in blob storage, independent of the e-sign provider
+ALTER TABLE "leases"
+    ADD COLUMN IF NOT EXISTS "generated_pdf_key" TEXT NULL,
+    ADD COLUMN IF NOT EXISTS "signed_pdf_key" TEXT NULL;
+
+COMMENT ON COLUMN "leases"."generated_pdf_key" IS 'storage key of the lease PDF as generated, before signing';
+COMMENT ON COLUMN "leases"."signed_pdf_key" IS 'storage key of the fully signed lease PDF';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "document_downloads";
+DROP TABLE IF EXISTS "documents";
+DROP TYPE IF EXISTS "Document_Category";
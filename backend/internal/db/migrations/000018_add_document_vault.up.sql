@@ -0,0 +1,63 @@
+CREATE TYPE "Document_Category" AS ENUM (
+    'lease',
+    'amendment',
+    'notice',
+    'receipt',
+    'inspection_report',
+    'statement',
+    'other'
+    );
+
+-- Every document a tenant can see: signed leases and notices are added automatically, anything else is
+-- uploaded by an admin against a tenant, a lease or a unit
+CREATE TABLE IF NOT EXISTS "documents"
+(
+    "id"           BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "category"     "Document_Category" NOT NULL,
+    "title"        TEXT                NOT NULL,
+    "file_name"    TEXT                NOT NULL,
+    "content_type" TEXT                NOT NULL,
+    "size_bytes"   BIGINT              NOT NULL DEFAULT 0,
+    "storage_key"  TEXT                NOT NULL UNIQUE,
+    "tenant_id"    BIGINT              NULL REFERENCES users (id) ON DELETE CASCADE,
+    "lease_id"     BIGINT              NULL REFERENCES leases (id) ON DELETE CASCADE,
+    "apartment_id" BIGINT              NULL REFERENCES apartments (id) ON DELETE SET NULL,
+    "uploaded_by"  BIGINT              NULL REFERENCES users (id) ON DELETE SET NULL,
+    "created_at"   TIMESTAMP(0)        NOT NULL DEFAULT now(),
+    CHECK ("tenant_id" IS NOT NULL OR "lease_id" IS NOT NULL OR "apartment_id" IS NOT NULL)
+);
+
+COMMENT ON COLUMN "documents"."tenant_id" IS 'only this tenant sees the document';
+COMMENT ON COLUMN "documents"."lease_id" IS 'every tenant on this lease sees the document';
+COMMENT ON COLUMN "documents"."apartment_id" IS 'current tenants of the unit see the document when it is not tied to a tenant or lease';
+COMMENT ON COLUMN "documents"."uploaded_by" IS 'admin who uploaded the document; NULL when the system filed it';
+
+CREATE INDEX IF NOT EXISTS "documents_tenant_id_idx" ON "documents" ("tenant_id");
+CREATE INDEX IF NOT EXISTS "documents_lease_id_idx" ON "documents" ("lease_id");
+CREATE INDEX IF NOT EXISTS "documents_apartment_id_idx" ON "documents" ("apartment_id");
+
+-- Who asked for a download link to a document and when
+CREATE TABLE IF NOT EXISTS "document_downloads"
+(
+    "id"            BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "document_id"   BIGINT       NOT NULL REFERENCES documents (id) ON DELETE CASCADE,
+    "user_id"       BIGINT       NULL REFERENCES users (id) ON DELETE SET NULL,
+    "ip_address"    TEXT         NOT NULL DEFAULT '',
+    "user_agent"    TEXT         NOT NULL DEFAULT '',
+    "downloaded_at" TIMESTAMP(0) NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS "document_downloads_document_id_idx" ON "document_downloads" ("document_id");
+
+-- Leases signed before the vault existed
+INSERT INTO "documents" ("category", "title", "file_name", "content_type", "storage_key", "lease_id", "apartment_id")
+SELECT CASE WHEN "document_type" = 'amendment' THEN 'amendment'::"Document_Category" ELSE 'lease'::"Document_Category" END,
+       'Lease #' || "lease_number",
+       'signed.pdf',
+       'application/pdf',
+       "signed_pdf_key",
+       "id",
+       "apartment_id"
+FROM "leases"
+WHERE "signed_pdf_key" IS NOT NULL
+ON CONFLICT ("storage_key") DO NOTHING;
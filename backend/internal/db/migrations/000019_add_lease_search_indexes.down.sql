@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "apartment_building_id_index";
+DROP INDEX IF EXISTS "lease_rent_amount_index";
+DROP INDEX IF EXISTS "lease_end_date_index";
+DROP INDEX IF EXISTS "lease_start_date_index";
+DROP INDEX IF EXISTS "lease_status_end_date_index";
+DROP INDEX IF EXISTS "lease_tenant_id_index";
@@ -0,0 +1,7 @@
+-- Filters and keyset pagination of the admin lease list
+CREATE INDEX IF NOT EXISTS "lease_tenant_id_index" ON "leases" ("tenant_id");
+CREATE INDEX IF NOT EXISTS "lease_status_end_date_index" ON "leases" ("status", "lease_end_date", "id");
+CREATE INDEX IF NOT EXISTS "lease_start_date_index" ON "leases" ("lease_start_date", "id");
+CREATE INDEX IF NOT EXISTS "lease_end_date_index" ON "leases" ("lease_end_date", "id");
+CREATE INDEX IF NOT EXISTS "lease_rent_amount_index" ON "leases" ("rent_amount", "id");
+CREATE INDEX IF NOT EXISTS "apartment_building_id_index" ON "apartments" ("building_id");
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "lease_status_history";
@@ -0,0 +1,23 @@
+-- Every status change of a lease, with who made it, why and what caused it
+CREATE TABLE IF NOT EXISTS "lease_status_history"
+(
+    "id"          BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "lease_id"    BIGINT         NOT NULL REFERENCES leases (id) ON DELETE CASCADE,
+    "from_status" "Lease_Status" NULL,
+    "to_status"   "Lease_Status" NOT NULL,
+    "event"       VARCHAR(32)    NOT NULL,
+    "reason"      TEXT           NULL,
+    "changed_by"  BIGINT         NULL REFERENCES users (id) ON DELETE SET NULL,
+    "changed_at"  TIMESTAMP(0)   NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN "lease_status_history"."from_status" IS 'NULL when the lease was created';
+COMMENT ON COLUMN "lease_status_history"."event" IS 'what caused the change, like sent, signed, expired or holdover';
+COMMENT ON COLUMN "lease_status_history"."changed_by" IS 'user who made the change; NULL for scheduled jobs';
+
+CREATE INDEX IF NOT EXISTS "lease_status_history_lease_id_idx" ON "lease_status_history" ("lease_id", "changed_at");
+
+-- Start the trail of existing leases at the status they are in now
+INSERT INTO "lease_status_history" ("lease_id", "from_status", "to_status", "event", "reason", "changed_by", "changed_at")
+SELECT l.id, NULL, l.status, 'created', 'Recorded when status history was introduced', NULL, COALESCE(l.created_at, now())
+FROM leases l;
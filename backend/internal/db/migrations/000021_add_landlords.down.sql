@@ -0,0 +1,11 @@
+ALTER TABLE "buildings" DROP CONSTRAINT IF EXISTS "building_management_id_foreign";
+
+UPDATE "buildings" b
+SET "management_id" = l.signer_user_id
+FROM "landlords" l
+WHERE l.id = b.management_id
+  AND l.signer_user_id IS NOT NULL;
+
+COMMENT ON COLUMN "buildings"."management_id" IS NULL;
+
+DROP TABLE IF EXISTS "landlords";
@@ -0,0 +1,37 @@
+-- The owner or management company leases are made with. Each building is managed by one landlord, and its
+-- signing contact signs leases on the landlord's behalf.
+CREATE TABLE IF NOT EXISTS "landlords"
+(
+    "id"             BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "legal_name"     TEXT         NOT NULL,
+    "address"        TEXT         NOT NULL DEFAULT '',
+    "license_number" VARCHAR(64)  NULL,
+    "signer_user_id" BIGINT       NULL REFERENCES users (id) ON DELETE SET NULL,
+    "signer_name"    TEXT         NOT NULL,
+    "signer_email"   TEXT         NOT NULL,
+    "created_at"     TIMESTAMP(0) NOT NULL DEFAULT now(),
+    "updated_at"     TIMESTAMP(0) NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN "landlords"."legal_name" IS 'name the lease is made in, like a person or a management company';
+COMMENT ON COLUMN "landlords"."address" IS 'address for notices, printed on leases';
+COMMENT ON COLUMN "landlords"."license_number" IS 'property management or broker license, where one is required';
+COMMENT ON COLUMN "landlords"."signer_user_id" IS 'admin user who signs for the landlord; NULL when the signer has no account';
+
+CREATE INDEX IF NOT EXISTS "landlords_signer_user_id_idx" ON "landlords" ("signer_user_id");
+
+-- Buildings were managed by an admin user directly; each of those users becomes a landlord signing for itself
+INSERT INTO "landlords" ("legal_name", "signer_user_id", "signer_name", "signer_email")
+SELECT u.first_name || ' ' || u.last_name, u.id, u.first_name || ' ' || u.last_name, u.email
+FROM users u
+WHERE u.id IN (SELECT management_id FROM buildings);
+
+UPDATE "buildings" b
+SET "management_id" = l.id
+FROM "landlords" l
+WHERE l.signer_user_id = b.management_id;
+
+ALTER TABLE "buildings"
+    ADD CONSTRAINT "building_management_id_foreign" FOREIGN KEY ("management_id") REFERENCES "landlords" ("id");
+
+COMMENT ON COLUMN "buildings"."management_id" IS 'landlord managing the building';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "rental_application_documents";
+DROP TABLE IF EXISTS "rental_application_references";
+DROP TABLE IF EXISTS "rental_application_members";
+DROP TABLE IF EXISTS "rental_applications";
+DROP TYPE IF EXISTS "Application_Status";
@@ -0,0 +1,80 @@
+CREATE TYPE "Application_Status" AS ENUM (
+    'submitted',
+    'conditional',
+    'approved',
+    'denied'
+    );
+
+-- Applications prospects send for an available unit. Approving one invites the applicant and drafts their lease.
+CREATE TABLE IF NOT EXISTS "rental_applications"
+(
+    "id"              BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "apartment_id"    BIGINT               NOT NULL REFERENCES apartments (id) ON DELETE CASCADE,
+    "status"          "Application_Status" NOT NULL DEFAULT 'submitted',
+    "first_name"      TEXT                 NOT NULL,
+    "last_name"       TEXT                 NOT NULL,
+    "email"           TEXT                 NOT NULL,
+    "phone"           TEXT                 NOT NULL DEFAULT '',
+    "desired_move_in" DATE                 NULL,
+    "monthly_income"  NUMERIC(10, 2)       NOT NULL DEFAULT 0 CHECK ("monthly_income" >= 0),
+    "employer"        TEXT                 NOT NULL DEFAULT '',
+    "message"         TEXT                 NOT NULL DEFAULT '',
+    "decision_reason" TEXT                 NOT NULL DEFAULT '',
+    "conditions"      TEXT                 NOT NULL DEFAULT '',
+    "decided_by"      BIGINT               NULL REFERENCES users (id) ON DELETE SET NULL,
+    "decided_at"      TIMESTAMP(0)         NULL,
+    "tenant_id"       BIGINT               NULL REFERENCES users (id) ON DELETE SET NULL,
+    "lease_id"        BIGINT               NULL REFERENCES leases (id) ON DELETE SET NULL,
+    "created_at"      TIMESTAMP(0)         NOT NULL DEFAULT now(),
+    "updated_at"      TIMESTAMP(0)         NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN "rental_applications"."monthly_income" IS 'gross monthly income of the applicant';
+COMMENT ON COLUMN "rental_applications"."message" IS 'anything the applicant wants the landlord to know';
+COMMENT ON COLUMN "rental_applications"."conditions" IS 'what the applicant still has to provide when conditionally approved';
+COMMENT ON COLUMN "rental_applications"."tenant_id" IS 'account the applicant was given on approval';
+COMMENT ON COLUMN "rental_applications"."lease_id" IS 'draft lease created on approval';
+
+CREATE INDEX IF NOT EXISTS "rental_applications_status_idx" ON "rental_applications" ("status");
+CREATE INDEX IF NOT EXISTS "rental_applications_apartment_id_idx" ON "rental_applications" ("apartment_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "rental_applications_open_unique" ON "rental_applications" ("apartment_id", lower("email")) WHERE status IN ('submitted', 'conditional');
+
+-- Everyone else who would live in the unit
+CREATE TABLE IF NOT EXISTS "rental_application_members"
+(
+    "id"             BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "application_id" BIGINT         NOT NULL REFERENCES rental_applications (id) ON DELETE CASCADE,
+    "name"           TEXT           NOT NULL,
+    "relationship"   TEXT           NOT NULL DEFAULT '',
+    "is_adult"       BOOLEAN        NOT NULL DEFAULT true,
+    "monthly_income" NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK ("monthly_income" >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS "rental_application_members_application_id_idx" ON "rental_application_members" ("application_id");
+
+-- Landlords and employers the applicant gives as references
+CREATE TABLE IF NOT EXISTS "rental_application_references"
+(
+    "id"             BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "application_id" BIGINT NOT NULL REFERENCES rental_applications (id) ON DELETE CASCADE,
+    "name"           TEXT   NOT NULL,
+    "relationship"   TEXT   NOT NULL DEFAULT '',
+    "phone"          TEXT   NOT NULL DEFAULT '',
+    "email"          TEXT   NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS "rental_application_references_application_id_idx" ON "rental_application_references" ("application_id");
+
+-- Files uploaded with the application, like pay stubs or ID. Only admins can open them.
+CREATE TABLE IF NOT EXISTS "rental_application_documents"
+(
+    "id"             BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "application_id" BIGINT       NOT NULL REFERENCES rental_applications (id) ON DELETE CASCADE,
+    "file_name"      TEXT         NOT NULL,
+    "content_type"   TEXT         NOT NULL,
+    "size_bytes"     BIGINT       NOT NULL DEFAULT 0,
+    "storage_key"    TEXT         NOT NULL UNIQUE,
+    "created_at"     TIMESTAMP(0) NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS "rental_application_documents_application_id_idx" ON "rental_application_documents" ("application_id");
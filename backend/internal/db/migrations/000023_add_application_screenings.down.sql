@@ -0,0 +1,4 @@
+ALTER TABLE "rental_applications"
+    DROP COLUMN IF EXISTS "screening_id";
+DROP TABLE IF EXISTS "rental_application_screenings";
+DROP TYPE IF EXISTS "Screening_Recommendation";
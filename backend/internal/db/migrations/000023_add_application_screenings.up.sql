@@ -0,0 +1,29 @@
+CREATE TYPE "Screening_Recommendation" AS ENUM (
+    'pass',
+    'review',
+    'fail'
+    );
+
+-- Credit, criminal and eviction checks run on an applicant through the screening provider
+CREATE TABLE IF NOT EXISTS "rental_application_screenings"
+(
+    "id"             BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "application_id" BIGINT                     NOT NULL REFERENCES rental_applications (id) ON DELETE CASCADE,
+    "provider"       TEXT                       NOT NULL,
+    "reference"      TEXT                       NOT NULL DEFAULT '',
+    "recommendation" "Screening_Recommendation" NOT NULL,
+    "report"         JSONB                      NOT NULL,
+    "requested_by"   BIGINT                     NULL REFERENCES users (id) ON DELETE SET NULL,
+    "created_at"     TIMESTAMP(0)               NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN "rental_application_screenings"."reference" IS 'the provider''s ID for the report';
+COMMENT ON COLUMN "rental_application_screenings"."recommendation" IS 'least favourable result of the checks';
+COMMENT ON COLUMN "rental_application_screenings"."report" IS 'results of each check as returned by the provider';
+
+CREATE INDEX IF NOT EXISTS "rental_application_screenings_application_id_idx" ON "rental_application_screenings" ("application_id");
+
+ALTER TABLE "rental_applications"
+    ADD COLUMN IF NOT EXISTS "screening_id" BIGINT NULL REFERENCES rental_application_screenings (id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN "rental_applications"."screening_id" IS 'screening the decision was made on';
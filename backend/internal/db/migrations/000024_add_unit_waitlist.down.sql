@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "waitlist_offers";
+DROP TABLE IF EXISTS "waitlist_entries";
+DROP TYPE IF EXISTS "Waitlist_Offer_Status";
+DROP TYPE IF EXISTS "Waitlist_Entry_Status";
+
+ALTER TABLE "apartments"
+    DROP COLUMN IF EXISTS "unit_type",
+    DROP COLUMN IF EXISTS "bedrooms";
@@ -0,0 +1,65 @@
+ALTER TABLE "apartments"
+    ADD COLUMN IF NOT EXISTS "bedrooms"  SMALLINT NULL,
+    ADD COLUMN IF NOT EXISTS "unit_type" TEXT     NULL;
+
+COMMENT ON COLUMN "apartments"."bedrooms" IS 'number of bedrooms, 0 for a studio';
+COMMENT ON COLUMN "apartments"."unit_type" IS 'kind of unit prospects can wait for, like studio, loft or townhouse';
+
+CREATE TYPE "Waitlist_Entry_Status" AS ENUM (
+    'waiting',
+    'offered',
+    'accepted',
+    'withdrawn'
+    );
+
+CREATE TYPE "Waitlist_Offer_Status" AS ENUM (
+    'pending',
+    'accepted',
+    'declined',
+    'expired',
+    'withdrawn'
+    );
+
+-- Prospects waiting for a unit of a type, bedroom count or building to free up. Unset criteria match any unit.
+CREATE TABLE IF NOT EXISTS "waitlist_entries"
+(
+    "id"          BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "first_name"  TEXT                    NOT NULL,
+    "last_name"   TEXT                    NOT NULL,
+    "email"       TEXT                    NOT NULL,
+    "phone"       TEXT                    NOT NULL DEFAULT '',
+    "building_id" BIGINT                  NULL REFERENCES buildings (id) ON DELETE CASCADE,
+    "bedrooms"    SMALLINT                NULL,
+    "unit_type"   TEXT                    NULL,
+    "status"      "Waitlist_Entry_Status" NOT NULL DEFAULT 'waiting',
+    "token"       TEXT                    NOT NULL UNIQUE,
+    "created_at"  TIMESTAMP(0)            NOT NULL DEFAULT now(),
+    "updated_at"  TIMESTAMP(0)            NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN "waitlist_entries"."token" IS 'secret in the links emailed to the prospect, lets them leave the waitlist';
+CREATE INDEX IF NOT EXISTS "waitlist_entries_status_created_at_idx" ON "waitlist_entries" ("status", "created_at");
+CREATE UNIQUE INDEX "waitlist_entries_open_unique" ON "waitlist_entries" (lower(email), coalesce(building_id, 0), coalesce(bedrooms, -1), lower(coalesce(unit_type, '')))
+    WHERE status IN ('waiting', 'offered');
+
+-- Time-limited offers of a freed-up apartment to the next prospect on the waitlist
+CREATE TABLE IF NOT EXISTS "waitlist_offers"
+(
+    "id"             BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "entry_id"       BIGINT                  NOT NULL REFERENCES waitlist_entries (id) ON DELETE CASCADE,
+    "apartment_id"   BIGINT                  NOT NULL REFERENCES apartments (id) ON DELETE CASCADE,
+    "token"          TEXT                    NOT NULL UNIQUE,
+    "status"         "Waitlist_Offer_Status" NOT NULL DEFAULT 'pending',
+    "expires_at"     TIMESTAMP(0)            NOT NULL,
+    "application_id" BIGINT                  NULL REFERENCES rental_applications (id) ON DELETE SET NULL,
+    "responded_at"   TIMESTAMP(0)            NULL,
+    "created_at"     TIMESTAMP(0)            NOT NULL DEFAULT now(),
+    "updated_at"     TIMESTAMP(0)            NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN "waitlist_offers"."token" IS 'secret used in the link emailed to the prospect';
+COMMENT ON COLUMN "waitlist_offers"."expires_at" IS 'the offer passes to the next prospect if not answered by then';
+COMMENT ON COLUMN "waitlist_offers"."application_id" IS 'rental application submitted when the offer was accepted';
+CREATE INDEX IF NOT EXISTS "waitlist_offers_entry_id_idx" ON "waitlist_offers" ("entry_id");
+CREATE UNIQUE INDEX "waitlist_offers_pending_apartment_unique" ON "waitlist_offers" ("apartment_id") WHERE status = 'pending';
+CREATE UNIQUE INDEX "waitlist_offers_pending_entry_unique" ON "waitlist_offers" ("entry_id") WHERE status = 'pending';
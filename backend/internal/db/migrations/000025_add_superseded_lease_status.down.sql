@@ -0,0 +1,3 @@
+-- Postgres cannot drop an enum value, so superseded leases go back to renewed and the value is left in place
+UPDATE "leases" SET "status" = 'renewed' WHERE "status" = 'superseded';
+UPDATE "lease_status_history" SET "to_status" = 'renewed' WHERE "to_status" = 'superseded';
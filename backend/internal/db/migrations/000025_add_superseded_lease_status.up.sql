@@ -0,0 +1,3 @@
+-- A lease replaced by a signed amendment is superseded, not renewed. Postgres cannot use a new enum value in
+-- the transaction that adds it, so leases already retired by an amendment are relabeled in the next migration.
+ALTER TYPE "Lease_Status" ADD VALUE IF NOT EXISTS 'superseded' AFTER 'renewed';
@@ -0,0 +1,2 @@
+UPDATE "leases" SET "status" = 'renewed' WHERE "status" = 'superseded';
+UPDATE "lease_status_history" SET "to_status" = 'renewed' WHERE "to_status" = 'superseded';
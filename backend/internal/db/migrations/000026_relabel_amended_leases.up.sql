@@ -0,0 +1,9 @@
+-- Leases an amendment replaced were stored as renewed; relabel them and their history as superseded
+UPDATE "leases" l
+SET "status" = 'superseded'
+WHERE l."status" = 'renewed'
+  AND EXISTS (SELECT 1 FROM "lease_status_history" h WHERE h."lease_id" = l."id" AND h."to_status" = 'renewed' AND h."event" = 'amended');
+
+UPDATE "lease_status_history"
+SET "to_status" = 'superseded'
+WHERE "to_status" = 'renewed' AND "event" = 'amended';
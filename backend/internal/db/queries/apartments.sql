@@ -23,7 +23,10 @@ SELECT id,
   price,
   size,
   management_id,
-  availability
+  availability,
+  building_id,
+  bedrooms,
+  unit_type
 FROM apartments
 WHERE id = $1
 LIMIT 1;
@@ -34,7 +37,10 @@ SELECT id,
   price,
   size,
   management_id,
-  availability
+  availability,
+  building_id,
+  bedrooms,
+  unit_type
 FROM apartments
 ORDER BY unit_number DESC;
 
@@ -46,6 +52,14 @@ SET price = $2,
   updated_at = now()
 WHERE id = $1;
 
+-- name: UpdateApartmentLayout :exec
+-- The bedroom count and unit type waitlist entries are matched on
+UPDATE apartments
+SET bedrooms = $2,
+  unit_type = $3,
+  updated_at = now()
+WHERE id = $1;
+
 -- name: DeleteApartment :exec
 DELETE FROM apartments
 WHERE id = $1;
@@ -70,12 +84,15 @@ SELECT
   price,
   size,
   management_id,
-  availability
+  availability,
+  building_id,
+  bedrooms,
+  unit_type
 FROM apartments
 WHERE 
   id NOT IN (
     SELECT apartment_id FROM leases 
-    WHERE status = 'active'
+    WHERE status IN ('active', 'month_to_month')
   )
   AND availability = true
 ORDER BY unit_number ASC;
\ No newline at end of file
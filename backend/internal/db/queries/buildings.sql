@@ -20,3 +20,34 @@ SET parking_total = $2,
     per_user_parking = $3,
     updated_at = now()
 WHERE id = $1;
+
+-- name: UpdateBuildingRules :exec
+UPDATE buildings
+SET rules = $2,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: UpdateBuildingProrationMethod :exec
+UPDATE buildings
+SET proration_method = $2,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: GetApartmentProrationMethod :one
+SELECT b.proration_method
+FROM apartments a
+JOIN buildings b ON b.id = a.building_id
+WHERE a.id = $1;
+
+-- name: UpdateBuildingHoldoverPolicy :exec
+UPDATE buildings
+SET holdover_policy = $2,
+    holdover_premium_percent = $3,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: UpdateBuildingManagement :exec
+UPDATE buildings
+SET management_id = $2,
+    updated_at = now()
+WHERE id = $1;
@@ -0,0 +1,67 @@
+-- name: UpsertLeaseDeposit :one
+INSERT INTO lease_deposits (lease_id, amount, received_on, holding_account, interest_rate, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (lease_id) DO UPDATE
+SET amount = EXCLUDED.amount,
+    received_on = EXCLUDED.received_on,
+    holding_account = EXCLUDED.holding_account,
+    interest_rate = EXCLUDED.interest_rate,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetLeaseDeposit :one
+SELECT * FROM lease_deposits
+WHERE lease_id = $1;
+
+-- name: CreateDepositDisposition :one
+-- Issues the statement, completing one opened at move-out with its dates. An issued statement is never changed.
+INSERT INTO deposit_dispositions (
+  lease_id, deposit_amount, interest_amount, deductions, total_deductions,
+  refund_amount, move_out_date, due_by, status, created_by
+) VALUES (
+  $1, $2, $3, $4, $5,
+  $6, $7, $8, 'issued', $9
+)
+ON CONFLICT (lease_id) DO UPDATE
+SET deposit_amount = EXCLUDED.deposit_amount,
+    interest_amount = EXCLUDED.interest_amount,
+    deductions = EXCLUDED.deductions,
+    total_deductions = EXCLUDED.total_deductions,
+    refund_amount = EXCLUDED.refund_amount,
+    status = 'issued'
+WHERE deposit_dispositions.status = 'pending_disposition'
+RETURNING *;
+
+-- name: OpenDepositDisposition :one
+-- Holds the deposit of a lease that ended until its statement is issued. Nothing is returned when the lease
+-- has no deposit or its statement was already opened.
+INSERT INTO deposit_dispositions (
+  lease_id, deposit_amount, deductions, total_deductions, refund_amount, move_out_date, due_by, status, created_by
+)
+SELECT d.lease_id, d.amount, '[]', 0, d.amount, sqlc.arg(move_out_date), sqlc.arg(due_by), 'pending_disposition', sqlc.narg(created_by)
+FROM lease_deposits d
+WHERE d.lease_id = sqlc.arg(lease_id)
+ON CONFLICT (lease_id) DO NOTHING
+RETURNING *;
+
+-- name: GetDepositDisposition :one
+SELECT * FROM deposit_dispositions
+WHERE lease_id = $1;
+
+-- name: MarkDepositDispositionSent :exec
+UPDATE deposit_dispositions
+SET sent_to = $2,
+    sent_at = now()
+WHERE id = $1;
+
+-- name: ListUnsentDepositDispositions :many
+SELECT * FROM deposit_dispositions
+WHERE sent_at IS NULL
+  AND status = 'issued'
+ORDER BY due_by, id;
+
+-- name: ListDueDepositDispositions :many
+SELECT * FROM deposit_dispositions
+WHERE status = 'pending_disposition'
+  AND due_by <= $1
+ORDER BY due_by, id;
@@ -0,0 +1,53 @@
+-- name: CreateDocument :one
+INSERT INTO documents (
+  category, title, file_name, content_type, size_bytes, storage_key,
+  tenant_id, lease_id, apartment_id, uploaded_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6,
+  $7, $8, $9, $10
+)
+ON CONFLICT (storage_key) DO UPDATE
+SET title = EXCLUDED.title,
+    size_bytes = EXCLUDED.size_bytes
+RETURNING *;
+
+-- name: GetDocument :one
+SELECT * FROM documents
+WHERE id = $1;
+
+-- name: ListDocuments :many
+SELECT * FROM documents
+WHERE (sqlc.narg('tenant_id')::BIGINT IS NULL OR tenant_id = sqlc.narg('tenant_id'))
+  AND (sqlc.narg('lease_id')::BIGINT IS NULL OR lease_id = sqlc.narg('lease_id'))
+  AND (sqlc.narg('apartment_id')::BIGINT IS NULL OR apartment_id = sqlc.narg('apartment_id'))
+  AND (sqlc.narg('category')::"Document_Category" IS NULL OR category = sqlc.narg('category'))
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListTenantDocuments :many
+-- A tenant sees documents filed to them, to a lease they are on, or to the unit they currently rent
+SELECT d.* FROM documents d
+WHERE d.tenant_id = $1
+   OR d.lease_id IN (SELECT lt.lease_id FROM lease_tenants lt WHERE lt.tenant_id = $1)
+   OR (d.tenant_id IS NULL AND d.lease_id IS NULL AND d.apartment_id IN (
+        SELECT l.apartment_id FROM leases l
+        JOIN lease_tenants lt ON lt.lease_id = l.id
+        WHERE lt.tenant_id = $1
+          AND l.status IN ('active', 'month_to_month')
+      ))
+ORDER BY d.created_at DESC, d.id DESC;
+
+-- name: DeleteDocument :exec
+DELETE FROM documents
+WHERE id = $1;
+
+-- name: RecordDocumentDownload :exec
+INSERT INTO document_downloads (document_id, user_id, ip_address, user_agent)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListDocumentDownloads :many
+SELECT dd.id, dd.document_id, dd.user_id, u.first_name, u.last_name, u.email, u.role,
+    dd.ip_address, dd.user_agent, dd.downloaded_at
+FROM document_downloads dd
+LEFT JOIN users u ON u.id = dd.user_id
+WHERE dd.document_id = $1
+ORDER BY dd.downloaded_at DESC, dd.id DESC;
@@ -0,0 +1,48 @@
+-- name: CreateLandlord :one
+INSERT INTO landlords (
+    legal_name,
+    address,
+    license_number,
+    signer_user_id,
+    signer_name,
+    signer_email
+  ) VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetLandlord :one
+SELECT *
+FROM landlords
+WHERE id = $1;
+
+-- name: ListLandlords :many
+SELECT *
+FROM landlords
+ORDER BY legal_name, id;
+
+-- name: UpdateLandlord :one
+UPDATE landlords
+SET legal_name = $2,
+    address = $3,
+    license_number = $4,
+    signer_user_id = $5,
+    signer_name = $6,
+    signer_email = $7,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetLandlordBySignerUserID :one
+-- The first landlord an admin signs for
+SELECT *
+FROM landlords
+WHERE signer_user_id = $1
+ORDER BY id
+LIMIT 1;
+
+-- name: GetApartmentLandlord :one
+-- The landlord managing the building an apartment is in
+SELECT l.*
+FROM apartments a
+JOIN buildings b ON b.id = a.building_id
+JOIN landlords l ON l.id = b.management_id
+WHERE a.id = $1;
@@ -0,0 +1,37 @@
+-- name: CreateLeaseAddendum :one
+INSERT INTO lease_addenda (name, title, body, created_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: UpdateLeaseAddendum :one
+UPDATE lease_addenda
+SET title = $2,
+    body = $3,
+    archived = $4,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetLeaseAddendum :one
+SELECT * FROM lease_addenda
+WHERE id = $1;
+
+-- name: ListLeaseAddenda :many
+SELECT * FROM lease_addenda
+ORDER BY archived, name;
+
+-- name: AttachLeaseAddendum :exec
+INSERT INTO lease_attached_addenda (
+  lease_id, addendum_id, position, title, body
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (lease_id, addendum_id) DO UPDATE
+SET position = EXCLUDED.position,
+    title = EXCLUDED.title,
+    body = EXCLUDED.body;
+
+-- name: ListLeaseAttachedAddenda :many
+SELECT * FROM lease_attached_addenda
+WHERE lease_id = $1
+ORDER BY position;
@@ -0,0 +1,40 @@
+-- name: CreateLeaseAmendment :one
+INSERT INTO lease_amendments (
+  lease_id, amended_lease_id, version, changes, external_doc_id, created_by
+) VALUES (
+  sqlc.arg(lease_id),
+  sqlc.arg(amended_lease_id),
+  COALESCE((SELECT a.version FROM lease_amendments a WHERE a.amended_lease_id = sqlc.arg(lease_id)), 0) + 1,
+  sqlc.arg(changes),
+  sqlc.arg(external_doc_id),
+  sqlc.arg(created_by)
+)
+RETURNING *;
+
+-- name: GetLeaseAmendmentByAmendedLease :one
+SELECT * FROM lease_amendments
+WHERE amended_lease_id = $1;
+
+-- name: SetLeaseDocumentType :exec
+UPDATE leases
+SET document_type = $2
+WHERE id = $1;
+
+-- name: GetLeaseHistory :many
+WITH RECURSIVE ancestors AS (
+  SELECT l.id, l.previous_lease_id FROM leases l WHERE l.id = $1
+  UNION
+  SELECT p.id, p.previous_lease_id FROM leases p
+  JOIN ancestors a ON p.id = a.previous_lease_id
+), chain AS (
+  SELECT a.id FROM ancestors a WHERE a.previous_lease_id IS NULL
+  UNION
+  SELECT c.id FROM leases c
+  JOIN chain ON c.previous_lease_id = chain.id
+)
+SELECT l.id, l.lease_number, l.document_type, l.status,
+  l.lease_start_date, l.lease_end_date, l.rent_amount,
+  l.external_doc_id, l.previous_lease_id, l.created_at
+FROM leases l
+JOIN chain ON chain.id = l.id
+ORDER BY l.created_at, l.id;
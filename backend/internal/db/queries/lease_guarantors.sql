@@ -0,0 +1,15 @@
+-- name: CreateLeaseGuarantor :one
+INSERT INTO lease_guarantors (
+  lease_id, name, email, relationship
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (lease_id, email) DO UPDATE
+SET name = EXCLUDED.name,
+    relationship = EXCLUDED.relationship
+RETURNING *;
+
+-- name: ListLeaseGuarantors :many
+SELECT * FROM lease_guarantors
+WHERE lease_id = $1
+ORDER BY id;
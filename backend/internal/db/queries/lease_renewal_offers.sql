@@ -0,0 +1,74 @@
+-- name: CreateLeaseRenewalOffer :one
+INSERT INTO lease_renewal_offers (lease_id, token, options, notice_stage, created_by, expires_at, last_notified_at)
+VALUES ($1, $2, $3, $4, $5, $6, now())
+RETURNING *;
+
+-- name: GetLeaseRenewalOfferByToken :one
+SELECT * FROM lease_renewal_offers
+WHERE token = $1;
+
+-- name: GetLeaseRenewalOffer :one
+SELECT * FROM lease_renewal_offers
+WHERE id = $1;
+
+-- name: GetLatestLeaseRenewalOffer :one
+SELECT * FROM lease_renewal_offers
+WHERE lease_id = $1
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListLeaseRenewalOffers :many
+SELECT * FROM lease_renewal_offers
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListPendingLeaseRenewalOffers :many
+SELECT o.id, o.lease_id, o.token, o.options, o.notice_stage,
+  l.tenant_id, l.apartment_id, l.lease_end_date
+FROM lease_renewal_offers o
+JOIN leases l ON l.id = o.lease_id
+WHERE o.status = 'pending'
+ORDER BY l.lease_end_date;
+
+-- name: AcceptLeaseRenewalOffer :one
+-- Only an open offer that has not expired, on a lease that can still be renewed, can be accepted
+UPDATE lease_renewal_offers o
+SET status = 'accepted',
+    accepted_option = $2,
+    responded_at = now(),
+    updated_at = now()
+FROM leases l
+WHERE o.id = $1
+  AND o.status = 'pending'
+  AND o.expires_at > now()
+  AND l.id = o.lease_id
+  AND l.status IN ('active', 'month_to_month')
+RETURNING o.*;
+
+-- name: RespondToLeaseRenewalOffer :one
+UPDATE lease_renewal_offers
+SET status = $2,
+    accepted_option = $3,
+    responded_at = now(),
+    updated_at = now()
+WHERE id = $1
+  AND status = 'pending'
+RETURNING *;
+
+-- name: UpdateLeaseRenewalOfferStatus :exec
+UPDATE lease_renewal_offers
+SET status = $2,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: SetLeaseRenewalOfferRenewedLease :exec
+UPDATE lease_renewal_offers
+SET renewed_lease_id = $2,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: UpdateLeaseRenewalOfferNotice :exec
+UPDATE lease_renewal_offers
+SET notice_stage = $2,
+    last_notified_at = now(),
+    updated_at = now()
+WHERE id = $1;
@@ -0,0 +1,36 @@
+-- name: UpsertLeaseSigner :one
+INSERT INTO lease_signers (
+  lease_id, user_id, role, name, email, signing_url
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (lease_id, email) DO UPDATE
+SET user_id = EXCLUDED.user_id,
+    role = EXCLUDED.role,
+    name = EXCLUDED.name,
+    signing_url = EXCLUDED.signing_url,
+    status = 'pending',
+    signed_at = NULL,
+    rejection_reason = NULL,
+    updated_at = now()
+RETURNING *;
+
+-- name: ListLeaseSigners :many
+SELECT * FROM lease_signers
+WHERE lease_id = $1
+ORDER BY role, id;
+
+-- name: UpdateLeaseSignerSigningURL :exec
+UPDATE lease_signers
+SET signing_url = $3, updated_at = now()
+WHERE lease_id = $1 AND email = $2;
+
+-- name: MarkLeaseSignerSigned :execrows
+UPDATE lease_signers
+SET status = 'signed', signed_at = $3, rejection_reason = NULL, updated_at = now()
+WHERE lease_id = $1 AND email = $2 AND status <> 'signed';
+
+-- name: MarkLeaseSignerRejected :execrows
+UPDATE lease_signers
+SET status = 'rejected', rejection_reason = $3, updated_at = now()
+WHERE lease_id = $1 AND email = $2 AND status <> 'rejected';
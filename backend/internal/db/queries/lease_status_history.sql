@@ -0,0 +1,53 @@
+-- name: TransitionLeaseStatus :one
+-- Moves a lease out of from_status and records the change in one statement. Nothing is returned when the
+-- lease is no longer in from_status, so two concurrent changes cannot both apply.
+WITH changed AS (
+    UPDATE leases
+    SET status = sqlc.arg('to_status'),
+        updated_by = COALESCE(sqlc.narg('changed_by'), updated_by),
+        updated_at = now()
+    WHERE id = sqlc.arg('lease_id')
+      AND status = sqlc.arg('from_status')
+    RETURNING id, status
+)
+INSERT INTO lease_status_history (lease_id, from_status, to_status, event, reason, changed_by)
+SELECT id, sqlc.arg('from_status'), status, sqlc.arg('event'), sqlc.narg('reason'), sqlc.narg('changed_by')
+FROM changed
+RETURNING *;
+
+-- name: RecordLeaseCreated :exec
+INSERT INTO lease_status_history (lease_id, from_status, to_status, event, changed_by)
+VALUES ($1, NULL, $2, 'created', $3);
+
+-- name: ListLeaseStatusHistory :many
+SELECT h.id, h.lease_id, h.from_status, h.to_status, h.event, h.reason, h.changed_by, h.changed_at,
+    u.first_name AS changed_by_first_name, u.last_name AS changed_by_last_name
+FROM lease_status_history h
+LEFT JOIN users u ON u.id = h.changed_by
+WHERE h.lease_id = $1
+ORDER BY h.changed_at, h.id;
+
+-- name: MarkRenewedLeases :execrows
+-- A lease whose renewal is signed and has started becomes renewed instead of expiring or holding over
+WITH due AS (
+    SELECT l.id, l.status
+    FROM leases l
+    WHERE l.status IN ('active', 'month_to_month', 'expired')
+      AND EXISTS (
+        SELECT 1 FROM leases s
+        WHERE s.previous_lease_id = l.id
+          AND s.document_type IS DISTINCT FROM 'amendment'
+          AND s.status IN ('active', 'month_to_month')
+          AND s.lease_start_date <= CURRENT_DATE
+      )
+    FOR UPDATE OF l
+), renewed AS (
+    UPDATE leases
+    SET status = 'renewed', updated_at = now()
+    FROM due
+    WHERE leases.id = due.id
+    RETURNING leases.id, due.status AS from_status
+)
+INSERT INTO lease_status_history (lease_id, from_status, to_status, event, reason)
+SELECT id, from_status, 'renewed', 'renewed', 'Renewal lease has started'
+FROM renewed;
@@ -0,0 +1,31 @@
+-- name: CreateLeaseTemplateVersion :one
+INSERT INTO lease_templates (name, version, content, created_by)
+SELECT sqlc.arg(name), COALESCE(MAX(version), 0) + 1, sqlc.arg(content), sqlc.narg(created_by)
+FROM lease_templates
+WHERE name = sqlc.arg(name)
+RETURNING *;
+
+-- name: GetLeaseTemplate :one
+SELECT * FROM lease_templates
+WHERE id = $1;
+
+-- name: GetLatestLeaseTemplate :one
+SELECT * FROM lease_templates
+WHERE name = $1
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: ListLeaseTemplates :many
+SELECT * FROM lease_templates
+ORDER BY name, version DESC;
+
+-- name: SetLeaseTemplate :exec
+UPDATE leases
+SET lease_template_id = $2
+WHERE id = $1;
+
+-- name: GetApartmentLeaseDetails :one
+SELECT a.unit_number, b.rules AS building_rules
+FROM apartments a
+JOIN buildings b ON b.id = a.building_id
+WHERE a.id = $1;
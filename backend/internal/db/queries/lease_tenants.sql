@@ -0,0 +1,45 @@
+-- name: AddLeaseTenant :exec
+INSERT INTO lease_tenants (
+  lease_id, tenant_id, is_primary
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (lease_id, tenant_id) DO UPDATE
+SET is_primary = EXCLUDED.is_primary;
+
+-- name: ListLeaseTenants :many
+SELECT lt.lease_id, lt.is_primary, u.id, u.first_name, u.last_name, u.email
+FROM lease_tenants lt
+JOIN users u ON u.id = lt.tenant_id
+WHERE lt.lease_id = $1
+ORDER BY lt.is_primary DESC, u.id;
+
+-- name: ListTenantLeases :many
+SELECT l.id, l.lease_number,
+    l.external_doc_id,
+    l.lease_pdf_s3,
+    l.tenant_id,
+    l.landlord_id,
+    l.apartment_id,
+    l.lease_start_date,
+    l.lease_end_date,
+    l.rent_amount,
+    l.status,
+    l.created_by,
+    l.updated_by,
+    l.previous_lease_id
+FROM leases l
+JOIN lease_tenants lt ON lt.lease_id = l.id
+WHERE lt.tenant_id = $1
+ORDER BY l.created_at DESC;
+
+-- name: GetConflictingOccupantLease :one
+SELECT l.id, l.lease_number, l.status
+FROM leases l
+JOIN lease_tenants lt ON lt.lease_id = l.id
+WHERE lt.tenant_id = $1
+  AND l.status = 'active'
+  AND l.lease_start_date <= $3
+  AND l.lease_end_date >= $2
+  AND l.id IS DISTINCT FROM sqlc.narg(excluded_lease_id)
+LIMIT 1;
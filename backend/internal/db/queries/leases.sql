@@ -44,17 +44,6 @@ WHERE tenant_id = $1
 LIMIT 1;
 
 
--- name: TerminateLease :one
-UPDATE leases
-SET
-    status = 'terminated', 
-    updated_by = $1, 
-    updated_at = now()
-WHERE id = $2
-RETURNING id, lease_number, external_doc_id, tenant_id, landlord_id, apartment_id, 
-    lease_start_date, lease_end_date, rent_amount, status, 
-    updated_by, updated_at, previous_lease_id;
-
 -- name: ListLeases :many
 SELECT id, lease_number,
     external_doc_id,
@@ -87,37 +76,16 @@ SELECT id,
     updated_by,
     previous_lease_id,
     tenant_signing_url,
-    landlord_signing_url
+    landlord_signing_url,
+    rent_escalation,
+    notice_period_days,
+    early_termination_fee,
+    holdover_policy,
+    holdover_premium_percent,
+    holdover_rent
 FROM leases
 WHERE id = $1;
 
--- name: UpdateLease :exec
-UPDATE leases
-SET 
-    tenant_id = $1,
-    status = $2,
-    status = $2,
-    lease_start_date = $3,
-    lease_end_date = $4,
-    rent_amount = $5,
-    updated_by = $6,
-    updated_at = now()
-WHERE id = $7
-RETURNING lease_number,
-    external_doc_id,
-    lease_pdf_s3,
-    tenant_id,
-    landlord_id,
-    apartment_id,
-    lease_start_date,
-    lease_end_date,
-    rent_amount,
-    status,
-    created_by,
-    updated_by,
-    previous_lease_id;
-
-
 -- name: StoreGeneratedLeasePDFURL :exec
 UPDATE leases
 SET lease_pdf_s3 = $1, external_doc_id = $2, updated_at = now()
@@ -125,24 +93,6 @@ WHERE id = $3
 RETURNING lease_pdf_s3;
 
 
--- name: MarkLeaseAsSignedBothParties :exec
-UPDATE leases
-SET status = 'active', updated_at = now()
-WHERE id = $1
-RETURNING lease_number,
-    external_doc_id,
-    lease_pdf_s3,
-    tenant_id,
-    landlord_id,
-    apartment_id,
-    lease_start_date,
-    lease_end_date,
-    rent_amount,
-    status,
-    created_by,
-    updated_by,
-    previous_lease_id;
-
 -- name: UpdateLeasePDF :exec
 UPDATE leases
 SET 
@@ -170,6 +120,10 @@ WITH expired_leases AS (
     SET status = 'expired', updated_at = NOW()
     WHERE status = 'active' AND lease_end_date <= CURRENT_DATE
     RETURNING id
+), history AS (
+    INSERT INTO lease_status_history (lease_id, from_status, to_status, event, reason)
+    SELECT id, 'active', 'expired', 'expired', 'Lease end date passed'
+    FROM expired_leases
 )
 SELECT 
     COUNT(*) as expired_count,
@@ -199,15 +153,6 @@ SELECT id, lease_number, external_doc_id, lease_pdf_s3,
 WHERE external_doc_id = $1
 LIMIT 1;
 
--- name: UpdateLeaseStatus :one
-UPDATE leases
-SET status = $2, updated_by = $3, updated_at = NOW()
-WHERE id = $1
-RETURNING id, lease_number, external_doc_id, tenant_id, landlord_id, apartment_id, 
-    lease_start_date, lease_end_date, rent_amount, status, created_by, 
-    updated_by, updated_at, previous_lease_id;
-
-
 -- name: UpdateSigningURLs :exec
 UPDATE leases
 SET tenant_signing_url = $2,
@@ -228,35 +173,13 @@ FROM leases
 WHERE id = $1;
 
 
--- name: UpdateLeaseAndApartmentOnDocumentCompletion :one
-WITH updated_lease AS (
-    UPDATE leases
-    SET 
-        status = 'active',
-        updated_at = now(),
-        updated_by = $2
-    WHERE external_doc_id = $1
-    RETURNING id, apartment_id, rent_amount, landlord_id
-)
-UPDATE apartments
-SET 
-    availability = false,
-    lease_id = (SELECT id FROM updated_lease),
-    updated_at = now()
-WHERE id = (SELECT apartment_id FROM updated_lease)
-RETURNING 
-    (SELECT json_build_object(
-        'lease_id', ul.id,
-        'apartment_id', ul.apartment_id,
-        'success', true
-    ) FROM updated_lease ul);
-
-
 -- name: GetTenantLeaseStatusAndURLByUserID :one
-SELECT status,tenant_signing_url, lease_number
-FROM leases
-WHERE tenant_id = $1
-ORDER BY lease_number DESC
+SELECT l.status, COALESCE(ls.signing_url, l.tenant_signing_url) AS tenant_signing_url, l.lease_number
+FROM leases l
+JOIN lease_tenants lt ON lt.lease_id = l.id
+LEFT JOIN lease_signers ls ON ls.lease_id = l.id AND ls.user_id = lt.tenant_id
+WHERE lt.tenant_id = $1
+ORDER BY l.id DESC
 LIMIT 1;
 
 
@@ -280,7 +203,7 @@ LIMIT 1;
 -- name: GetActiveLeasesByTenant :many
 SELECT * FROM leases
 WHERE tenant_id = $1
-AND status IN ('active', 'draft', 'pending_approval')
+AND status IN ('active', 'month_to_month', 'draft', 'pending_approval')
 ORDER BY id DESC;
 
 -- name: GetLeaseByExternalID :one
@@ -301,4 +224,137 @@ SELECT id,
     tenant_signing_url,
     landlord_signing_url
 FROM leases
-WHERE external_doc_id = $1;
\ No newline at end of file
+WHERE external_doc_id = $1;
+
+-- name: SetLeaseRentEscalation :exec
+UPDATE leases
+SET rent_escalation = $2,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: SetLeaseTerminationTerms :exec
+UPDATE leases
+SET notice_period_days = $2,
+    early_termination_fee = $3,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: SetLeaseHoldoverPolicy :exec
+UPDATE leases
+SET holdover_policy = $2,
+    holdover_premium_percent = $3,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: ListLeasesDueForHoldover :many
+SELECT l.id, l.tenant_id, l.landlord_id, l.apartment_id, l.lease_start_date, l.lease_end_date,
+    l.rent_amount, l.rent_escalation,
+    COALESCE(l.holdover_premium_percent, b.holdover_premium_percent)::NUMERIC AS holdover_premium_percent,
+    b.proration_method
+FROM leases l
+JOIN apartments a ON a.id = l.apartment_id
+JOIN buildings b ON b.id = a.building_id
+WHERE l.status = 'active'
+  AND l.lease_end_date <= CURRENT_DATE
+  AND COALESCE(l.holdover_policy, b.holdover_policy) = 'month_to_month'
+  AND NOT EXISTS (
+    SELECT 1 FROM leases nl
+    WHERE nl.previous_lease_id = l.id
+      AND nl.status IN ('draft', 'pending_approval', 'active')
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM lease_vacate_notices n
+    WHERE n.lease_id = l.id
+      AND n.status IN ('pending_signature', 'scheduled')
+  )
+ORDER BY l.id;
+
+-- name: ConvertLeaseToMonthToMonth :execrows
+WITH converted AS (
+    UPDATE leases
+    SET status = 'month_to_month',
+        holdover_rent = $2,
+        updated_at = now()
+    WHERE id = $1
+      AND status = 'active'
+    RETURNING id
+)
+INSERT INTO lease_status_history (lease_id, from_status, to_status, event, reason)
+SELECT id, 'active', 'month_to_month', 'holdover', 'Lease end date passed under a holdover policy'
+FROM converted;
+
+-- name: SetLeaseGeneratedPdfKey :exec
+UPDATE leases
+SET generated_pdf_key = $2,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: SetLeaseSignedPdfKey :exec
+UPDATE leases
+SET signed_pdf_key = $2,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: GetLeasePdfKeys :one
+SELECT generated_pdf_key, signed_pdf_key
+FROM leases
+WHERE id = $1;
+
+-- name: ListLeasesForDocumentReconciliation :many
+-- Leases that still depend on their document at the e-sign provider: unsigned ones waiting for signatures and
+-- signed ones we have not kept our own copy of yet.
+SELECT id, external_doc_id, status, landlord_id
+FROM leases
+WHERE external_doc_id <> ''
+  AND (status IN ('draft', 'pending_approval')
+    OR (status IN ('active', 'month_to_month', 'expired', 'terminated', 'renewed', 'superseded') AND signed_pdf_key IS NULL))
+ORDER BY id;
+
+-- name: SearchLeases :many
+-- Admin lease list. Every filter is optional; rows come back in sort_by/sort_dir order and continue after
+-- the cursor, which is the sort value and id of the last row of the previous page.
+SELECT l.id, l.lease_number, l.external_doc_id, l.tenant_id, l.apartment_id,
+    l.lease_start_date, l.lease_end_date, l.rent_amount, l.status, l.created_at,
+    u.first_name AS tenant_first_name, u.last_name AS tenant_last_name, u.email AS tenant_email,
+    a.unit_number, a.building_id
+FROM leases l
+JOIN users u ON u.id = l.tenant_id
+JOIN apartments a ON a.id = l.apartment_id
+WHERE (sqlc.narg('status')::"Lease_Status" IS NULL OR l.status = sqlc.narg('status'))
+  AND (sqlc.narg('tenant_id')::BIGINT IS NULL OR l.tenant_id = sqlc.narg('tenant_id')
+    OR EXISTS (SELECT 1 FROM lease_tenants lt WHERE lt.lease_id = l.id AND lt.tenant_id = sqlc.narg('tenant_id')))
+  AND (sqlc.narg('tenant_search')::TEXT IS NULL
+    OR (u.first_name || ' ' || u.last_name) ILIKE '%' || sqlc.narg('tenant_search') || '%' ESCAPE '\'
+    OR u.email ILIKE '%' || sqlc.narg('tenant_search') || '%' ESCAPE '\')
+  AND (sqlc.narg('apartment_id')::BIGINT IS NULL OR l.apartment_id = sqlc.narg('apartment_id'))
+  AND (sqlc.narg('building_id')::BIGINT IS NULL OR a.building_id = sqlc.narg('building_id'))
+  AND (sqlc.narg('start_from')::DATE IS NULL OR l.lease_start_date >= sqlc.narg('start_from'))
+  AND (sqlc.narg('start_to')::DATE IS NULL OR l.lease_start_date <= sqlc.narg('start_to'))
+  AND (sqlc.narg('end_from')::DATE IS NULL OR l.lease_end_date >= sqlc.narg('end_from'))
+  AND (sqlc.narg('end_to')::DATE IS NULL OR l.lease_end_date <= sqlc.narg('end_to'))
+  AND (sqlc.narg('rent_min')::NUMERIC IS NULL OR l.rent_amount >= sqlc.narg('rent_min'))
+  AND (sqlc.narg('rent_max')::NUMERIC IS NULL OR l.rent_amount <= sqlc.narg('rent_max'))
+  AND (sqlc.narg('cursor_id')::BIGINT IS NULL OR CASE sqlc.arg('sort_by')::TEXT
+    WHEN 'start_date' THEN CASE WHEN sqlc.arg('sort_dir')::TEXT = 'asc'
+      THEN (l.lease_start_date, l.id) > (sqlc.narg('cursor_date')::DATE, sqlc.narg('cursor_id'))
+      ELSE (l.lease_start_date, l.id) < (sqlc.narg('cursor_date')::DATE, sqlc.narg('cursor_id')) END
+    WHEN 'end_date' THEN CASE WHEN sqlc.arg('sort_dir')::TEXT = 'asc'
+      THEN (l.lease_end_date, l.id) > (sqlc.narg('cursor_date')::DATE, sqlc.narg('cursor_id'))
+      ELSE (l.lease_end_date, l.id) < (sqlc.narg('cursor_date')::DATE, sqlc.narg('cursor_id')) END
+    WHEN 'rent' THEN CASE WHEN sqlc.arg('sort_dir')::TEXT = 'asc'
+      THEN (l.rent_amount, l.id) > (sqlc.narg('cursor_rent')::NUMERIC, sqlc.narg('cursor_id'))
+      ELSE (l.rent_amount, l.id) < (sqlc.narg('cursor_rent')::NUMERIC, sqlc.narg('cursor_id')) END
+    ELSE CASE WHEN sqlc.arg('sort_dir')::TEXT = 'asc'
+      THEN l.id > sqlc.narg('cursor_id')
+      ELSE l.id < sqlc.narg('cursor_id') END
+  END)
+ORDER BY
+  CASE WHEN sqlc.arg('sort_by')::TEXT = 'start_date' AND sqlc.arg('sort_dir')::TEXT = 'asc' THEN l.lease_start_date END ASC,
+  CASE WHEN sqlc.arg('sort_by')::TEXT = 'start_date' AND sqlc.arg('sort_dir')::TEXT = 'desc' THEN l.lease_start_date END DESC,
+  CASE WHEN sqlc.arg('sort_by')::TEXT = 'end_date' AND sqlc.arg('sort_dir')::TEXT = 'asc' THEN l.lease_end_date END ASC,
+  CASE WHEN sqlc.arg('sort_by')::TEXT = 'end_date' AND sqlc.arg('sort_dir')::TEXT = 'desc' THEN l.lease_end_date END DESC,
+  CASE WHEN sqlc.arg('sort_by')::TEXT = 'rent' AND sqlc.arg('sort_dir')::TEXT = 'asc' THEN l.rent_amount END ASC,
+  CASE WHEN sqlc.arg('sort_by')::TEXT = 'rent' AND sqlc.arg('sort_dir')::TEXT = 'desc' THEN l.rent_amount END DESC,
+  CASE WHEN sqlc.arg('sort_dir')::TEXT = 'asc' THEN l.id END ASC,
+  CASE WHEN sqlc.arg('sort_dir')::TEXT = 'desc' THEN l.id END DESC
+LIMIT sqlc.arg('row_limit');
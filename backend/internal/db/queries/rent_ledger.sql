@@ -0,0 +1,45 @@
+-- name: CreateLedgerEntry :one
+INSERT INTO rent_ledger_entries (
+  lease_id, tenant_id, entry_type, amount, description,
+  period_start, due_date, entry_date, payment_method, reference, created_by
+) VALUES (
+  $1, $2, $3, $4, $5,
+  $6, $7, $8, $9, $10, $11
+)
+RETURNING *;
+
+-- name: CreateRentCharge :execrows
+INSERT INTO rent_ledger_entries (
+  lease_id, tenant_id, entry_type, amount, description, period_start, due_date, entry_date
+) VALUES (
+  $1, $2, 'charge', $3, $4, $5, $6, $5
+)
+ON CONFLICT (lease_id, period_start) WHERE entry_type = 'charge' AND period_start IS NOT NULL
+DO NOTHING;
+
+-- name: ListLedgerEntriesByLease :many
+SELECT * FROM rent_ledger_entries
+WHERE lease_id = $1
+ORDER BY entry_date, id;
+
+-- name: ListLedgerEntriesByLeaseMember :many
+SELECT e.* FROM rent_ledger_entries e
+JOIN lease_tenants lt ON lt.lease_id = e.lease_id
+WHERE lt.tenant_id = $1
+ORDER BY e.entry_date, e.id;
+
+-- name: ListTenantLedgerPostings :many
+SELECT l.tenant_id, u.first_name, u.last_name, u.email, l.entry_type, l.amount
+FROM rent_ledger_entries l
+JOIN users u ON u.id = l.tenant_id
+ORDER BY l.tenant_id, l.entry_date, l.id;
+
+-- name: ListBillableLeases :many
+SELECT l.id, l.tenant_id, l.apartment_id, l.lease_start_date, l.lease_end_date, l.rent_amount, l.status,
+    l.rent_escalation, l.holdover_rent, b.proration_method
+FROM leases l
+JOIN apartments a ON a.id = l.apartment_id
+JOIN buildings b ON b.id = a.building_id
+WHERE l.lease_start_date <= $1
+  AND ((l.status = 'active' AND l.lease_end_date >= $2) OR l.status = 'month_to_month')
+ORDER BY l.id;
@@ -0,0 +1,107 @@
+-- name: CreateRentalApplication :one
+INSERT INTO rental_applications (
+  apartment_id, first_name, last_name, email, phone,
+  desired_move_in, monthly_income, employer, message
+) VALUES (
+  $1, $2, $3, $4, $5,
+  $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: CountOpenRentalApplications :one
+-- Applications for the unit from the same email that are still being reviewed
+SELECT COUNT(*) FROM rental_applications
+WHERE apartment_id = $1
+  AND lower(email) = lower(sqlc.arg('email')::TEXT)
+  AND status IN ('submitted', 'conditional');
+
+-- name: GetRentalApplication :one
+SELECT * FROM rental_applications
+WHERE id = $1;
+
+-- name: ListRentalApplications :many
+-- The review queue, oldest first
+SELECT * FROM rental_applications
+WHERE (sqlc.narg('status')::"Application_Status" IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('apartment_id')::BIGINT IS NULL OR apartment_id = sqlc.narg('apartment_id'))
+ORDER BY created_at ASC, id ASC;
+
+-- name: DecideRentalApplication :one
+-- Only applications still under review can be decided, so two admins cannot decide the same one
+UPDATE rental_applications
+SET status          = $2,
+    decision_reason = $3,
+    conditions      = $4,
+    decided_by      = $5,
+    screening_id    = $6,
+    decided_at      = now(),
+    updated_at      = now()
+WHERE id = $1
+  AND status IN ('submitted', 'conditional')
+RETURNING *;
+
+-- name: ReopenRentalApplication :exec
+-- Puts an approval back in the queue when its lease could not be created
+UPDATE rental_applications
+SET status       = $2,
+    decided_by   = NULL,
+    screening_id = NULL,
+    decided_at   = NULL,
+    updated_at   = now()
+WHERE id = $1;
+
+-- name: SetRentalApplicationLease :exec
+UPDATE rental_applications
+SET tenant_id  = $2,
+    lease_id   = $3,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: CreateRentalApplicationMember :exec
+INSERT INTO rental_application_members (application_id, name, relationship, is_adult, monthly_income)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListRentalApplicationMembers :many
+SELECT * FROM rental_application_members
+WHERE application_id = $1
+ORDER BY id;
+
+-- name: CreateRentalApplicationReference :exec
+INSERT INTO rental_application_references (application_id, name, relationship, phone, email)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListRentalApplicationReferences :many
+SELECT * FROM rental_application_references
+WHERE application_id = $1
+ORDER BY id;
+
+-- name: CreateRentalApplicationDocument :one
+INSERT INTO rental_application_documents (application_id, file_name, content_type, size_bytes, storage_key)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListRentalApplicationDocuments :many
+SELECT * FROM rental_application_documents
+WHERE application_id = $1
+ORDER BY id;
+
+-- name: GetRentalApplicationDocument :one
+SELECT * FROM rental_application_documents
+WHERE id = $1
+  AND application_id = $2;
+
+-- name: CreateRentalApplicationScreening :one
+INSERT INTO rental_application_screenings (application_id, provider, reference, recommendation, report, requested_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListRentalApplicationScreenings :many
+SELECT * FROM rental_application_screenings
+WHERE application_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetLatestRentalApplicationScreening :one
+SELECT * FROM rental_application_screenings
+WHERE application_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
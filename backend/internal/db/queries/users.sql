@@ -93,4 +93,36 @@ VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id, clerk_id, first_name, last_name, email, phone, role, status, updated_at, created_at;
 
 -- name: GetUserCount :one
-SELECT COUNT(*) FROM users;
\ No newline at end of file
+SELECT COUNT(*) FROM users;
+-- name: GetTenantByEmail :one
+SELECT id, clerk_id, first_name, last_name, email, phone, role, status
+FROM users
+WHERE lower(email) = lower($1)
+  AND role = 'tenant'
+ORDER BY id
+LIMIT 1;
+
+-- name: CreateApplicantUser :one
+-- A tenant account for an approved applicant who has not signed up yet. clerk_id holds a placeholder until
+-- the applicant accepts their invitation and the Clerk webhook claims the account.
+INSERT INTO users (clerk_id, first_name, last_name, email, phone, role, status, updated_at)
+VALUES ($1, $2, $3, $4, $5, 'tenant', 'inactive', now())
+RETURNING id, clerk_id, first_name, last_name, email, phone, role, status;
+
+-- name: ClaimApplicantUser :one
+UPDATE users
+SET clerk_id   = $2,
+    first_name = $3,
+    last_name  = $4,
+    email      = $5,
+    status     = 'active',
+    updated_at = now()
+WHERE id = $1
+  AND clerk_id LIKE 'application\_%'
+RETURNING id, clerk_id, first_name, last_name, email, phone, role, created_at;
+
+-- name: DeleteApplicantUser :exec
+DELETE
+FROM users
+WHERE id = $1
+  AND clerk_id LIKE 'application\_%';
@@ -0,0 +1,62 @@
+-- name: CreateVacateNotice :one
+INSERT INTO lease_vacate_notices (
+  lease_id, initiated_by, requested_by, notice_date, move_out_date,
+  early_termination, termination_fee, reason, external_doc_id, signing_urls
+) VALUES (
+  $1, $2, $3, $4, $5,
+  $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: GetVacateNotice :one
+SELECT * FROM lease_vacate_notices
+WHERE id = $1;
+
+-- name: GetVacateNoticeByExternalDocID :one
+SELECT * FROM lease_vacate_notices
+WHERE external_doc_id = $1;
+
+-- name: GetOpenVacateNotice :one
+SELECT * FROM lease_vacate_notices
+WHERE lease_id = $1
+  AND status IN ('pending_signature', 'scheduled');
+
+-- name: ListVacateNotices :many
+SELECT * FROM lease_vacate_notices
+WHERE (sqlc.narg('status')::"Notice_Status" IS NULL OR status = sqlc.narg('status'))
+ORDER BY move_out_date, id;
+
+-- name: ListTenantVacateNotices :many
+SELECT n.* FROM lease_vacate_notices n
+JOIN lease_tenants lt ON lt.lease_id = n.lease_id
+WHERE lt.tenant_id = $1
+ORDER BY n.created_at DESC;
+
+-- name: ListDueVacateNotices :many
+SELECT * FROM lease_vacate_notices
+WHERE status = 'scheduled'
+  AND move_out_date <= $1
+ORDER BY move_out_date, id;
+
+-- name: MarkVacateNoticeScheduled :execrows
+UPDATE lease_vacate_notices
+SET status = 'scheduled',
+    signed_at = now(),
+    updated_at = now()
+WHERE id = $1
+  AND status = 'pending_signature';
+
+-- name: CompleteVacateNotice :exec
+UPDATE lease_vacate_notices
+SET status = 'completed',
+    completed_at = now(),
+    updated_at = now()
+WHERE id = $1;
+
+-- name: CancelVacateNotice :one
+UPDATE lease_vacate_notices
+SET status = 'cancelled',
+    updated_at = now()
+WHERE id = $1
+  AND status IN ('pending_signature', 'scheduled')
+RETURNING *;
@@ -0,0 +1,123 @@
+-- name: CreateWaitlistEntry :one
+INSERT INTO waitlist_entries (
+  first_name, last_name, email, phone,
+  building_id, bedrooms, unit_type, token
+) VALUES (
+  $1, $2, $3, $4,
+  $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: GetWaitlistEntry :one
+SELECT * FROM waitlist_entries
+WHERE id = $1;
+
+-- name: GetWaitlistEntryByToken :one
+SELECT * FROM waitlist_entries
+WHERE token = $1;
+
+-- name: ListWaitlistEntries :many
+-- The waitlist in the order prospects joined
+SELECT * FROM waitlist_entries
+WHERE (sqlc.narg('status')::"Waitlist_Entry_Status" IS NULL OR status = sqlc.narg('status'))
+ORDER BY created_at ASC, id ASC;
+
+-- name: NextWaitlistEntryForApartment :one
+-- The longest waiting prospect whose criteria the apartment meets and who was not offered it before.
+-- Locked so two workers cannot offer the same prospect at once.
+SELECT e.* FROM waitlist_entries e
+JOIN apartments a ON a.id = sqlc.arg('apartment_id')
+WHERE e.status = 'waiting'
+  AND (e.building_id IS NULL OR e.building_id = a.building_id)
+  AND (e.bedrooms IS NULL OR e.bedrooms = a.bedrooms)
+  AND (e.unit_type IS NULL OR lower(e.unit_type) = lower(a.unit_type))
+  AND NOT EXISTS (
+    SELECT 1 FROM waitlist_offers o
+    WHERE o.entry_id = e.id AND o.apartment_id = a.id
+  )
+ORDER BY e.created_at ASC, e.id ASC
+LIMIT 1
+FOR UPDATE OF e SKIP LOCKED;
+
+-- name: SetWaitlistEntryStatus :exec
+UPDATE waitlist_entries
+SET status     = $2,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: ReturnWaitlistEntryToQueue :exec
+-- Puts a prospect whose offer ended back in line, keeping their place
+UPDATE waitlist_entries
+SET status     = 'waiting',
+    updated_at = now()
+WHERE id = $1
+  AND status = 'offered';
+
+-- name: WithdrawWaitlistEntry :one
+UPDATE waitlist_entries
+SET status     = 'withdrawn',
+    updated_at = now()
+WHERE id = $1
+  AND status IN ('waiting', 'offered')
+RETURNING *;
+
+-- name: CreateWaitlistOffer :one
+INSERT INTO waitlist_offers (entry_id, apartment_id, token, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetWaitlistOfferByToken :one
+SELECT * FROM waitlist_offers
+WHERE token = $1;
+
+-- name: GetPendingWaitlistOfferForApartment :one
+SELECT * FROM waitlist_offers
+WHERE apartment_id = $1
+  AND status = 'pending';
+
+-- name: GetPendingWaitlistOfferForEntry :one
+SELECT * FROM waitlist_offers
+WHERE entry_id = $1
+  AND status = 'pending';
+
+-- name: ListWaitlistOffers :many
+SELECT * FROM waitlist_offers
+WHERE (sqlc.narg('status')::"Waitlist_Offer_Status" IS NULL OR status = sqlc.narg('status'))
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListExpiredWaitlistOffers :many
+SELECT * FROM waitlist_offers
+WHERE status = 'pending'
+  AND expires_at <= $1
+ORDER BY expires_at ASC;
+
+-- name: RespondToWaitlistOffer :one
+-- Only pending offers can be answered, so an offer cannot be both accepted and expired
+UPDATE waitlist_offers
+SET status       = $2,
+    responded_at = now(),
+    updated_at   = now()
+WHERE id = $1
+  AND status = 'pending'
+RETURNING *;
+
+-- name: SetWaitlistOfferApplication :exec
+UPDATE waitlist_offers
+SET application_id = $2,
+    updated_at     = now()
+WHERE id = $1;
+
+-- name: CountApplicationsHoldingApartment :one
+-- Applications that keep an apartment from being offered: submitted ones that passed screening or answer a
+-- waitlist offer, ones the admin conditionally approved and approved ones whose lease is not signed yet.
+-- An unscreened submission from the public form holds nothing, so applying alone cannot tie up a unit.
+SELECT COUNT(*) FROM rental_applications ra
+LEFT JOIN leases l ON l.id = ra.lease_id
+WHERE ra.apartment_id = $1
+  AND (ra.status = 'conditional'
+    OR (ra.status = 'submitted' AND EXISTS (
+      SELECT 1 FROM rental_application_screenings s
+      WHERE s.application_id = ra.id AND s.recommendation <> 'fail'))
+    OR (ra.status = 'submitted' AND EXISTS (
+      SELECT 1 FROM waitlist_offers o WHERE o.application_id = ra.id))
+    OR (ra.status = 'approved' AND l.status IN ('draft', 'pending_approval')));
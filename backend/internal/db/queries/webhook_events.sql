@@ -0,0 +1,57 @@
+-- name: ClaimWebhookEvent :one
+-- Takes a logged delivery for processing. Nothing is returned when it was already processed or is being
+-- processed for another delivery of the same event, so concurrent redeliveries are applied once. A claim
+-- left behind by a crash is given up after 15 minutes.
+UPDATE webhook_events
+SET status = 'processing', updated_at = now()
+WHERE id = $1
+  AND (status IN ('received', 'failed')
+    OR (status = 'processing' AND updated_at < now() - INTERVAL '15 minutes'))
+RETURNING *;
+
+-- name: CreateWebhookEvent :one
+INSERT INTO webhook_events (
+  source, delivery_id, event_type, headers, body
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (source, delivery_id) DO NOTHING
+RETURNING *;
+
+-- name: GetWebhookEvent :one
+SELECT * FROM webhook_events
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetWebhookEventByDelivery :one
+SELECT * FROM webhook_events
+WHERE source = $1 AND delivery_id = $2
+LIMIT 1;
+
+-- name: ListWebhookEvents :many
+SELECT * FROM webhook_events
+WHERE (sqlc.narg('source')::VARCHAR IS NULL OR source = sqlc.narg('source'))
+  AND (sqlc.narg('status')::"Webhook_Event_Status" IS NULL OR status = sqlc.narg('status'))
+ORDER BY id DESC
+LIMIT sqlc.arg('row_limit');
+
+-- name: MarkWebhookEventProcessed :exec
+UPDATE webhook_events
+SET status = 'processed', attempts = attempts + 1, last_error = NULL, processed_at = now(), updated_at = now()
+WHERE id = $1;
+
+-- name: MarkWebhookEventFailed :exec
+UPDATE webhook_events
+SET status = 'failed', attempts = attempts + 1, last_error = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: RecordRejectedWebhookEvent :exec
+-- Keeps a delivery that failed verification so it can be inspected. Rejected deliveries are never claimed,
+-- so they are not processed or replayed.
+INSERT INTO webhook_events (
+  source, delivery_id, event_type, headers, body, status, attempts, last_error
+) VALUES (
+  $1, $2, $3, $4, $5, 'rejected', 1, $6
+)
+ON CONFLICT (source, delivery_id) DO UPDATE
+SET attempts = webhook_events.attempts + 1, last_error = EXCLUDED.last_error, updated_at = now();
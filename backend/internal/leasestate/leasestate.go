@@ -0,0 +1,114 @@
+// Package leasestate is the lease lifecycle: the statuses a lease can be in, which moves between them are
+// allowed and the events that cause them.
+package leasestate
+
+import (
+	"fmt"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+)
+
+// Event is what caused a lease to change status. It is kept with every change in lease_status_history.
+type Event string
+
+const (
+	EventCreated Event = "created"
+	// EventSent is the lease going out to its signers, by an admin or as reported by the e-sign provider
+	EventSent Event = "sent"
+	// EventSigned is every signer having signed
+	EventSigned Event = "signed"
+	// EventRejected is a signer declining to sign
+	EventRejected Event = "rejected"
+	// EventSigningCancelled is the signing request being withdrawn at the e-sign provider
+	EventSigningCancelled Event = "signing_cancelled"
+	// EventAmended is the lease being superseded by an amended version, once that version is signed
+	EventAmended Event = "amended"
+	// EventReplaced is the lease being terminated to make way for a new one for the same tenant and unit
+	EventReplaced Event = "replaced"
+	// EventTerminated is an admin ending the lease
+	EventTerminated Event = "terminated"
+	// EventVacated is the tenant moving out at the end of a notice to vacate
+	EventVacated Event = "vacated"
+	// EventExpired is the lease end date passing
+	EventExpired Event = "expired"
+	// EventHoldover is the lease going month-to-month after its end date
+	EventHoldover Event = "holdover"
+	// EventRenewed is the lease being succeeded by a renewal
+	EventRenewed Event = "renewed"
+	// EventDocumentMissing is the lease's document no longer existing at the e-sign provider
+	EventDocumentMissing Event = "document_missing"
+)
+
+// ExpiresSoonDays is how close to its end date an active lease is reported as expiring soon
+const ExpiresSoonDays = 60
+
+// StatusExpiresSoon is reported for active leases ending within ExpiresSoonDays. It is never stored.
+const StatusExpiresSoon = "expires_soon"
+
+// transitions lists the statuses each status may move to. Terminated, renewed, superseded and canceled
+// leases are final. Only a lease that was never signed can be canceled; one in force ends by being
+// terminated or renewed, or is superseded when an amendment replacing it is signed.
+var transitions = map[db.LeaseStatus][]db.LeaseStatus{
+	db.LeaseStatusDraft:           {db.LeaseStatusPendingApproval, db.LeaseStatusActive, db.LeaseStatusCanceled},
+	db.LeaseStatusPendingApproval: {db.LeaseStatusActive, db.LeaseStatusCanceled},
+	db.LeaseStatusActive: {db.LeaseStatusExpired, db.LeaseStatusMonthToMonth, db.LeaseStatusTerminated,
+		db.LeaseStatusRenewed, db.LeaseStatusSuperseded},
+	db.LeaseStatusMonthToMonth: {db.LeaseStatusTerminated, db.LeaseStatusRenewed, db.LeaseStatusSuperseded},
+	db.LeaseStatusExpired:      {db.LeaseStatusTerminated, db.LeaseStatusRenewed, db.LeaseStatusSuperseded},
+	db.LeaseStatusTerminated:   {},
+	db.LeaseStatusRenewed:      {},
+	db.LeaseStatusSuperseded:   {},
+	db.LeaseStatusCanceled:     {},
+}
+
+// ErrInvalidTransition is returned for a status change the lifecycle does not allow
+type ErrInvalidTransition struct {
+	From, To db.LeaseStatus
+}
+
+func (e *ErrInvalidTransition) Error() string {
+	return fmt.Sprintf("a %s lease cannot become %s", e.From, e.To)
+}
+
+// Initial reports whether a new lease may start out in the status
+func Initial(status db.LeaseStatus) bool {
+	return status == db.LeaseStatusDraft || status == db.LeaseStatusPendingApproval
+}
+
+// Next returns the statuses a lease in the given status may move to
+func Next(from db.LeaseStatus) []db.LeaseStatus {
+	return append([]db.LeaseStatus(nil), transitions[from]...)
+}
+
+// Check returns an *ErrInvalidTransition unless a lease may move from one status to the other
+func Check(from, to db.LeaseStatus) error {
+	for _, next := range transitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return &ErrInvalidTransition{From: from, To: to}
+}
+
+// Final reports whether no further status changes are allowed
+func Final(status db.LeaseStatus) bool {
+	next, known := transitions[status]
+	return known && len(next) == 0
+}
+
+// Reported is the status shown for a lease on the given day. An active lease is stored as active until the
+// daily job expires it, so one past its end date already reads as expired, and one ending within
+// ExpiresSoonDays as expires_soon.
+func Reported(status db.LeaseStatus, end, today time.Time) string {
+	if status != db.LeaseStatusActive {
+		return string(status)
+	}
+	if end.Before(today) {
+		return string(db.LeaseStatusExpired)
+	}
+	if end.Sub(today).Hours()/24 <= ExpiresSoonDays {
+		return StatusExpiresSoon
+	}
+	return string(db.LeaseStatusActive)
+}
@@ -0,0 +1,79 @@
+package leasestate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+)
+
+func TestCheck(t *testing.T) {
+	allowed := [][2]db.LeaseStatus{
+		{db.LeaseStatusDraft, db.LeaseStatusPendingApproval},
+		{db.LeaseStatusPendingApproval, db.LeaseStatusActive},
+		{db.LeaseStatusActive, db.LeaseStatusMonthToMonth},
+		{db.LeaseStatusActive, db.LeaseStatusExpired},
+		{db.LeaseStatusMonthToMonth, db.LeaseStatusTerminated},
+		{db.LeaseStatusExpired, db.LeaseStatusRenewed},
+		{db.LeaseStatusActive, db.LeaseStatusSuperseded},
+		{db.LeaseStatusMonthToMonth, db.LeaseStatusSuperseded},
+	}
+	for _, c := range allowed {
+		if err := Check(c[0], c[1]); err != nil {
+			t.Errorf("Check(%s, %s) = %v, want nil", c[0], c[1], err)
+		}
+	}
+
+	rejected := [][2]db.LeaseStatus{
+		{db.LeaseStatusTerminated, db.LeaseStatusActive},
+		{db.LeaseStatusCanceled, db.LeaseStatusPendingApproval},
+		{db.LeaseStatusExpired, db.LeaseStatusActive},
+		{db.LeaseStatusPendingApproval, db.LeaseStatusDraft},
+		{db.LeaseStatusDraft, db.LeaseStatusTerminated},
+		{db.LeaseStatusActive, db.LeaseStatusActive},
+		{db.LeaseStatusActive, db.LeaseStatusCanceled},
+		{db.LeaseStatusMonthToMonth, db.LeaseStatusCanceled},
+		{db.LeaseStatusDraft, db.LeaseStatusSuperseded},
+		{db.LeaseStatusSuperseded, db.LeaseStatusActive},
+	}
+	for _, c := range rejected {
+		var invalid *ErrInvalidTransition
+		if err := Check(c[0], c[1]); !errors.As(err, &invalid) {
+			t.Errorf("Check(%s, %s) = %v, want ErrInvalidTransition", c[0], c[1], err)
+		}
+	}
+}
+
+func TestFinal(t *testing.T) {
+	for _, s := range []db.LeaseStatus{db.LeaseStatusTerminated, db.LeaseStatusRenewed, db.LeaseStatusSuperseded, db.LeaseStatusCanceled} {
+		if !Final(s) {
+			t.Errorf("Final(%s) = false, want true", s)
+		}
+	}
+	for _, s := range []db.LeaseStatus{db.LeaseStatusDraft, db.LeaseStatusActive, db.LeaseStatusMonthToMonth, "bogus"} {
+		if Final(s) {
+			t.Errorf("Final(%s) = true, want false", s)
+		}
+	}
+}
+
+func TestReported(t *testing.T) {
+	today := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		status db.LeaseStatus
+		end    time.Time
+		want   string
+	}{
+		{db.LeaseStatusActive, today.AddDate(1, 0, 0), "active"},
+		{db.LeaseStatusActive, today.AddDate(0, 0, 30), StatusExpiresSoon},
+		{db.LeaseStatusActive, today.AddDate(0, 0, -1), "expired"},
+		{db.LeaseStatusMonthToMonth, today.AddDate(0, 0, -90), "month_to_month"},
+		{db.LeaseStatusTerminated, today.AddDate(1, 0, 0), "terminated"},
+	}
+	for _, c := range cases {
+		if got := Reported(c.status, c.end, today); got != c.want {
+			t.Errorf("Reported(%s, %s) = %s, want %s", c.status, c.end.Format("2006-01-02"), got, c.want)
+		}
+	}
+}
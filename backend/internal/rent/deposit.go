@@ -0,0 +1,13 @@
+package rent
+
+import "time"
+
+// DepositInterest returns the simple interest owed on a deposit held from received to returned at an
+// annual percentage rate
+func DepositInterest(amount, annualPercent float64, received, returned time.Time) float64 {
+	if annualPercent <= 0 || !returned.After(received) {
+		return 0
+	}
+	days := day(returned).Sub(day(received)).Hours() / 24
+	return roundCents(amount * annualPercent / 100 * days / 365)
+}
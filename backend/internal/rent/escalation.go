@@ -0,0 +1,130 @@
+package rent
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Schedule describes how rent steps up over a lease. Either Percent compounds every IntervalMonths
+// from the lease start, or Steps set a fixed rent from a given lease month onward. A zero Schedule
+// keeps the base rent for the whole lease.
+type Schedule struct {
+	Percent        float64 `json:"percent,omitempty"`
+	IntervalMonths int     `json:"interval_months,omitempty"` // defaults to 12
+	Steps          []Step  `json:"steps,omitempty"`
+}
+
+// Step sets the rent from a 1-based lease month onward, e.g. month 13 is the first month of year two
+type Step struct {
+	Month  int     `json:"month"`
+	Amount float64 `json:"amount"`
+}
+
+// Period is a stretch of the lease billed at one rent amount
+type Period struct {
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Amount float64   `json:"amount"`
+}
+
+// IsZero reports whether the schedule leaves the rent unchanged
+func (s Schedule) IsZero() bool {
+	return s.Percent == 0 && len(s.Steps) == 0
+}
+
+// Validate checks that the schedule uses one kind of escalation and sensible values
+func (s Schedule) Validate() error {
+	if s.Percent != 0 && len(s.Steps) > 0 {
+		return errors.New("rent escalation can use a percent or fixed steps, not both")
+	}
+	if s.Percent < 0 || s.Percent > 100 {
+		return errors.New("rent escalation percent must be between 0 and 100")
+	}
+	if s.IntervalMonths < 0 {
+		return errors.New("rent escalation interval must be positive")
+	}
+	seen := map[int]bool{}
+	for _, step := range s.Steps {
+		if step.Month < 2 {
+			return fmt.Errorf("rent step month %d must be 2 or later", step.Month)
+		}
+		if step.Amount <= 0 {
+			return fmt.Errorf("rent step for month %d needs a positive amount", step.Month)
+		}
+		if seen[step.Month] {
+			return fmt.Errorf("rent step month %d is listed twice", step.Month)
+		}
+		seen[step.Month] = true
+	}
+	return nil
+}
+
+// LeaseMonth returns the 1-based month of a lease that the given date falls in
+func LeaseMonth(start, on time.Time) int {
+	months := (on.Year()-start.Year())*12 + int(on.Month()) - int(start.Month())
+	if on.Day() < start.Day() {
+		months--
+	}
+	if months < 0 {
+		return 1
+	}
+	return months + 1
+}
+
+// EffectiveRent returns the monthly rent in effect on a date for a lease starting on start
+func EffectiveRent(base float64, start time.Time, schedule Schedule, on time.Time) float64 {
+	month := LeaseMonth(start, on)
+	if schedule.Percent > 0 {
+		interval := schedule.IntervalMonths
+		if interval == 0 {
+			interval = 12
+		}
+		increases := (month - 1) / interval
+		return math.Round(base*math.Pow(1+schedule.Percent/100, float64(increases))*100) / 100
+	}
+
+	amount := base
+	best := 0
+	for _, step := range schedule.Steps {
+		if step.Month <= month && step.Month > best {
+			best = step.Month
+			amount = step.Amount
+		}
+	}
+	return amount
+}
+
+// Table lists the rent periods of a lease from start to end, one row per rent amount
+func Table(base float64, start, end time.Time, schedule Schedule) []Period {
+	changes := []int{1}
+	if schedule.Percent > 0 {
+		interval := schedule.IntervalMonths
+		if interval == 0 {
+			interval = 12
+		}
+		for m := 1 + interval; start.AddDate(0, m-1, 0).Before(end); m += interval {
+			changes = append(changes, m)
+		}
+	} else {
+		for _, step := range schedule.Steps {
+			if start.AddDate(0, step.Month-1, 0).Before(end) {
+				changes = append(changes, step.Month)
+			}
+		}
+		sort.Ints(changes)
+	}
+
+	periods := make([]Period, 0, len(changes))
+	for i, month := range changes {
+		from := start.AddDate(0, month-1, 0)
+		to := end
+		if i+1 < len(changes) {
+			to = start.AddDate(0, changes[i+1]-1, -1)
+		}
+		periods = append(periods, Period{From: from, To: to, Amount: EffectiveRent(base, start, schedule, from)})
+	}
+	return periods
+}
@@ -0,0 +1,10 @@
+package rent
+
+// HoldoverRent is the monthly rent once a lease goes month-to-month: the last rent charged plus the
+// holdover premium in percent
+func HoldoverRent(lastRent, premiumPercent float64) float64 {
+	if premiumPercent <= 0 {
+		return roundCents(lastRent)
+	}
+	return roundCents(lastRent * (1 + premiumPercent/100))
+}
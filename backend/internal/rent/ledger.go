@@ -0,0 +1,38 @@
+package rent
+
+import "math"
+
+// Posting is one ledger entry as it affects the balance. Charges add to what the tenant owes; payments
+// and credits take from it.
+type Posting struct {
+	Type   string // charge, payment or credit
+	Amount float64
+}
+
+// Totals is what a run of postings adds up to
+type Totals struct {
+	Charges float64 `json:"total_charges"`
+	Paid    float64 `json:"total_paid"`
+	Balance float64 `json:"balance"`
+}
+
+// Post applies postings in order and returns the totals with the running balance after each one. Sums are
+// kept in whole cents so a long ledger does not drift.
+func Post(postings []Posting) (Totals, []float64) {
+	var charges, paid int64
+	running := make([]float64, len(postings))
+	for i, p := range postings {
+		cents := int64(math.Round(p.Amount * 100))
+		if p.Type == "charge" {
+			charges += cents
+		} else {
+			paid += cents
+		}
+		running[i] = float64(charges-paid) / 100
+	}
+	return Totals{
+		Charges: float64(charges) / 100,
+		Paid:    float64(paid) / 100,
+		Balance: float64(charges-paid) / 100,
+	}, running
+}
@@ -0,0 +1,21 @@
+package rent
+
+import "time"
+
+// VacateDate returns the day a tenant can move out after giving notice on noticeDate. That is the requested
+// date, or the end of the notice period when the request is sooner, but never past the lease end date. Early
+// reports whether the move-out cuts the lease short. A zero leaseEnd is a month-to-month tenancy, which has
+// no end date to cut short.
+func VacateDate(noticeDate, requested, leaseEnd time.Time, noticeDays int) (moveOut time.Time, early bool) {
+	moveOut = day(noticeDate).AddDate(0, 0, noticeDays)
+	if !requested.IsZero() && day(requested).After(moveOut) {
+		moveOut = day(requested)
+	}
+	if leaseEnd.IsZero() {
+		return moveOut, false
+	}
+	if end := day(leaseEnd); !moveOut.Before(end) {
+		return end, false
+	}
+	return moveOut, true
+}
@@ -0,0 +1,114 @@
+package rent
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ProrationMethod is how the daily rate of a partial month is worked out
+type ProrationMethod string
+
+const (
+	// ActualDays divides the monthly rent by the number of days in that calendar month
+	ActualDays ProrationMethod = "actual_days"
+	// ThirtyDayMonth treats every month as 30 days, so a day costs the same all year
+	ThirtyDayMonth ProrationMethod = "thirty_day"
+)
+
+// ParseProrationMethod checks a method name, defaulting to actual days when empty
+func ParseProrationMethod(s string) (ProrationMethod, error) {
+	switch ProrationMethod(s) {
+	case "":
+		return ActualDays, nil
+	case ActualDays, ThirtyDayMonth:
+		return ProrationMethod(s), nil
+	}
+	return "", fmt.Errorf("unknown proration method %q, use %s or %s", s, ActualDays, ThirtyDayMonth)
+}
+
+// Proration is the rent owed for part of one calendar month
+type Proration struct {
+	Method      ProrationMethod `json:"method"`
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	Days        int             `json:"days"`
+	DaysInMonth int             `json:"days_in_month"`
+	MonthlyRent float64         `json:"monthly_rent"`
+	DailyRate   float64         `json:"daily_rate"`
+	Amount      float64         `json:"amount"`
+}
+
+// Prorate returns the rent for the days from through to, inclusive. Both dates must fall in the same
+// calendar month; a span covering the whole month is charged the full monthly rent.
+func Prorate(monthly float64, from, to time.Time, method ProrationMethod) Proration {
+	from, to = day(from), day(to)
+	p := Proration{Method: method, From: from, To: to, MonthlyRent: monthly}
+
+	switch method {
+	case ThirtyDayMonth:
+		p.DaysInMonth = 30
+		p.Days = thirtyDayOf(to) - thirtyDayOf(from) + 1
+	default:
+		p.Method = ActualDays
+		p.DaysInMonth = DaysInMonth(from)
+		p.Days = int(to.Sub(from).Hours()/24) + 1
+	}
+	if p.Days < 0 {
+		p.Days = 0
+	}
+	if p.Days > p.DaysInMonth {
+		p.Days = p.DaysInMonth
+	}
+
+	p.DailyRate = roundCents(monthly / float64(p.DaysInMonth))
+	p.Amount = roundCents(monthly * float64(p.Days) / float64(p.DaysInMonth))
+	return p
+}
+
+// MoveIn prorates the first month of a lease that starts after the 1st. It reports false when the lease
+// starts on the 1st and the first month is billed in full.
+func MoveIn(monthly float64, start time.Time, method ProrationMethod) (Proration, bool) {
+	if start.Day() == 1 {
+		return Proration{}, false
+	}
+	return Prorate(monthly, start, lastOfMonth(start), method), true
+}
+
+// MoveOut prorates the last month of a lease, or the month of an early termination, ending on end. It
+// reports false when end is the last day of its month and that month is billed in full.
+func MoveOut(monthly float64, end time.Time, method ProrationMethod) (Proration, bool) {
+	if day(end).Equal(lastOfMonth(end)) {
+		return Proration{}, false
+	}
+	return Prorate(monthly, firstOfMonth(end), end, method), true
+}
+
+// DaysInMonth returns the number of days in the calendar month of t
+func DaysInMonth(t time.Time) int {
+	return lastOfMonth(t).Day()
+}
+
+// thirtyDayOf maps a date onto a 30-day month, so the 31st and the last day of February both count as day 30
+func thirtyDayOf(t time.Time) int {
+	if t.Day() >= 30 || day(t).Equal(lastOfMonth(t)) {
+		return 30
+	}
+	return t.Day()
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func lastOfMonth(t time.Time) time.Time {
+	return firstOfMonth(t).AddDate(0, 1, -1)
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,178 @@
+package rent
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEffectiveRentCompoundsPercentYearly(t *testing.T) {
+	start := date(2025, time.March, 15)
+	schedule := Schedule{Percent: 3}
+
+	cases := []struct {
+		on   time.Time
+		want float64
+	}{
+		{date(2025, time.March, 15), 1000},
+		{date(2026, time.March, 14), 1000},
+		{date(2026, time.March, 15), 1030},
+		{date(2027, time.April, 1), 1060.90},
+	}
+	for _, c := range cases {
+		if got := EffectiveRent(1000, start, schedule, c.on); got != c.want {
+			t.Errorf("EffectiveRent on %s = %.2f, want %.2f", c.on.Format("2006-01-02"), got, c.want)
+		}
+	}
+}
+
+func TestTableUsesFixedSteps(t *testing.T) {
+	start := date(2025, time.January, 1)
+	end := date(2026, time.December, 31)
+	schedule := Schedule{Steps: []Step{{Month: 13, Amount: 1550}}}
+	if err := schedule.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	table := Table(1500, start, end, schedule)
+	if len(table) != 2 {
+		t.Fatalf("got %d periods, want 2: %+v", len(table), table)
+	}
+	if !table[0].To.Equal(date(2025, time.December, 31)) || table[0].Amount != 1500 {
+		t.Errorf("first period = %+v", table[0])
+	}
+	if !table[1].From.Equal(date(2026, time.January, 1)) || table[1].Amount != 1550 {
+		t.Errorf("second period = %+v", table[1])
+	}
+}
+
+func TestMoveInProration(t *testing.T) {
+	cases := []struct {
+		start  time.Time
+		method ProrationMethod
+		days   int
+		want   float64
+	}{
+		{date(2025, time.March, 15), ActualDays, 17, 850},
+		{date(2025, time.March, 15), ThirtyDayMonth, 16, 826.67},
+		{date(2025, time.February, 15), ActualDays, 14, 775},
+		{date(2025, time.February, 15), ThirtyDayMonth, 16, 826.67},
+	}
+	for _, c := range cases {
+		p, ok := MoveIn(1550, c.start, c.method)
+		if !ok {
+			t.Fatalf("MoveIn(%s, %s) was not prorated", c.start.Format("2006-01-02"), c.method)
+		}
+		if p.Days != c.days || p.Amount != c.want {
+			t.Errorf("MoveIn(%s, %s) = %d days / %.2f, want %d days / %.2f",
+				c.start.Format("2006-01-02"), c.method, p.Days, p.Amount, c.days, c.want)
+		}
+	}
+
+	if _, ok := MoveIn(1550, date(2025, time.March, 1), ActualDays); ok {
+		t.Error("a lease starting on the 1st should not be prorated")
+	}
+}
+
+func TestMoveOutProration(t *testing.T) {
+	p, ok := MoveOut(1500, date(2025, time.April, 10), ActualDays)
+	if !ok || p.Days != 10 || p.Amount != 500 {
+		t.Errorf("MoveOut on April 10 = %+v (prorated %v), want 10 days / 500.00", p, ok)
+	}
+	if _, ok := MoveOut(1500, date(2025, time.March, 31), ThirtyDayMonth); ok {
+		t.Error("a lease ending on the last day of the month should not be prorated")
+	}
+}
+
+func TestVacateDate(t *testing.T) {
+	leaseEnd := date(2025, time.December, 31)
+	cases := []struct {
+		requested time.Time
+		want      time.Time
+		early     bool
+	}{
+		{time.Time{}, date(2025, time.May, 1), true},
+		{date(2025, time.April, 15), date(2025, time.May, 1), true},
+		{date(2025, time.June, 30), date(2025, time.June, 30), true},
+		{date(2026, time.March, 1), leaseEnd, false},
+	}
+	for _, c := range cases {
+		got, early := VacateDate(date(2025, time.April, 1), c.requested, leaseEnd, 30)
+		if !got.Equal(c.want) || early != c.early {
+			t.Errorf("VacateDate(requested %s) = %s (early %v), want %s (early %v)",
+				c.requested.Format("2006-01-02"), got.Format("2006-01-02"), early, c.want.Format("2006-01-02"), c.early)
+		}
+	}
+
+	if got, early := VacateDate(date(2026, time.March, 1), time.Time{}, time.Time{}, 30); !got.Equal(date(2026, time.March, 31)) || early {
+		t.Errorf("month-to-month VacateDate = %s (early %v), want 2026-03-31 and not early", got.Format("2006-01-02"), early)
+	}
+}
+
+func TestHoldoverRent(t *testing.T) {
+	if got := HoldoverRent(1545, 10); got != 1699.5 {
+		t.Errorf("HoldoverRent(1545, 10) = %.2f, want 1699.50", got)
+	}
+	if got := HoldoverRent(1545, 0); got != 1545 {
+		t.Errorf("HoldoverRent without a premium = %.2f, want 1545.00", got)
+	}
+}
+
+func TestPostLedger(t *testing.T) {
+	cases := []struct {
+		name     string
+		postings []Posting
+		want     Totals
+		running  []float64
+	}{
+		{"empty ledger", nil, Totals{}, []float64{}},
+		{
+			"charge then full payment",
+			[]Posting{{"charge", 1500}, {"payment", 1500}},
+			Totals{Charges: 1500, Paid: 1500, Balance: 0},
+			[]float64{1500, 0},
+		},
+		{
+			"partial payment and a credit",
+			[]Posting{{"charge", 1500}, {"payment", 1000}, {"credit", 125.50}},
+			Totals{Charges: 1500, Paid: 1125.50, Balance: 374.50},
+			[]float64{1500, 500, 374.50},
+		},
+		{
+			"overpayment leaves a credit balance",
+			[]Posting{{"payment", 200}, {"charge", 150}},
+			Totals{Charges: 150, Paid: 200, Balance: -50},
+			[]float64{-200, -50},
+		},
+		{
+			"cents do not drift",
+			[]Posting{{"charge", 0.10}, {"charge", 0.20}, {"payment", 0.30}},
+			Totals{Charges: 0.30, Paid: 0.30, Balance: 0},
+			[]float64{0.10, 0.30, 0},
+		},
+		{
+			"prorated move-in month then a full month",
+			[]Posting{{"charge", 850}, {"charge", 1550}, {"payment", 2400}},
+			Totals{Charges: 2400, Paid: 2400, Balance: 0},
+			[]float64{850, 2400, 0},
+		},
+	}
+	for _, c := range cases {
+		got, running := Post(c.postings)
+		if got != c.want {
+			t.Errorf("%s: totals = %+v, want %+v", c.name, got, c.want)
+		}
+		if len(running) != len(c.running) {
+			t.Errorf("%s: %d running balances, want %d", c.name, len(running), len(c.running))
+			continue
+		}
+		for i := range running {
+			if running[i] != c.running[i] {
+				t.Errorf("%s: balance after entry %d = %.2f, want %.2f", c.name, i, running[i], c.running[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package screening
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MockProvider screens applicants in process with fixed results, for development and tests. The results only
+// depend on the applicant, so screening the same applicant twice gives the same report. A tag after a plus
+// sign in the email picks a fixture:
+//
+//	+lowcredit    credit score below 580, fails the credit check
+//	+criminal     a criminal record, the criminal check needs review
+//	+eviction     an eviction judgment, fails the eviction check
+//	+unavailable  the provider is unavailable
+//
+// Any other applicant passes with a credit score between 650 and 800. Income under three times the rent
+// turns a passing credit check into one that needs review.
+type MockProvider struct {
+	// Now stamps reports; time.Now when nil
+	Now func() time.Time
+}
+
+var _ Provider = (*MockProvider)(nil)
+
+func (p *MockProvider) Name() string {
+	return "mock"
+}
+
+func (p *MockProvider) Screen(ctx context.Context, applicant Applicant, checks []Check) (Report, error) {
+	if err := ctx.Err(); err != nil {
+		return Report{}, err
+	}
+	email := strings.ToLower(strings.TrimSpace(applicant.Email))
+	tags := mockTags(email)
+	if tags["unavailable"] {
+		return Report{}, fmt.Errorf("%w: mock fixture for %s", ErrUnavailable, email)
+	}
+
+	sum := sha256.Sum256([]byte(email + "|" + strings.ToLower(applicant.FirstName+" "+applicant.LastName)))
+	report := Report{
+		Provider:  p.Name(),
+		Reference: "mock-" + hex.EncodeToString(sum[:6]),
+	}
+	recommendations := make([]Recommendation, 0, len(checks))
+	for _, c := range checks {
+		var result Result
+		switch c {
+		case CheckCredit:
+			result = mockCredit(applicant, tags, binary.BigEndian.Uint16(sum[6:8]))
+		case CheckCriminal:
+			result = Result{Check: CheckCriminal, Recommendation: Pass, Summary: "No criminal records found"}
+			if tags["criminal"] {
+				result.Recommendation = Review
+				result.Summary = "1 criminal record found"
+				result.Records = []Record{{Date: "2016-04-12", Jurisdiction: "Travis County, TX", Description: "Misdemeanor theft, convicted"}}
+			}
+		case CheckEviction:
+			result = Result{Check: CheckEviction, Recommendation: Pass, Summary: "No eviction records found"}
+			if tags["eviction"] {
+				result.Recommendation = Fail
+				result.Summary = "1 eviction judgment found"
+				result.Records = []Record{{Date: "2021-09-30", Jurisdiction: "Cook County, IL", Description: "Eviction judgment for nonpayment of rent"}}
+			}
+		default:
+			return Report{}, fmt.Errorf("unknown screening check %q", c)
+		}
+		report.Results = append(report.Results, result)
+		recommendations = append(recommendations, result.Recommendation)
+	}
+	report.Recommendation = Worst(recommendations...)
+
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	report.CompletedAt = now().UTC().Truncate(time.Second)
+	return report, nil
+}
+
+func mockCredit(applicant Applicant, tags map[string]bool, seed uint16) Result {
+	result := Result{Check: CheckCredit, Recommendation: Pass, CreditScore: 650 + int(seed%151)}
+	if tags["lowcredit"] {
+		result.CreditScore = 540 + int(seed%40)
+		result.Recommendation = Fail
+	}
+	result.Summary = fmt.Sprintf("Credit score %d", result.CreditScore)
+	if applicant.MonthlyRent > 0 && applicant.MonthlyIncome < 3*applicant.MonthlyRent {
+		result.Recommendation = Worst(result.Recommendation, Review)
+		result.Summary += fmt.Sprintf(", income is %.1f times the rent", applicant.MonthlyIncome/applicant.MonthlyRent)
+	}
+	return result
+}
+
+// mockTags are the plus tags in the local part of an email, like lowcredit in jo+lowcredit@example.com
+func mockTags(email string) map[string]bool {
+	tags := map[string]bool{}
+	local, _, _ := strings.Cut(email, "@")
+	parts := strings.Split(local, "+")
+	for _, tag := range parts[1:] {
+		tags[tag] = true
+	}
+	return tags
+}
@@ -0,0 +1,127 @@
+// Package screening runs credit, criminal and eviction history checks on rental applicants through a
+// screening provider, and sums the results up as a recommendation for the admin deciding the application.
+package screening
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Check is one kind of background check
+type Check string
+
+const (
+	CheckCredit   Check = "credit"
+	CheckCriminal Check = "criminal"
+	CheckEviction Check = "eviction"
+)
+
+// AllChecks are the checks an applicant has to pass before they can be approved
+var AllChecks = []Check{CheckCredit, CheckCriminal, CheckEviction}
+
+// Recommendation is what a provider suggests doing with an applicant, from best to worst
+type Recommendation string
+
+const (
+	Pass   Recommendation = "pass"
+	Review Recommendation = "review"
+	Fail   Recommendation = "fail"
+)
+
+var severity = map[Recommendation]int{Pass: 0, Review: 1, Fail: 2}
+
+// Worst returns the least favourable of the recommendations, or Pass when there are none
+func Worst(recommendations ...Recommendation) Recommendation {
+	worst := Pass
+	for _, r := range recommendations {
+		if severity[r] > severity[worst] {
+			worst = r
+		}
+	}
+	return worst
+}
+
+// ErrUnavailable is returned when the provider cannot be reached or refuses the request; screening can be
+// retried later
+var ErrUnavailable = errors.New("screening: provider unavailable")
+
+// Applicant is who is screened. Identity details beyond these, like a social security number, are collected
+// by the provider from the applicant directly and never pass through us.
+type Applicant struct {
+	FirstName     string
+	LastName      string
+	Email         string
+	Phone         string
+	MonthlyIncome float64 // Household income, for the rent to income ratio
+	MonthlyRent   float64
+}
+
+// Record is one entry found by a criminal or eviction check
+type Record struct {
+	Date         string `json:"date"`
+	Jurisdiction string `json:"jurisdiction"`
+	Description  string `json:"description"`
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Check          Check          `json:"check"`
+	Recommendation Recommendation `json:"recommendation"`
+	Summary        string         `json:"summary"`
+	CreditScore    int            `json:"credit_score,omitempty"`
+	Records        []Record       `json:"records,omitempty"`
+}
+
+// Report is everything a provider returned for one screening
+type Report struct {
+	Provider       string         `json:"provider"`
+	Reference      string         `json:"reference"` // The provider's ID for the report
+	Recommendation Recommendation `json:"recommendation"`
+	Results        []Result       `json:"results"`
+	CompletedAt    time.Time      `json:"completed_at"`
+}
+
+// Provider screens applicants
+type Provider interface {
+	// Name identifies the provider in stored reports and adverse action notices
+	Name() string
+	Screen(ctx context.Context, applicant Applicant, checks []Check) (Report, error)
+}
+
+// ParseChecks checks the names of the requested checks; none means all of them
+func ParseChecks(names []string) ([]Check, error) {
+	if len(names) == 0 {
+		return AllChecks, nil
+	}
+	checks := make([]Check, 0, len(names))
+	seen := map[Check]bool{}
+	for _, name := range names {
+		c := Check(name)
+		switch c {
+		case CheckCredit, CheckCriminal, CheckEviction:
+		default:
+			return nil, fmt.Errorf("unknown screening check %q", name)
+		}
+		if !seen[c] {
+			seen[c] = true
+			checks = append(checks, c)
+		}
+	}
+	return checks, nil
+}
+
+// Covers reports whether the report has a result for every check in AllChecks
+func (r Report) Covers() bool {
+	done := map[Check]bool{}
+	for _, result := range r.Results {
+		done[result.Check] = true
+	}
+	for _, c := range AllChecks {
+		if !done[c] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package screening
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWorst(t *testing.T) {
+	cases := []struct {
+		in   []Recommendation
+		want Recommendation
+	}{
+		{nil, Pass},
+		{[]Recommendation{Pass, Pass}, Pass},
+		{[]Recommendation{Pass, Review}, Review},
+		{[]Recommendation{Fail, Review, Pass}, Fail},
+	}
+	for _, c := range cases {
+		if got := Worst(c.in...); got != c.want {
+			t.Errorf("Worst(%v) = %s, want %s", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseChecks(t *testing.T) {
+	checks, err := ParseChecks(nil)
+	if err != nil || !reflect.DeepEqual(checks, AllChecks) {
+		t.Errorf("ParseChecks(nil) = %v, %v, want all checks", checks, err)
+	}
+	checks, err = ParseChecks([]string{"eviction", "credit", "eviction"})
+	if err != nil || !reflect.DeepEqual(checks, []Check{CheckEviction, CheckCredit}) {
+		t.Errorf("ParseChecks = %v, %v, want [eviction credit]", checks, err)
+	}
+	if _, err := ParseChecks([]string{"horoscope"}); err == nil {
+		t.Error("ParseChecks accepted an unknown check")
+	}
+}
+
+func TestMockProviderIsDeterministic(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	p := &MockProvider{Now: func() time.Time { return now }}
+	applicant := Applicant{FirstName: "Jo", LastName: "Doe", Email: "jo@example.com", MonthlyIncome: 6000, MonthlyRent: 1500}
+
+	first, err := p.Screen(context.Background(), applicant, AllChecks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := p.Screen(context.Background(), applicant, AllChecks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("screening the same applicant twice differed:\n%+v\n%+v", first, second)
+	}
+	if first.Recommendation != Pass || !first.Covers() {
+		t.Errorf("clean applicant got %s, covers all checks %v", first.Recommendation, first.Covers())
+	}
+	if score := first.Results[0].CreditScore; score < 650 || score > 800 {
+		t.Errorf("credit score %d outside 650-800", score)
+	}
+}
+
+func TestMockProviderFixtures(t *testing.T) {
+	p := &MockProvider{}
+	ctx := context.Background()
+	cases := []struct {
+		email string
+		check Check
+		want  Recommendation
+	}{
+		{"jo+lowcredit@example.com", CheckCredit, Fail},
+		{"jo+criminal@example.com", CheckCriminal, Review},
+		{"jo+eviction@example.com", CheckEviction, Fail},
+	}
+	for _, c := range cases {
+		report, err := p.Screen(ctx, Applicant{Email: c.email, MonthlyIncome: 9000, MonthlyRent: 1000}, AllChecks)
+		if err != nil {
+			t.Fatalf("%s: %v", c.email, err)
+		}
+		for _, result := range report.Results {
+			want := Pass
+			if result.Check == c.check {
+				want = c.want
+			}
+			if result.Recommendation != want {
+				t.Errorf("%s: %s check = %s, want %s", c.email, result.Check, result.Recommendation, want)
+			}
+		}
+		if report.Recommendation != c.want {
+			t.Errorf("%s: report = %s, want %s", c.email, report.Recommendation, c.want)
+		}
+	}
+
+	report, err := p.Screen(ctx, Applicant{Email: "jo@example.com", MonthlyIncome: 2000, MonthlyRent: 1000}, []Check{CheckCredit})
+	if err != nil || report.Recommendation != Review || report.Covers() {
+		t.Errorf("low income credit check = %+v, %v, want review covering only credit", report, err)
+	}
+
+	if _, err := p.Screen(ctx, Applicant{Email: "jo+unavailable@example.com"}, AllChecks); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("unavailable fixture = %v, want ErrUnavailable", err)
+	}
+}
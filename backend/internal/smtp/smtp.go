@@ -1,10 +1,12 @@
 package smtp
 
 import (
+	"encoding/base64"
 	"fmt"
 	"log"
 	"net/smtp"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -136,3 +138,64 @@ func SendEmailHTML(to string, subject string, textBody string, htmlBody string)
 
 	return fmt.Errorf("Failed to send HTML email to %s after %d attempts: %v", to, maxRetries, sendMailErr)
 }
+
+// SendEmailWithAttachment sends a plain text email with one file attached, such as a generated PDF
+func SendEmailWithAttachment(to string, subject string, body string, filename string, contentType string, attachment []byte) error {
+	smtpConfig, err := LoadSMTPConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load SMTP config: %v", err)
+	}
+
+	from := os.Getenv("SMTP_FROM")
+	boundary := "NextPart_" + fmt.Sprintf("%d", time.Now().UnixNano())
+
+	// Base64 lines may not be longer than 76 characters
+	encoded := base64.StdEncoding.EncodeToString(attachment)
+	var wrapped strings.Builder
+	for len(encoded) > 76 {
+		wrapped.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	wrapped.WriteString(encoded)
+
+	msg := []byte("From: " + from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"" + boundary + "\"\r\n" +
+		"\r\n" +
+		"--" + boundary + "\r\n" +
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n" +
+		"Content-Transfer-Encoding: 7bit\r\n" +
+		"\r\n" +
+		body + "\r\n" +
+		"\r\n" +
+		"--" + boundary + "\r\n" +
+		"Content-Type: " + contentType + "; name=\"" + filename + "\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"Content-Disposition: attachment; filename=\"" + filename + "\"\r\n" +
+		"\r\n" +
+		wrapped.String() + "\r\n" +
+		"\r\n" +
+		"--" + boundary + "--\r\n")
+
+	addr := fmt.Sprintf("%s:%s", smtpConfig.Host, smtpConfig.Port)
+	auth := smtp.PlainAuth("", smtpConfig.User, smtpConfig.Password, smtpConfig.Host)
+
+	var sendMailErr error
+	maxRetries := 3
+	for i := 0; i < maxRetries; i++ {
+		sendMailErr = smtp.SendMail(addr, auth, from, []string{to}, msg)
+		if sendMailErr == nil {
+			log.Printf("Sent email with attachment %s to %s", filename, to)
+			return nil
+		}
+
+		log.Printf("Attempt %d: Failed to send email with attachment to %s: %v", i+1, to, sendMailErr)
+
+		waitTime := (1 << i) * 500
+		time.Sleep(time.Duration(waitTime) * time.Millisecond)
+	}
+
+	return fmt.Errorf("Failed to send email with attachment to %s after %d attempts: %v", to, maxRetries, sendMailErr)
+}
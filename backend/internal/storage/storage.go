@@ -0,0 +1,37 @@
+// Package storage keeps generated and signed documents in a blob store and hands out short-lived signed
+// URLs for them, so downloads never depend on the e-sign provider still having a copy.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when no object is stored under a key
+	ErrNotFound = errors.New("storage: object not found")
+	// ErrInvalidKey is returned for keys that are empty or try to leave the store
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// Store holds documents by key. Keys are slash separated, like leases/12/signed.pdf.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidateKey rejects keys that are empty, absolute or contain . or .. segments
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// DepositDeduction is one itemized charge against a security deposit
+type DepositDeduction struct {
+	Category    string  `json:"category"` // damages, unpaid_rent, cleaning or other
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
+// DepositDispositionData is everything printed on a move-out deposit statement
+type DepositDispositionData struct {
+	Landlord        LeaseParty
+	Tenants         []LeaseParty
+	PropertyAddress string
+	UnitNumber      string
+	ReceivedOn      time.Time
+	MoveOutDate     time.Time
+	StatementDate   time.Time
+	HoldingAccount  string
+	DepositAmount   float64
+	InterestAmount  float64
+	Deductions      []DepositDeduction
+	TotalDeductions float64
+	RefundAmount    float64 // negative when the tenant still owes after the deposit is used up
+}
+
+var depositCategoryLabels = map[string]string{
+	"damages":     "Damages",
+	"unpaid_rent": "Unpaid rent",
+	"cleaning":    "Cleaning",
+	"other":       "Other",
+}
+
+// RenderDepositDisposition produces the itemized statement of what was withheld from a security deposit
+// and what is refunded to the tenant
+func RenderDepositDisposition(data DepositDispositionData) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(10, 10, "SECURITY DEPOSIT DISPOSITION STATEMENT")
+	pdf.Ln(15)
+
+	statementDate := data.StatementDate
+	if statementDate.IsZero() {
+		statementDate = time.Now()
+	}
+	names := make([]string, 0, len(data.Tenants))
+	for _, t := range data.Tenants {
+		names = append(names, t.Name)
+	}
+	address := data.PropertyAddress
+	if data.UnitNumber != "" {
+		address = strings.TrimPrefix(fmt.Sprintf("%s, Unit %s", address, data.UnitNumber), ", ")
+	}
+
+	pdf.SetFont("Arial", "", 11)
+	for _, line := range [][2]string{
+		{"Statement date", statementDate.Format("January 2, 2006")},
+		{"Landlord", data.Landlord.Name},
+		{"Tenants", strings.Join(names, ", ")},
+		{"Premises", address},
+		{"Deposit received", data.ReceivedOn.Format("January 2, 2006")},
+		{"Move-out date", data.MoveOutDate.Format("January 2, 2006")},
+		{"Held in", data.HoldingAccount},
+	} {
+		if line[1] == "" {
+			continue
+		}
+		pdf.CellFormat(45, 7, line[0]+":", "", 0, "", false, 0, "")
+		pdf.MultiCell(0, 7, tr(line[1]), "", "", false)
+	}
+	pdf.Ln(5)
+
+	row := func(label string, amount float64, bold bool) {
+		style := ""
+		if bold {
+			style = "B"
+		}
+		pdf.SetFont("Arial", style, 11)
+		pdf.CellFormat(120, 7, tr(label), "1", 0, "", false, 0, "")
+		pdf.CellFormat(50, 7, fmt.Sprintf("$%.2f", amount), "1", 1, "R", false, 0, "")
+	}
+
+	row("Security deposit", data.DepositAmount, false)
+	if data.InterestAmount > 0 {
+		row("Interest on deposit", data.InterestAmount, false)
+	}
+	pdf.Ln(5)
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 10, "ITEMIZED DEDUCTIONS")
+	pdf.Ln(10)
+	if len(data.Deductions) == 0 {
+		pdf.SetFont("Arial", "", 11)
+		pdf.Cell(40, 7, "No deductions were made.")
+		pdf.Ln(10)
+	}
+	for _, d := range data.Deductions {
+		label := depositCategoryLabels[d.Category]
+		if label == "" {
+			label = d.Category
+		}
+		if d.Description != "" {
+			label = fmt.Sprintf("%s - %s", label, d.Description)
+		}
+		row(label, d.Amount, false)
+	}
+	if len(data.Deductions) > 0 {
+		row("Total deductions", data.TotalDeductions, true)
+	}
+	pdf.Ln(5)
+
+	if data.RefundAmount >= 0 {
+		row("Amount refunded to tenant", data.RefundAmount, true)
+	} else {
+		row("Balance owed by tenant", -data.RefundAmount, true)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate deposit statement PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
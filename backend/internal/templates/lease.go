@@ -0,0 +1,417 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/careecodes/RentDaddy/internal/rent"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// DefaultLeaseTemplateName is used when a lease does not choose a template
+const DefaultLeaseTemplateName = "standard"
+
+// LeaseTemplateContent is the stored body of one lease template version. Clauses are rendered in
+// order and may use the placeholders in LeasePlaceholders, e.g. {{tenant_names}}.
+type LeaseTemplateContent struct {
+	Title   string        `json:"title"`
+	Clauses []LeaseClause `json:"clauses"`
+	// Guaranty is the text of the addendum page each guarantor signs. It may also use
+	// {{guarantor_name}} and {{guarantor_relationship}}.
+	Guaranty string `json:"guaranty,omitempty"`
+}
+
+// LeaseClause is a named block of the lease. A clause whose body renders empty is left out.
+type LeaseClause struct {
+	Name    string `json:"name"`
+	Heading string `json:"heading,omitempty"`
+	Body    string `json:"body"`
+}
+
+// LeaseParty is a signer of the lease
+type LeaseParty struct {
+	Name  string
+	Email string
+}
+
+// LeaseGuarantor is a guarantor who signs the guaranty addendum
+type LeaseGuarantor struct {
+	Name         string
+	Email        string
+	Relationship string
+}
+
+// LeaseAddendum is an addendum page appended to the lease, e.g. a pet or parking addendum.
+// The body may use the same placeholders as lease clauses.
+type LeaseAddendum struct {
+	Title string
+	Body  string
+}
+
+// LeaseData fills the placeholders of a lease template
+type LeaseData struct {
+	Title           string // overrides the template title when set
+	AgreementDate   time.Time
+	Landlord        LeaseParty // who signs for the landlord
+	LandlordName    string     // legal name of the landlord; Landlord.Name when empty
+	LandlordAddress string
+	LandlordLicense string
+	Tenants         []LeaseParty
+	Guarantors      []LeaseGuarantor
+	PropertyAddress string
+	UnitNumber      string
+	RentAmount      float64
+	StartDate       time.Time
+	EndDate         time.Time
+	BuildingRules   string
+	Addenda         []LeaseAddendum
+	RentSchedule    []rent.Period   // printed as a rent table when the rent steps up during the lease
+	MoveIn          *rent.Proration // partial first month, nil when the lease starts on the 1st
+	MoveOut         *rent.Proration // partial last month, nil when the lease ends on a month end
+}
+
+// SignatureField is where a signer fills in a signature or date on the rendered lease.
+// Positions are percentages of the page, which is how the e-sign provider places fields.
+type SignatureField struct {
+	Email  string
+	Type   string // SIGNATURE or DATE
+	Page   int
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// LeasePlaceholders lists every placeholder a lease clause may use
+var LeasePlaceholders = []string{
+	"agreement_date",
+	"landlord_name",
+	"landlord_address",
+	"landlord_license_number",
+	"tenant_names",
+	"property_address",
+	"unit_number",
+	"rent_amount",
+	"start_date",
+	"end_date",
+	"building_rules",
+}
+
+var guarantyPlaceholders = []string{"guarantor_name", "guarantor_relationship"}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]+)\s*\}\}`)
+
+// DefaultLeaseTemplate is the first version of the standard lease, created when no template exists yet
+func DefaultLeaseTemplate() LeaseTemplateContent {
+	return LeaseTemplateContent{
+		Title: "Residential Lease Agreement",
+		Clauses: []LeaseClause{
+			{Name: "preamble", Body: "This Lease Agreement is entered into on {{agreement_date}}."},
+			{Name: "landlord", Heading: "LANDLORD", Body: "{{landlord_name}}\n{{landlord_address}}"},
+			{Name: "tenants", Heading: "TENANTS", Body: "{{tenant_names}}"},
+			{Name: "property", Heading: "PROPERTY", Body: "{{property_address}}"},
+			{Name: "term", Heading: "LEASE TERM", Body: "Fixed Lease: From {{start_date}} To {{end_date}}"},
+			{Name: "rent", Heading: "RENT", Body: "Monthly Rent: {{rent_amount}}"},
+			{Name: "basic_terms", Heading: "BASIC TERMS", Body: "1. Tenant shall maintain the Property in good condition.\n" +
+				"2. Rent is due on the 1st of each month.\n" +
+				"3. A security deposit equal to one month's rent is required.\n" +
+				"4. Tenant shall not disturb neighbors.\n" +
+				"5. Landlord may enter with 24 hours notice for inspections or repairs."},
+			{Name: "building_rules", Heading: "BUILDING RULES", Body: "{{building_rules}}"},
+		},
+		Guaranty: "This Guaranty Addendum is part of the lease for {{property_address}} between {{landlord_name}} (Landlord) and {{tenant_names}} (Tenant).\n\n" +
+			"In consideration of the Landlord entering into the lease, {{guarantor_name}} ({{guarantor_relationship}} of the Tenant) " +
+			"unconditionally guarantees the full and timely payment of the monthly rent of {{rent_amount}} and every other " +
+			"obligation of the Tenant under the lease for the term from {{start_date}} to {{end_date}}, including any amount owed when the Tenant moves out.\n\n" +
+			"The Landlord may pursue the Guarantor without first pursuing the Tenant. " +
+			"This guaranty is not affected by any change in the number of tenants on the lease.",
+	}
+}
+
+// ParseLeaseTemplate decodes and validates stored template content
+func ParseLeaseTemplate(raw []byte) (LeaseTemplateContent, error) {
+	var content LeaseTemplateContent
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return content, fmt.Errorf("invalid lease template: %w", err)
+	}
+	return content, content.Validate()
+}
+
+// Validate checks that clause names are present and unique and that only known placeholders are used
+func (c LeaseTemplateContent) Validate() error {
+	if len(c.Clauses) == 0 {
+		return fmt.Errorf("lease template needs at least one clause")
+	}
+
+	known := map[string]bool{}
+	for _, p := range LeasePlaceholders {
+		known[p] = true
+	}
+	names := map[string]bool{}
+	for i, clause := range c.Clauses {
+		if clause.Name == "" {
+			return fmt.Errorf("clause %d has no name", i+1)
+		}
+		if names[clause.Name] {
+			return fmt.Errorf("clause name %q is used twice", clause.Name)
+		}
+		names[clause.Name] = true
+		if err := checkPlaceholders(clause.Heading+clause.Body, known); err != nil {
+			return fmt.Errorf("clause %q: %w", clause.Name, err)
+		}
+	}
+
+	for _, p := range guarantyPlaceholders {
+		known[p] = true
+	}
+	if err := checkPlaceholders(c.Guaranty, known); err != nil {
+		return fmt.Errorf("guaranty: %w", err)
+	}
+	return nil
+}
+
+// ValidateAddendum checks that an addendum body only uses known placeholders
+func ValidateAddendum(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("addendum body is required")
+	}
+	known := map[string]bool{}
+	for _, p := range LeasePlaceholders {
+		known[p] = true
+	}
+	return checkPlaceholders(body, known)
+}
+
+func checkPlaceholders(text string, known map[string]bool) error {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		if !known[match[1]] {
+			return fmt.Errorf("unknown placeholder {{%s}}", match[1])
+		}
+	}
+	return nil
+}
+
+func fillPlaceholders(text string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return values[placeholderPattern.FindStringSubmatch(match)[1]]
+	})
+}
+
+func (d LeaseData) placeholderValues() map[string]string {
+	names := make([]string, 0, len(d.Tenants))
+	for _, t := range d.Tenants {
+		names = append(names, t.Name)
+	}
+	agreementDate := d.AgreementDate
+	if agreementDate.IsZero() {
+		agreementDate = time.Now()
+	}
+	landlordName := d.LandlordName
+	if landlordName == "" {
+		landlordName = d.Landlord.Name
+	}
+	return map[string]string{
+		"agreement_date":          agreementDate.Format("January 2, 2006"),
+		"landlord_name":           landlordName,
+		"landlord_address":        d.LandlordAddress,
+		"landlord_license_number": d.LandlordLicense,
+		"tenant_names":            strings.Join(names, ", "),
+		"property_address":        d.PropertyAddress,
+		"unit_number":             d.UnitNumber,
+		"rent_amount":             fmt.Sprintf("$%.2f", d.RentAmount),
+		"start_date":              d.StartDate.Format("January 2, 2006"),
+		"end_date":                d.EndDate.Format("January 2, 2006"),
+		"building_rules":          d.BuildingRules,
+	}
+}
+
+// RenderLease produces the lease PDF from a template. The clauses are followed by the rent table when the
+// rent steps up during the lease, the prorated rent of partial months, and a signature block for the
+// landlord and each tenant, then one page per attached addendum signed by the same parties, then a
+// guaranty addendum page per guarantor. It returns where each signer's fields landed.
+func RenderLease(content LeaseTemplateContent, data LeaseData) ([]byte, []SignatureField, error) {
+	if err := content.Validate(); err != nil {
+		return nil, nil, err
+	}
+	values := data.placeholderValues()
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	title := data.Title
+	if title == "" {
+		title = content.Title
+	}
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(10, 10, tr(title))
+	pdf.Ln(15)
+
+	for _, clause := range content.Clauses {
+		body := strings.TrimSpace(fillPlaceholders(clause.Body, values))
+		if body == "" {
+			continue
+		}
+		if clause.Heading != "" {
+			pdf.SetFont("Arial", "B", 12)
+			pdf.Cell(40, 10, tr(fillPlaceholders(clause.Heading, values)))
+			pdf.Ln(10)
+		}
+		pdf.SetFont("Arial", "", 11)
+		pdf.MultiCell(0, 6, tr(body), "", "", false)
+		pdf.Ln(5)
+	}
+
+	if len(data.RentSchedule) > 1 {
+		drawRentTable(pdf, data.RentSchedule)
+	}
+	if data.MoveIn != nil || data.MoveOut != nil {
+		drawProration(pdf, tr, data.MoveIn, data.MoveOut)
+	}
+
+	// Signatures section
+	pdf.Ln(5)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 10, "SIGNATURES")
+	pdf.Ln(15)
+
+	// One block per signer, two to a row: landlord first, then each tenant
+	blocks := []signatureBlock{{"Landlord Signature:", data.Landlord}}
+	for _, tenant := range data.Tenants {
+		blocks = append(blocks, signatureBlock{"Tenant Signature:", tenant})
+	}
+	fields := drawSignatureRows(pdf, tr, blocks)
+
+	// Every attached addendum starts on its own page and is signed separately
+	for _, addendum := range data.Addenda {
+		pdf.AddPage()
+		pdf.SetFont("Arial", "B", 16)
+		pdf.Cell(10, 10, tr(strings.ToUpper(addendum.Title)))
+		pdf.Ln(15)
+		pdf.SetFont("Arial", "", 11)
+		pdf.MultiCell(0, 6, tr(fillPlaceholders(addendum.Body, values)), "", "", false)
+		pdf.Ln(15)
+		fields = append(fields, drawSignatureRows(pdf, tr, blocks)...)
+	}
+
+	// Each guarantor signs their own guaranty addendum page
+	for _, guarantor := range data.Guarantors {
+		values["guarantor_name"] = guarantor.Name
+		values["guarantor_relationship"] = guarantor.Relationship
+
+		pdf.AddPage()
+		pdf.SetFont("Arial", "B", 16)
+		pdf.Cell(10, 10, "GUARANTY ADDENDUM")
+		pdf.Ln(15)
+		pdf.SetFont("Arial", "", 11)
+		pdf.MultiCell(0, 6, tr(fillPlaceholders(content.Guaranty, values)), "", "", false)
+		pdf.Ln(15)
+
+		party := LeaseParty{Name: guarantor.Name, Email: guarantor.Email}
+		fields = append(fields, drawSignatureRows(pdf, tr, []signatureBlock{{"Guarantor Signature:", party}})...)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate lease PDF: %w", err)
+	}
+	return buf.Bytes(), fields, nil
+}
+
+// drawRentTable lists each rent period of the lease with its monthly rent
+func drawRentTable(pdf *gofpdf.Fpdf, periods []rent.Period) {
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 10, "RENT SCHEDULE")
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(55, 7, "From", "1", 0, "", false, 0, "")
+	pdf.CellFormat(55, 7, "To", "1", 0, "", false, 0, "")
+	pdf.CellFormat(50, 7, "Monthly Rent", "1", 1, "R", false, 0, "")
+	pdf.SetFont("Arial", "", 11)
+	for _, p := range periods {
+		pdf.CellFormat(55, 7, p.From.Format("January 2, 2006"), "1", 0, "", false, 0, "")
+		pdf.CellFormat(55, 7, p.To.Format("January 2, 2006"), "1", 0, "", false, 0, "")
+		pdf.CellFormat(50, 7, fmt.Sprintf("$%.2f", p.Amount), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(5)
+}
+
+// drawProration states the rent owed for a partial first or last month
+func drawProration(pdf *gofpdf.Fpdf, tr func(string) string, moveIn, moveOut *rent.Proration) {
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 10, "PRORATED RENT")
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "", 11)
+	line := func(label string, p *rent.Proration) {
+		pdf.MultiCell(0, 6, tr(fmt.Sprintf("%s (%s to %s): %d of %d days at $%.2f per day = $%.2f",
+			label, p.From.Format("January 2, 2006"), p.To.Format("January 2, 2006"),
+			p.Days, p.DaysInMonth, p.DailyRate, p.Amount)), "", "", false)
+	}
+	if moveIn != nil {
+		line("First month", moveIn)
+	}
+	if moveOut != nil {
+		line("Last month", moveOut)
+	}
+	pdf.Ln(5)
+}
+
+type signatureBlock struct {
+	label string
+	party LeaseParty
+}
+
+// drawSignatureRows draws a signature line and a date line per block, two blocks to a row starting at the
+// current position, and returns where each signer's fields landed
+func drawSignatureRows(pdf *gofpdf.Fpdf, tr func(string) string, blocks []signatureBlock) []SignatureField {
+	const rowHeight = 42.0
+	pageWidth, pageHeight := pdf.GetPageSize()
+	_, _, _, bottomMargin := pdf.GetMargins()
+	columns := []float64{20, 110}
+	var fields []SignatureField
+	for i := 0; i < len(blocks); i += len(columns) {
+		if pdf.GetY()+rowHeight > pageHeight-bottomMargin {
+			pdf.AddPage()
+		}
+		top := pdf.GetY()
+		for col, x := range columns {
+			if i+col >= len(blocks) {
+				break
+			}
+			block := blocks[i+col]
+
+			pdf.SetFont("Arial", "", 12)
+			pdf.SetXY(x, top)
+			pdf.Cell(80, 8, block.label)
+			pdf.Line(x, top+22, x+75, top+22)
+			pdf.SetFont("Arial", "", 9)
+			pdf.SetXY(x, top+22)
+			pdf.Cell(80, 5, tr(block.party.Name))
+			pdf.SetFont("Arial", "", 12)
+			pdf.SetXY(x, top+30)
+			pdf.Cell(12, 8, "Date:")
+			pdf.Line(x+12, top+38, x+75, top+38)
+
+			fields = append(fields,
+				SignatureField{
+					Email: block.party.Email, Type: "SIGNATURE", Page: pdf.PageNo(),
+					X: x / pageWidth * 100, Y: (top + 8) / pageHeight * 100,
+					Width: 75 / pageWidth * 100, Height: 14 / pageHeight * 100,
+				},
+				SignatureField{
+					Email: block.party.Email, Type: "DATE", Page: pdf.PageNo(),
+					X: (x + 12) / pageWidth * 100, Y: (top + 30) / pageHeight * 100,
+					Width: 63 / pageWidth * 100, Height: 8 / pageHeight * 100,
+				},
+			)
+		}
+		pdf.SetXY(columns[0], top+rowHeight)
+	}
+	return fields
+}
@@ -0,0 +1,131 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseLeaseTemplateRejectsUnknownPlaceholder(t *testing.T) {
+	content := DefaultLeaseTemplate()
+	content.Clauses = append(content.Clauses, LeaseClause{Name: "pets", Heading: "PETS", Body: "Pet fee: {{pet_fee}}"})
+	raw, _ := json.Marshal(content)
+	if _, err := ParseLeaseTemplate(raw); err == nil {
+		t.Fatal("expected unknown placeholder to be rejected")
+	}
+
+	content = DefaultLeaseTemplate()
+	content.Clauses = append(content.Clauses, LeaseClause{Name: "rent", Body: "duplicate"})
+	if err := content.Validate(); err == nil {
+		t.Fatal("expected duplicate clause name to be rejected")
+	}
+}
+
+func TestRenderLeasePlacesFieldsForEverySigner(t *testing.T) {
+	data := LeaseData{
+		Landlord:        LeaseParty{Name: "Landlord", Email: "landlord@example.com"},
+		Tenants:         []LeaseParty{{Name: "Ana", Email: "ana@example.com"}, {Name: "Bo", Email: "bo@example.com"}},
+		Guarantors:      []LeaseGuarantor{{Name: "Cy", Email: "cy@example.com", Relationship: "parent"}},
+		PropertyAddress: "1 Main St",
+		RentAmount:      1200,
+		StartDate:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:         time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+	pdf, fields, err := RenderLease(DefaultLeaseTemplate(), data)
+	if err != nil {
+		t.Fatalf("RenderLease: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF")) {
+		t.Fatal("output is not a PDF")
+	}
+
+	perSigner := map[string]int{}
+	for _, f := range fields {
+		perSigner[f.Email]++
+		if f.X < 0 || f.X+f.Width > 100 || f.Y < 0 || f.Y+f.Height > 100 {
+			t.Errorf("field %+v is off the page", f)
+		}
+	}
+	for _, email := range []string{"landlord@example.com", "ana@example.com", "bo@example.com", "cy@example.com"} {
+		if perSigner[email] != 2 {
+			t.Errorf("%s has %d fields, want signature and date", email, perSigner[email])
+		}
+	}
+	if last := fields[len(fields)-1]; last.Email != "cy@example.com" || last.Page < 2 {
+		t.Errorf("guarantor fields should be on the addendum page, got %+v", last)
+	}
+}
+
+func TestRenderLeaseSignsEachAddendum(t *testing.T) {
+	data := LeaseData{
+		Landlord: LeaseParty{Name: "Landlord", Email: "landlord@example.com"},
+		Tenants:  []LeaseParty{{Name: "Ana", Email: "ana@example.com"}},
+		Addenda: []LeaseAddendum{
+			{Title: "Pet Addendum", Body: "Pets are allowed at {{property_address}}."},
+			{Title: "Parking Addendum", Body: "One space."},
+		},
+		PropertyAddress: "1 Main St",
+	}
+	_, fields, err := RenderLease(DefaultLeaseTemplate(), data)
+	if err != nil {
+		t.Fatalf("RenderLease: %v", err)
+	}
+
+	pages := map[int]int{}
+	for _, f := range fields {
+		if f.Email == "ana@example.com" && f.Type == "SIGNATURE" {
+			pages[f.Page]++
+		}
+	}
+	if len(pages) != 3 {
+		t.Errorf("tenant should sign the lease and both addenda on separate pages, got pages %v", pages)
+	}
+	if err := ValidateAddendum("Fee: {{pet_fee}}"); err == nil {
+		t.Error("expected unknown placeholder in addendum to be rejected")
+	}
+}
+
+func TestRenderVacateNoticePlacesFieldsForEverySigner(t *testing.T) {
+	data := VacateNoticeData{
+		Landlord:         LeaseParty{Name: "Landlord", Email: "landlord@example.com"},
+		Tenants:          []LeaseParty{{Name: "Ana", Email: "ana@example.com"}, {Name: "Bo", Email: "bo@example.com"}},
+		InitiatedBy:      "tenant",
+		NoticeDate:       time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		MoveOutDate:      time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		LeaseEndDate:     time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+		NoticePeriodDays: 30,
+		EarlyTermination: true,
+		TerminationFee:   1500,
+	}
+	pdf, fields, err := RenderVacateNotice(data)
+	if err != nil {
+		t.Fatalf("RenderVacateNotice: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF")) {
+		t.Fatal("output is not a PDF")
+	}
+	perSigner := map[string]int{}
+	for _, f := range fields {
+		perSigner[f.Email]++
+	}
+	for _, email := range []string{"landlord@example.com", "ana@example.com", "bo@example.com"} {
+		if perSigner[email] != 2 {
+			t.Errorf("%s has %d fields, want signature and date", email, perSigner[email])
+		}
+	}
+}
+
+func TestLeasePlaceholdersUseLandlordLegalName(t *testing.T) {
+	data := LeaseData{Landlord: LeaseParty{Name: "Pat Manager", Email: "pat@example.com"}}
+	if got := data.placeholderValues()["landlord_name"]; got != "Pat Manager" {
+		t.Errorf("landlord_name without a legal name = %q, want the signer", got)
+	}
+
+	data.LandlordName = "Example Properties LLC"
+	data.LandlordLicense = "PM-1234"
+	values := data.placeholderValues()
+	if values["landlord_name"] != "Example Properties LLC" || values["landlord_license_number"] != "PM-1234" {
+		t.Errorf("landlord placeholders = %q, %q", values["landlord_name"], values["landlord_license_number"])
+	}
+}
@@ -0,0 +1,113 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// VacateNoticeData is everything printed on a notice to vacate
+type VacateNoticeData struct {
+	Landlord         LeaseParty
+	Tenants          []LeaseParty
+	UnitNumber       string
+	InitiatedBy      string // tenant or landlord
+	NoticeDate       time.Time
+	MoveOutDate      time.Time
+	LeaseEndDate     time.Time
+	NoticePeriodDays int
+	EarlyTermination bool
+	TerminationFee   float64
+	Reason           string
+}
+
+// RenderVacateNotice produces the notice to vacate signed by every tenant and the landlord, and returns
+// where each signer's fields landed
+func RenderVacateNotice(data VacateNoticeData) ([]byte, []SignatureField, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(10, 10, "NOTICE TO VACATE")
+	pdf.Ln(15)
+
+	names := make([]string, 0, len(data.Tenants))
+	for _, t := range data.Tenants {
+		names = append(names, t.Name)
+	}
+	premises := ""
+	if data.UnitNumber != "" {
+		premises = fmt.Sprintf("Unit %s", data.UnitNumber)
+	}
+
+	leaseEnds := "Month-to-month"
+	if !data.LeaseEndDate.IsZero() {
+		leaseEnds = data.LeaseEndDate.Format("January 2, 2006")
+	}
+
+	pdf.SetFont("Arial", "", 11)
+	for _, line := range [][2]string{
+		{"Notice date", data.NoticeDate.Format("January 2, 2006")},
+		{"Landlord", data.Landlord.Name},
+		{"Tenants", strings.Join(names, ", ")},
+		{"Premises", premises},
+		{"Lease ends", leaseEnds},
+		{"Move-out date", data.MoveOutDate.Format("January 2, 2006")},
+	} {
+		if line[1] == "" {
+			continue
+		}
+		pdf.CellFormat(45, 7, line[0]+":", "", 0, "", false, 0, "")
+		pdf.MultiCell(0, 7, tr(line[1]), "", "", false)
+	}
+	pdf.Ln(5)
+
+	var body strings.Builder
+	if data.InitiatedBy == "landlord" {
+		body.WriteString(fmt.Sprintf("The Landlord gives the Tenants notice that the lease of the premises ends on %s, and the Tenants must vacate "+
+			"the premises and return all keys by that date.", data.MoveOutDate.Format("January 2, 2006")))
+	} else {
+		body.WriteString(fmt.Sprintf("The Tenants give the Landlord notice of their intent to vacate the premises on %s, and will return "+
+			"all keys by that date.", data.MoveOutDate.Format("January 2, 2006")))
+	}
+	body.WriteString(fmt.Sprintf(" The lease requires %d days of written notice.", data.NoticePeriodDays))
+	if data.EarlyTermination {
+		body.WriteString(" The move-out date is before the end of the lease, so the lease is terminated early on that date.")
+		if data.TerminationFee > 0 {
+			body.WriteString(fmt.Sprintf(" Under the lease terms an early termination fee of $%.2f is charged to the Tenants' account on the move-out date.", data.TerminationFee))
+		}
+	}
+	body.WriteString(" Rent is owed through the move-out date, and the security deposit is settled as required by law after the Tenants move out.")
+	pdf.MultiCell(0, 6, tr(body.String()), "", "", false)
+	pdf.Ln(5)
+
+	if data.Reason != "" {
+		pdf.SetFont("Arial", "B", 12)
+		pdf.Cell(40, 10, "REASON")
+		pdf.Ln(10)
+		pdf.SetFont("Arial", "", 11)
+		pdf.MultiCell(0, 6, tr(data.Reason), "", "", false)
+		pdf.Ln(5)
+	}
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 10, "SIGNATURES")
+	pdf.Ln(15)
+
+	blocks := []signatureBlock{{"Landlord Signature:", data.Landlord}}
+	for _, tenant := range data.Tenants {
+		blocks = append(blocks, signatureBlock{"Tenant Signature:", tenant})
+	}
+	fields := drawSignatureRows(pdf, tr, blocks)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate notice to vacate PDF: %w", err)
+	}
+	return buf.Bytes(), fields, nil
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"log"
+	"math"
 	"math/big"
 	"math/rand"
 	"os"
@@ -76,3 +77,17 @@ func ConvertToPgTypeNumeric(value int) pgtype.Numeric {
 	numeric.Valid = true
 	return numeric
 }
+
+// ConvertFloatToPgNumeric converts a dollar amount into a NUMERIC(10, 2) value
+func ConvertFloatToPgNumeric(value float64) pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(int64(math.Round(value * 100))), Exp: -2, Valid: true}
+}
+
+// ConvertPgNumericToFloat returns the float value of a NUMERIC column, or 0 when it is NULL
+func ConvertPgNumericToFloat(value pgtype.Numeric) float64 {
+	f, err := value.Float64Value()
+	if err != nil || !f.Valid {
+		return 0
+	}
+	return f.Float64
+}
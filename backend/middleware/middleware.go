@@ -81,6 +81,11 @@ func IsAdmin(next http.Handler) http.Handler {
 }
 
 func GetUserCtx(r *http.Request) *clerk.User {
+	// ClerkAuthMiddleware already fetched the user for this request
+	if user, ok := r.Context().Value(UserKey).(*clerk.User); ok {
+		return user
+	}
+
 	claims, ok := clerk.SessionClaimsFromContext(r.Context())
 	if !ok {
 		return nil
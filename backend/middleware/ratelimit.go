@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateWindow counts the requests one address made since start
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimit lets each client address make at most limit requests per window and answers the rest with
+// 429. It is meant for public endpoints; the address is the connection's, as X-Forwarded-For can be forged.
+func RateLimit(limit int, window time.Duration) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	windows := map[string]*rateWindow{}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := r.RemoteAddr
+			if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+				ip = host
+			}
+			now := time.Now()
+
+			mu.Lock()
+			// Forget addresses whose window has passed so the map does not grow without bound
+			if len(windows) > 10000 {
+				for addr, win := range windows {
+					if now.Sub(win.start) >= window {
+						delete(windows, addr)
+					}
+				}
+			}
+			win, ok := windows[ip]
+			if !ok || now.Sub(win.start) >= window {
+				win = &rateWindow{start: now}
+				windows[ip] = win
+			}
+			win.count++
+			retryAfter := win.start.Add(window).Sub(now)
+			allowed := win.count <= limit
+			mu.Unlock()
+
+			if !allowed {
+				log.Printf("[RATE_LIMIT] %s exceeded %d requests to %s", ip, limit, r.URL.Path)
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+				http.Error(w, "Too many requests, try again later", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
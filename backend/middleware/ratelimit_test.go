@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	handler := RateLimit(2, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	send := func(remoteAddr, forwardedFor string) int {
+		r := httptest.NewRequest(http.MethodPost, "/apartments/3/applications", nil)
+		r.RemoteAddr = remoteAddr
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	// A forged X-Forwarded-For does not buy a fresh allowance
+	for i, forwarded := range []string{"198.51.100.1", "198.51.100.2"} {
+		if code := send("203.0.113.7:1000", forwarded); code != http.StatusCreated {
+			t.Fatalf("request %d: status = %d, want 201", i+1, code)
+		}
+	}
+	if code := send("203.0.113.7:2000", "198.51.100.3"); code != http.StatusTooManyRequests {
+		t.Errorf("third request: status = %d, want 429", code)
+	}
+	if code := send("203.0.113.8:1000", ""); code != http.StatusCreated {
+		t.Errorf("other address: status = %d, want 201", code)
+	}
+}
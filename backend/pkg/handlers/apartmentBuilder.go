@@ -7,8 +7,10 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/rent"
 	"github.com/careecodes/RentDaddy/internal/utils"
 	"github.com/careecodes/RentDaddy/middleware"
 	"github.com/go-chi/chi/v5"
@@ -78,13 +80,23 @@ func ConstructApartments(queries *db.Queries, w http.ResponseWriter, r *http.Req
 	}
 	log.Println("[Construct-CreateManyParkingSpaces] created parking spaces successfully")
 
+	landlord, err := adminLandlord(r.Context(), queries, LeaseParty{
+		UserID: adminUser.ID,
+		Name:   fmt.Sprintf("%s %s", adminUser.FirstName, adminUser.LastName),
+		Email:  adminUser.Email,
+	})
+	if err != nil {
+		log.Printf("[Construct-Landlord] error finding landlord of admin %d: %v", adminUser.ID, err)
+		return errors.New("[Construct] error finding landlord: " + err.Error())
+	}
+
 	aCount := 0
 	for _, building := range params.Buildings {
 
 		buildingParams := db.CreateBuildingParams{
 			ParkingTotal:   pgtype.Int8{Int64: int64(params.ParkingTotal), Valid: true},
 			PerUserParking: pgtype.Int8{Int64: int64(params.PerUserParking), Valid: true},
-			ManagementID:   adminUser.ID,
+			ManagementID:   landlord.ID,
 		}
 		buildingResponse, err := queries.CreateBuilding(r.Context(), buildingParams)
 		if err != nil {
@@ -148,6 +160,18 @@ type UpdateBuildingRequest struct {
 	NumberOfRooms  int `json:"numberOfRooms"`
 	ParkingTotal   int `json:"parkingTotal"`
 	PerUserParking int `json:"perUserParking"`
+	// Rules are the house rules printed on leases for this building. Left unchanged when omitted.
+	Rules *string `json:"rules,omitempty"`
+	// ProrationMethod is actual_days or thirty_day, used to prorate partial months. Left unchanged when omitted.
+	ProrationMethod *string `json:"proration_method,omitempty"`
+	// HoldoverPolicy is expire or month_to_month, applied to leases that reach their end date without one of
+	// their own. Left unchanged when omitted.
+	HoldoverPolicy *string `json:"holdover_policy,omitempty"`
+	// HoldoverPremiumPercent is added to the last rent when a lease goes month-to-month. Left unchanged when omitted.
+	HoldoverPremiumPercent *float64 `json:"holdover_premium_percent,omitempty"`
+	// ManagementID is the landlord managing the building, whose details new leases are made in. Left
+	// unchanged when omitted.
+	ManagementID *int64 `json:"management_id,omitempty"`
 }
 
 // UpdateBuildingHandler updates an existing building
@@ -176,6 +200,25 @@ func (h BuildingHandler) UpdateBuildingHandler(w http.ResponseWriter, r *http.Re
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	var prorationMethod rent.ProrationMethod
+	if updateReq.ProrationMethod != nil {
+		prorationMethod, err = rent.ParseProrationMethod(*updateReq.ProrationMethod)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	holdover := HoldoverPolicyRequest{Policy: updateReq.HoldoverPolicy, PremiumPercent: updateReq.HoldoverPremiumPercent}
+	if err := validateHoldoverPolicy(holdover); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if updateReq.ManagementID != nil {
+		if _, err := h.queries.GetLandlord(r.Context(), *updateReq.ManagementID); err != nil {
+			http.Error(w, "Landlord not found", http.StatusBadRequest)
+			return
+		}
+	}
 
 	// Verify admin permissions
 	adminClerkID := adminCtxt.ID
@@ -203,6 +246,68 @@ func (h BuildingHandler) UpdateBuildingHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	if updateReq.Rules != nil {
+		rules := strings.TrimSpace(*updateReq.Rules)
+		err = h.queries.UpdateBuildingRules(r.Context(), db.UpdateBuildingRulesParams{
+			ID:    buildingID,
+			Rules: pgtype.Text{String: rules, Valid: rules != ""},
+		})
+		if err != nil {
+			log.Printf("[UpdateBuilding] error updating building rules: %v", err)
+			http.Error(w, "Failed to update building rules", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if updateReq.ProrationMethod != nil {
+		err = h.queries.UpdateBuildingProrationMethod(r.Context(), db.UpdateBuildingProrationMethodParams{
+			ID:              buildingID,
+			ProrationMethod: db.ProrationMethod(prorationMethod),
+		})
+		if err != nil {
+			log.Printf("[UpdateBuilding] error updating proration method: %v", err)
+			http.Error(w, "Failed to update building proration method", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if holdover.Policy != nil || holdover.PremiumPercent != nil {
+		building, err := h.queries.GetBuilding(r.Context(), buildingID)
+		if err != nil {
+			log.Printf("[UpdateBuilding] error fetching building: %v", err)
+			http.Error(w, "Building not found", http.StatusNotFound)
+			return
+		}
+		params := db.UpdateBuildingHoldoverPolicyParams{
+			ID:                     buildingID,
+			HoldoverPolicy:         building.HoldoverPolicy,
+			HoldoverPremiumPercent: building.HoldoverPremiumPercent,
+		}
+		if holdover.Policy != nil {
+			params.HoldoverPolicy = db.HoldoverPolicy(*holdover.Policy)
+		}
+		if holdover.PremiumPercent != nil {
+			params.HoldoverPremiumPercent = utils.ConvertFloatToPgNumeric(*holdover.PremiumPercent)
+		}
+		if err := h.queries.UpdateBuildingHoldoverPolicy(r.Context(), params); err != nil {
+			log.Printf("[UpdateBuilding] error updating holdover policy: %v", err)
+			http.Error(w, "Failed to update building holdover policy", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if updateReq.ManagementID != nil {
+		err = h.queries.UpdateBuildingManagement(r.Context(), db.UpdateBuildingManagementParams{
+			ID:           buildingID,
+			ManagementID: *updateReq.ManagementID,
+		})
+		if err != nil {
+			log.Printf("[UpdateBuilding] error updating building landlord: %v", err)
+			http.Error(w, "Failed to update building landlord", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	// Return success response
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -31,6 +31,9 @@ type UpdateApartmentParams struct {
 	ManagementID pgtype.Int8    `json:"management_id"`
 	Availability bool           `json:"availability"`
 	LeaseID      pgtype.Int8    `json:"lease_id"`
+	// Bedrooms and UnitType are only changed when given
+	Bedrooms pgtype.Int2 `json:"bedrooms"`
+	UnitType pgtype.Text `json:"unit_type"`
 }
 
 func (h ApartmentHandler) GetApartmentHandler(w http.ResponseWriter, r *http.Request) {
@@ -168,6 +171,31 @@ func (h ApartmentHandler) UpdateApartmentHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
+	if updateRequestParams.Bedrooms.Valid || updateRequestParams.UnitType.Valid {
+		current, err := h.queries.GetApartment(r.Context(), int64(apartmentID))
+		if err != nil {
+			log.Printf("Error fetching apartment %d: %v", apartmentID, err)
+			http.Error(w, "Apartment not found", http.StatusNotFound)
+			return
+		}
+		layout := db.UpdateApartmentLayoutParams{
+			ID:       current.ID,
+			Bedrooms: current.Bedrooms,
+			UnitType: current.UnitType,
+		}
+		if updateRequestParams.Bedrooms.Valid {
+			layout.Bedrooms = updateRequestParams.Bedrooms
+		}
+		if updateRequestParams.UnitType.Valid {
+			layout.UnitType = updateRequestParams.UnitType
+		}
+		if err := h.queries.UpdateApartmentLayout(r.Context(), layout); err != nil {
+			log.Printf("Error updating layout of apartment %d: %v", apartmentID, err)
+			http.Error(w, "Failed to update apartment", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	w.Header().Set("Content-Type", "application/json")
 	jsonRes, err := json.Marshal(updateParams)
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/screening"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// screeningTimeout bounds a single call to the screening provider
+const screeningTimeout = 30 * time.Second
+
+// newScreeningProvider picks the tenant screening provider from SCREENING_PROVIDER. "mock" runs the
+// in-process mock with fixed results (development and tests). "none" turns screening off and reports it
+// optional, so approvals do not wait for one. Unset leaves no provider, but approvals still require a
+// screening and are refused until one is configured. An unknown name stops the server, as a typo must not
+// turn screening off.
+func newScreeningProvider() (provider screening.Provider, optional bool) {
+	switch name := os.Getenv("SCREENING_PROVIDER"); name {
+	case "":
+		log.Println("[SCREENING] No screening provider is configured, applications cannot be approved until one is")
+		return nil, false
+	case "none":
+		log.Println("[SCREENING] Screening is turned off, applications are approved without one")
+		return nil, true
+	case "mock":
+		log.Println("[SCREENING] Using the mock screening provider, results are not real")
+		return &screening.MockProvider{}, false
+	default:
+		log.Fatalf("[SCREENING] Unknown screening provider %q, set SCREENING_PROVIDER to mock or none", name)
+		return nil, false
+	}
+}
+
+type ScreenRentalApplicationRequest struct {
+	Checks []string `json:"checks,omitempty"` // credit, criminal, eviction; all of them when empty
+}
+
+// ScreeningResponse is one screening of an applicant with the result of each check
+type ScreeningResponse struct {
+	ID             int64              `json:"id"`
+	Provider       string             `json:"provider"`
+	Reference      string             `json:"reference"`
+	Recommendation string             `json:"recommendation"`
+	Results        []screening.Result `json:"results"`
+	RequestedBy    *int64             `json:"requested_by,omitempty"`
+	CreatedAt      string             `json:"created_at"`
+}
+
+func toScreeningResponse(s db.RentalApplicationScreening) ScreeningResponse {
+	resp := ScreeningResponse{
+		ID:             s.ID,
+		Provider:       s.Provider,
+		Reference:      s.Reference,
+		Recommendation: string(s.Recommendation),
+		CreatedAt:      s.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+	}
+	var report screening.Report
+	if err := json.Unmarshal(s.Report, &report); err != nil {
+		log.Printf("[SCREENING] Could not read report of screening %d: %v", s.ID, err)
+	}
+	resp.Results = report.Results
+	if s.RequestedBy.Valid {
+		resp.RequestedBy = &s.RequestedBy.Int64
+	}
+	return resp
+}
+
+// latestScreening returns the applicant's most recent screening, or nil when they were never screened
+func (h *LeaseHandler) latestScreening(ctx context.Context, applicationID int64) (*db.RentalApplicationScreening, error) {
+	row, err := h.queries.GetLatestRentalApplicationScreening(ctx, applicationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// screeningCoversAllChecks reports whether a screening ran every check an approval needs
+func screeningCoversAllChecks(s db.RentalApplicationScreening) bool {
+	var report screening.Report
+	if err := json.Unmarshal(s.Report, &report); err != nil {
+		return false
+	}
+	return report.Covers()
+}
+
+// ScreenRentalApplication runs credit, criminal and eviction checks on the applicant and stores the report
+// with the application. Income is the household's, measured against the unit's rent.
+func (h *LeaseHandler) ScreenRentalApplication(w http.ResponseWriter, r *http.Request) {
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if h.screening == nil {
+		http.Error(w, "No screening provider is configured", http.StatusServiceUnavailable)
+		return
+	}
+	app, ok := h.loadRentalApplication(w, r)
+	if !ok {
+		return
+	}
+	if app.Status != db.ApplicationStatusSubmitted && app.Status != db.ApplicationStatusConditional {
+		http.Error(w, "Only applications under review can be screened", http.StatusConflict)
+		return
+	}
+	var req ScreenRentalApplicationRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid screening request", http.StatusBadRequest)
+			return
+		}
+	}
+	checks, err := screening.ParseChecks(req.Checks)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	applicant := screening.Applicant{
+		FirstName:     app.FirstName,
+		LastName:      app.LastName,
+		Email:         app.Email,
+		Phone:         app.Phone,
+		MonthlyIncome: utils.ConvertPgNumericToFloat(app.MonthlyIncome),
+	}
+	members, err := h.queries.ListRentalApplicationMembers(ctx, app.ID)
+	if err != nil {
+		log.Printf("[SCREENING] Failed listing members of application %d: %v", app.ID, err)
+		http.Error(w, "Failed to load application", http.StatusInternalServerError)
+		return
+	}
+	for _, m := range members {
+		applicant.MonthlyIncome += utils.ConvertPgNumericToFloat(m.MonthlyIncome)
+	}
+	if apartment, err := h.queries.GetApartment(ctx, app.ApartmentID); err == nil {
+		applicant.MonthlyRent = utils.ConvertPgNumericToFloat(apartment.Price)
+	}
+
+	screenCtx, cancel := context.WithTimeout(ctx, screeningTimeout)
+	defer cancel()
+	report, err := h.screening.Screen(screenCtx, applicant, checks)
+	if err != nil {
+		log.Printf("[SCREENING] %s failed screening application %d: %v", h.screening.Name(), app.ID, err)
+		if errors.Is(err, screening.ErrUnavailable) || errors.Is(err, context.DeadlineExceeded) {
+			http.Error(w, "The screening provider is unavailable, try again later", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Screening failed", http.StatusBadGateway)
+		return
+	}
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		log.Printf("[SCREENING] Failed encoding report for application %d: %v", app.ID, err)
+		http.Error(w, "Failed to save screening", http.StatusInternalServerError)
+		return
+	}
+	row, err := h.queries.CreateRentalApplicationScreening(ctx, db.CreateRentalApplicationScreeningParams{
+		ApplicationID:  app.ID,
+		Provider:       report.Provider,
+		Reference:      report.Reference,
+		Recommendation: db.ScreeningRecommendation(report.Recommendation),
+		Report:         reportJSON,
+		RequestedBy:    pgtype.Int8{Int64: adminID, Valid: true},
+	})
+	if err != nil {
+		log.Printf("[SCREENING] Failed saving screening of application %d: %v", app.ID, err)
+		http.Error(w, "Failed to save screening", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[SCREENING] Admin %d screened application %d with %s: %s", adminID, app.ID, row.Provider, row.Recommendation)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toScreeningResponse(row)); err != nil {
+		log.Printf("[SCREENING] Error encoding response: %v", err)
+	}
+}
+
+// ListRentalApplicationScreenings returns every screening of an applicant, newest first
+func (h *LeaseHandler) ListRentalApplicationScreenings(w http.ResponseWriter, r *http.Request) {
+	app, ok := h.loadRentalApplication(w, r)
+	if !ok {
+		return
+	}
+	rows, err := h.queries.ListRentalApplicationScreenings(r.Context(), app.ID)
+	if err != nil {
+		log.Printf("[SCREENING] Failed listing screenings of application %d: %v", app.ID, err)
+		http.Error(w, "Failed to fetch screenings", http.StatusInternalServerError)
+		return
+	}
+	resp := make([]ScreeningResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, toScreeningResponse(row))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[SCREENING] Error encoding response: %v", err)
+	}
+}
+
+// screeningID is the screening a decision is recorded against, if any
+func screeningID(s *db.RentalApplicationScreening) pgtype.Int8 {
+	if s == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: s.ID, Valid: true}
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -10,6 +13,7 @@ import (
 	db "github.com/careecodes/RentDaddy/internal/db/generated"
 	"github.com/careecodes/RentDaddy/internal/utils"
 	"github.com/clerk/clerk-sdk-go/v2/user"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	svix "github.com/svix/svix-webhooks/go"
@@ -48,7 +52,7 @@ type ClerkWebhookPayload struct {
 }
 
 func ClerkWebhookHandler(w http.ResponseWriter, r *http.Request, pool *pgxpool.Pool, queries *db.Queries) {
-	body, err := io.ReadAll(r.Body)
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
 	if err != nil {
 		log.Println("[CLERK_WEBHOOK] Failed reading body")
 		http.Error(w, "Failed reading body", http.StatusBadRequest)
@@ -58,6 +62,9 @@ func ClerkWebhookHandler(w http.ResponseWriter, r *http.Request, pool *pgxpool.P
 
 	if !Verify(body, r.Header) {
 		log.Println("[CLERK_WEBHOOK] Invalid webhook signature")
+		if queries != nil {
+			recordRejectedWebhookEvent(r.Context(), queries, webhookSourceClerk, r.Header, body, errors.New("invalid webhook signature"))
+		}
 		http.Error(w, "Invalid signature", http.StatusUnauthorized)
 		return
 	}
@@ -82,21 +89,56 @@ func ClerkWebhookHandler(w http.ResponseWriter, r *http.Request, pool *pgxpool.P
 		return
 	}
 
+	// Log the delivery first so a failure below can be replayed
+	event, duplicate, err := recordWebhookEvent(r.Context(), queries, webhookSourceClerk, r.Header.Get("svix-id"), payload.Type, r.Header, body)
+	if err != nil {
+		log.Printf("[CLERK_WEBHOOK] Failed recording webhook event: %v", err)
+	}
+	if duplicate {
+		log.Printf("[CLERK_WEBHOOK] Delivery %s already processed or being processed, skipping", event.DeliveryID)
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`{"status":"duplicate"}`)); err != nil {
+			log.Printf("[CLERK_WEBHOOK] Failed writing response: %v", err)
+		}
+		return
+	}
+
+	err = processClerkEvent(r.Context(), payload, queries)
+	finishWebhookEvent(r.Context(), queries, event.ID, err)
+	if err != nil {
+		log.Printf("[CLERK_WEBHOOK] Failed processing %s: %v", payload.Type, err)
+		http.Error(w, "Error processing webhook", http.StatusInternalServerError)
+		return
+	}
+
+	if payload.Type == "user.created" {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(`{"status":"received"}`)); err != nil {
+		log.Printf("[CLERK_WEBHOOK] Failed writing response: %v", err)
+	}
+}
+
+// processClerkEvent applies a Clerk webhook payload to the users table
+func processClerkEvent(ctx context.Context, payload ClerkWebhookPayload, queries *db.Queries) error {
+	var clerkUserData ClerkUserData
+	if err := json.Unmarshal(payload.Data, &clerkUserData); err != nil {
+		return fmt.Errorf("invalid user data: %w", err)
+	}
+
 	// Subscribed events
 	switch payload.Type {
 	case "user.created":
-		createUser(w, r, clerkUserData, queries)
+		return createUser(ctx, clerkUserData, queries)
 	case "user.updated":
-		updateUser(w, r, clerkUserData, queries)
+		return updateUser(ctx, clerkUserData, queries)
 	case "user.deleted":
-		deleteUser(w, r, clerkUserData, queries)
+		return deleteUser(ctx, clerkUserData, queries)
 	default:
 		log.Printf("[CLERK_WEBHOOK] Unhandled event: %s", payload.Type)
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte(`{"status":"received"}`)); err != nil {
-			log.Printf("[CLERK_WEBHOOK] Failed writing response: %v", err)
-		}
-		return
+		return nil
 	}
 }
 
@@ -121,7 +163,7 @@ func Verify(payload []byte, headers http.Header) bool {
 	return true
 }
 
-func createUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData, queries *db.Queries) {
+func createUser(ctx context.Context, userData ClerkUserData, queries *db.Queries) error {
 	userRole := db.RoleTenant
 	AdminFirstName := os.Getenv("ADMIN_FIRST_NAME")
 	AdminLastName := os.Getenv("ADMIN_LAST_NAME")
@@ -142,7 +184,7 @@ func createUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData,
 	// If we need to check if this is the first user
 	if checkIfFirstUser {
 		// Query to check if any users exist in the database
-		userCount, err := queries.GetUserCount(r.Context())
+		userCount, err := queries.GetUserCount(ctx)
 		if err != nil {
 			log.Printf("[CLERK_WEBHOOK] Error checking user count: %v", err)
 			// Continue with tenant role as fallback
@@ -182,7 +224,28 @@ func createUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData,
 		userRole = db.RoleAdmin
 	}
 
-	userRes, err := queries.CreateUser(r.Context(), db.CreateUserParams{
+	// An applicant approved before signing up already has an account; their invitation carries its ID
+	var userRes db.CreateUserRow
+	if userMetadata.DbId != 0 && userRole == db.RoleTenant {
+		claimed, err := queries.ClaimApplicantUser(ctx, db.ClaimApplicantUserParams{
+			ID:        int64(userMetadata.DbId),
+			ClerkID:   userData.ID,
+			FirstName: userData.FirstName,
+			LastName:  userData.LastName,
+			Email:     primaryUserEmail,
+		})
+		if err == nil {
+			log.Printf("[CLERK_WEBHOOK] User %s claimed applicant account %d", userData.ID, claimed.ID)
+			userRes = db.CreateUserRow(claimed)
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("[CLERK_WEBHOOK] Failed claiming applicant account %d: %v", userMetadata.DbId, err)
+		}
+	}
+	if userRes.ID != 0 {
+		return updateClerkMetadata(ctx, userData.ID, userRes, primaryUserEmail)
+	}
+
+	userRes, err = queries.CreateUser(ctx, db.CreateUserParams{
 		ClerkID:   userData.ID,
 		FirstName: userData.FirstName,
 		LastName:  userData.LastName,
@@ -192,10 +255,13 @@ func createUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData,
 	})
 	if err != nil {
 		log.Printf("[CLERK_WEBHOOK] Failed inserting user in DB: %v", err)
-		http.Error(w, "Error inserting user", http.StatusInternalServerError)
-		return
+		return fmt.Errorf("error inserting user: %w", err)
 	}
+	return updateClerkMetadata(ctx, userData.ID, userRes, primaryUserEmail)
+}
 
+// updateClerkMetadata stores the user's database ID and role on their Clerk user
+func updateClerkMetadata(ctx context.Context, clerkID string, userRes db.CreateUserRow, primaryUserEmail string) error {
 	// Update clerk user metadata with DB ID, role, ect.
 	metadata := &ClerkUserPublicMetaData{
 		DbId: int32(userRes.ID),
@@ -210,7 +276,7 @@ func createUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData,
 	}
 	metadataRaw := json.RawMessage(metadataBytes)
 
-	_, err = user.Update(r.Context(), userData.ID, &user.UpdateParams{
+	_, err = user.Update(ctx, clerkID, &user.UpdateParams{
 		PublicMetadata: &metadataRaw,
 	})
 	if err != nil {
@@ -218,22 +284,21 @@ func createUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData,
 		// Currently not erroring out
 	}
 
-	log.Printf("[CLERK_WEBHOOK] New user created: %s (%s)", userData.ID, primaryUserEmail)
-	w.WriteHeader(http.StatusCreated)
+	log.Printf("[CLERK_WEBHOOK] New user created: %s (%s)", clerkID, primaryUserEmail)
+	return nil
 }
 
-func updateUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData, queries *db.Queries) {
+func updateUser(ctx context.Context, userData ClerkUserData, queries *db.Queries) error {
 	primaryUserEmail := userData.EmailAddresses[0].EmailAddress
 
 	// First, check if the user exists in our database
-	existingUser, err := queries.GetUser(r.Context(), userData.ID)
+	existingUser, err := queries.GetUser(ctx, userData.ID)
 	if err != nil {
 		// User doesn't exist yet - create the user instead of updating
 		log.Printf("[CLERK_WEBHOOK] User %s not found, creating new user instead of updating", userData.ID)
 
 		// Call createUser to handle the creation logic
-		createUser(w, r, userData, queries)
-		return
+		return createUser(ctx, userData, queries)
 	}
 
 	// Extract role from clerk metadata if present
@@ -267,7 +332,7 @@ func updateUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData,
 			log.Printf("[CLERK_WEBHOOK] Error marshaling metadata: %v", err)
 		} else {
 			metadataRaw := json.RawMessage(metadataBytes)
-			_, updateErr := user.Update(r.Context(), userData.ID, &user.UpdateParams{
+			_, updateErr := user.Update(ctx, userData.ID, &user.UpdateParams{
 				PublicMetadata: &metadataRaw,
 			})
 			
@@ -286,7 +351,7 @@ func updateUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData,
 	// We need to maintain the existing phone if it wasn't changed
 	phoneToUse := existingUser.Phone
 	
-	if err := queries.UpdateUser(r.Context(), db.UpdateUserParams{
+	if err := queries.UpdateUser(ctx, db.UpdateUserParams{
 		ClerkID:   userData.ID,
 		FirstName: userData.FirstName,
 		LastName:  userData.LastName,
@@ -295,24 +360,21 @@ func updateUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData,
 		Role:      userRole,
 	}); err != nil {
 		log.Printf("[CLERK_WEBHOOK] Failed updating user %s: %v", userData.ID, err)
-		http.Error(w, "Error updating user data", http.StatusInternalServerError)
-		return
+		return fmt.Errorf("error updating user data: %w", err)
 	}
 
 	log.Printf("[CLERK_WEBHOOK] User updated: %s (%s) with role %s", 
 		userData.ID, primaryUserEmail, userRole)
-	w.WriteHeader(http.StatusOK)
+	return nil
 }
 
-func deleteUser(w http.ResponseWriter, r *http.Request, userData ClerkUserData, queries *db.Queries) {
-	if err := queries.DeleteUser(r.Context(), userData.ID); err != nil {
+func deleteUser(ctx context.Context, userData ClerkUserData, queries *db.Queries) error {
+	if err := queries.DeleteUser(ctx, userData.ID); err != nil {
 		log.Printf("[CLERK_WEBHOOK] Failed deleting user %s: %v", userData.ID, err)
-		http.Error(w, "Error deleting user data", http.StatusInternalServerError)
-		return
-
+		return fmt.Errorf("error deleting user data: %w", err)
 	}
 
 	// TODO: DELETE tenat's lease, parking_permits, lockers, ect
 
-	w.WriteHeader(http.StatusOK)
+	return nil
 }
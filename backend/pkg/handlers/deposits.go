@@ -0,0 +1,605 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/rent"
+	"github.com/careecodes/RentDaddy/internal/smtp"
+	"github.com/careecodes/RentDaddy/internal/templates"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// defaultDepositDispositionDays is how long after move-out the deposit statement must reach the tenant
+// when no deadline is configured
+const defaultDepositDispositionDays = 30
+
+type DepositRequest struct {
+	Amount         float64  `json:"amount"`
+	ReceivedOn     string   `json:"received_on"` // Format: YYYY-MM-DD
+	HoldingAccount string   `json:"holding_account"`
+	InterestRate   *float64 `json:"interest_rate,omitempty"` // Annual percent, only where the law requires interest
+}
+
+type DepositResponse struct {
+	LeaseID        int64    `json:"lease_id"`
+	Amount         float64  `json:"amount"`
+	ReceivedOn     string   `json:"received_on"`
+	HoldingAccount string   `json:"holding_account"`
+	InterestRate   *float64 `json:"interest_rate,omitempty"`
+}
+
+type DepositDispositionResponse struct {
+	LeaseID         int64                        `json:"lease_id"`
+	DepositAmount   float64                      `json:"deposit_amount"`
+	InterestAmount  float64                      `json:"interest_amount"`
+	Deductions      []templates.DepositDeduction `json:"deductions"`
+	TotalDeductions float64                      `json:"total_deductions"`
+	RefundAmount    float64                      `json:"refund_amount"`
+	MoveOutDate     string                       `json:"move_out_date"`
+	DueBy           string                       `json:"due_by"`
+	Status          string                       `json:"status"`
+	SentTo          string                       `json:"sent_to,omitempty"`
+	SentAt          string                       `json:"sent_at,omitempty"`
+}
+
+// DepositDispositionRequest itemizes the deductions for a deposit statement that is still pending
+type DepositDispositionRequest struct {
+	Deductions []templates.DepositDeduction `json:"deductions"`
+}
+
+// errDepositDispositionIssued is returned when a lease's deposit statement has already been issued
+var errDepositDispositionIssued = errors.New("deposit statement already issued")
+
+// TerminateLeaseRequest is the optional body of a termination. Deductions are itemized against the
+// security deposit; unpaid rent on the ledger is added automatically. Reason is kept in the lease's
+// status history.
+type TerminateLeaseRequest struct {
+	Deductions []templates.DepositDeduction `json:"deductions,omitempty"`
+	Reason     string                       `json:"reason,omitempty"`
+}
+
+func toDepositResponse(d db.LeaseDeposit) DepositResponse {
+	resp := DepositResponse{
+		LeaseID:        d.LeaseID,
+		Amount:         utils.ConvertPgNumericToFloat(d.Amount),
+		ReceivedOn:     d.ReceivedOn.Time.Format("2006-01-02"),
+		HoldingAccount: d.HoldingAccount,
+	}
+	if d.InterestRate.Valid {
+		rate := utils.ConvertPgNumericToFloat(d.InterestRate)
+		resp.InterestRate = &rate
+	}
+	return resp
+}
+
+func toDepositDispositionResponse(d db.DepositDisposition) DepositDispositionResponse {
+	resp := DepositDispositionResponse{
+		LeaseID:         d.LeaseID,
+		DepositAmount:   utils.ConvertPgNumericToFloat(d.DepositAmount),
+		InterestAmount:  utils.ConvertPgNumericToFloat(d.InterestAmount),
+		TotalDeductions: utils.ConvertPgNumericToFloat(d.TotalDeductions),
+		RefundAmount:    utils.ConvertPgNumericToFloat(d.RefundAmount),
+		MoveOutDate:     d.MoveOutDate.Time.Format("2006-01-02"),
+		DueBy:           d.DueBy.Time.Format("2006-01-02"),
+		Status:          string(d.Status),
+		SentTo:          d.SentTo.String,
+	}
+	if err := json.Unmarshal(d.Deductions, &resp.Deductions); err != nil {
+		log.Printf("[DEPOSIT] Disposition %d has unreadable deductions: %v", d.ID, err)
+	}
+	if d.SentAt.Valid {
+		resp.SentAt = d.SentAt.Time.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+// validateDeposit checks a deposit request and returns the date it was received
+func validateDeposit(req DepositRequest) (time.Time, error) {
+	if req.Amount < 0 {
+		return time.Time{}, errors.New("deposit amount cannot be negative")
+	}
+	receivedOn, err := time.Parse("2006-01-02", req.ReceivedOn)
+	if err != nil {
+		return time.Time{}, errors.New("deposit received_on must be a YYYY-MM-DD date")
+	}
+	if req.InterestRate != nil && (*req.InterestRate < 0 || *req.InterestRate > 100) {
+		return time.Time{}, errors.New("deposit interest rate must be between 0 and 100")
+	}
+	return receivedOn, nil
+}
+
+func validateDepositDeductions(deductions []templates.DepositDeduction) error {
+	for _, d := range deductions {
+		switch d.Category {
+		case "damages", "unpaid_rent", "cleaning", "other":
+		default:
+			return fmt.Errorf("unknown deduction category %q, use damages, unpaid_rent, cleaning or other", d.Category)
+		}
+		if d.Amount <= 0 {
+			return fmt.Errorf("%s deduction needs a positive amount", d.Category)
+		}
+	}
+	return nil
+}
+
+// depositDispositionDays returns the statutory deadline for the deposit statement in days after move-out.
+// It is read from app_config so it can be changed per jurisdiction without a restart.
+func (h *LeaseHandler) depositDispositionDays(ctx context.Context) int {
+	value := os.Getenv("DEPOSIT_DISPOSITION_DAYS")
+	if config, err := h.queries.GetConfigByKey(ctx, "deposit_disposition_days"); err == nil && config.Value != "" {
+		value = config.Value
+	}
+	if days, err := strconv.Atoi(value); err == nil && days > 0 {
+		return days
+	}
+	return defaultDepositDispositionDays
+}
+
+func (h *LeaseHandler) saveLeaseDeposit(ctx context.Context, leaseID int64, req DepositRequest, receivedOn time.Time, adminID int64) (db.LeaseDeposit, error) {
+	params := db.UpsertLeaseDepositParams{
+		LeaseID:        leaseID,
+		Amount:         utils.ConvertFloatToPgNumeric(req.Amount),
+		ReceivedOn:     pgtype.Date{Time: receivedOn, Valid: true},
+		HoldingAccount: strings.TrimSpace(req.HoldingAccount),
+		CreatedBy:      pgtype.Int8{Int64: adminID, Valid: adminID != 0},
+	}
+	if req.InterestRate != nil {
+		params.InterestRate = utils.ConvertFloatToPgNumeric(*req.InterestRate)
+	}
+	return h.queries.UpsertLeaseDeposit(ctx, params)
+}
+
+// recordLeaseDeposit stores the deposit sent with a new lease
+func (h *LeaseHandler) recordLeaseDeposit(ctx context.Context, leaseID int64, req *DepositRequest, adminID int64) error {
+	if req == nil {
+		return nil
+	}
+	receivedOn, err := validateDeposit(*req)
+	if err != nil {
+		return fmt.Errorf("invalid deposit on lease %d: %w", leaseID, err)
+	}
+	if _, err := h.saveLeaseDeposit(ctx, leaseID, *req, receivedOn, adminID); err != nil {
+		return fmt.Errorf("failed recording deposit on lease %d: %w", leaseID, err)
+	}
+	return nil
+}
+
+// GetLeaseDeposit returns the security deposit held against a lease
+func (h *LeaseHandler) GetLeaseDeposit(w http.ResponseWriter, r *http.Request) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+
+	deposit, err := h.queries.GetLeaseDeposit(r.Context(), leaseID)
+	if err != nil {
+		http.Error(w, "No deposit recorded for this lease", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toDepositResponse(deposit)); err != nil {
+		log.Printf("[DEPOSIT] Error encoding response: %v", err)
+	}
+}
+
+// RecordLeaseDeposit records or corrects the security deposit on a lease
+func (h *LeaseHandler) RecordLeaseDeposit(w http.ResponseWriter, r *http.Request) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+
+	var req DepositRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid deposit request", http.StatusBadRequest)
+		return
+	}
+	receivedOn, err := validateDeposit(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if _, err := h.queries.GetLeaseByID(r.Context(), leaseID); err != nil {
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
+
+	deposit, err := h.saveLeaseDeposit(r.Context(), leaseID, req, receivedOn, adminID)
+	if err != nil {
+		log.Printf("[DEPOSIT] Failed recording deposit on lease %d: %v", leaseID, err)
+		http.Error(w, "Failed to record deposit", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[DEPOSIT] Recorded deposit of %.2f on lease %d", req.Amount, leaseID)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toDepositResponse(deposit)); err != nil {
+		log.Printf("[DEPOSIT] Error encoding response: %v", err)
+	}
+}
+
+// issueDepositDisposition itemizes what is withheld from the deposit of a lease that ended on moveOut.
+// Any rent still owing on the ledger is withheld as unpaid rent and the deposit is applied to it.
+// It returns pgx.ErrNoRows when the lease has no deposit and errDepositDispositionIssued when its
+// statement was issued before.
+func (h *LeaseHandler) issueDepositDisposition(ctx context.Context, leaseID int64, moveOut time.Time, deductions []templates.DepositDeduction, adminID int64) (db.DepositDisposition, error) {
+	deposit, err := h.queries.GetLeaseDeposit(ctx, leaseID)
+	if err != nil {
+		return db.DepositDisposition{}, err
+	}
+	lease, err := h.queries.GetLeaseByID(ctx, leaseID)
+	if err != nil {
+		return db.DepositDisposition{}, fmt.Errorf("lease %d not found: %w", leaseID, err)
+	}
+
+	depositAmount := utils.ConvertPgNumericToFloat(deposit.Amount)
+	interest := rent.DepositInterest(depositAmount, utils.ConvertPgNumericToFloat(deposit.InterestRate), deposit.ReceivedOn.Time, moveOut)
+
+	unpaidRent := 0.0
+	for _, d := range deductions {
+		if d.Category == "unpaid_rent" {
+			unpaidRent += d.Amount
+		}
+	}
+	if unpaidRent == 0 {
+		if entries, err := h.queries.ListLedgerEntriesByLease(ctx, leaseID); err == nil {
+			if totals, _ := postLedger(entries); totals.Balance > 0 {
+				unpaidRent = totals.Balance
+				deductions = append(deductions, templates.DepositDeduction{
+					Category:    "unpaid_rent",
+					Description: "Rent balance owing at move-out",
+					Amount:      totals.Balance,
+				})
+			}
+		} else {
+			log.Printf("[DEPOSIT] Could not load ledger balance of lease %d: %v", leaseID, err)
+		}
+	}
+
+	total := 0.0
+	for _, d := range deductions {
+		total += d.Amount
+	}
+	if deductions == nil {
+		deductions = []templates.DepositDeduction{}
+	}
+	itemized, _ := json.Marshal(deductions)
+
+	// The statement and the rent it pays down are saved together, so a statement never claims a
+	// deposit was applied to rent the ledger still shows as owing
+	var disposition db.DepositDisposition
+	err = h.inTx(ctx, func(tx *LeaseHandler) error {
+		disposition, err = tx.queries.CreateDepositDisposition(ctx, db.CreateDepositDispositionParams{
+			LeaseID:         leaseID,
+			DepositAmount:   deposit.Amount,
+			InterestAmount:  utils.ConvertFloatToPgNumeric(interest),
+			Deductions:      itemized,
+			TotalDeductions: utils.ConvertFloatToPgNumeric(total),
+			RefundAmount:    utils.ConvertFloatToPgNumeric(depositAmount + interest - total),
+			MoveOutDate:     pgtype.Date{Time: moveOut, Valid: true},
+			DueBy:           pgtype.Date{Time: moveOut.AddDate(0, 0, h.depositDispositionDays(ctx)), Valid: true},
+			CreatedBy:       pgtype.Int8{Int64: adminID, Valid: adminID != 0},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("lease %d: %w", leaseID, errDepositDispositionIssued)
+		}
+		if err != nil {
+			return fmt.Errorf("failed saving deposit statement for lease %d: %w", leaseID, err)
+		}
+
+		// The part of the deposit kept for unpaid rent pays that rent down on the ledger
+		if applied := min(unpaidRent, depositAmount+interest); applied > 0 {
+			if _, err := tx.queries.CreateLedgerEntry(ctx, db.CreateLedgerEntryParams{
+				LeaseID:     leaseID,
+				TenantID:    lease.TenantID,
+				EntryType:   db.LedgerEntryTypeCredit,
+				Amount:      utils.ConvertFloatToPgNumeric(applied),
+				Description: "Security deposit applied to unpaid rent",
+				EntryDate:   pgtype.Date{Time: moveOut, Valid: true},
+				CreatedBy:   pgtype.Int8{Int64: adminID, Valid: adminID != 0},
+			}); err != nil {
+				return fmt.Errorf("failed applying deposit to rent on lease %d: %w", leaseID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return db.DepositDisposition{}, err
+	}
+	return disposition, nil
+}
+
+// sendDepositDisposition renders the statement and emails it to every tenant on the lease
+func (h *LeaseHandler) sendDepositDisposition(ctx context.Context, disposition db.DepositDisposition) error {
+	lease, err := h.queries.GetLeaseByID(ctx, disposition.LeaseID)
+	if err != nil {
+		return fmt.Errorf("lease %d not found: %w", disposition.LeaseID, err)
+	}
+	deposit, err := h.queries.GetLeaseDeposit(ctx, lease.ID)
+	if err != nil {
+		return fmt.Errorf("deposit of lease %d not found: %w", lease.ID, err)
+	}
+	occupants, err := h.queries.ListLeaseTenants(ctx, lease.ID)
+	if err != nil || len(occupants) == 0 {
+		return fmt.Errorf("no tenants to send the deposit statement of lease %d to", lease.ID)
+	}
+
+	resp := toDepositDispositionResponse(disposition)
+	data := templates.DepositDispositionData{
+		ReceivedOn:      deposit.ReceivedOn.Time,
+		MoveOutDate:     disposition.MoveOutDate.Time,
+		HoldingAccount:  deposit.HoldingAccount,
+		DepositAmount:   resp.DepositAmount,
+		InterestAmount:  resp.InterestAmount,
+		Deductions:      resp.Deductions,
+		TotalDeductions: resp.TotalDeductions,
+		RefundAmount:    resp.RefundAmount,
+	}
+	if landlord, err := h.landlordOfLease(ctx, lease.ApartmentID, lease.LandlordID); err == nil {
+		data.Landlord = templates.LeaseParty{Name: landlord.LegalName, Email: landlord.Signer.Email}
+	}
+	if details, err := h.queries.GetApartmentLeaseDetails(ctx, lease.ApartmentID); err == nil && details.UnitNumber.Valid {
+		data.UnitNumber = strconv.FormatInt(details.UnitNumber.Int64, 10)
+	}
+	for _, o := range occupants {
+		data.Tenants = append(data.Tenants, templates.LeaseParty{Name: fmt.Sprintf("%s %s", o.FirstName, o.LastName), Email: o.Email})
+	}
+
+	pdfData, err := templates.RenderDepositDisposition(data)
+	if err != nil {
+		return err
+	}
+	h.recordDepositStatement(ctx, lease, pdfData)
+
+	var body strings.Builder
+	body.WriteString("Hello,\n\n")
+	body.WriteString(fmt.Sprintf("Attached is the itemized statement for the security deposit on your lease that ended %s.\n\n",
+		disposition.MoveOutDate.Time.Format("January 2, 2006")))
+	if resp.RefundAmount >= 0 {
+		body.WriteString(fmt.Sprintf("Amount refunded to you: $%.2f\n", resp.RefundAmount))
+	} else {
+		body.WriteString(fmt.Sprintf("Balance still owed after applying the deposit: $%.2f\n", -resp.RefundAmount))
+	}
+	body.WriteString("\nPlease reply to this email if you have any questions about the deductions.\n")
+
+	filename := fmt.Sprintf("deposit-statement-lease-%d.pdf", lease.ID)
+	var sentTo []string
+	for _, t := range data.Tenants {
+		if err := smtp.SendEmailWithAttachment(t.Email, "Security deposit statement", body.String(), filename, "application/pdf", pdfData); err != nil {
+			log.Printf("[DEPOSIT] Failed emailing deposit statement of lease %d to %s: %v", lease.ID, t.Email, err)
+			continue
+		}
+		sentTo = append(sentTo, t.Email)
+	}
+	if len(sentTo) == 0 {
+		return fmt.Errorf("deposit statement of lease %d could not be emailed", lease.ID)
+	}
+
+	return h.queries.MarkDepositDispositionSent(ctx, db.MarkDepositDispositionSentParams{
+		ID:     disposition.ID,
+		SentTo: pgtype.Text{String: strings.Join(sentTo, ", "), Valid: true},
+	})
+}
+
+// recordDepositStatement keeps the deposit statement and files it in the vault for the tenants on the lease
+func (h *LeaseHandler) recordDepositStatement(ctx context.Context, lease db.GetLeaseByIDRow, pdfData []byte) {
+	key := leasePdfKey(lease.ID, "deposit-statement")
+	if err := h.documents.Put(ctx, key, pdfData, "application/pdf"); err != nil {
+		log.Printf("[DEPOSIT] Failed storing deposit statement of lease %d: %v", lease.ID, err)
+		return
+	}
+	h.recordDocument(ctx, db.CreateDocumentParams{
+		Category:    db.DocumentCategoryStatement,
+		Title:       "Security deposit statement",
+		FileName:    path.Base(key),
+		ContentType: "application/pdf",
+		SizeBytes:   int64(len(pdfData)),
+		StorageKey:  key,
+		LeaseID:     pgtype.Int8{Int64: lease.ID, Valid: true},
+		ApartmentID: pgtype.Int8{Int64: lease.ApartmentID, Valid: lease.ApartmentID != 0},
+	})
+}
+
+// issueAndSendDepositDisposition issues the deposit statement and emails it. A statement that could not be
+// emailed is retried by the cron job until its deadline.
+func (h *LeaseHandler) issueAndSendDepositDisposition(ctx context.Context, leaseID int64, moveOut time.Time, deductions []templates.DepositDeduction, adminID int64) (DepositDispositionResponse, error) {
+	disposition, err := h.issueDepositDisposition(ctx, leaseID, moveOut, deductions, adminID)
+	if err != nil {
+		return DepositDispositionResponse{}, err
+	}
+
+	if err := h.sendDepositDisposition(ctx, disposition); err != nil {
+		log.Printf("[DEPOSIT] %v; it is due by %s", err, disposition.DueBy.Time.Format("2006-01-02"))
+	} else if sent, err := h.queries.GetDepositDisposition(ctx, leaseID); err == nil {
+		disposition = sent
+	}
+	return toDepositDispositionResponse(disposition), nil
+}
+
+// settleDeposit issues and emails the deposit statement when an admin terminates a lease
+func (h *LeaseHandler) settleDeposit(ctx context.Context, leaseID int64, moveOut time.Time, deductions []templates.DepositDeduction, adminID int64) *DepositDispositionResponse {
+	resp, err := h.issueAndSendDepositDisposition(ctx, leaseID, moveOut, deductions, adminID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("[DEPOSIT] Lease %d has no deposit on record, no statement issued", leaseID)
+		return nil
+	}
+	if err != nil {
+		log.Printf("[DEPOSIT] %v", err)
+		return nil
+	}
+	return &resp
+}
+
+// openDepositDisposition holds the deposit of a lease that ended on moveOut for its statement, which is
+// issued by an admin once the damages are known, or by the cron job when the deadline arrives
+func (h *LeaseHandler) openDepositDisposition(ctx context.Context, leaseID int64, moveOut time.Time, adminID int64) *DepositDispositionResponse {
+	disposition, err := h.queries.OpenDepositDisposition(ctx, db.OpenDepositDispositionParams{
+		LeaseID:     leaseID,
+		MoveOutDate: pgtype.Date{Time: moveOut, Valid: true},
+		DueBy:       pgtype.Date{Time: moveOut.AddDate(0, 0, h.depositDispositionDays(ctx)), Valid: true},
+		CreatedBy:   pgtype.Int8{Int64: adminID, Valid: adminID != 0},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("[DEPOSIT] Lease %d has no deposit on record or its statement is already open", leaseID)
+		return nil
+	}
+	if err != nil {
+		log.Printf("[DEPOSIT] Failed opening deposit statement for lease %d: %v", leaseID, err)
+		return nil
+	}
+	log.Printf("[DEPOSIT] Deposit of lease %d awaits its statement, due by %s", leaseID, disposition.DueBy.Time.Format("2006-01-02"))
+	resp := toDepositDispositionResponse(disposition)
+	return &resp
+}
+
+// IssueDepositDisposition issues the pending deposit statement of a lease that ended, with the deductions
+// the admin itemized after inspecting the unit, and emails it to the tenants
+func (h *LeaseHandler) IssueDepositDisposition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+
+	var req DepositDispositionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid deposit statement request", http.StatusBadRequest)
+		return
+	}
+	if err := validateDepositDeductions(req.Deductions); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	pending, err := h.queries.GetDepositDisposition(ctx, leaseID)
+	if err != nil {
+		http.Error(w, "No deposit statement is open for this lease", http.StatusNotFound)
+		return
+	}
+	if pending.Status != db.DepositDispositionStatusPendingDisposition {
+		http.Error(w, "The deposit statement for this lease was already issued", http.StatusConflict)
+		return
+	}
+
+	resp, err := h.issueAndSendDepositDisposition(ctx, leaseID, pending.MoveOutDate.Time, req.Deductions, adminID)
+	if errors.Is(err, errDepositDispositionIssued) {
+		http.Error(w, "The deposit statement for this lease was already issued", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("[DEPOSIT] %v", err)
+		http.Error(w, "Failed to issue deposit statement", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[DEPOSIT] Issued deposit statement for lease %d with %d deductions", leaseID, len(req.Deductions))
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[DEPOSIT] Error encoding response: %v", err)
+	}
+}
+
+// GetDepositDisposition returns the deposit statement issued when a lease ended
+func (h *LeaseHandler) GetDepositDisposition(w http.ResponseWriter, r *http.Request) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+
+	disposition, err := h.queries.GetDepositDisposition(r.Context(), leaseID)
+	if err != nil {
+		http.Error(w, "No deposit statement for this lease", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toDepositDispositionResponse(disposition)); err != nil {
+		log.Printf("[DEPOSIT] Error encoding response: %v", err)
+	}
+}
+
+// SendDepositDispositions emails every deposit statement that has not reached the tenant yet. Run from
+// cron so a failed send is retried before the statutory deadline; overdue statements are logged. Statements
+// still pending on their deadline are issued first with whatever deductions the ledger shows.
+func (h *LeaseHandler) SendDepositDispositions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	due, err := h.queries.ListDueDepositDispositions(ctx, pgtype.Date{Time: today, Valid: true})
+	if err != nil {
+		log.Printf("[DEPOSIT] Failed listing statements due today: %v", err)
+		http.Error(w, "Failed to list deposit statements", http.StatusInternalServerError)
+		return
+	}
+	issued := 0
+	for _, d := range due {
+		log.Printf("[DEPOSIT] Statement for lease %d is due by %s and no deductions were recorded, issuing it",
+			d.LeaseID, d.DueBy.Time.Format("2006-01-02"))
+		if _, err := h.issueDepositDisposition(ctx, d.LeaseID, d.MoveOutDate.Time, nil, 0); err != nil {
+			log.Printf("[DEPOSIT] %v", err)
+			continue
+		}
+		issued++
+	}
+
+	pending, err := h.queries.ListUnsentDepositDispositions(ctx)
+	if err != nil {
+		log.Printf("[DEPOSIT] Failed listing unsent statements: %v", err)
+		http.Error(w, "Failed to list deposit statements", http.StatusInternalServerError)
+		return
+	}
+
+	sent, overdue := 0, 0
+	for _, d := range pending {
+		if err := h.sendDepositDisposition(ctx, d); err != nil {
+			log.Printf("[DEPOSIT] %v", err)
+		} else {
+			sent++
+			continue
+		}
+		if today.After(d.DueBy.Time) {
+			overdue++
+			log.Printf("[DEPOSIT] Statement for lease %d was due by %s and has not been sent", d.LeaseID, d.DueBy.Time.Format("2006-01-02"))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"issued":  issued,
+		"pending": len(pending),
+		"sent":    sent,
+		"overdue": overdue,
+	})
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/templates"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var testMoveOut = time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)
+
+// depositStatement is the statement of lease 9 in the given status, for a $1000 deposit
+func depositStatement(status db.DepositDispositionStatus) db.DepositDisposition {
+	return db.DepositDisposition{
+		ID:              2,
+		LeaseID:         9,
+		DepositAmount:   utils.ConvertFloatToPgNumeric(1000),
+		InterestAmount:  utils.ConvertFloatToPgNumeric(0),
+		Deductions:      []byte("[]"),
+		TotalDeductions: utils.ConvertFloatToPgNumeric(0),
+		RefundAmount:    utils.ConvertFloatToPgNumeric(1000),
+		MoveOutDate:     pgtype.Date{Time: testMoveOut, Valid: true},
+		DueBy:           pgtype.Date{Time: testMoveOut.AddDate(0, 0, defaultDepositDispositionDays), Valid: true},
+		Status:          status,
+	}
+}
+
+// answerDeposit answers the lookups made when issuing the statement of lease 9
+func answerDeposit(fdb *fakeDB) {
+	fdb.returns("GetLeaseDeposit", db.LeaseDeposit{LeaseID: 9, Amount: utils.ConvertFloatToPgNumeric(1000), ReceivedOn: pgtype.Date{Time: testMoveOut.AddDate(-1, 0, 0), Valid: true}})
+	fdb.returns("GetLeaseByID", activeLease())
+	fdb.on("CreateDepositDisposition", func(args []any) (any, error) {
+		issued := depositStatement(db.DepositDispositionStatusIssued)
+		issued.Deductions = args[3].([]byte)
+		issued.TotalDeductions = args[4].(pgtype.Numeric)
+		issued.RefundAmount = args[5].(pgtype.Numeric)
+		return issued, nil
+	})
+}
+
+func TestIssueDepositDisposition(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	signInAdmin(fdb)
+	answerDeposit(fdb)
+	fdb.returns("GetDepositDisposition", depositStatement(db.DepositDispositionStatusPendingDisposition))
+
+	body := DepositDispositionRequest{Deductions: []templates.DepositDeduction{{Category: "damages", Description: "Broken window", Amount: 200}}}
+	rec := httptest.NewRecorder()
+	h.IssueDepositDisposition(rec, newTestRequest(t, http.MethodPost, "/admin/leases/9/deposit/disposition", body, "leaseID", "9"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	issued := fdb.called("CreateDepositDisposition")
+	if len(issued) != 1 {
+		t.Fatalf("CreateDepositDisposition calls = %+v, want the statement issued once", issued)
+	}
+	if moveOut := issued[0].Args[6].(pgtype.Date); !moveOut.Time.Equal(testMoveOut) {
+		t.Errorf("issued for move-out %s, want the %s recorded when the lease ended", moveOut.Time, testMoveOut)
+	}
+	var got DepositDispositionResponse
+	decodeResponse(t, rec, &got)
+	if got.Status != "issued" || got.TotalDeductions != 200 || got.RefundAmount != 800 {
+		t.Errorf("statement = %+v, want $200 withheld and $800 refunded", got)
+	}
+}
+
+func TestIssueDepositDispositionRefusesIssuedStatement(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	signInAdmin(fdb)
+	answerDeposit(fdb)
+	fdb.returns("GetDepositDisposition", depositStatement(db.DepositDispositionStatusIssued))
+
+	rec := httptest.NewRecorder()
+	h.IssueDepositDisposition(rec, newTestRequest(t, http.MethodPost, "/admin/leases/9/deposit/disposition", DepositDispositionRequest{}, "leaseID", "9"))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409: %s", rec.Code, rec.Body.String())
+	}
+	if calls := fdb.called("CreateDepositDisposition"); len(calls) != 0 {
+		t.Errorf("issued statement changed: %+v", calls)
+	}
+}
+
+func TestIssueDepositDispositionWithoutOpenStatement(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	signInAdmin(fdb)
+
+	rec := httptest.NewRecorder()
+	h.IssueDepositDisposition(rec, newTestRequest(t, http.MethodPost, "/admin/leases/9/deposit/disposition", DepositDispositionRequest{}, "leaseID", "9"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSendDepositDispositionsIssuesStatementsOnTheirDeadline(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerDeposit(fdb)
+	fdb.returns("ListDueDepositDispositions", []db.DepositDisposition{depositStatement(db.DepositDispositionStatusPendingDisposition)})
+
+	rec := httptest.NewRecorder()
+	h.SendDepositDispositions(rec, newTestRequest(t, http.MethodPost, "/cron/deposits/dispositions", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var got map[string]int
+	decodeResponse(t, rec, &got)
+	if got["issued"] != 1 {
+		t.Errorf("response = %v, want one statement issued", got)
+	}
+	issued := fdb.called("CreateDepositDisposition")
+	if len(issued) != 1 || string(issued[0].Args[3].([]byte)) != "[]" {
+		t.Errorf("CreateDepositDisposition calls = %+v, want the statement issued without deductions", issued)
+	}
+}
+
+func TestIssueDepositDispositionRollsBackWhenRentCannotBeCredited(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	signInAdmin(fdb)
+	answerDeposit(fdb)
+	fdb.returns("GetDepositDisposition", depositStatement(db.DepositDispositionStatusPendingDisposition))
+	fdb.fails("CreateLedgerEntry", errors.New("ledger unavailable"))
+
+	body := DepositDispositionRequest{Deductions: []templates.DepositDeduction{{Category: "unpaid_rent", Description: "March rent", Amount: 300}}}
+	rec := httptest.NewRecorder()
+	h.IssueDepositDisposition(rec, newTestRequest(t, http.MethodPost, "/admin/leases/9/deposit/disposition", body, "leaseID", "9"))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500: %s", rec.Code, rec.Body.String())
+	}
+	if fdb.committed != 0 || fdb.rolledBack == 0 {
+		t.Errorf("committed %d, rolled back %d transactions, want the statement rolled back with the credit", fdb.committed, fdb.rolledBack)
+	}
+}
+
+func TestSendDepositDispositionsWithholdsTheLedgerBalance(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerDeposit(fdb)
+	fdb.returns("ListDueDepositDispositions", []db.DepositDisposition{depositStatement(db.DepositDispositionStatusPendingDisposition)})
+	fdb.returns("ListLedgerEntriesByLease", []db.RentLedgerEntry{
+		{ID: 1, LeaseID: 9, EntryType: db.LedgerEntryTypeCharge, Amount: utils.ConvertFloatToPgNumeric(300.10)},
+		{ID: 2, LeaseID: 9, EntryType: db.LedgerEntryTypePayment, Amount: utils.ConvertFloatToPgNumeric(0.1)},
+	})
+
+	rec := httptest.NewRecorder()
+	h.SendDepositDispositions(rec, newTestRequest(t, http.MethodPost, "/cron/deposits/dispositions", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	issued := fdb.called("CreateDepositDisposition")
+	if len(issued) != 1 || utils.ConvertPgNumericToFloat(issued[0].Args[4].(pgtype.Numeric)) != 300 {
+		t.Fatalf("CreateDepositDisposition calls = %+v, want the 300 owing withheld", issued)
+	}
+	credits := fdb.called("CreateLedgerEntry")
+	if len(credits) != 1 || utils.ConvertPgNumericToFloat(credits[0].Args[3].(pgtype.Numeric)) != 300 {
+		t.Errorf("CreateLedgerEntry calls = %+v, want 300 of the deposit applied to rent", credits)
+	}
+}
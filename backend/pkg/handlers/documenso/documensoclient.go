@@ -2,6 +2,7 @@ package documenso
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,19 +14,28 @@ import (
 	"time"
 )
 
-// DocumensoClientInterface defines interactions with the Documenso API
+// DocumensoClientInterface defines the e-signature operations the lease flow depends on.
+// DocumensoClient talks to a Documenso instance; LocalSigner implements the same contract
+// in-process so leases can be created and signed without one.
 type DocumensoClientInterface interface {
-	UploadDocument(pdfData []byte, title string) (string, error)
-
 	UploadDocumentWithSigners(pdfData []byte, title string, signers []Signer) (string, map[string]RecipientInfo, string, error)
+	GetRecipients(documentID string) ([]RecipientInfo, error)
+	AddSignatureField(docID string, recipientID, page int, x, y, width, height float64, fieldType ...string) error
+	SendDocument(documentID string) (map[string]string, error)
+
+	GetSigningURL(documentID string) string
+	GetSigningURLs(documentID string, tenantEmail string, landlordEmail string) (string, string, error)
+	GetDocumentAdminURL(documentID string) string
+	GetDocumentDownloadURL(documentID string) (string, error)
+	DownloadDocument(documentID string) ([]byte, error)
 
 	VerifyDocumentExists(documentID string) (bool, error)
-	GetTenantSigningURL(documentID string, tenantEmail string) (string, error)
 	DeleteDocument(documentID string) error
-	DownloadDocument(documentID string) ([]byte, error)
-	SendDocument(documentID string) (map[string]string, error)
+	Ping(ctx context.Context) error
 }
 
+var _ DocumensoClientInterface = (*DocumensoClient)(nil)
+
 // DocumensoClient handles Documenso API interactions
 type DocumensoClient struct {
 	BaseURL string
@@ -267,6 +277,86 @@ func (c *DocumensoClient) VerifyDocumentExists(documentID string) (bool, error)
 	return false, fmt.Errorf("document verification failed after %d attempts", maxRetries)
 }
 
+// GetRecipients returns the recipients of a document with their Documenso recipient IDs
+func (c *DocumensoClient) GetRecipients(documentID string) ([]RecipientInfo, error) {
+	// Documenso processes uploads asynchronously, give it a moment before reading back
+	time.Sleep(5 * time.Second)
+
+	url := fmt.Sprintf("%s/documents/%s", c.BaseURL, documentID)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.ApiKey)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document details: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Documenso returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var docResponse struct {
+		Recipients []struct {
+			Id         int    `json:"id"`
+			Email      string `json:"email"`
+			Name       string `json:"name"`
+			SigningURL string `json:"signingUrl"`
+		} `json:"recipients"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&docResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse document response: %w", err)
+	}
+
+	recipients := make([]RecipientInfo, 0, len(docResponse.Recipients))
+	for _, r := range docResponse.Recipients {
+		recipients = append(recipients, RecipientInfo{
+			ID:         r.Id,
+			Email:      r.Email,
+			Name:       r.Name,
+			SigningURL: r.SigningURL,
+		})
+	}
+	return recipients, nil
+}
+
+// GetDocumentAdminURL returns the URL an admin can open to view the document in Documenso
+func (c *DocumensoClient) GetDocumentAdminURL(documentID string) string {
+	publicHost := os.Getenv("DOCUMENSO_PUBLIC_URL")
+	if publicHost == "" {
+		publicHost = strings.Replace(c.BaseURL, "/api/v1", "", 1)
+	}
+	publicHost = strings.TrimSuffix(publicHost, "/")
+	return fmt.Sprintf("%s/documents/%s", publicHost, documentID)
+}
+
+// Ping checks that the Documenso API is reachable with the configured key
+func (c *DocumensoClient) Ping(ctx context.Context) error {
+	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(checkCtx, "GET", c.BaseURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.ApiKey)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("service check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("service check returned status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // DeleteDocument deletes a document from Documenso by ID
 func (c *DocumensoClient) DeleteDocument(documentID string) error {
 	// Construct the URL for document deletion
@@ -369,8 +459,8 @@ func (c *DocumensoClient) GetSigningURL(documentID string) string {
 	return signURL
 }
 
-// AddSignatureField adds a signature field or date field to a document for a specific recipient with retries
-func (c *DocumensoClient) AddSignatureField(docID string, recipientID int, x, y, width, height float64, fieldType ...string) error {
+// AddSignatureField adds a signature field or date field on the given page (1-based) for a specific recipient with retries
+func (c *DocumensoClient) AddSignatureField(docID string, recipientID, page int, x, y, width, height float64, fieldType ...string) error {
 	maxRetries := 3
 
 	// Default to signature field if no type is specified
@@ -384,7 +474,7 @@ func (c *DocumensoClient) AddSignatureField(docID string, recipientID int, x, y,
 		payload := map[string]interface{}{
 			"recipientId": recipientID,
 			"type":        actualFieldType, // SIGNATURE or DATE
-			"pageNumber":  1,               // First page
+			"pageNumber":  page,
 			"pageX":       x,
 			"pageY":       y,
 			"pageWidth":   width,
@@ -800,8 +890,8 @@ func UpdateDocumensoConfig(w http.ResponseWriter, r *http.Request) {
 	os.Setenv("DOCUMENSO_API_KEY", config.ApiKey)
 	os.Setenv("DOCUMENSO_WEBHOOK_SECRET", config.WebhookSecret)
 
-	log.Printf("[DOCUMENSO_CONFIG] Updated API key to %s and webhook secret to %s",
-		MaskSecret(config.ApiKey), MaskSecret(config.WebhookSecret))
+	log.Printf("[DOCUMENSO_CONFIG] Updated API key to %s and rotated the webhook secret",
+		MaskSecret(config.ApiKey))
 
 	// Write the config to a .env file or similar if needed
 	// This is optional and depends on your deployment strategy
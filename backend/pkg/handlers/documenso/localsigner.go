@@ -0,0 +1,684 @@
+package documenso
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// LocalSigner is an in-process e-signature provider. It keeps uploaded PDFs on disk,
+// serves simple signing pages and posts the same webhook callbacks Documenso would,
+// so the lease flow can run end to end in development and tests.
+type LocalSigner struct {
+	// Dir is where PDFs and document state are stored
+	Dir string
+	// PublicURL is the base URL the signing pages are mounted under
+	PublicURL string
+	// WebhookURL receives the Documenso-shaped event callbacks
+	WebhookURL string
+	Client     *http.Client
+
+	mu     sync.Mutex
+	secret string
+	docs   map[string]*localDocument
+	nextID int
+}
+
+var _ DocumensoClientInterface = (*LocalSigner)(nil)
+
+type localDocument struct {
+	ID            int               `json:"id"`
+	Title         string            `json:"title"`
+	Status        string            `json:"status"` // PENDING, COMPLETED or REJECTED
+	DownloadToken string            `json:"download_token"`
+	Recipients    []*localRecipient `json:"recipients"`
+	CreatedAt     time.Time         `json:"created_at"`
+	CompletedAt   *time.Time        `json:"completed_at,omitempty"`
+}
+
+type localRecipient struct {
+	ID       int          `json:"id"`
+	Name     string       `json:"name"`
+	Email    string       `json:"email"`
+	Role     SignerRole   `json:"role"`
+	Token    string       `json:"token"`
+	SignedAt *time.Time   `json:"signed_at,omitempty"`
+	Fields   []localField `json:"fields"`
+
+	RejectedAt      *time.Time `json:"rejected_at,omitempty"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+}
+
+type localField struct {
+	Type   string  `json:"type"`
+	Page   int     `json:"page"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// NewLocalSigner creates a LocalSigner rooted at dir and loads any documents left from a previous run
+func NewLocalSigner(dir, publicURL, webhookURL, webhookSecret string) (*LocalSigner, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local signer directory: %w", err)
+	}
+
+	s := &LocalSigner{
+		Dir:        dir,
+		PublicURL:  strings.TrimSuffix(publicURL, "/"),
+		WebhookURL: webhookURL,
+		secret:     webhookSecret,
+		Client:     &http.Client{Timeout: 30 * time.Second},
+		docs:       make(map[string]*localDocument),
+		nextID:     1,
+	}
+
+	stateFiles, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local signer documents: %w", err)
+	}
+	for _, path := range stateFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("[LOCAL_SIGNER] Skipping unreadable document state %s: %v", path, err)
+			continue
+		}
+		var doc localDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			log.Printf("[LOCAL_SIGNER] Skipping invalid document state %s: %v", path, err)
+			continue
+		}
+		s.docs[strconv.Itoa(doc.ID)] = &doc
+		if doc.ID >= s.nextID {
+			s.nextID = doc.ID + 1
+		}
+	}
+
+	log.Printf("[LOCAL_SIGNER] Serving signing pages at %s (%d stored documents)", s.PublicURL, len(s.docs))
+	return s, nil
+}
+
+func newLocalToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+func (s *LocalSigner) pdfPath(documentID string) string {
+	return filepath.Join(s.Dir, documentID+".pdf")
+}
+
+// saveLocked persists document state. Callers must hold s.mu.
+func (s *LocalSigner) saveLocked(doc *localDocument) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.Dir, strconv.Itoa(doc.ID)+".json"), data, 0o644)
+}
+
+func (s *LocalSigner) signingURL(r *localRecipient) string {
+	return fmt.Sprintf("%s/sign/%s", s.PublicURL, r.Token)
+}
+
+func (s *LocalSigner) recipientInfo(r *localRecipient) RecipientInfo {
+	return RecipientInfo{
+		ID:         r.ID,
+		Email:      r.Email,
+		Name:       r.Name,
+		SigningURL: s.signingURL(r),
+	}
+}
+
+// UploadDocumentWithSigners stores the PDF and creates a signing token for every signer
+func (s *LocalSigner) UploadDocumentWithSigners(pdfData []byte, title string, signers []Signer) (string, map[string]RecipientInfo, string, error) {
+	if len(pdfData) == 0 {
+		return "", nil, "", errors.New("empty PDF document")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc := &localDocument{
+		ID:            s.nextID,
+		Title:         title,
+		Status:        "PENDING",
+		DownloadToken: newLocalToken(),
+		CreatedAt:     time.Now().UTC(),
+	}
+	for i, signer := range signers {
+		doc.Recipients = append(doc.Recipients, &localRecipient{
+			ID:    doc.ID*100 + i + 1,
+			Name:  signer.Name,
+			Email: signer.Email,
+			Role:  signer.Role,
+			Token: newLocalToken(),
+		})
+	}
+
+	documentID := strconv.Itoa(doc.ID)
+	if err := os.WriteFile(s.pdfPath(documentID), pdfData, 0o644); err != nil {
+		return "", nil, "", fmt.Errorf("failed to store PDF: %w", err)
+	}
+	if err := s.saveLocked(doc); err != nil {
+		return "", nil, "", fmt.Errorf("failed to store document state: %w", err)
+	}
+	s.docs[documentID] = doc
+	s.nextID++
+
+	recipients := make(map[string]RecipientInfo, len(doc.Recipients))
+	for _, r := range doc.Recipients {
+		recipients[r.Email] = s.recipientInfo(r)
+	}
+
+	log.Printf("[LOCAL_SIGNER] Stored document %s (%q) with %d recipients", documentID, title, len(doc.Recipients))
+	return documentID, recipients, "", nil
+}
+
+// GetRecipients returns the recipients of a stored document
+func (s *LocalSigner) GetRecipients(documentID string) ([]RecipientInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.docs[documentID]
+	if !ok {
+		return nil, fmt.Errorf("document %s not found", documentID)
+	}
+
+	recipients := make([]RecipientInfo, 0, len(doc.Recipients))
+	for _, r := range doc.Recipients {
+		recipients = append(recipients, s.recipientInfo(r))
+	}
+	return recipients, nil
+}
+
+// AddSignatureField records a field the recipient has to fill in
+func (s *LocalSigner) AddSignatureField(docID string, recipientID, page int, x, y, width, height float64, fieldType ...string) error {
+	actualFieldType := "SIGNATURE"
+	if len(fieldType) > 0 && fieldType[0] == "DATE" {
+		actualFieldType = "DATE"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.docs[docID]
+	if !ok {
+		return fmt.Errorf("document %s not found", docID)
+	}
+	for _, r := range doc.Recipients {
+		if r.ID == recipientID {
+			r.Fields = append(r.Fields, localField{Type: actualFieldType, Page: page, X: x, Y: y, Width: width, Height: height})
+			return s.saveLocked(doc)
+		}
+	}
+	return fmt.Errorf("recipient %d not found on document %s", recipientID, docID)
+}
+
+// SendDocument returns the signing URL of every recipient keyed by lowercase email
+func (s *LocalSigner) SendDocument(documentID string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.docs[documentID]
+	if !ok {
+		return nil, fmt.Errorf("document %s not found", documentID)
+	}
+
+	signingURLs := make(map[string]string, len(doc.Recipients))
+	for _, r := range doc.Recipients {
+		signingURLs[strings.ToLower(r.Email)] = s.signingURL(r)
+	}
+	return signingURLs, nil
+}
+
+// GetSigningURL returns the document overview page
+func (s *LocalSigner) GetSigningURL(documentID string) string {
+	return fmt.Sprintf("%s/documents/%s", s.PublicURL, documentID)
+}
+
+// GetSigningURLs returns the tenant and landlord signing URLs of a document
+func (s *LocalSigner) GetSigningURLs(documentID string, tenantEmail string, landlordEmail string) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.docs[documentID]
+	if !ok {
+		return "", "", fmt.Errorf("document %s not found", documentID)
+	}
+
+	var tenantSigningURL, landlordSigningURL string
+	for _, r := range doc.Recipients {
+		if strings.EqualFold(r.Email, tenantEmail) {
+			tenantSigningURL = s.signingURL(r)
+		}
+		if strings.EqualFold(r.Email, landlordEmail) {
+			landlordSigningURL = s.signingURL(r)
+		}
+	}
+	if tenantSigningURL == "" || landlordSigningURL == "" {
+		return "", "", fmt.Errorf("tenant %s or landlord %s not found in document %s", tenantEmail, landlordEmail, documentID)
+	}
+	return tenantSigningURL, landlordSigningURL, nil
+}
+
+// GetDocumentAdminURL returns the document overview page
+func (s *LocalSigner) GetDocumentAdminURL(documentID string) string {
+	return s.GetSigningURL(documentID)
+}
+
+// GetDocumentDownloadURL returns a tokenized URL serving the stored PDF
+func (s *LocalSigner) GetDocumentDownloadURL(documentID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.docs[documentID]
+	if !ok {
+		return "", fmt.Errorf("document %s not found", documentID)
+	}
+	return fmt.Sprintf("%s/documents/%s/download?token=%s", s.PublicURL, documentID, doc.DownloadToken), nil
+}
+
+// DownloadDocument returns the stored PDF
+func (s *LocalSigner) DownloadDocument(documentID string) ([]byte, error) {
+	if ok, _ := s.VerifyDocumentExists(documentID); !ok {
+		return nil, fmt.Errorf("document %s not found", documentID)
+	}
+	return os.ReadFile(s.pdfPath(documentID))
+}
+
+// VerifyDocumentExists reports whether the document is stored
+func (s *LocalSigner) VerifyDocumentExists(documentID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.docs[documentID]
+	return ok, nil
+}
+
+// DeleteDocument removes the PDF and its state
+func (s *LocalSigner) DeleteDocument(documentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.docs[documentID]; !ok {
+		return fmt.Errorf("document %s not found", documentID)
+	}
+	delete(s.docs, documentID)
+
+	for _, path := range []string{s.pdfPath(documentID), filepath.Join(s.Dir, documentID+".json")} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Ping checks that the storage directory is usable
+func (s *LocalSigner) Ping(ctx context.Context) error {
+	info, err := os.Stat(s.Dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", s.Dir)
+	}
+	return nil
+}
+
+// SetWebhookSecret changes the secret webhook deliveries are signed with
+func (s *LocalSigner) SetWebhookSecret(secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secret = secret
+}
+
+func (s *LocalSigner) webhookSecret() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.secret
+}
+
+// Handler serves the signing pages. Mount it at the path PublicURL points to.
+func (s *LocalSigner) Handler() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/sign/{token}", s.handleSigningPage)
+	r.Post("/sign/{token}", s.handleSign)
+	r.Post("/sign/{token}/reject", s.handleReject)
+	r.Get("/sign/{token}/document", s.handleRecipientPDF)
+	r.Get("/documents/{documentID}", s.handleDocumentPage)
+	r.Get("/documents/{documentID}/download", s.handleDownload)
+	return r
+}
+
+// findByTokenLocked returns the document and recipient a signing token belongs to. Callers must hold s.mu.
+func (s *LocalSigner) findByTokenLocked(token string) (*localDocument, *localRecipient) {
+	for _, doc := range s.docs {
+		for _, r := range doc.Recipients {
+			if r.Token == token {
+				return doc, r
+			}
+		}
+	}
+	return nil, nil
+}
+
+var localSigningPage = template.Must(template.New("sign").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="font-family: sans-serif; max-width: 720px; margin: 2em auto;">
+<h1>{{.Title}}</h1>
+{{if not .Overview}}<p>Signing as <strong>{{.Name}}</strong> ({{.Email}})</p>{{end}}
+<p><a href="{{.DocumentURL}}" target="_blank">Review the document</a></p>
+{{if .Overview}}
+<p>Status: {{.Status}}</p>
+{{else if .Signed}}
+<p>You signed this document on {{.SignedAt}}.</p>
+{{else if .Closed}}
+<p>This document is no longer open for signing ({{.Status}}).</p>
+{{else}}
+<form method="post">
+<p><label><input type="checkbox" name="agree" value="yes" required> I have read the document and agree to sign it electronically.</label></p>
+<p><label>Type your full name: <input type="text" name="signature" required></label></p>
+<p><button type="submit">Sign document</button></p>
+</form>
+<form method="post" action="{{.RejectURL}}">
+<p><label>Reason: <input type="text" name="reason" required></label> <button type="submit">Reject document</button></p>
+</form>
+{{end}}
+<h2>Signers</h2>
+<ul>
+{{range .Recipients}}<li>{{.Name}} ({{.Email}}): {{if .SignedAt}}signed{{else if .RejectedAt}}rejected ({{.RejectionReason}}){{else}}pending{{end}}</li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+type localPageData struct {
+	Overview    bool
+	Status      string
+	Title       string
+	Name        string
+	Email       string
+	DocumentURL string
+	Signed      bool
+	SignedAt    string
+	Closed      bool
+	RejectURL   string
+	Recipients  []*localRecipient
+}
+
+func (s *LocalSigner) handleSigningPage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	doc, recipient := s.findByTokenLocked(chi.URLParam(r, "token"))
+	if doc == nil {
+		s.mu.Unlock()
+		http.Error(w, "Signing link not found", http.StatusNotFound)
+		return
+	}
+	data := localPageData{
+		Title:       doc.Title,
+		Name:        recipient.Name,
+		Email:       recipient.Email,
+		DocumentURL: fmt.Sprintf("%s/sign/%s/document", s.PublicURL, recipient.Token),
+		Signed:      recipient.SignedAt != nil,
+		Status:      doc.Status,
+		Closed:      doc.Status != "PENDING",
+		RejectURL:   fmt.Sprintf("%s/sign/%s/reject", s.PublicURL, recipient.Token),
+		Recipients:  doc.Recipients,
+	}
+	if recipient.SignedAt != nil {
+		data.SignedAt = recipient.SignedAt.Format(time.RFC1123)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := localSigningPage.Execute(w, data); err != nil {
+		log.Printf("[LOCAL_SIGNER] Failed rendering signing page: %v", err)
+	}
+}
+
+func (s *LocalSigner) handleDocumentPage(w http.ResponseWriter, r *http.Request) {
+	documentID := chi.URLParam(r, "documentID")
+
+	s.mu.Lock()
+	doc, ok := s.docs[documentID]
+	if !ok {
+		s.mu.Unlock()
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return
+	}
+	data := localPageData{
+		Overview:    true,
+		Status:      doc.Status,
+		Title:       doc.Title,
+		DocumentURL: fmt.Sprintf("%s/documents/%s/download?token=%s", s.PublicURL, documentID, doc.DownloadToken),
+		Recipients:  doc.Recipients,
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := localSigningPage.Execute(w, data); err != nil {
+		log.Printf("[LOCAL_SIGNER] Failed rendering document page: %v", err)
+	}
+}
+
+func (s *LocalSigner) handleRecipientPDF(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	doc, _ := s.findByTokenLocked(chi.URLParam(r, "token"))
+	s.mu.Unlock()
+	if doc == nil {
+		http.Error(w, "Signing link not found", http.StatusNotFound)
+		return
+	}
+	s.servePDF(w, strconv.Itoa(doc.ID))
+}
+
+func (s *LocalSigner) handleDownload(w http.ResponseWriter, r *http.Request) {
+	documentID := chi.URLParam(r, "documentID")
+
+	s.mu.Lock()
+	doc, ok := s.docs[documentID]
+	s.mu.Unlock()
+	if !ok || r.URL.Query().Get("token") != doc.DownloadToken {
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return
+	}
+	s.servePDF(w, documentID)
+}
+
+func (s *LocalSigner) servePDF(w http.ResponseWriter, documentID string) {
+	data, err := os.ReadFile(s.pdfPath(documentID))
+	if err != nil {
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "document-"+documentID+".pdf"))
+	w.Write(data)
+}
+
+func (s *LocalSigner) handleSign(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	if r.PostForm.Get("agree") != "yes" || strings.TrimSpace(r.PostForm.Get("signature")) == "" {
+		http.Error(w, "You must agree and type your name to sign", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	doc, recipient := s.findByTokenLocked(chi.URLParam(r, "token"))
+	if doc == nil {
+		s.mu.Unlock()
+		http.Error(w, "Signing link not found", http.StatusNotFound)
+		return
+	}
+	if recipient.SignedAt != nil || doc.Status != "PENDING" {
+		s.mu.Unlock()
+		http.Redirect(w, r, s.signingURL(recipient), http.StatusSeeOther)
+		return
+	}
+
+	now := time.Now().UTC()
+	recipient.SignedAt = &now
+	completed := true
+	for _, other := range doc.Recipients {
+		if other.Role == SignerRoleSigner && other.SignedAt == nil {
+			completed = false
+		}
+	}
+	if completed {
+		doc.Status = "COMPLETED"
+		doc.CompletedAt = &now
+	}
+	if err := s.saveLocked(doc); err != nil {
+		log.Printf("[LOCAL_SIGNER] Failed saving document %d: %v", doc.ID, err)
+	}
+	signedEvent := s.webhookBodyLocked(EventDocumentSigned, doc)
+	var completedEvent []byte
+	if completed {
+		completedEvent = s.webhookBodyLocked(EventDocumentCompleted, doc)
+	}
+	s.mu.Unlock()
+
+	log.Printf("[LOCAL_SIGNER] %s signed document %d", recipient.Email, doc.ID)
+	s.emit(EventDocumentSigned, signedEvent)
+	if completed {
+		log.Printf("[LOCAL_SIGNER] Document %d completed", doc.ID)
+		s.emit(EventDocumentCompleted, completedEvent)
+	}
+
+	http.Redirect(w, r, s.signingURL(recipient), http.StatusSeeOther)
+}
+
+// handleReject declines the document on behalf of a recipient, which closes it for everyone
+func (s *LocalSigner) handleReject(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	reason := strings.TrimSpace(r.PostForm.Get("reason"))
+	if reason == "" {
+		http.Error(w, "A reason is required to reject the document", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	doc, recipient := s.findByTokenLocked(chi.URLParam(r, "token"))
+	if doc == nil {
+		s.mu.Unlock()
+		http.Error(w, "Signing link not found", http.StatusNotFound)
+		return
+	}
+	if recipient.SignedAt != nil || doc.Status != "PENDING" {
+		s.mu.Unlock()
+		http.Redirect(w, r, s.signingURL(recipient), http.StatusSeeOther)
+		return
+	}
+
+	now := time.Now().UTC()
+	recipient.RejectedAt = &now
+	recipient.RejectionReason = reason
+	doc.Status = "REJECTED"
+	if err := s.saveLocked(doc); err != nil {
+		log.Printf("[LOCAL_SIGNER] Failed saving document %d: %v", doc.ID, err)
+	}
+	rejectedEvent := s.webhookBodyLocked(EventDocumentRejected, doc)
+	s.mu.Unlock()
+
+	log.Printf("[LOCAL_SIGNER] %s rejected document %d", recipient.Email, doc.ID)
+	s.emit(EventDocumentRejected, rejectedEvent)
+
+	http.Redirect(w, r, s.signingURL(recipient), http.StatusSeeOther)
+}
+
+// webhookBodyLocked builds a Documenso-shaped webhook body for doc. Callers must hold s.mu.
+func (s *LocalSigner) webhookBodyLocked(event string, doc *localDocument) []byte {
+	sorted := append([]*localRecipient(nil), doc.Recipients...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	recipients := make([]WebhookRecipient, 0, len(sorted))
+	for _, r := range sorted {
+		signingStatus := SigningStatusNotSigned
+		if r.SignedAt != nil {
+			signingStatus = SigningStatusSigned
+		} else if r.RejectedAt != nil {
+			signingStatus = SigningStatusRejected
+		}
+		recipients = append(recipients, WebhookRecipient{
+			ID:              r.ID,
+			DocumentID:      doc.ID,
+			Email:           r.Email,
+			Name:            r.Name,
+			Role:            r.Role,
+			Token:           r.Token,
+			SigningStatus:   signingStatus,
+			SignedAt:        r.SignedAt,
+			RejectionReason: r.RejectionReason,
+		})
+	}
+
+	body, _ := json.Marshal(WebhookEvent{
+		Event: event,
+		Payload: WebhookDocument{
+			ID:          doc.ID,
+			Title:       doc.Title,
+			Status:      doc.Status,
+			CreatedAt:   doc.CreatedAt,
+			CompletedAt: doc.CompletedAt,
+			Recipients:  recipients,
+		},
+		CreatedAt:       time.Now().UTC(),
+		WebhookEndpoint: s.WebhookURL,
+	})
+	return body
+}
+
+// emit posts a webhook callback the same way Documenso does
+func (s *LocalSigner) emit(event string, body []byte) {
+	if s.WebhookURL == "" {
+		return
+	}
+
+	req, err := http.NewRequest("POST", s.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("[LOCAL_SIGNER] Failed creating %s webhook: %v", event, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret := s.webhookSecret(); secret != "" {
+		req.Header.Set(SignatureHeader, SignWebhook(secret, body, time.Now()))
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		log.Printf("[LOCAL_SIGNER] Failed delivering %s webhook: %v", event, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Printf("[LOCAL_SIGNER] %s webhook returned status %d", event, resp.StatusCode)
+	}
+}
@@ -0,0 +1,123 @@
+package documenso
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLocalSignerSigningFlow(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading webhook: %v", err)
+		}
+		if err := VerifyWebhook(r.Header, raw, []string{"test-secret"}, time.Now(), time.Minute); err != nil {
+			t.Errorf("VerifyWebhook: %v", err)
+		}
+		var body struct {
+			Event   string `json:"event"`
+			Payload struct {
+				ID     int    `json:"id"`
+				Status string `json:"status"`
+			} `json:"payload"`
+		}
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("decoding webhook: %v", err)
+		}
+		mu.Lock()
+		events = append(events, body.Event+":"+body.Payload.Status)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	signer, err := NewLocalSigner(t.TempDir(), "", webhook.URL, "test-secret")
+	if err != nil {
+		t.Fatalf("NewLocalSigner: %v", err)
+	}
+	pages := httptest.NewServer(signer.Handler())
+	defer pages.Close()
+	signer.PublicURL = pages.URL
+
+	docID, recipients, _, err := signer.UploadDocumentWithSigners([]byte("%PDF-1.4 test"), "Lease Agreement", []Signer{
+		{Name: "Tenant", Email: "tenant@example.com", Role: SignerRoleSigner},
+		{Name: "Landlord", Email: "landlord@example.com", Role: SignerRoleSigner},
+	})
+	if err != nil {
+		t.Fatalf("UploadDocumentWithSigners: %v", err)
+	}
+	if len(recipients) != 2 {
+		t.Fatalf("got %d recipients, want 2", len(recipients))
+	}
+	if _, err := signer.SendDocument(docID); err != nil {
+		t.Fatalf("SendDocument: %v", err)
+	}
+
+	tenantURL, landlordURL, err := signer.GetSigningURLs(docID, "tenant@example.com", "landlord@example.com")
+	if err != nil {
+		t.Fatalf("GetSigningURLs: %v", err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	for _, signingURL := range []string{tenantURL, landlordURL} {
+		resp, err := client.Get(signingURL)
+		if err != nil {
+			t.Fatalf("GET %s: %v", signingURL, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s: status %d", signingURL, resp.StatusCode)
+		}
+
+		resp, err = client.PostForm(signingURL, url.Values{"agree": {"yes"}, "signature": {"Signer"}})
+		if err != nil {
+			t.Fatalf("POST %s: %v", signingURL, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusSeeOther {
+			t.Fatalf("POST %s: status %d", signingURL, resp.StatusCode)
+		}
+	}
+
+	mu.Lock()
+	got := append([]string(nil), events...)
+	mu.Unlock()
+	want := []string{"DOCUMENT_SIGNED:PENDING", "DOCUMENT_SIGNED:COMPLETED", "DOCUMENT_COMPLETED:COMPLETED"}
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+
+	downloadURL, err := signer.GetDocumentDownloadURL(docID)
+	if err != nil {
+		t.Fatalf("GetDocumentDownloadURL: %v", err)
+	}
+	resp, err := http.Get(downloadURL)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(data) != "%PDF-1.4 test" {
+		t.Fatalf("download returned %d %q", resp.StatusCode, data)
+	}
+
+	if err := signer.DeleteDocument(docID); err != nil {
+		t.Fatalf("DeleteDocument: %v", err)
+	}
+	if exists, _ := signer.VerifyDocumentExists(docID); exists {
+		t.Fatalf("document %s still exists after delete", docID)
+	}
+}
@@ -0,0 +1,185 @@
+package documenso
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Webhook event types sent by Documenso
+const (
+	EventDocumentCreated   = "DOCUMENT_CREATED"
+	EventDocumentSent      = "DOCUMENT_SENT"
+	EventDocumentOpened    = "DOCUMENT_OPENED"
+	EventDocumentSigned    = "DOCUMENT_SIGNED"
+	EventDocumentCompleted = "DOCUMENT_COMPLETED"
+	EventDocumentRejected  = "DOCUMENT_REJECTED"
+	EventDocumentCancelled = "DOCUMENT_CANCELLED"
+)
+
+// Recipient signing states reported in webhook payloads
+const (
+	SigningStatusNotSigned = "NOT_SIGNED"
+	SigningStatusSigned    = "SIGNED"
+	SigningStatusRejected  = "REJECTED"
+)
+
+// WebhookEvent is the body Documenso posts to the webhook endpoint
+type WebhookEvent struct {
+	Event           string          `json:"event"`
+	Payload         WebhookDocument `json:"payload"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	WebhookEndpoint string          `json:"webhookEndpoint"`
+}
+
+// WebhookDocument is the document a webhook event refers to
+type WebhookDocument struct {
+	ID          int                `json:"id"`
+	Title       string             `json:"title"`
+	Status      string             `json:"status"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	CompletedAt *time.Time         `json:"completedAt"`
+	Recipients  []WebhookRecipient `json:"recipients"`
+	// Documenso sends the list under its Prisma relation name
+	RecipientList []WebhookRecipient `json:"Recipient,omitempty"`
+}
+
+// WebhookRecipient is a recipient's state at the time of the event
+type WebhookRecipient struct {
+	ID              int        `json:"id"`
+	DocumentID      int        `json:"documentId"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	Role            SignerRole `json:"role"`
+	Token           string     `json:"token,omitempty"`
+	SigningStatus   string     `json:"signingStatus"`
+	SignedAt        *time.Time `json:"signedAt"`
+	RejectionReason string     `json:"rejectionReason,omitempty"`
+}
+
+// DocumentID returns the document id as stored in leases.external_doc_id
+func (d WebhookDocument) DocumentID() string {
+	return strconv.Itoa(d.ID)
+}
+
+// AllRecipients returns the recipients regardless of which payload shape was sent
+func (d WebhookDocument) AllRecipients() []WebhookRecipient {
+	if len(d.Recipients) > 0 {
+		return d.Recipients
+	}
+	return d.RecipientList
+}
+
+// Webhook authentication headers. Documenso itself sends the shared secret in SecretHeader;
+// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256 of "t.body">" and is preferred when present.
+// t is when that attempt was signed, so a retry carries a fresh one.
+const (
+	SecretHeader    = "X-Documenso-Secret"
+	SignatureHeader = "X-Documenso-Signature"
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook is not signed")
+	ErrInvalidSignature = errors.New("webhook signature does not match")
+	ErrStaleWebhook     = errors.New("webhook signature is outside the replay window")
+)
+
+// SignWebhook returns the SignatureHeader value for body signed with secret at ts
+func SignWebhook(secret string, body []byte, ts time.Time) string {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, webhookMAC(secret, timestamp, body))
+}
+
+func webhookMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks that a delivery was sent with one of secrets. A signed delivery must also have
+// been signed within window of now. Deliveries authenticated with the shared secret carry no signing time
+// and are not checked against window: the event's own createdAt is kept on retries, so it cannot tell a
+// retry from a replay. Replays are caught by delivery dedupe instead.
+// Any of secrets is accepted so the secret can be rotated without dropping deliveries in flight.
+func VerifyWebhook(headers http.Header, body []byte, secrets []string, now time.Time, window time.Duration) error {
+	if signature := headers.Get(SignatureHeader); signature != "" {
+		return verifySignatureHeader(signature, body, secrets, now, window)
+	}
+
+	received := headers.Get(SecretHeader)
+	if received == "" {
+		return ErrMissingSignature
+	}
+	if !matchesAnySecret(secrets, func(secret string) bool {
+		return subtle.ConstantTimeCompare([]byte(secret), []byte(received)) == 1
+	}) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func verifySignatureHeader(signature string, body []byte, secrets []string, now time.Time, window time.Duration) error {
+	var timestamp string
+	var macs []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			macs = append(macs, value)
+		}
+	}
+	if timestamp == "" || len(macs) == 0 {
+		return ErrInvalidSignature
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if !matchesAnySecret(secrets, func(secret string) bool {
+		expected := []byte(webhookMAC(secret, timestamp, body))
+		for _, mac := range macs {
+			if hmac.Equal(expected, []byte(mac)) {
+				return true
+			}
+		}
+		return false
+	}) {
+		return ErrInvalidSignature
+	}
+	return checkReplayWindow(time.Unix(seconds, 0), now, window)
+}
+
+func matchesAnySecret(secrets []string, match func(secret string) bool) bool {
+	matched := false
+	for _, secret := range secrets {
+		// Check every secret so timing does not reveal which one matched
+		if secret != "" && match(secret) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+func checkReplayWindow(sentAt, now time.Time, window time.Duration) error {
+	age := now.Sub(sentAt)
+	if age > window || age < -window {
+		return ErrStaleWebhook
+	}
+	return nil
+}
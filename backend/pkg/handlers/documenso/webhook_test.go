@@ -0,0 +1,52 @@
+package documenso
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"event":"DOCUMENT_SIGNED","payload":{"id":7},"createdAt":"2025-04-01T11:59:00Z"}`)
+	signed := func(secret string, at time.Time) http.Header {
+		h := http.Header{}
+		h.Set(SignatureHeader, SignWebhook(secret, body, at))
+		return h
+	}
+	shared := func(secret string) http.Header {
+		h := http.Header{}
+		h.Set(SecretHeader, secret)
+		return h
+	}
+
+	tests := []struct {
+		name    string
+		headers http.Header
+		body    []byte
+		secrets []string
+		want    error
+	}{
+		{"valid signature", signed("current", now), body, []string{"current"}, nil},
+		{"previous secret during rotation", signed("previous", now), body, []string{"current", "previous"}, nil},
+		{"wrong secret", signed("other", now), body, []string{"current"}, ErrInvalidSignature},
+		{"tampered body", signed("current", now), []byte(`{"event":"DOCUMENT_COMPLETED"}`), []string{"current"}, ErrInvalidSignature},
+		{"stale signature", signed("current", now.Add(-10*time.Minute)), body, []string{"current"}, ErrStaleWebhook},
+		{"unsigned", http.Header{}, body, []string{"current"}, ErrMissingSignature},
+		{"shared secret", shared("current"), body, []string{"current"}, nil},
+		{"wrong shared secret", shared("nope"), body, []string{"current"}, ErrInvalidSignature},
+		{"shared secret on a retried event", shared("current"), []byte(`{"createdAt":"2025-03-30T10:00:00Z"}`), []string{"current"}, nil},
+		{"stale signature with the wrong secret", signed("other", now.Add(-10*time.Minute)), body, []string{"current"}, ErrInvalidSignature},
+		{"malformed signature", http.Header{SignatureHeader: {"v1=abc"}}, body, []string{"current"}, ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhook(tt.headers, tt.body, tt.secrets, now, 5*time.Minute)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("VerifyWebhook() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
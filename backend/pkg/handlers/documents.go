@@ -0,0 +1,501 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxDocumentUploadBytes caps a single document uploaded to the vault
+const maxDocumentUploadBytes = 20 << 20
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// DocumentResponse is a vault document without its storage key
+type DocumentResponse struct {
+	ID          int64     `json:"id"`
+	Category    string    `json:"category"`
+	Title       string    `json:"title"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	TenantID    *int64    `json:"tenant_id,omitempty"`
+	LeaseID     *int64    `json:"lease_id,omitempty"`
+	ApartmentID *int64    `json:"apartment_id,omitempty"`
+	UploadedBy  *int64    `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func toDocumentResponse(d db.Document) DocumentResponse {
+	resp := DocumentResponse{
+		ID:          d.ID,
+		Category:    string(d.Category),
+		Title:       d.Title,
+		FileName:    d.FileName,
+		ContentType: d.ContentType,
+		SizeBytes:   d.SizeBytes,
+		CreatedAt:   d.CreatedAt.Time,
+	}
+	for _, ref := range []struct {
+		src pgtype.Int8
+		dst **int64
+	}{{d.TenantID, &resp.TenantID}, {d.LeaseID, &resp.LeaseID}, {d.ApartmentID, &resp.ApartmentID}, {d.UploadedBy, &resp.UploadedBy}} {
+		if ref.src.Valid {
+			id := ref.src.Int64
+			*ref.dst = &id
+		}
+	}
+	return resp
+}
+
+// DocumentDownloadResponse is one entry of a document's download audit trail
+type DocumentDownloadResponse struct {
+	UserID       *int64    `json:"user_id,omitempty"`
+	Name         string    `json:"name,omitempty"`
+	Email        string    `json:"email,omitempty"`
+	Role         string    `json:"role,omitempty"`
+	IPAddress    string    `json:"ip_address"`
+	UserAgent    string    `json:"user_agent"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// DocumentURLResponse is a short-lived link to a vault document
+type DocumentURLResponse struct {
+	DocumentID int64     `json:"document_id"`
+	URL        string    `json:"url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// parseDocumentCategory checks a document category name
+func parseDocumentCategory(s string) (db.DocumentCategory, error) {
+	switch c := db.DocumentCategory(s); c {
+	case db.DocumentCategoryLease, db.DocumentCategoryAmendment, db.DocumentCategoryNotice, db.DocumentCategoryReceipt,
+		db.DocumentCategoryInspectionReport, db.DocumentCategoryStatement, db.DocumentCategoryOther:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown document category %q", s)
+}
+
+// recordDocument files a document the system produced into the vault
+func (h *LeaseHandler) recordDocument(ctx context.Context, params db.CreateDocumentParams) {
+	if _, err := h.queries.CreateDocument(ctx, params); err != nil {
+		log.Printf("[DOCUMENTS] Failed filing %s into the vault: %v", params.StorageKey, err)
+	}
+}
+
+// recordSignedLeaseDocument files a signed lease, or the amendment it carries, for every tenant on it
+func (h *LeaseHandler) recordSignedLeaseDocument(ctx context.Context, leaseID int64, key string, size int) {
+	lease, err := h.queries.GetLeaseByID(ctx, leaseID)
+	if err != nil {
+		log.Printf("[DOCUMENTS] Could not load lease %d: %v", leaseID, err)
+		return
+	}
+	category := db.DocumentCategoryLease
+	title := fmt.Sprintf("Lease #%d", lease.LeaseNumber)
+	if amendment, err := h.queries.GetLeaseAmendmentByAmendedLease(ctx, leaseID); err == nil {
+		category = db.DocumentCategoryAmendment
+		title = fmt.Sprintf("Lease #%d amendment %d", lease.LeaseNumber, amendment.Version)
+	}
+	h.recordDocument(ctx, db.CreateDocumentParams{
+		Category:    category,
+		Title:       title,
+		FileName:    path.Base(key),
+		ContentType: "application/pdf",
+		SizeBytes:   int64(size),
+		StorageKey:  key,
+		LeaseID:     pgtype.Int8{Int64: leaseID, Valid: true},
+		ApartmentID: pgtype.Int8{Int64: lease.ApartmentID, Valid: lease.ApartmentID != 0},
+	})
+}
+
+// documentURL hands out a short-lived download link to a vault document. The link carries the document and
+// the user it was issued to, so ServeDocument can record each download when the file is actually fetched.
+func (h *LeaseHandler) documentURL(doc db.Document, userID int64) DocumentURLResponse {
+	params := url.Values{"document": {strconv.FormatInt(doc.ID, 10)}}
+	if userID != 0 {
+		params.Set("user", strconv.FormatInt(userID, 10))
+	}
+	link, expires := h.documentURLs.URLWithParams(doc.StorageKey, params, documentURLTTL, time.Now())
+	return DocumentURLResponse{DocumentID: doc.ID, URL: link, ExpiresAt: expires}
+}
+
+// recordDocumentDownload writes the audit entry of a vault document served through a link documentURL issued
+func (h *LeaseHandler) recordDocumentDownload(r *http.Request, params url.Values) error {
+	documentID, err := strconv.ParseInt(params.Get("document"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid document %q in download link: %w", params.Get("document"), err)
+	}
+	var userID pgtype.Int8
+	if user := params.Get("user"); user != "" {
+		if userID.Int64, err = strconv.ParseInt(user, 10, 64); err != nil {
+			return fmt.Errorf("invalid user %q in download link: %w", user, err)
+		}
+		userID.Valid = true
+	}
+	if err := h.queries.RecordDocumentDownload(r.Context(), db.RecordDocumentDownloadParams{
+		DocumentID: documentID,
+		UserID:     userID,
+		IpAddress:  clientIP(r),
+		UserAgent:  r.UserAgent(),
+	}); err != nil {
+		return fmt.Errorf("failed to record download of document %d: %w", documentID, err)
+	}
+	return nil
+}
+
+// clientIP is the address the request came from. X-Forwarded-For is ignored because any caller can set
+// its first hop, which would let them put whatever address they like in the audit log.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+func documentIDParam(r *http.Request) (int64, error) {
+	return strconv.ParseInt(chi.URLParam(r, "documentID"), 10, 64)
+}
+
+// TenantGetDocuments returns the vault documents the signed-in tenant can see
+func (h *LeaseHandler) TenantGetDocuments(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenantFromContext(r)
+	if err != nil {
+		log.Printf("[DOCUMENTS] %v", err)
+		http.Error(w, "Error no tenant context", http.StatusUnauthorized)
+		return
+	}
+	docs, err := h.queries.ListTenantDocuments(r.Context(), pgtype.Int8{Int64: tenantID, Valid: true})
+	if err != nil {
+		log.Printf("[DOCUMENTS] Failed listing documents of tenant %d: %v", tenantID, err)
+		http.Error(w, "Error querying documents for tenant", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]DocumentResponse, 0, len(docs))
+	for _, d := range docs {
+		resp = append(resp, toDocumentResponse(d))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[DOCUMENTS] Error encoding response: %v", err)
+	}
+}
+
+// TenantGetDocumentURL hands the signed-in tenant a download link to one of their documents
+func (h *LeaseHandler) TenantGetDocumentURL(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenantFromContext(r)
+	if err != nil {
+		log.Printf("[DOCUMENTS] %v", err)
+		http.Error(w, "Error no tenant context", http.StatusUnauthorized)
+		return
+	}
+	documentID, err := documentIDParam(r)
+	if err != nil {
+		http.Error(w, "Invalid document ID", http.StatusBadRequest)
+		return
+	}
+	docs, err := h.queries.ListTenantDocuments(r.Context(), pgtype.Int8{Int64: tenantID, Valid: true})
+	if err != nil {
+		log.Printf("[DOCUMENTS] Failed listing documents of tenant %d: %v", tenantID, err)
+		http.Error(w, "Error querying documents for tenant", http.StatusInternalServerError)
+		return
+	}
+	for _, d := range docs {
+		if d.ID != documentID {
+			continue
+		}
+		resp := h.documentURL(d, tenantID)
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("[DOCUMENTS] Error encoding response: %v", err)
+		}
+		return
+	}
+	// Documents of other tenants look the same as missing ones
+	http.Error(w, "Document not found", http.StatusNotFound)
+}
+
+// ListDocuments returns vault documents, filtered by tenant_id, lease_id, apartment_id and category
+func (h *LeaseHandler) ListDocuments(w http.ResponseWriter, r *http.Request) {
+	var params db.ListDocumentsParams
+	query := r.URL.Query()
+	for name, dst := range map[string]*pgtype.Int8{
+		"tenant_id":    &params.TenantID,
+		"lease_id":     &params.LeaseID,
+		"apartment_id": &params.ApartmentID,
+	} {
+		if v := query.Get(name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				http.Error(w, "Invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*dst = pgtype.Int8{Int64: id, Valid: true}
+		}
+	}
+	if v := query.Get("category"); v != "" {
+		category, err := parseDocumentCategory(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		params.Category = db.NullDocumentCategory{DocumentCategory: category, Valid: true}
+	}
+
+	docs, err := h.queries.ListDocuments(r.Context(), params)
+	if err != nil {
+		log.Printf("[DOCUMENTS] Failed listing documents: %v", err)
+		http.Error(w, "Failed to fetch documents", http.StatusInternalServerError)
+		return
+	}
+	resp := make([]DocumentResponse, 0, len(docs))
+	for _, d := range docs {
+		resp = append(resp, toDocumentResponse(d))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[DOCUMENTS] Error encoding response: %v", err)
+	}
+}
+
+// UploadDocument files a document uploaded by an admin against a tenant, a lease or a unit. The multipart
+// form carries file, category, title and at least one of tenant_id, lease_id and apartment_id.
+func (h *LeaseHandler) UploadDocument(w http.ResponseWriter, r *http.Request) {
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxDocumentUploadBytes+1<<20)
+	if err := r.ParseMultipartForm(maxDocumentUploadBytes); err != nil {
+		http.Error(w, "Invalid upload, files are limited to 20 MB", http.StatusBadRequest)
+		return
+	}
+
+	category, err := parseDocumentCategory(r.FormValue("category"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := db.CreateDocumentParams{
+		Category:   category,
+		Title:      strings.TrimSpace(r.FormValue("title")),
+		UploadedBy: pgtype.Int8{Int64: adminID, Valid: true},
+	}
+	for name, dst := range map[string]*pgtype.Int8{
+		"tenant_id":    &params.TenantID,
+		"lease_id":     &params.LeaseID,
+		"apartment_id": &params.ApartmentID,
+	} {
+		if v := r.FormValue(name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				http.Error(w, "Invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*dst = pgtype.Int8{Int64: id, Valid: true}
+		}
+	}
+	if !params.TenantID.Valid && !params.LeaseID.Valid && !params.ApartmentID.Valid {
+		http.Error(w, "A document must be filed to a tenant_id, lease_id or apartment_id", http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+	if params.TenantID.Valid {
+		if _, err := h.queries.GetUserByID(ctx, params.TenantID.Int64); err != nil {
+			http.Error(w, "Tenant not found", http.StatusNotFound)
+			return
+		}
+	}
+	if params.LeaseID.Valid {
+		lease, err := h.queries.GetLeaseByID(ctx, params.LeaseID.Int64)
+		if err != nil {
+			http.Error(w, "Lease not found", http.StatusNotFound)
+			return
+		}
+		if !params.ApartmentID.Valid {
+			params.ApartmentID = pgtype.Int8{Int64: lease.ApartmentID, Valid: lease.ApartmentID != 0}
+		}
+	}
+	if params.ApartmentID.Valid {
+		if _, err := h.queries.GetApartment(ctx, params.ApartmentID.Int64); err != nil {
+			http.Error(w, "Apartment not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil || len(data) == 0 {
+		http.Error(w, "Could not read the uploaded file", http.StatusBadRequest)
+		return
+	}
+
+	params.FileName = sanitizeFileName(header.Filename)
+	if params.Title == "" {
+		params.Title = strings.TrimSuffix(params.FileName, filepath.Ext(params.FileName))
+	}
+	params.ContentType = mime.TypeByExtension(filepath.Ext(params.FileName))
+	if params.ContentType == "" {
+		params.ContentType = http.DetectContentType(data)
+	}
+	params.SizeBytes = int64(len(data))
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		http.Error(w, "Failed to store document", http.StatusInternalServerError)
+		return
+	}
+	params.StorageKey = fmt.Sprintf("documents/%s/%s", hex.EncodeToString(token), params.FileName)
+
+	if err := h.documents.Put(ctx, params.StorageKey, data, params.ContentType); err != nil {
+		log.Printf("[DOCUMENTS] Failed storing upload %s: %v", params.StorageKey, err)
+		http.Error(w, "Failed to store document", http.StatusInternalServerError)
+		return
+	}
+	doc, err := h.queries.CreateDocument(ctx, params)
+	if err != nil {
+		log.Printf("[DOCUMENTS] Failed filing upload %s: %v", params.StorageKey, err)
+		if err := h.documents.Delete(ctx, params.StorageKey); err != nil {
+			log.Printf("[DOCUMENTS] Failed removing orphaned upload %s: %v", params.StorageKey, err)
+		}
+		http.Error(w, "Failed to save document", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[DOCUMENTS] Admin %d uploaded document %d (%s, %d bytes)", adminID, doc.ID, doc.Category, doc.SizeBytes)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toDocumentResponse(doc)); err != nil {
+		log.Printf("[DOCUMENTS] Error encoding response: %v", err)
+	}
+}
+
+// sanitizeFileName keeps an uploaded file's base name safe to use in a storage key
+func sanitizeFileName(name string) string {
+	name = unsafeFileNameChars.ReplaceAllString(filepath.Base(strings.ReplaceAll(name, "\\", "/")), "_")
+	name = strings.Trim(name, "._")
+	if name == "" {
+		return "document"
+	}
+	return name
+}
+
+// loadDocument writes the error response itself when the document does not exist
+func (h *LeaseHandler) loadDocument(w http.ResponseWriter, r *http.Request) (db.Document, bool) {
+	documentID, err := documentIDParam(r)
+	if err != nil {
+		http.Error(w, "Invalid document ID", http.StatusBadRequest)
+		return db.Document{}, false
+	}
+	doc, err := h.queries.GetDocument(r.Context(), documentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return db.Document{}, false
+	}
+	if err != nil {
+		log.Printf("[DOCUMENTS] Failed loading document %d: %v", documentID, err)
+		http.Error(w, "Failed to fetch document", http.StatusInternalServerError)
+		return db.Document{}, false
+	}
+	return doc, true
+}
+
+// GetDocumentURL hands an admin a download link to any vault document
+func (h *LeaseHandler) GetDocumentURL(w http.ResponseWriter, r *http.Request) {
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	doc, ok := h.loadDocument(w, r)
+	if !ok {
+		return
+	}
+	resp := h.documentURL(doc, adminID)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[DOCUMENTS] Error encoding response: %v", err)
+	}
+}
+
+// ListDocumentDownloads returns who downloaded a document and when
+func (h *LeaseHandler) ListDocumentDownloads(w http.ResponseWriter, r *http.Request) {
+	doc, ok := h.loadDocument(w, r)
+	if !ok {
+		return
+	}
+	rows, err := h.queries.ListDocumentDownloads(r.Context(), doc.ID)
+	if err != nil {
+		log.Printf("[DOCUMENTS] Failed listing downloads of document %d: %v", doc.ID, err)
+		http.Error(w, "Failed to fetch downloads", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]DocumentDownloadResponse, 0, len(rows))
+	for _, row := range rows {
+		entry := DocumentDownloadResponse{
+			Email:        row.Email.String,
+			IPAddress:    row.IpAddress,
+			UserAgent:    row.UserAgent,
+			DownloadedAt: row.DownloadedAt.Time,
+		}
+		if row.UserID.Valid {
+			id := row.UserID.Int64
+			entry.UserID = &id
+			entry.Name = strings.TrimSpace(row.FirstName.String + " " + row.LastName.String)
+		}
+		if row.Role.Valid {
+			entry.Role = string(row.Role.Role)
+		}
+		resp = append(resp, entry)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[DOCUMENTS] Error encoding response: %v", err)
+	}
+}
+
+// DeleteDocument removes a document from the vault and from storage
+func (h *LeaseHandler) DeleteDocument(w http.ResponseWriter, r *http.Request) {
+	doc, ok := h.loadDocument(w, r)
+	if !ok {
+		return
+	}
+	if err := h.queries.DeleteDocument(r.Context(), doc.ID); err != nil {
+		log.Printf("[DOCUMENTS] Failed deleting document %d: %v", doc.ID, err)
+		http.Error(w, "Failed to delete document", http.StatusInternalServerError)
+		return
+	}
+	// Lease PDFs stay in storage; the lease itself still points at them
+	if strings.HasPrefix(doc.StorageKey, "documents/") {
+		if err := h.documents.Delete(r.Context(), doc.StorageKey); err != nil {
+			log.Printf("[DOCUMENTS] Failed removing %s from storage: %v", doc.StorageKey, err)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
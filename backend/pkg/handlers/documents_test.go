@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/storage"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestClientIPIgnoresForwardedFor(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/documents/1", nil)
+	r.RemoteAddr = "203.0.113.7:51234"
+	r.Header.Set("X-Forwarded-For", "198.51.100.1, 203.0.113.7")
+	if got := clientIP(r); got != "203.0.113.7" {
+		t.Errorf("clientIP = %q, want the connection's address 203.0.113.7", got)
+	}
+}
+
+// fetchDocument serves the link from the given address
+func fetchDocument(t *testing.T, h *LeaseHandler, link, key, remoteAddr string) *httptest.ResponseRecorder {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("parsing link %s: %v", link, err)
+	}
+	r := newTestRequest(t, http.MethodGet, "/files/"+key+"?"+u.RawQuery, nil, "*", key)
+	r.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	h.ServeDocument(rec, r)
+	return rec
+}
+
+func TestDocumentDownloadsAreRecordedWhenServed(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	h.documentURLs = storage.NewURLSigner([]byte("secret"), "http://localhost/files")
+	doc := db.Document{ID: 3, StorageKey: "vault/3/lease.pdf"}
+	if err := h.documents.Put(context.Background(), doc.StorageKey, []byte("%PDF-1.7"), "application/pdf"); err != nil {
+		t.Fatalf("storing document: %v", err)
+	}
+
+	resp := h.documentURL(doc, testTenant.ID)
+	if calls := fdb.called("RecordDocumentDownload"); len(calls) != 0 {
+		t.Fatalf("download recorded when the link was issued: %+v", calls)
+	}
+
+	for _, addr := range []string{"203.0.113.7:51234", "198.51.100.4:40000"} {
+		if rec := fetchDocument(t, h, resp.URL, doc.StorageKey, addr); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+		}
+	}
+	downloads := fdb.called("RecordDocumentDownload")
+	if len(downloads) != 2 {
+		t.Fatalf("RecordDocumentDownload calls = %+v, want one per fetch", downloads)
+	}
+	if downloads[0].Args[0] != doc.ID || downloads[0].Args[1] != (pgtype.Int8{Int64: testTenant.ID, Valid: true}) {
+		t.Errorf("download recorded as %+v, want document 3 fetched by tenant %d", downloads[0].Args, testTenant.ID)
+	}
+	if downloads[1].Args[2] != "198.51.100.4" {
+		t.Errorf("second download from %v, want the address that fetched it", downloads[1].Args[2])
+	}
+}
+
+func TestDocumentIsNotServedWithoutAnAuditEntry(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	h.documentURLs = storage.NewURLSigner([]byte("secret"), "http://localhost/files")
+	doc := db.Document{ID: 3, StorageKey: "vault/3/lease.pdf"}
+	if err := h.documents.Put(context.Background(), doc.StorageKey, []byte("%PDF-1.7"), "application/pdf"); err != nil {
+		t.Fatalf("storing document: %v", err)
+	}
+	fdb.fails("RecordDocumentDownload", errors.New("audit log unavailable"))
+
+	rec := fetchDocument(t, h, h.documentURL(doc, testTenant.ID).URL, doc.StorageKey, "203.0.113.7:51234")
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "%PDF") {
+		t.Error("document served without its download recorded")
+	}
+}
@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/storage"
+	"github.com/careecodes/RentDaddy/middleware"
+	"github.com/careecodes/RentDaddy/pkg/handlers/documenso"
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeDB stands in for Postgres in handler tests. It answers the generated queries by their sqlc name with
+// canned results and records every call, so a test can check what a handler wrote without a database.
+// Queries nobody answers find no rows and change nothing.
+type fakeDB struct {
+	mu         sync.Mutex
+	answers    map[string]func(args []any) (any, error)
+	calls      []fakeCall
+	committed  int
+	rolledBack int
+}
+
+type fakeCall struct {
+	Name string
+	Args []any
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{answers: map[string]func(args []any) (any, error){}}
+}
+
+// on answers the named query. The answer is a row struct, which is scanned field by field, a slice of them
+// for :many queries, a single value for one-column rows, or the int64 number of rows an :exec changed.
+func (f *fakeDB) on(name string, answer func(args []any) (any, error)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.answers[name] = answer
+}
+
+// returns answers the named query with the same result every time
+func (f *fakeDB) returns(name string, result any) {
+	f.on(name, func([]any) (any, error) { return result, nil })
+}
+
+// fails answers the named query with an error
+func (f *fakeDB) fails(name string, err error) {
+	f.on(name, func([]any) (any, error) { return nil, err })
+}
+
+// called returns every call of the named query, oldest first
+func (f *fakeDB) called(name string) []fakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []fakeCall
+	for _, c := range f.calls {
+		if c.Name == name {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+func (f *fakeDB) answer(sql string, args []any) (any, bool, error) {
+	name := queryName(sql)
+	f.mu.Lock()
+	f.calls = append(f.calls, fakeCall{Name: name, Args: args})
+	answer, ok := f.answers[name]
+	f.mu.Unlock()
+	if !ok {
+		return nil, false, nil
+	}
+	result, err := answer(args)
+	return result, true, err
+}
+
+// queryName reads the name sqlc puts at the top of every generated query, "-- name: GetLeaseByID :one"
+func queryName(sql string) string {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(sql), "-- name:"))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+func (f *fakeDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	result, _, err := f.answer(sql, args)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	rows, _ := result.(int64)
+	return pgconn.NewCommandTag(fmt.Sprintf("UPDATE %d", rows)), nil
+}
+
+func (f *fakeDB) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	result, _, err := f.answer(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	rows := &fakeRows{}
+	if result != nil {
+		v := reflect.ValueOf(result)
+		for i := 0; i < v.Len(); i++ {
+			rows.items = append(rows.items, v.Index(i).Interface())
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeDB) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	result, ok, err := f.answer(sql, args)
+	if err == nil && (!ok || result == nil) {
+		err = pgx.ErrNoRows
+	}
+	return &fakeRow{value: result, err: err}
+}
+
+func (f *fakeDB) Begin(context.Context) (pgx.Tx, error) {
+	return &fakeTx{db: f}, nil
+}
+
+// fakeTx runs its queries straight against the fakeDB and counts how transactions end
+type fakeTx struct {
+	pgx.Tx
+	db   *fakeDB
+	done bool
+}
+
+func (t *fakeTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return t.db.Exec(ctx, sql, args...)
+}
+
+func (t *fakeTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return t.db.Query(ctx, sql, args...)
+}
+
+func (t *fakeTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return t.db.QueryRow(ctx, sql, args...)
+}
+
+func (t *fakeTx) Commit(context.Context) error {
+	if t.done {
+		return pgx.ErrTxClosed
+	}
+	t.done = true
+	t.db.mu.Lock()
+	t.db.committed++
+	t.db.mu.Unlock()
+	return nil
+}
+
+func (t *fakeTx) Rollback(context.Context) error {
+	if t.done {
+		return pgx.ErrTxClosed
+	}
+	t.done = true
+	t.db.mu.Lock()
+	t.db.rolledBack++
+	t.db.mu.Unlock()
+	return nil
+}
+
+type fakeRow struct {
+	value any
+	err   error
+}
+
+func (r *fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	return scanFake(r.value, dest)
+}
+
+type fakeRows struct {
+	items []any
+	next  int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.NewCommandTag("SELECT") }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return nil, nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.items)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	return scanFake(r.items[r.next-1], dest)
+}
+
+// scanFake copies a canned row into the destinations of a generated Scan call. A struct is spread over
+// the destinations in field order, which is the order sqlc scans columns in.
+func scanFake(value any, dest []any) error {
+	v := reflect.ValueOf(value)
+	if len(dest) == 1 && (v.Kind() != reflect.Struct || reflect.TypeOf(dest[0]).Elem() == v.Type()) {
+		return assignFake(dest[0], v)
+	}
+	if v.Kind() != reflect.Struct || v.NumField() != len(dest) {
+		return fmt.Errorf("fake row %T does not fit %d columns", value, len(dest))
+	}
+	for i := range dest {
+		if err := assignFake(dest[i], v.Field(i)); err != nil {
+			return fmt.Errorf("column %d of %T: %w", i, value, err)
+		}
+	}
+	return nil
+}
+
+func assignFake(dest any, v reflect.Value) error {
+	d := reflect.ValueOf(dest).Elem()
+	switch {
+	case v.Type().AssignableTo(d.Type()):
+		d.Set(v)
+	case v.Type().ConvertibleTo(d.Type()):
+		d.Set(v.Convert(d.Type()))
+	default:
+		return fmt.Errorf("cannot scan %s into %s", v.Type(), d.Type())
+	}
+	return nil
+}
+
+// fakeSigner is an e-sign provider that keeps nothing and records the documents it was asked to delete
+type fakeSigner struct {
+	mu      sync.Mutex
+	deleted []string
+}
+
+var _ documenso.DocumensoClientInterface = (*fakeSigner)(nil)
+
+func (s *fakeSigner) UploadDocumentWithSigners(_ []byte, _ string, signers []documenso.Signer) (string, map[string]documenso.RecipientInfo, string, error) {
+	recipients := make(map[string]documenso.RecipientInfo, len(signers))
+	for i, signer := range signers {
+		recipients[strings.ToLower(signer.Email)] = documenso.RecipientInfo{ID: i + 1, Email: signer.Email}
+	}
+	return "doc-1", recipients, "", nil
+}
+
+func (s *fakeSigner) GetRecipients(string) ([]documenso.RecipientInfo, error) { return nil, nil }
+func (s *fakeSigner) AddSignatureField(string, int, int, float64, float64, float64, float64, ...string) error {
+	return nil
+}
+func (s *fakeSigner) SendDocument(string) (map[string]string, error) { return map[string]string{}, nil }
+func (s *fakeSigner) GetSigningURL(string) string                    { return "" }
+func (s *fakeSigner) GetSigningURLs(string, string, string) (string, string, error) {
+	return "", "", nil
+}
+func (s *fakeSigner) GetDocumentAdminURL(string) string { return "" }
+func (s *fakeSigner) GetDocumentDownloadURL(string) (string, error) {
+	return "", fmt.Errorf("not stored")
+}
+func (s *fakeSigner) DownloadDocument(string) ([]byte, error)   { return nil, fmt.Errorf("not stored") }
+func (s *fakeSigner) VerifyDocumentExists(string) (bool, error) { return true, nil }
+func (s *fakeSigner) Ping(context.Context) error                { return nil }
+
+func (s *fakeSigner) DeleteDocument(documentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = append(s.deleted, documentID)
+	return nil
+}
+
+// newTestHandler returns a lease handler backed by a fakeDB, a fakeSigner and documents in a temporary directory
+func newTestHandler(t *testing.T) (*LeaseHandler, *fakeDB) {
+	t.Helper()
+	fdb := newFakeDB()
+	documents, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("creating document store: %v", err)
+	}
+	return &LeaseHandler{pool: fdb, queries: db.New(fdb), documenso_client: &fakeSigner{}, documents: documents}, fdb
+}
+
+// testAdmin is the admin every test request is made as
+var testAdmin = db.GetUserRow{ID: 1, ClerkID: "user_admin", FirstName: "Ada", LastName: "Admin", Email: "admin@example.com", Role: db.RoleAdmin}
+
+// signInAdmin answers the lookups GetLandlordInfo and the webhook handlers make for testAdmin. Other users,
+// such as the tenants of a lease, can be looked up by ID too.
+func signInAdmin(fdb *fakeDB, others ...db.GetUserByIDRow) {
+	fdb.on("GetUser", func(args []any) (any, error) {
+		if args[0] == testAdmin.ClerkID {
+			return testAdmin, nil
+		}
+		return nil, pgx.ErrNoRows
+	})
+	fdb.on("GetUserByID", func(args []any) (any, error) {
+		if args[0] == testAdmin.ID {
+			return db.GetUserByIDRow{ID: testAdmin.ID, ClerkID: testAdmin.ClerkID, FirstName: testAdmin.FirstName,
+				LastName: testAdmin.LastName, Email: testAdmin.Email, Role: testAdmin.Role}, nil
+		}
+		for _, user := range others {
+			if args[0] == user.ID {
+				return user, nil
+			}
+		}
+		return nil, pgx.ErrNoRows
+	})
+}
+
+// newTestRequest builds a request as testAdmin with a JSON body and chi URL parameters given as name, value pairs
+func newTestRequest(t *testing.T, method, target string, body any, params ...string) *http.Request {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encoding request body: %v", err)
+		}
+	}
+	r := httptest.NewRequest(method, target, &buf)
+	routeCtx := chi.NewRouteContext()
+	for i := 0; i+1 < len(params); i += 2 {
+		routeCtx.URLParams.Add(params[i], params[i+1])
+	}
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx)
+	ctx = context.WithValue(ctx, middleware.UserKey, &clerk.User{ID: testAdmin.ClerkID})
+	return r.WithContext(ctx)
+}
+
+// decodeResponse reads a JSON response body into v
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decoding response %q: %v", rec.Body.String(), err)
+	}
+}
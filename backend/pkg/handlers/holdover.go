@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/rent"
+	"github.com/careecodes/RentDaddy/internal/smtp"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// HoldoverPolicyRequest sets what happens when a tenant stays past the lease end date. Leaving both fields
+// out of a lease request falls back to the building's policy.
+type HoldoverPolicyRequest struct {
+	Policy         *string  `json:"holdover_policy,omitempty"`          // expire or month_to_month
+	PremiumPercent *float64 `json:"holdover_premium_percent,omitempty"` // Added to the last rent while month-to-month
+}
+
+// parseHoldoverPolicy checks a holdover policy name
+func parseHoldoverPolicy(s string) (db.HoldoverPolicy, error) {
+	switch db.HoldoverPolicy(s) {
+	case db.HoldoverPolicyExpire, db.HoldoverPolicyMonthToMonth:
+		return db.HoldoverPolicy(s), nil
+	}
+	return "", fmt.Errorf("unknown holdover policy %q, use %s or %s", s, db.HoldoverPolicyExpire, db.HoldoverPolicyMonthToMonth)
+}
+
+// validateHoldoverPolicy checks the holdover policy sent with a lease or building
+func validateHoldoverPolicy(req HoldoverPolicyRequest) error {
+	if req.Policy != nil {
+		if _, err := parseHoldoverPolicy(*req.Policy); err != nil {
+			return err
+		}
+	}
+	if req.PremiumPercent != nil && (*req.PremiumPercent < 0 || *req.PremiumPercent > 100) {
+		return errors.New("holdover premium must be between 0 and 100 percent")
+	}
+	return nil
+}
+
+func (req HoldoverPolicyRequest) params(leaseID int64) db.SetLeaseHoldoverPolicyParams {
+	params := db.SetLeaseHoldoverPolicyParams{ID: leaseID}
+	if req.Policy != nil {
+		params.HoldoverPolicy = db.NullHoldoverPolicy{HoldoverPolicy: db.HoldoverPolicy(*req.Policy), Valid: true}
+	}
+	if req.PremiumPercent != nil {
+		params.HoldoverPremiumPercent = utils.ConvertFloatToPgNumeric(*req.PremiumPercent)
+	}
+	return params
+}
+
+// inheritHoldoverPolicy carries the holdover policy of the lease being replaced onto the new version
+func (h *LeaseHandler) inheritHoldoverPolicy(ctx context.Context, leaseID int64, req *LeaseUpsertRequest) {
+	if req.HoldoverPolicy != nil || req.HoldoverPremiumPercent != nil {
+		return
+	}
+	lease, err := h.queries.GetLeaseByID(ctx, leaseID)
+	if err != nil {
+		log.Printf("[HOLDOVER] Could not load lease %d: %v", leaseID, err)
+		return
+	}
+	if lease.HoldoverPolicy.Valid {
+		policy := string(lease.HoldoverPolicy.HoldoverPolicy)
+		req.HoldoverPolicy = &policy
+	}
+	if lease.HoldoverPremiumPercent.Valid {
+		premium := utils.ConvertPgNumericToFloat(lease.HoldoverPremiumPercent)
+		req.HoldoverPremiumPercent = &premium
+	}
+}
+
+// recordLeaseHoldoverPolicy stores a lease's own holdover policy on a newly created lease
+func (h *LeaseHandler) recordLeaseHoldoverPolicy(ctx context.Context, leaseID int64, req HoldoverPolicyRequest) error {
+	if req.Policy == nil && req.PremiumPercent == nil {
+		return nil
+	}
+	if err := h.queries.SetLeaseHoldoverPolicy(ctx, req.params(leaseID)); err != nil {
+		return fmt.Errorf("failed recording holdover policy on lease %d: %w", leaseID, err)
+	}
+	return nil
+}
+
+// SetLeaseHoldoverPolicy overrides the building's holdover policy for one lease. An empty body clears the
+// override.
+func (h *LeaseHandler) SetLeaseHoldoverPolicy(w http.ResponseWriter, r *http.Request) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+	var req HoldoverPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid holdover policy request", http.StatusBadRequest)
+		return
+	}
+	if err := validateHoldoverPolicy(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := h.queries.GetLeaseByID(r.Context(), leaseID); err != nil {
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
+	if err := h.queries.SetLeaseHoldoverPolicy(r.Context(), req.params(leaseID)); err != nil {
+		log.Printf("[HOLDOVER] Failed setting holdover policy on lease %d: %v", leaseID, err)
+		http.Error(w, "Failed to update holdover policy", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"lease_id":                 leaseID,
+		"holdover_policy":          req.Policy,
+		"holdover_premium_percent": req.PremiumPercent,
+	}); err != nil {
+		log.Printf("[HOLDOVER] Error encoding response: %v", err)
+	}
+}
+
+// convertHoldoverLeases moves leases that reached their end date onto month-to-month where the lease or its
+// building asks for it, so they keep billing instead of expiring. It returns how many were converted.
+func (h *LeaseHandler) convertHoldoverLeases(ctx context.Context) int {
+	leases, err := h.queries.ListLeasesDueForHoldover(ctx)
+	if err != nil {
+		log.Printf("[HOLDOVER] Failed listing leases due for holdover: %v", err)
+		return 0
+	}
+
+	converted := 0
+	for _, lease := range leases {
+		lastRent := rent.EffectiveRent(
+			utils.ConvertPgNumericToFloat(lease.RentAmount),
+			lease.LeaseStartDate.Time,
+			parseRentEscalation(lease.ID, lease.RentEscalation),
+			lease.LeaseEndDate.Time,
+		)
+		holdoverRent := rent.HoldoverRent(lastRent, utils.ConvertPgNumericToFloat(lease.HoldoverPremiumPercent))
+		rows, err := h.queries.ConvertLeaseToMonthToMonth(ctx, db.ConvertLeaseToMonthToMonthParams{
+			ID:           lease.ID,
+			HoldoverRent: utils.ConvertFloatToPgNumeric(holdoverRent),
+		})
+		if err != nil {
+			log.Printf("[HOLDOVER] Failed converting lease %d to month-to-month: %v", lease.ID, err)
+			continue
+		}
+		if rows == 0 {
+			continue
+		}
+		converted++
+		log.Printf("[HOLDOVER] Lease %d is now month-to-month at $%.2f", lease.ID, holdoverRent)
+
+		// The end month was billed through the end date, so the rest of it is billed at the holdover rent
+		holdoverStart := lease.LeaseEndDate.Time.AddDate(0, 0, 1)
+		if stub, ok := rent.MoveIn(holdoverRent, holdoverStart, rent.ProrationMethod(lease.ProrationMethod)); ok {
+			if _, err := h.queries.CreateLedgerEntry(ctx, db.CreateLedgerEntryParams{
+				LeaseID:     lease.ID,
+				TenantID:    lease.TenantID,
+				EntryType:   db.LedgerEntryTypeCharge,
+				Amount:      utils.ConvertFloatToPgNumeric(stub.Amount),
+				Description: fmt.Sprintf("Month-to-month rent for %s (%d of %d days)", holdoverStart.Format("January 2006"), stub.Days, stub.DaysInMonth),
+				DueDate:     pgtype.Date{Time: holdoverStart, Valid: true},
+				EntryDate:   pgtype.Date{Time: holdoverStart, Valid: true},
+			}); err != nil {
+				log.Printf("[HOLDOVER] Failed billing the rest of %s on lease %d: %v", holdoverStart.Format("2006-01"), lease.ID, err)
+			}
+		}
+
+		if err := h.notifyHoldover(ctx, lease, holdoverRent); err != nil {
+			log.Printf("[HOLDOVER] Failed notifying about lease %d: %v", lease.ID, err)
+		}
+	}
+	return converted
+}
+
+// notifyHoldover tells every tenant and the admin that a lease went month-to-month and what it now costs
+func (h *LeaseHandler) notifyHoldover(ctx context.Context, lease db.ListLeasesDueForHoldoverRow, holdoverRent float64) error {
+	endedOn := lease.LeaseEndDate.Time.Format("January 2, 2006")
+	startsOn := lease.LeaseEndDate.Time.AddDate(0, 0, 1).Format("January 2, 2006")
+	noticeDays := 30
+	if terms, err := h.queries.GetLeaseByID(ctx, lease.ID); err == nil {
+		noticeDays = int(terms.NoticePeriodDays)
+	}
+
+	occupants, err := h.queries.ListLeaseTenants(ctx, lease.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list tenants: %w", err)
+	}
+	for _, o := range occupants {
+		body := fmt.Sprintf("Hello %s,\n\n"+
+			"Your lease ended on %s. Because you are still living in the unit, it now continues month-to-month "+
+			"starting %s at $%.2f per month.\n\n"+
+			"You or the landlord can end the tenancy with %d days of written notice, which you can give from the tenant portal.\n",
+			o.FirstName, endedOn, startsOn, holdoverRent, noticeDays)
+		if err := smtp.SendEmail(o.Email, "Your lease is now month-to-month", body); err != nil {
+			log.Printf("[HOLDOVER] Failed emailing %s about lease %d: %v", o.Email, lease.ID, err)
+		}
+	}
+
+	adminEmail := os.Getenv("ADMIN_EMAIL")
+	if adminEmail == "" {
+		landlord, err := h.landlordOfLease(ctx, lease.ApartmentID, lease.LandlordID)
+		if err != nil {
+			return err
+		}
+		adminEmail = landlord.Signer.Email
+	}
+	body := fmt.Sprintf("Lease %d ended on %s with the tenant still in the unit and has been converted to month-to-month "+
+		"at $%.2f per month from %s.\n", lease.ID, endedOn, holdoverRent, startsOn)
+	return smtp.SendEmail(adminEmail, fmt.Sprintf("Lease %d converted to month-to-month", lease.ID), body)
+}
+
+// holdoverRentOn returns the month-to-month rent of a lease on a date past its end date
+func holdoverRentOn(lease db.GetLeaseByIDRow, on time.Time) (float64, bool) {
+	if !lease.HoldoverRent.Valid || !on.After(lease.LeaseEndDate.Time) {
+		return 0, false
+	}
+	return utils.ConvertPgNumericToFloat(lease.HoldoverRent), true
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LandlordHandler manages the landlords buildings are managed by
+type LandlordHandler struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+// NewLandlordHandler creates a new LandlordHandler instance
+func NewLandlordHandler(pool *pgxpool.Pool, queries *db.Queries) *LandlordHandler {
+	return &LandlordHandler{
+		pool:    pool,
+		queries: queries,
+	}
+}
+
+// LandlordRequest creates or replaces a landlord. SignerName and SignerEmail default to those of the
+// signer's account when SignerUserID is set.
+type LandlordRequest struct {
+	LegalName     string `json:"legal_name"`
+	Address       string `json:"address"`
+	LicenseNumber string `json:"license_number"`
+	SignerUserID  *int64 `json:"signer_user_id,omitempty"`
+	SignerName    string `json:"signer_name"`
+	SignerEmail   string `json:"signer_email"`
+}
+
+// LandlordResponse is a landlord as returned by the API
+type LandlordResponse struct {
+	ID            int64  `json:"id"`
+	LegalName     string `json:"legal_name"`
+	Address       string `json:"address"`
+	LicenseNumber string `json:"license_number,omitempty"`
+	SignerUserID  *int64 `json:"signer_user_id,omitempty"`
+	SignerName    string `json:"signer_name"`
+	SignerEmail   string `json:"signer_email"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+func toLandlordResponse(l db.Landlord) LandlordResponse {
+	resp := LandlordResponse{
+		ID:            l.ID,
+		LegalName:     l.LegalName,
+		Address:       l.Address,
+		LicenseNumber: l.LicenseNumber.String,
+		SignerName:    l.SignerName,
+		SignerEmail:   l.SignerEmail,
+		UpdatedAt:     l.UpdatedAt.Time.Format(time.RFC3339),
+	}
+	if l.SignerUserID.Valid {
+		resp.SignerUserID = &l.SignerUserID.Int64
+	}
+	return resp
+}
+
+// landlordParams validates a landlord request and fills in the signer from their account
+func (h *LandlordHandler) landlordParams(ctx context.Context, req LandlordRequest) (db.CreateLandlordParams, error) {
+	params := db.CreateLandlordParams{
+		LegalName:     strings.TrimSpace(req.LegalName),
+		Address:       strings.TrimSpace(req.Address),
+		LicenseNumber: pgtype.Text{String: strings.TrimSpace(req.LicenseNumber), Valid: strings.TrimSpace(req.LicenseNumber) != ""},
+		SignerName:    strings.TrimSpace(req.SignerName),
+		SignerEmail:   strings.TrimSpace(req.SignerEmail),
+	}
+	if params.LegalName == "" {
+		return params, errors.New("legal_name is required")
+	}
+	if len(params.LicenseNumber.String) > 64 {
+		return params, errors.New("license_number must be at most 64 characters")
+	}
+	if req.SignerUserID != nil {
+		signer, err := h.queries.GetUserByID(ctx, *req.SignerUserID)
+		if err != nil {
+			return params, fmt.Errorf("signer user %d not found", *req.SignerUserID)
+		}
+		if signer.Role != db.RoleAdmin {
+			return params, errors.New("the signer must be an admin")
+		}
+		params.SignerUserID = pgtype.Int8{Int64: signer.ID, Valid: true}
+		if params.SignerName == "" {
+			params.SignerName = fmt.Sprintf("%s %s", signer.FirstName, signer.LastName)
+		}
+		if params.SignerEmail == "" {
+			params.SignerEmail = signer.Email
+		}
+	}
+	if params.SignerName == "" || params.SignerEmail == "" {
+		return params, errors.New("signer_name and signer_email are required without a signer_user_id")
+	}
+	if _, err := mail.ParseAddress(params.SignerEmail); err != nil {
+		return params, errors.New("signer_email is not a valid email address")
+	}
+	return params, nil
+}
+
+// ListLandlords returns every landlord
+func (h *LandlordHandler) ListLandlords(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.queries.ListLandlords(r.Context())
+	if err != nil {
+		log.Printf("[LANDLORDS] Failed listing landlords: %v", err)
+		http.Error(w, "Failed to fetch landlords", http.StatusInternalServerError)
+		return
+	}
+	resp := make([]LandlordResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, toLandlordResponse(row))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[LANDLORDS] Error encoding response: %v", err)
+	}
+}
+
+// GetLandlord returns one landlord
+func (h *LandlordHandler) GetLandlord(w http.ResponseWriter, r *http.Request) {
+	landlordID, err := strconv.ParseInt(chi.URLParam(r, "landlordID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid landlord ID", http.StatusBadRequest)
+		return
+	}
+	row, err := h.queries.GetLandlord(r.Context(), landlordID)
+	if err != nil {
+		http.Error(w, "Landlord not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toLandlordResponse(row)); err != nil {
+		log.Printf("[LANDLORDS] Error encoding response: %v", err)
+	}
+}
+
+// CreateLandlord adds a landlord that buildings can then be assigned to
+func (h *LandlordHandler) CreateLandlord(w http.ResponseWriter, r *http.Request) {
+	var req LandlordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid landlord request", http.StatusBadRequest)
+		return
+	}
+	params, err := h.landlordParams(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	row, err := h.queries.CreateLandlord(r.Context(), params)
+	if err != nil {
+		log.Printf("[LANDLORDS] Failed creating landlord %s: %v", params.LegalName, err)
+		http.Error(w, "Failed to create landlord", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[LANDLORDS] Created landlord %d (%s)", row.ID, row.LegalName)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toLandlordResponse(row)); err != nil {
+		log.Printf("[LANDLORDS] Error encoding response: %v", err)
+	}
+}
+
+// UpdateLandlord replaces a landlord's details. Leases already sent keep the details they were generated with.
+func (h *LandlordHandler) UpdateLandlord(w http.ResponseWriter, r *http.Request) {
+	landlordID, err := strconv.ParseInt(chi.URLParam(r, "landlordID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid landlord ID", http.StatusBadRequest)
+		return
+	}
+	var req LandlordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid landlord request", http.StatusBadRequest)
+		return
+	}
+	params, err := h.landlordParams(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	row, err := h.queries.UpdateLandlord(r.Context(), db.UpdateLandlordParams{
+		ID:            landlordID,
+		LegalName:     params.LegalName,
+		Address:       params.Address,
+		LicenseNumber: params.LicenseNumber,
+		SignerUserID:  params.SignerUserID,
+		SignerName:    params.SignerName,
+		SignerEmail:   params.SignerEmail,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Landlord not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("[LANDLORDS] Failed updating landlord %d: %v", landlordID, err)
+		http.Error(w, "Failed to update landlord", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toLandlordResponse(row)); err != nil {
+		log.Printf("[LANDLORDS] Error encoding response: %v", err)
+	}
+}
+
+// adminLandlord returns the landlord the admin signs for, creating one in the admin's own name the first
+// time. Buildings set up by an admin are managed by it.
+func adminLandlord(ctx context.Context, queries *db.Queries, admin LeaseParty) (db.Landlord, error) {
+	landlord, err := queries.GetLandlordBySignerUserID(ctx, pgtype.Int8{Int64: admin.UserID, Valid: true})
+	if err == nil || !errors.Is(err, pgx.ErrNoRows) {
+		return landlord, err
+	}
+	return queries.CreateLandlord(ctx, db.CreateLandlordParams{
+		LegalName:    admin.Name,
+		SignerUserID: pgtype.Int8{Int64: admin.UserID, Valid: true},
+		SignerName:   admin.Name,
+		SignerEmail:  admin.Email,
+	})
+}
+
+// leaseLandlord is who a lease is made with and who signs it for them
+type leaseLandlord struct {
+	// UserID is recorded as the lease's landlord: the signer's account, or the fallback user when the
+	// signer has none
+	UserID        int64
+	Signer        LeaseParty
+	LegalName     string
+	Address       string
+	LicenseNumber string
+}
+
+// apartmentLandlord returns the landlord managing the apartment's building. The fallback signs for
+// apartments without one.
+func (h *LeaseHandler) apartmentLandlord(ctx context.Context, apartmentID int64, fallback LeaseParty) (leaseLandlord, error) {
+	row, err := h.queries.GetApartmentLandlord(ctx, apartmentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("[LANDLORDS] Apartment %d has no landlord, %s signs", apartmentID, fallback.Email)
+		return leaseLandlord{UserID: fallback.UserID, Signer: fallback, LegalName: fallback.Name}, nil
+	}
+	if err != nil {
+		return leaseLandlord{}, fmt.Errorf("failed to load landlord of apartment %d: %w", apartmentID, err)
+	}
+
+	landlord := leaseLandlord{
+		UserID:        fallback.UserID,
+		Signer:        LeaseParty{Name: row.SignerName, Email: row.SignerEmail},
+		LegalName:     row.LegalName,
+		Address:       row.Address,
+		LicenseNumber: row.LicenseNumber.String,
+	}
+	if row.SignerUserID.Valid {
+		landlord.UserID = row.SignerUserID.Int64
+		landlord.Signer.UserID = row.SignerUserID.Int64
+	}
+	return landlord, nil
+}
+
+// landlordOfLease returns the landlord of an existing lease, falling back to the user recorded on it
+func (h *LeaseHandler) landlordOfLease(ctx context.Context, apartmentID, landlordUserID int64) (leaseLandlord, error) {
+	user, err := h.queries.GetUserByID(ctx, landlordUserID)
+	if err != nil {
+		return leaseLandlord{}, fmt.Errorf("landlord user %d not found: %w", landlordUserID, err)
+	}
+	return h.apartmentLandlord(ctx, apartmentID, LeaseParty{
+		UserID: user.ID,
+		Name:   fmt.Sprintf("%s %s", user.FirstName, user.LastName),
+		Email:  user.Email,
+	})
+}
+
+// leaseLandlordSignerEmail is the email the landlord signs a lease with: the one it was uploaded with, or
+// the current signer of the apartment's landlord for leases created before signers were recorded
+func (h *LeaseHandler) leaseLandlordSignerEmail(ctx context.Context, lease db.GetLeaseByIDRow) (string, error) {
+	if signers, err := h.queries.ListLeaseSigners(ctx, lease.ID); err == nil {
+		for _, signer := range signers {
+			if signer.Role == db.LeaseSignerRoleLandlord {
+				return signer.Email, nil
+			}
+		}
+	}
+	landlord, err := h.landlordOfLease(ctx, lease.ApartmentID, lease.LandlordID)
+	if err != nil {
+		return "", err
+	}
+	return landlord.Signer.Email, nil
+}
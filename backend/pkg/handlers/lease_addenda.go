@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/templates"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type LeaseAddendumRequest struct {
+	Name     string `json:"name"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	Archived bool   `json:"archived"`
+}
+
+type LeaseAddendumResponse struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	Archived bool   `json:"archived"`
+}
+
+// LeaseAttachedAddendumResponse is an addendum as it was attached to a lease
+type LeaseAttachedAddendumResponse struct {
+	AddendumID int64  `json:"addendum_id"`
+	Position   int32  `json:"position"`
+	Title      string `json:"title"`
+	Body       string `json:"body"`
+}
+
+func toLeaseAddendumResponse(a db.LeaseAddenda) LeaseAddendumResponse {
+	return LeaseAddendumResponse{
+		ID:       a.ID,
+		Name:     a.Name,
+		Title:    a.Title,
+		Body:     a.Body,
+		Archived: a.Archived,
+	}
+}
+
+func toLeaseAttachedAddendumResponses(addenda []db.LeaseAttachedAddenda) []LeaseAttachedAddendumResponse {
+	resp := make([]LeaseAttachedAddendumResponse, 0, len(addenda))
+	for _, a := range addenda {
+		resp = append(resp, LeaseAttachedAddendumResponse{
+			AddendumID: a.AddendumID,
+			Position:   a.Position,
+			Title:      a.Title,
+			Body:       a.Body,
+		})
+	}
+	return resp
+}
+
+// ListLeaseAddenda returns the addendum library, active addenda first
+func (h *LeaseHandler) ListLeaseAddenda(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.queries.ListLeaseAddenda(r.Context())
+	if err != nil {
+		log.Printf("[LEASE_ADDENDA] Failed listing addenda: %v", err)
+		http.Error(w, "Failed to fetch lease addenda", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]LeaseAddendumResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, toLeaseAddendumResponse(row))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[LEASE_ADDENDA] Error encoding response: %v", err)
+	}
+}
+
+// CreateLeaseAddendum adds an addendum to the library
+func (h *LeaseHandler) CreateLeaseAddendum(w http.ResponseWriter, r *http.Request) {
+	var req LeaseAddendumRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid addendum request", http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Name == "" || req.Title == "" {
+		http.Error(w, "Addendum name and title are required", http.StatusBadRequest)
+		return
+	}
+	if err := templates.ValidateAddendum(req.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	row, err := h.queries.CreateLeaseAddendum(r.Context(), db.CreateLeaseAddendumParams{
+		Name:      req.Name,
+		Title:     req.Title,
+		Body:      req.Body,
+		CreatedBy: pgtype.Int8{Int64: adminID, Valid: true},
+	})
+	if err != nil {
+		log.Printf("[LEASE_ADDENDA] Failed saving addendum %s: %v", req.Name, err)
+		http.Error(w, "Failed to save lease addendum", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toLeaseAddendumResponse(row)); err != nil {
+		log.Printf("[LEASE_ADDENDA] Error encoding response: %v", err)
+	}
+}
+
+// UpdateLeaseAddendum changes the text of a library addendum or archives it. Leases that already have
+// the addendum attached keep the text they were generated with.
+func (h *LeaseHandler) UpdateLeaseAddendum(w http.ResponseWriter, r *http.Request) {
+	addendumID, err := strconv.ParseInt(chi.URLParam(r, "addendumID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid addendum ID", http.StatusBadRequest)
+		return
+	}
+
+	var req LeaseAddendumRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid addendum request", http.StatusBadRequest)
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		http.Error(w, "Addendum title is required", http.StatusBadRequest)
+		return
+	}
+	if err := templates.ValidateAddendum(req.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	row, err := h.queries.UpdateLeaseAddendum(r.Context(), db.UpdateLeaseAddendumParams{
+		ID:       addendumID,
+		Title:    req.Title,
+		Body:     req.Body,
+		Archived: req.Archived,
+	})
+	if err != nil {
+		log.Printf("[LEASE_ADDENDA] Failed updating addendum %d: %v", addendumID, err)
+		http.Error(w, "Lease addendum not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toLeaseAddendumResponse(row)); err != nil {
+		log.Printf("[LEASE_ADDENDA] Error encoding response: %v", err)
+	}
+}
+
+// loadLeaseAddenda looks up the selected library addenda in the order given. Duplicates are dropped and
+// archived addenda are rejected.
+func (h *LeaseHandler) loadLeaseAddenda(ctx context.Context, addendumIDs []int64) ([]db.LeaseAddenda, error) {
+	addenda := make([]db.LeaseAddenda, 0, len(addendumIDs))
+	seen := map[int64]bool{}
+	for _, id := range addendumIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		addendum, err := h.queries.GetLeaseAddendum(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("addendum %d not found", id)
+		}
+		if addendum.Archived {
+			return nil, fmt.Errorf("addendum %s is archived", addendum.Name)
+		}
+		addenda = append(addenda, addendum)
+	}
+	return addenda, nil
+}
+
+// attachedAddendumIDs returns the library IDs of the addenda on a lease, so an amendment keeps them
+// unless the admin picks a new list
+func (h *LeaseHandler) attachedAddendumIDs(ctx context.Context, leaseID int64) []int64 {
+	attached, err := h.queries.ListLeaseAttachedAddenda(ctx, leaseID)
+	if err != nil {
+		log.Printf("[LEASE_ADDENDA] Could not load addenda of lease %d: %v", leaseID, err)
+		return nil
+	}
+	ids := make([]int64, 0, len(attached))
+	for _, a := range attached {
+		ids = append(ids, a.AddendumID)
+	}
+	return ids
+}
+
+func toTemplateAddenda(addenda []db.LeaseAddenda) []templates.LeaseAddendum {
+	out := make([]templates.LeaseAddendum, 0, len(addenda))
+	for _, a := range addenda {
+		out = append(out, templates.LeaseAddendum{Title: a.Title, Body: a.Body})
+	}
+	return out
+}
+
+// recordLeaseAddenda copies the attached addenda onto the lease in page order
+func (h *LeaseHandler) recordLeaseAddenda(ctx context.Context, leaseID int64, addenda []db.LeaseAddenda) error {
+	for i, a := range addenda {
+		if err := h.queries.AttachLeaseAddendum(ctx, db.AttachLeaseAddendumParams{
+			LeaseID:    leaseID,
+			AddendumID: a.ID,
+			Position:   int32(i + 1),
+			Title:      a.Title,
+			Body:       a.Body,
+		}); err != nil {
+			return fmt.Errorf("failed attaching addendum %d to lease %d: %w", a.ID, leaseID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// LeaseTermChange is one changed term of an amended lease
+type LeaseTermChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type LeaseAmendmentResponse struct {
+	Version       int32             `json:"version"`
+	AmendedFrom   int64             `json:"amended_from"`
+	Changes       []LeaseTermChange `json:"changes"`
+	ExternalDocID string            `json:"external_doc_id"`
+	CreatedBy     int64             `json:"created_by"`
+	CreatedAt     string            `json:"created_at"`
+}
+
+// LeaseHistoryEntry is one version of a lease in its history chain
+type LeaseHistoryEntry struct {
+	ID              int64                   `json:"id"`
+	LeaseNumber     int64                   `json:"lease_number"`
+	DocumentType    string                  `json:"document_type"`
+	Status          string                  `json:"status"`
+	LeaseStartDate  string                  `json:"lease_start_date"`
+	LeaseEndDate    string                  `json:"lease_end_date"`
+	RentAmount      float64                 `json:"rent_amount"`
+	ExternalDocID   string                  `json:"external_doc_id"`
+	PreviousLeaseID *int64                  `json:"previous_lease_id,omitempty"`
+	CreatedAt       string                  `json:"created_at"`
+	Amendment       *LeaseAmendmentResponse `json:"amendment,omitempty"`
+}
+
+// leaseTerms are the terms an amendment can change
+type leaseTerms struct {
+	lease     db.GetLeaseByIDRow
+	occupants []db.ListLeaseTenantsRow
+}
+
+func (h *LeaseHandler) loadLeaseTerms(ctx context.Context, leaseID int64) (leaseTerms, error) {
+	lease, err := h.queries.GetLeaseByID(ctx, leaseID)
+	if err != nil {
+		return leaseTerms{}, fmt.Errorf("lease %d not found: %w", leaseID, err)
+	}
+	occupants, err := h.queries.ListLeaseTenants(ctx, leaseID)
+	if err != nil {
+		return leaseTerms{}, fmt.Errorf("could not load occupants of lease %d: %w", leaseID, err)
+	}
+	return leaseTerms{lease: lease, occupants: occupants}, nil
+}
+
+func occupantList(occupants []db.ListLeaseTenantsRow) string {
+	names := make([]string, 0, len(occupants))
+	for _, o := range occupants {
+		names = append(names, fmt.Sprintf("%s %s", o.FirstName, o.LastName))
+	}
+	return strings.Join(names, ", ")
+}
+
+// diffLeaseTerms lists the terms that differ between a lease and its amended version
+func diffLeaseTerms(before, after leaseTerms) []LeaseTermChange {
+	changes := []LeaseTermChange{}
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, LeaseTermChange{Field: field, From: from, To: to})
+		}
+	}
+	add("rent_amount",
+		fmt.Sprintf("%.2f", utils.ConvertPgNumericToFloat(before.lease.RentAmount)),
+		fmt.Sprintf("%.2f", utils.ConvertPgNumericToFloat(after.lease.RentAmount)))
+	add("lease_start_date", before.lease.LeaseStartDate.Time.Format("2006-01-02"), after.lease.LeaseStartDate.Time.Format("2006-01-02"))
+	add("lease_end_date", before.lease.LeaseEndDate.Time.Format("2006-01-02"), after.lease.LeaseEndDate.Time.Format("2006-01-02"))
+	add("apartment_id", strconv.FormatInt(before.lease.ApartmentID, 10), strconv.FormatInt(after.lease.ApartmentID, 10))
+	add("occupants", occupantList(before.occupants), occupantList(after.occupants))
+	return changes
+}
+
+// recordLeaseAmendment marks the new lease version as an amendment and stores what changed. The new
+// version already has its own Documenso document, so the amendment is signed separately from the original.
+func (h *LeaseHandler) recordLeaseAmendment(ctx context.Context, before leaseTerms, amendedLeaseID, adminID int64) {
+	if err := h.queries.SetLeaseDocumentType(ctx, db.SetLeaseDocumentTypeParams{
+		ID:           amendedLeaseID,
+		DocumentType: db.TypeAmendment,
+	}); err != nil {
+		log.Printf("[LEASE_AMEND] Failed marking lease %d as an amendment: %v", amendedLeaseID, err)
+	}
+
+	after, err := h.loadLeaseTerms(ctx, amendedLeaseID)
+	if err != nil {
+		log.Printf("[LEASE_AMEND] Could not diff amendment: %v", err)
+		return
+	}
+	changes, _ := json.Marshal(diffLeaseTerms(before, after))
+
+	amendment, err := h.queries.CreateLeaseAmendment(ctx, db.CreateLeaseAmendmentParams{
+		LeaseID:        before.lease.ID,
+		AmendedLeaseID: amendedLeaseID,
+		Changes:        changes,
+		ExternalDocID:  after.lease.ExternalDocID,
+		CreatedBy:      adminID,
+	})
+	if err != nil {
+		log.Printf("[LEASE_AMEND] Failed recording amendment of lease %d: %v", before.lease.ID, err)
+		return
+	}
+	log.Printf("[LEASE_AMEND] Lease %d amended as version %d (lease %d): %s", before.lease.ID, amendment.Version, amendedLeaseID, changes)
+}
+
+// GetLeaseHistory returns every version of a lease, from the original agreement through each
+// amendment, following previous_lease_id in both directions from the requested lease
+func (h *LeaseHandler) GetLeaseHistory(w http.ResponseWriter, r *http.Request) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.queries.GetLeaseHistory(r.Context(), leaseID)
+	if err != nil {
+		log.Printf("[LEASE_HISTORY] Failed loading history of lease %d: %v", leaseID, err)
+		http.Error(w, "Failed to fetch lease history", http.StatusInternalServerError)
+		return
+	}
+	if len(rows) == 0 {
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
+
+	history := make([]LeaseHistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := LeaseHistoryEntry{
+			ID:             row.ID,
+			LeaseNumber:    row.LeaseNumber,
+			DocumentType:   string(row.DocumentType),
+			Status:         string(row.Status),
+			LeaseStartDate: row.LeaseStartDate.Time.Format("2006-01-02"),
+			LeaseEndDate:   row.LeaseEndDate.Time.Format("2006-01-02"),
+			RentAmount:     utils.ConvertPgNumericToFloat(row.RentAmount),
+			ExternalDocID:  row.ExternalDocID,
+			CreatedAt:      row.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+		}
+		if row.PreviousLeaseID.Valid {
+			entry.PreviousLeaseID = &row.PreviousLeaseID.Int64
+		}
+
+		if amendment, err := h.queries.GetLeaseAmendmentByAmendedLease(r.Context(), row.ID); err == nil {
+			var changes []LeaseTermChange
+			if err := json.Unmarshal(amendment.Changes, &changes); err != nil {
+				log.Printf("[LEASE_HISTORY] Amendment %d has unreadable changes: %v", amendment.ID, err)
+			}
+			entry.Amendment = &LeaseAmendmentResponse{
+				Version:       amendment.Version,
+				AmendedFrom:   amendment.LeaseID,
+				Changes:       changes,
+				ExternalDocID: amendment.ExternalDocID,
+				CreatedBy:     amendment.CreatedBy,
+				CreatedAt:     amendment.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+			}
+		}
+		history = append(history, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"lease_id": leaseID,
+		"history":  history,
+	}); err != nil {
+		log.Printf("[LEASE_HISTORY] Error encoding response: %v", err)
+	}
+}
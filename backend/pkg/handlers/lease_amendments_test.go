@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/pkg/handlers/documenso"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// activeLease is testTenant's lease 9 on apartment 3, in force for the whole of this year and next
+func activeLease() db.GetLeaseByIDRow {
+	year := time.Now().Year()
+	return db.GetLeaseByIDRow{
+		ID:             9,
+		LeaseNumber:    1,
+		ExternalDocID:  "41",
+		TenantID:       testTenant.ID,
+		LandlordID:     testAdmin.ID,
+		ApartmentID:    3,
+		LeaseStartDate: pgtype.Date{Time: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		LeaseEndDate:   pgtype.Date{Time: time.Date(year+1, time.December, 31, 0, 0, 0, 0, time.UTC), Valid: true},
+		Status:         db.LeaseStatusActive,
+	}
+}
+
+func TestAmendActiveLease(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerLeaseCreation(t, fdb)
+	original := activeLease()
+	fdb.returns("GetLeaseForAmending", db.GetLeaseForAmendingRow{
+		ID: original.ID, LeaseNumber: original.LeaseNumber, ExternalDocID: original.ExternalDocID, TenantID: original.TenantID,
+		LandlordID: original.LandlordID, ApartmentID: original.ApartmentID, LeaseStartDate: original.LeaseStartDate,
+		LeaseEndDate: original.LeaseEndDate, Status: original.Status,
+	})
+	fdb.on("GetLeaseByID", func(args []any) (any, error) {
+		switch args[0] {
+		case original.ID:
+			return original, nil
+		case int64(11):
+			amended := original
+			amended.ID, amended.Status, amended.PreviousLeaseID = 11, db.LeaseStatusDraft, pgtype.Int8{Int64: original.ID, Valid: true}
+			return amended, nil
+		}
+		return nil, pgx.ErrNoRows
+	})
+	// The tenant's current lease overlaps the amended term, but it is the one being replaced
+	fdb.on("GetConflictingOccupantLease", func(args []any) (any, error) {
+		if args[3] == (pgtype.Int8{Int64: original.ID, Valid: true}) {
+			return nil, pgx.ErrNoRows
+		}
+		return db.GetConflictingOccupantLeaseRow{ID: original.ID, LeaseNumber: 1, Status: db.LeaseStatusActive}, nil
+	})
+	fdb.returns("CreateLeaseAmendment", db.LeaseAmendment{LeaseID: original.ID, AmendedLeaseID: 11, Version: 1})
+
+	req := newLeaseRequest()
+	req.StartDate = original.LeaseStartDate.Time.Format("2006-01-02")
+	req.EndDate = original.LeaseEndDate.Time.Format("2006-01-02")
+	req.RentAmount = 1600
+	rec := httptest.NewRecorder()
+	h.AmendLease(rec, newTestRequest(t, http.MethodPost, "/admin/leases/amend", req))
+	if rec.Code != http.StatusOK && rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want success: %s", rec.Code, rec.Body.String())
+	}
+
+	created := fdb.called("RenewLease")
+	if len(created) != 1 {
+		t.Fatalf("RenewLease calls = %+v, want the amendment saved once", created)
+	}
+	if amendments := fdb.called("CreateLeaseAmendment"); len(amendments) != 1 || amendments[0].Args[0] != original.ID {
+		t.Errorf("CreateLeaseAmendment calls = %+v, want lease 9 amended", amendments)
+	}
+	if transitions := fdb.called("TransitionLeaseStatus"); len(transitions) != 0 {
+		t.Errorf("TransitionLeaseStatus calls = %+v, want the original left active until the amendment is signed", transitions)
+	}
+}
+
+func TestSignedAmendmentRetiresOriginalLease(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	signInAdmin(fdb)
+	fdb.returns("GetLeaseByExternalDocID", db.GetLeaseByExternalDocIDRow{ID: 11, ExternalDocID: "42", LandlordID: testAdmin.ID, ApartmentID: 3, Status: db.LeaseStatusPendingApproval})
+	fdb.returns("ListLeaseSigners", []db.LeaseSigner{leaseSigner("tenant@example.com", db.SigningStatusSigned)})
+	fdb.returns("TransitionLeaseStatus", db.LeaseStatusHistory{})
+	fdb.returns("GetApartment", db.GetApartmentRow{ID: 3})
+	fdb.returns("GetLeaseAmendmentByAmendedLease", db.LeaseAmendment{LeaseID: 9, AmendedLeaseID: 11, Version: 1})
+	fdb.returns("GetLeaseByID", activeLease())
+
+	err := h.processDocumensoEvent(context.Background(), signingEvent(documenso.EventDocumentCompleted,
+		documenso.WebhookRecipient{Email: "tenant@example.com", SigningStatus: documenso.SigningStatusSigned},
+	))
+	if err != nil {
+		t.Fatalf("processDocumensoEvent: %v", err)
+	}
+
+	transitions := fdb.called("TransitionLeaseStatus")
+	if len(transitions) != 2 {
+		t.Fatalf("TransitionLeaseStatus calls = %+v, want the amendment activated and the original retired", transitions)
+	}
+	if transitions[0].Args[2] != int64(11) || transitions[0].Args[0] != db.LeaseStatusActive {
+		t.Errorf("first transition = %+v, want amendment 11 activated", transitions[0])
+	}
+	if transitions[1].Args[2] != int64(9) || transitions[1].Args[0] != db.LeaseStatusSuperseded {
+		t.Errorf("second transition = %+v, want original lease 9 superseded", transitions[1])
+	}
+	if fdb.committed != 1 {
+		t.Errorf("committed %d transactions, want both changes in one", fdb.committed)
+	}
+}
+
+func TestSignedLeaseWithoutAmendmentLeavesOtherLeases(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	signInAdmin(fdb)
+	fdb.returns("GetLeaseByExternalDocID", signingLease(db.LeaseStatusPendingApproval))
+	fdb.returns("ListLeaseSigners", []db.LeaseSigner{leaseSigner("tenant@example.com", db.SigningStatusSigned)})
+	fdb.returns("TransitionLeaseStatus", db.LeaseStatusHistory{})
+	fdb.returns("GetApartment", db.GetApartmentRow{ID: 3})
+
+	err := h.processDocumensoEvent(context.Background(), signingEvent(documenso.EventDocumentCompleted,
+		documenso.WebhookRecipient{Email: "tenant@example.com", SigningStatus: documenso.SigningStatusSigned},
+	))
+	if err != nil {
+		t.Fatalf("processDocumensoEvent: %v", err)
+	}
+	if transitions := fdb.called("TransitionLeaseStatus"); len(transitions) != 1 {
+		t.Errorf("TransitionLeaseStatus calls = %+v, want only the signed lease activated", transitions)
+	}
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/storage"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// documentURLTTL is how long a download link handed out by the API stays valid
+const documentURLTTL = 15 * time.Minute
+
+// newDocumentStore picks where lease PDFs are kept from STORAGE_BACKEND. "s3" uses any S3-compatible
+// service and refuses to start when it is misconfigured, anything else keeps them on disk under STORAGE_DIR.
+func newDocumentStore() storage.Store {
+	if os.Getenv("STORAGE_BACKEND") == "s3" {
+		pathStyle, _ := strconv.ParseBool(os.Getenv("S3_PATH_STYLE"))
+		store, err := storage.NewS3Store(
+			os.Getenv("S3_ENDPOINT"),
+			os.Getenv("S3_REGION"),
+			os.Getenv("S3_BUCKET"),
+			os.Getenv("S3_ACCESS_KEY_ID"),
+			os.Getenv("S3_SECRET_ACCESS_KEY"),
+			pathStyle,
+		)
+		if err != nil {
+			// Quietly keeping documents on local disk would lose them on the next deploy
+			log.Fatalf("[DOCUMENT_STORE] STORAGE_BACKEND is s3 but S3 storage could not be configured: %v", err)
+		}
+		return store
+	}
+
+	dir := os.Getenv("STORAGE_DIR")
+	if dir == "" {
+		dir = filepath.Join(tempDir, "storage")
+	}
+	store, err := storage.NewLocalStore(dir)
+	if err != nil {
+		log.Printf("[DOCUMENT_STORE] %v", err)
+		return &storage.LocalStore{Dir: dir}
+	}
+	return store
+}
+
+// newDocumentURLSigner signs download links with FILE_URL_SECRET, which every replica must share. It is
+// required outside development; there a random secret stands in, so links stop working on restart.
+func newDocumentURLSigner() *storage.URLSigner {
+	secret := []byte(os.Getenv("FILE_URL_SECRET"))
+	if len(secret) == 0 {
+		if os.Getenv("ENV") != "development" {
+			// A per-process secret breaks links on every restart and between replicas
+			log.Fatalf("[DOCUMENT_STORE] FILE_URL_SECRET must be set outside development")
+		}
+		log.Println("[DOCUMENT_STORE] FILE_URL_SECRET not set, download links will not survive a restart")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatalf("[DOCUMENT_STORE] Could not generate a URL signing secret: %v", err)
+		}
+	}
+	return storage.NewURLSigner(secret, utils.GetAbsoluteUrl("/files"))
+}
+
+// leasePdfKey is where a lease's PDF of the given kind (generated or signed) is stored
+func leasePdfKey(leaseID int64, kind string) string {
+	return fmt.Sprintf("leases/%d/%s.pdf", leaseID, kind)
+}
+
+// recordGeneratedLeasePDF keeps our own copy of the lease as it was sent for signing
+func (h *LeaseHandler) recordGeneratedLeasePDF(ctx context.Context, leaseID int64, pdfData []byte) {
+	key := leasePdfKey(leaseID, "generated")
+	if err := h.documents.Put(ctx, key, pdfData, "application/pdf"); err != nil {
+		log.Printf("[DOCUMENT_STORE] Failed storing generated PDF of lease %d: %v", leaseID, err)
+		return
+	}
+	if err := h.queries.SetLeaseGeneratedPdfKey(ctx, db.SetLeaseGeneratedPdfKeyParams{
+		ID:              leaseID,
+		GeneratedPdfKey: pgtype.Text{String: key, Valid: true},
+	}); err != nil {
+		log.Printf("[DOCUMENT_STORE] Failed recording generated PDF of lease %d: %v", leaseID, err)
+	}
+}
+
+// storeSignedLeasePDF downloads the completed document from the e-sign provider and keeps our own copy
+func (h *LeaseHandler) storeSignedLeasePDF(ctx context.Context, leaseID int64, documentID string) (string, error) {
+	pdfData, err := h.documenso_client.DownloadDocument(documentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to download signed document %s: %w", documentID, err)
+	}
+	key := leasePdfKey(leaseID, "signed")
+	if err := h.documents.Put(ctx, key, pdfData, "application/pdf"); err != nil {
+		return "", err
+	}
+	if err := h.queries.SetLeaseSignedPdfKey(ctx, db.SetLeaseSignedPdfKeyParams{
+		ID:           leaseID,
+		SignedPdfKey: pgtype.Text{String: key, Valid: true},
+	}); err != nil {
+		return "", fmt.Errorf("failed to record signed PDF of lease %d: %w", leaseID, err)
+	}
+	h.recordSignedLeaseDocument(ctx, leaseID, key, len(pdfData))
+	return key, nil
+}
+
+// leaseDocumentKey returns the stored PDF of a lease, preferring the signed copy. A signed lease whose copy
+// is missing, like one signed before documents were stored, is fetched from the e-sign provider first.
+func (h *LeaseHandler) leaseDocumentKey(ctx context.Context, lease db.GetLeaseByIDRow) (key string, signed bool, err error) {
+	keys, err := h.queries.GetLeasePdfKeys(ctx, lease.ID)
+	if err != nil {
+		return "", false, err
+	}
+	if keys.SignedPdfKey.Valid {
+		return keys.SignedPdfKey.String, true, nil
+	}
+	switch lease.Status {
+	case db.LeaseStatusActive, db.LeaseStatusMonthToMonth, db.LeaseStatusExpired, db.LeaseStatusTerminated,
+		db.LeaseStatusRenewed, db.LeaseStatusSuperseded:
+		if lease.ExternalDocID != "" {
+			key, err := h.storeSignedLeasePDF(ctx, lease.ID, lease.ExternalDocID)
+			if err == nil {
+				return key, true, nil
+			}
+			log.Printf("[DOCUMENT_STORE] Could not fetch signed copy of lease %d: %v", lease.ID, err)
+		}
+	}
+	if keys.GeneratedPdfKey.Valid {
+		return keys.GeneratedPdfKey.String, false, nil
+	}
+	return "", false, storage.ErrNotFound
+}
+
+// PdfS3GetDocumentURL returns a short-lived link to our stored copy of a lease PDF
+func (h *LeaseHandler) PdfS3GetDocumentURL(w http.ResponseWriter, r *http.Request) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID format", http.StatusBadRequest)
+		return
+	}
+	lease, err := h.queries.GetLeaseByID(r.Context(), leaseID)
+	if err != nil {
+		log.Printf("[PDF_URL] Error fetching lease %d: %v", leaseID, err)
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
+
+	key, signed, err := h.leaseDocumentKey(r.Context(), lease)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, "Lease has no stored PDF", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("[PDF_URL] Error finding PDF of lease %d: %v", leaseID, err)
+		http.Error(w, "Failed to find lease PDF", http.StatusInternalServerError)
+		return
+	}
+	link, expires := h.documentURLs.URL(key, documentURLTTL, time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"lease_id":   leaseID,
+		"url":        link,
+		"expires_at": expires,
+		"signed":     signed,
+		"status":     lease.Status,
+	}); err != nil {
+		log.Printf("[PDF_URL] Error encoding response: %v", err)
+	}
+}
+
+// inlineDocumentTypes are the content types ServeDocument lets the browser display rather than download
+var inlineDocumentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+}
+
+// ServeDocument streams a stored document to whoever holds a valid signed link to it
+func (h *LeaseHandler) ServeDocument(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "*")
+	params, err := h.documentURLs.VerifyQuery(key, r.URL.Query(), time.Now())
+	if err != nil {
+		if errors.Is(err, storage.ErrURLExpired) {
+			http.Error(w, "Link has expired", http.StatusGone)
+			return
+		}
+		http.Error(w, "Invalid link", http.StatusForbidden)
+		return
+	}
+
+	data, err := h.documents.Get(r.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("[DOCUMENT_STORE] Failed reading %s: %v", key, err)
+		http.Error(w, "Failed to read document", http.StatusInternalServerError)
+		return
+	}
+
+	// Vault links name the document, and each fetch of one is a download; no file without an audit entry
+	if params.Has("document") {
+		if err := h.recordDocumentDownload(r, params); err != nil {
+			log.Printf("[DOCUMENTS] %v", err)
+			http.Error(w, "Failed to read document", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	// Only PDFs and raster images open in the browser; anything else uploaded to the vault, SVG and HTML
+	// included, is downloaded so it cannot run script on our origin
+	disposition := "attachment"
+	if inlineDocumentTypes[contentType] {
+		disposition = "inline"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, path.Base(key)))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
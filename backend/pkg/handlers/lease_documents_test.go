@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/careecodes/RentDaddy/internal/storage"
+)
+
+func TestServeDocumentOnlyDisplaysPDFsAndRasterImages(t *testing.T) {
+	tests := []struct {
+		key         string
+		disposition string
+	}{
+		{"leases/9/signed.pdf", "inline"},
+		{"vault/photo.png", "inline"},
+		{"vault/photo.jpg", "inline"},
+		{"vault/drawing.svg", "attachment"},
+		{"vault/page.html", "attachment"},
+		{"vault/notes.txt", "attachment"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			h, _ := newTestHandler(t)
+			h.documentURLs = storage.NewURLSigner([]byte("secret"), "http://localhost/files")
+			if err := h.documents.Put(context.Background(), tt.key, []byte("<script>alert(1)</script>"), ""); err != nil {
+				t.Fatalf("storing %s: %v", tt.key, err)
+			}
+			link, _ := h.documentURLs.URL(tt.key, time.Minute, time.Now())
+			u, err := url.Parse(link)
+			if err != nil {
+				t.Fatalf("parsing link %s: %v", link, err)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeDocument(rec, newTestRequest(t, http.MethodGet, "/files/"+tt.key+"?"+u.RawQuery, nil, "*", tt.key))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(got, tt.disposition+";") {
+				t.Errorf("Content-Disposition = %q, want %s", got, tt.disposition)
+			}
+			if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+			}
+			if got := rec.Header().Get("Content-Security-Policy"); got != "sandbox" {
+				t.Errorf("Content-Security-Policy = %q, want sandbox", got)
+			}
+		})
+	}
+}
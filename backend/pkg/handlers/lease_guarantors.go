@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+)
+
+// LeaseGuarantorRequest is a guarantor named on a lease. Guarantors sign the lease document
+// but are not tenants and have no account.
+type LeaseGuarantorRequest struct {
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Relationship string `json:"relationship"`
+}
+
+type LeaseGuarantorResponse struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Relationship string `json:"relationship"`
+}
+
+func toLeaseGuarantorResponses(guarantors []db.LeaseGuarantor) []LeaseGuarantorResponse {
+	resp := make([]LeaseGuarantorResponse, 0, len(guarantors))
+	for _, g := range guarantors {
+		resp = append(resp, LeaseGuarantorResponse{
+			ID:           g.ID,
+			Name:         g.Name,
+			Email:        g.Email,
+			Relationship: g.Relationship,
+		})
+	}
+	return resp
+}
+
+// validateLeaseGuarantors checks every guarantor is complete and is not already a signer on the lease.
+// Documenso matches recipients by email, so each signer needs a distinct one.
+func validateLeaseGuarantors(guarantors []LeaseGuarantorRequest, occupants []LeaseParty, landlordEmail string) error {
+	taken := map[string]string{strings.ToLower(landlordEmail): "the landlord"}
+	for _, occupant := range occupants {
+		taken[strings.ToLower(occupant.Email)] = "a tenant"
+	}
+
+	for i, g := range guarantors {
+		if strings.TrimSpace(g.Name) == "" || strings.TrimSpace(g.Relationship) == "" {
+			return fmt.Errorf("guarantor %d needs a name and relationship", i+1)
+		}
+		if _, err := mail.ParseAddress(g.Email); err != nil {
+			return fmt.Errorf("guarantor %s has an invalid email", g.Name)
+		}
+		email := strings.ToLower(g.Email)
+		if who, ok := taken[email]; ok {
+			return fmt.Errorf("guarantor email %s is already used by %s", g.Email, who)
+		}
+		taken[email] = "another guarantor"
+	}
+	return nil
+}
+
+// recordLeaseGuarantors stores the guarantors against the lease
+func (h *LeaseHandler) recordLeaseGuarantors(ctx context.Context, leaseID int64, guarantors []LeaseGuarantorRequest) error {
+	for _, g := range guarantors {
+		if _, err := h.queries.CreateLeaseGuarantor(ctx, db.CreateLeaseGuarantorParams{
+			LeaseID:      leaseID,
+			Name:         g.Name,
+			Email:        g.Email,
+			Relationship: g.Relationship,
+		}); err != nil {
+			return fmt.Errorf("failed recording guarantor %s for lease %d: %w", g.Email, leaseID, err)
+		}
+	}
+	return nil
+}
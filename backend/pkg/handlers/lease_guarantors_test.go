@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/templates"
+)
+
+var testTenant = db.GetUserByIDRow{ID: 5, ClerkID: "user_tenant", FirstName: "Tom", LastName: "Tenant", Email: "tenant@example.com", Role: db.RoleTenant}
+
+// newLeaseRequest is a lease for testTenant that CreateLease accepts
+func newLeaseRequest(guarantors ...LeaseGuarantorRequest) LeaseUpsertRequest {
+	return LeaseUpsertRequest{
+		TenantID:        testTenant.ID,
+		ApartmentID:     3,
+		StartDate:       "2025-01-01",
+		EndDate:         "2025-12-31",
+		RentAmount:      1500,
+		DocumentTitle:   "Lease",
+		PropertyAddress: "1 Main St",
+		Guarantors:      guarantors,
+	}
+}
+
+// answerLeaseCreation answers the lookups lease creation makes for testTenant with the standard template
+func answerLeaseCreation(t *testing.T, fdb *fakeDB) {
+	t.Helper()
+	signInAdmin(fdb, testTenant)
+	content, err := json.Marshal(templates.DefaultLeaseTemplate())
+	if err != nil {
+		t.Fatalf("encoding default template: %v", err)
+	}
+	fdb.returns("GetLatestLeaseTemplate", db.LeaseTemplate{ID: 1, Name: templates.DefaultLeaseTemplateName, Version: 1, Content: content})
+	fdb.returns("RenewLease", db.RenewLeaseRow{ID: 11, LeaseNumber: 1})
+	fdb.returns("UpsertLeaseSigner", db.LeaseSigner{})
+	fdb.returns("CreateLeaseGuarantor", db.LeaseGuarantor{})
+}
+
+func TestCreateLeaseRecordsGuarantors(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerLeaseCreation(t, fdb)
+	guarantor := LeaseGuarantorRequest{Name: "Pat Parent", Email: "pat@example.com", Relationship: "parent"}
+
+	rec := httptest.NewRecorder()
+	h.CreateLease(rec, newTestRequest(t, http.MethodPost, "/admin/leases/create", newLeaseRequest(guarantor)))
+	if rec.Code != http.StatusOK && rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want success: %s", rec.Code, rec.Body.String())
+	}
+
+	recorded := fdb.called("CreateLeaseGuarantor")
+	if len(recorded) != 1 || recorded[0].Args[0] != int64(11) || recorded[0].Args[2] != guarantor.Email || recorded[0].Args[3] != guarantor.Relationship {
+		t.Errorf("CreateLeaseGuarantor calls = %+v, want the guarantor stored against lease 11", recorded)
+	}
+	var roles []db.LeaseSignerRole
+	for _, call := range fdb.called("UpsertLeaseSigner") {
+		roles = append(roles, call.Args[2].(db.LeaseSignerRole))
+		if call.Args[2] == db.LeaseSignerRoleGuarantor && call.Args[4] != guarantor.Email {
+			t.Errorf("guarantor signer email = %v, want %s", call.Args[4], guarantor.Email)
+		}
+	}
+	want := []db.LeaseSignerRole{db.LeaseSignerRoleTenant, db.LeaseSignerRoleGuarantor, db.LeaseSignerRoleLandlord}
+	if len(roles) != len(want) {
+		t.Fatalf("signer roles = %v, want %v", roles, want)
+	}
+	for i := range want {
+		if roles[i] != want[i] {
+			t.Errorf("signer roles = %v, want %v", roles, want)
+			break
+		}
+	}
+	if fdb.committed != 1 {
+		t.Errorf("committed %d transactions, want 1", fdb.committed)
+	}
+}
+
+func TestCreateLeaseRejectsInvalidGuarantors(t *testing.T) {
+	tests := []struct {
+		name      string
+		guarantor LeaseGuarantorRequest
+	}{
+		{"missing relationship", LeaseGuarantorRequest{Name: "Pat Parent", Email: "pat@example.com"}},
+		{"invalid email", LeaseGuarantorRequest{Name: "Pat Parent", Email: "pat", Relationship: "parent"}},
+		{"tenant's email", LeaseGuarantorRequest{Name: "Pat Parent", Email: "Tenant@example.com", Relationship: "parent"}},
+		{"landlord's email", LeaseGuarantorRequest{Name: "Pat Parent", Email: testAdmin.Email, Relationship: "parent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fdb := newTestHandler(t)
+			answerLeaseCreation(t, fdb)
+
+			rec := httptest.NewRecorder()
+			h.CreateLease(rec, newTestRequest(t, http.MethodPost, "/admin/leases/create", newLeaseRequest(tt.guarantor)))
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body.String())
+			}
+			if calls := fdb.called("RenewLease"); len(calls) != 0 {
+				t.Errorf("lease saved despite an invalid guarantor: %+v", calls)
+			}
+			if calls := fdb.called("CreateLeaseGuarantor"); len(calls) != 0 {
+				t.Errorf("invalid guarantor recorded: %+v", calls)
+			}
+		})
+	}
+}
+
+func TestCreateLeaseRejectsDuplicateGuarantors(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerLeaseCreation(t, fdb)
+	guarantor := LeaseGuarantorRequest{Name: "Pat Parent", Email: "pat@example.com", Relationship: "parent"}
+
+	rec := httptest.NewRecorder()
+	h.CreateLease(rec, newTestRequest(t, http.MethodPost, "/admin/leases/create", newLeaseRequest(guarantor, guarantor)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateLeaseRollsBackWhenAGuarantorCannotBeRecorded(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerLeaseCreation(t, fdb)
+	fdb.fails("CreateLeaseGuarantor", errors.New("connection reset"))
+	guarantor := LeaseGuarantorRequest{Name: "Pat Parent", Email: "pat@example.com", Relationship: "parent"}
+
+	rec := httptest.NewRecorder()
+	h.CreateLease(rec, newTestRequest(t, http.MethodPost, "/admin/leases/create", newLeaseRequest(guarantor)))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500: %s", rec.Code, rec.Body.String())
+	}
+	if fdb.committed != 0 || fdb.rolledBack != 1 {
+		t.Errorf("committed %d and rolled back %d transactions, want the lease rolled back", fdb.committed, fdb.rolledBack)
+	}
+	if calls := fdb.called("UpsertLeaseSigner"); len(calls) != 0 {
+		t.Errorf("signers recorded after the guarantor failed: %+v", calls)
+	}
+}
@@ -0,0 +1,597 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/smtp"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RenewalOption is one set of terms a tenant can accept to renew their lease
+type RenewalOption struct {
+	TermMonths int     `json:"term_months"`
+	RentAmount float64 `json:"rent_amount"`
+}
+
+type RenewalOfferRequest struct {
+	Options []RenewalOption `json:"options"`
+}
+
+type RenewalOfferResponse struct {
+	ID             int64           `json:"id"`
+	LeaseID        int64           `json:"lease_id"`
+	Status         string          `json:"status"`
+	Options        []RenewalOption `json:"options"`
+	AcceptedOption *int32          `json:"accepted_option,omitempty"`
+	RenewedLeaseID *int64          `json:"renewed_lease_id,omitempty"`
+	NoticeStage    int32           `json:"notice_stage"`
+	LastNotifiedAt string          `json:"last_notified_at,omitempty"`
+	RespondedAt    string          `json:"responded_at,omitempty"`
+	ExpiresAt      string          `json:"expires_at"`
+	CreatedAt      string          `json:"created_at"`
+}
+
+// renewalNoticeDays are the days before the lease end date at which the offer is sent and then
+// each reminder. The last stage is the final notice and is copied to the landlord.
+var renewalNoticeDays = []int{60, 30, 14, 7}
+
+// renewalNoticeStage returns the notice stage for the days left on a lease, or -1 when it is too early
+func renewalNoticeStage(daysLeft int) int {
+	stage := -1
+	for i, days := range renewalNoticeDays {
+		if daysLeft <= days {
+			stage = i
+		}
+	}
+	return stage
+}
+
+// defaultRenewalOptions proposes a 12 month renewal at 3% over the current rent and a shorter
+// 6 month renewal at 5%
+func defaultRenewalOptions(currentRent float64) []RenewalOption {
+	return []RenewalOption{
+		{TermMonths: 12, RentAmount: math.Round(currentRent*103) / 100},
+		{TermMonths: 6, RentAmount: math.Round(currentRent*105) / 100},
+	}
+}
+
+func validateRenewalOptions(options []RenewalOption) error {
+	if len(options) == 0 {
+		return errors.New("a renewal offer needs at least one option")
+	}
+	for i, o := range options {
+		if o.TermMonths < 1 || o.TermMonths > 36 {
+			return fmt.Errorf("option %d: term must be between 1 and 36 months", i+1)
+		}
+		if o.RentAmount <= 0 {
+			return fmt.Errorf("option %d: rent amount must be positive", i+1)
+		}
+	}
+	return nil
+}
+
+func newRenewalOfferToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// renewalOfferExpiry is when an offer stops being open: the start of the day after the lease ends
+func renewalOfferExpiry(leaseEnd time.Time) time.Time {
+	return time.Date(leaseEnd.Year(), leaseEnd.Month(), leaseEnd.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+// renewalOfferClosed returns the status and message for an offer that can no longer be accepted: 410 once
+// it has expired, 409 when it was already answered or the lease has ended some other way. ok is false for
+// an offer that is still open.
+func renewalOfferClosed(offer db.LeaseRenewalOffer, leaseStatus db.LeaseStatus, now time.Time) (status int, message string, ok bool) {
+	switch {
+	case offer.Status == db.RenewalOfferStatusExpired || (offer.Status == db.RenewalOfferStatusPending && !now.Before(offer.ExpiresAt.Time)):
+		return http.StatusGone, "This renewal offer has expired", true
+	case offer.Status != db.RenewalOfferStatusPending:
+		return http.StatusConflict, "This renewal offer is no longer open", true
+	case leaseStatus != db.LeaseStatusActive && leaseStatus != db.LeaseStatusMonthToMonth:
+		return http.StatusConflict, fmt.Sprintf("This lease is %s and can no longer be renewed", leaseStatus), true
+	}
+	return 0, "", false
+}
+
+func renewalOfferURL(token string) string {
+	return utils.GetAbsoluteUrl("/renewal-offers/" + token)
+}
+
+func toRenewalOfferResponse(o db.LeaseRenewalOffer) RenewalOfferResponse {
+	resp := RenewalOfferResponse{
+		ID:          o.ID,
+		LeaseID:     o.LeaseID,
+		Status:      string(o.Status),
+		NoticeStage: o.NoticeStage,
+		ExpiresAt:   o.ExpiresAt.Time.Format("2006-01-02 15:04:05"),
+		CreatedAt:   o.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+	}
+	if err := json.Unmarshal(o.Options, &resp.Options); err != nil {
+		log.Printf("[RENEWAL_OFFER] Offer %d has unreadable options: %v", o.ID, err)
+	}
+	if o.AcceptedOption.Valid {
+		resp.AcceptedOption = &o.AcceptedOption.Int32
+	}
+	if o.RenewedLeaseID.Valid {
+		resp.RenewedLeaseID = &o.RenewedLeaseID.Int64
+	}
+	if o.LastNotifiedAt.Valid {
+		resp.LastNotifiedAt = o.LastNotifiedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	if o.RespondedAt.Valid {
+		resp.RespondedAt = o.RespondedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+// sendRenewalOfferEmail emails the tenant the offer link. Later stages are worded as reminders and the
+// last one as a final notice.
+func sendRenewalOfferEmail(tenant db.GetUserByIDRow, token string, options []RenewalOption, leaseEnd time.Time, stage int) error {
+	endDate := leaseEnd.Format("January 2, 2006")
+	var subject string
+	switch {
+	case stage <= 0:
+		subject = "Your lease renewal offer"
+	case stage == len(renewalNoticeDays)-1:
+		subject = fmt.Sprintf("Final notice: your lease ends on %s", endDate)
+	default:
+		subject = fmt.Sprintf("Reminder: your lease ends on %s", endDate)
+	}
+
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("Hello %s %s,\n\n", tenant.FirstName, tenant.LastName))
+	body.WriteString(fmt.Sprintf("Your lease ends on %s. We would like you to stay and are offering the following renewal terms:\n\n", endDate))
+	for i, o := range options {
+		body.WriteString(fmt.Sprintf("  Option %d: %d months at $%.2f per month\n", i+1, o.TermMonths, o.RentAmount))
+	}
+	body.WriteString(fmt.Sprintf("\nReview the offer and accept or decline it here:\n%s\n", renewalOfferURL(token)))
+	if stage == len(renewalNoticeDays)-1 {
+		body.WriteString("\nThis is the last reminder before your lease ends. If we do not hear from you the offer will expire on your lease end date.\n")
+	}
+	body.WriteString("\nOnce you accept, you will receive a new lease to sign.\n")
+
+	return smtp.SendEmail(tenant.Email, subject, body.String())
+}
+
+// createRenewalOffer stores an offer for a lease and emails it to the tenant
+func (h *LeaseHandler) createRenewalOffer(ctx context.Context, leaseID int64, tenant db.GetUserByIDRow, leaseEnd time.Time, options []RenewalOption, createdBy pgtype.Int8) (db.LeaseRenewalOffer, error) {
+	token, err := newRenewalOfferToken()
+	if err != nil {
+		return db.LeaseRenewalOffer{}, fmt.Errorf("could not create offer token: %w", err)
+	}
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return db.LeaseRenewalOffer{}, err
+	}
+	stage := renewalNoticeStage(int(time.Until(leaseEnd).Hours() / 24))
+	if stage < 0 {
+		stage = 0
+	}
+
+	offer, err := h.queries.CreateLeaseRenewalOffer(ctx, db.CreateLeaseRenewalOfferParams{
+		LeaseID:     leaseID,
+		Token:       token,
+		Options:     raw,
+		NoticeStage: int32(stage),
+		CreatedBy:   createdBy,
+		ExpiresAt:   pgtype.Timestamp{Time: renewalOfferExpiry(leaseEnd), Valid: true},
+	})
+	if err != nil {
+		return offer, fmt.Errorf("could not save renewal offer for lease %d: %w", leaseID, err)
+	}
+	if err := sendRenewalOfferEmail(tenant, token, options, leaseEnd, stage); err != nil {
+		log.Printf("[RENEWAL_OFFER] Failed emailing offer %d to %s: %v", offer.ID, tenant.Email, err)
+	}
+	log.Printf("[RENEWAL_OFFER] Sent renewal offer %d for lease %d", offer.ID, leaseID)
+	return offer, nil
+}
+
+// advanceRenewalOffer is run for every active lease nearing its end date. It creates the offer the first
+// time and sends the next reminder when the lease crosses a notice threshold. It returns the offer state.
+func (h *LeaseHandler) advanceRenewalOffer(ctx context.Context, lease db.ListLeasesRow, tenant db.GetUserByIDRow, daysLeft int) string {
+	offer, err := h.queries.GetLatestLeaseRenewalOffer(ctx, lease.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		currentRent := utils.ConvertPgNumericToFloat(lease.RentAmount)
+		if full, err := h.queries.GetLeaseByID(ctx, lease.ID); err == nil {
+			currentRent = effectiveLeaseRent(full, full.LeaseEndDate.Time)
+		}
+		options := defaultRenewalOptions(currentRent)
+		if _, err := h.createRenewalOffer(ctx, lease.ID, tenant, lease.LeaseEndDate.Time, options, pgtype.Int8{}); err != nil {
+			log.Printf("[RENEWAL_OFFER] %v", err)
+			return "error"
+		}
+		return "offer_sent"
+	}
+	if err != nil {
+		log.Printf("[RENEWAL_OFFER] Failed loading offer for lease %d: %v", lease.ID, err)
+		return "error"
+	}
+	if offer.Status != db.RenewalOfferStatusPending {
+		return string(offer.Status)
+	}
+
+	stage := renewalNoticeStage(daysLeft)
+	if stage <= int(offer.NoticeStage) {
+		return string(offer.Status)
+	}
+
+	var options []RenewalOption
+	if err := json.Unmarshal(offer.Options, &options); err != nil {
+		log.Printf("[RENEWAL_OFFER] Offer %d has unreadable options: %v", offer.ID, err)
+	}
+	if err := sendRenewalOfferEmail(tenant, offer.Token, options, lease.LeaseEndDate.Time, stage); err != nil {
+		log.Printf("[RENEWAL_OFFER] Failed emailing reminder for offer %d: %v", offer.ID, err)
+		return string(offer.Status)
+	}
+	if err := h.queries.UpdateLeaseRenewalOfferNotice(ctx, db.UpdateLeaseRenewalOfferNoticeParams{
+		ID:          offer.ID,
+		NoticeStage: int32(stage),
+	}); err != nil {
+		log.Printf("[RENEWAL_OFFER] Failed recording reminder for offer %d: %v", offer.ID, err)
+	}
+
+	if stage == len(renewalNoticeDays)-1 {
+		if landlord, err := h.queries.GetUserByID(ctx, lease.LandlordID); err == nil {
+			subject := fmt.Sprintf("Renewal offer for lease %d is still unanswered", lease.ID)
+			body := fmt.Sprintf("%s %s has not responded to the renewal offer for lease %d, which ends on %s.\n",
+				tenant.FirstName, tenant.LastName, lease.ID, lease.LeaseEndDate.Time.Format("2006-01-02"))
+			if err := smtp.SendEmail(landlord.Email, subject, body); err != nil {
+				log.Printf("[RENEWAL_OFFER] Failed notifying landlord about offer %d: %v", offer.ID, err)
+			}
+		}
+	}
+	return "reminder_sent"
+}
+
+// expireRenewalOffers closes pending offers whose lease has already ended
+func (h *LeaseHandler) expireRenewalOffers(ctx context.Context, today time.Time) int {
+	pending, err := h.queries.ListPendingLeaseRenewalOffers(ctx)
+	if err != nil {
+		log.Printf("[RENEWAL_OFFER] Failed listing pending offers: %v", err)
+		return 0
+	}
+	expired := 0
+	for _, offer := range pending {
+		if !offer.LeaseEndDate.Time.Before(today) {
+			continue
+		}
+		if err := h.queries.UpdateLeaseRenewalOfferStatus(ctx, db.UpdateLeaseRenewalOfferStatusParams{
+			ID:     offer.ID,
+			Status: db.RenewalOfferStatusExpired,
+		}); err != nil {
+			log.Printf("[RENEWAL_OFFER] Failed expiring offer %d: %v", offer.ID, err)
+			continue
+		}
+		expired++
+	}
+	return expired
+}
+
+// ListRenewalOffers returns every renewal offer, newest first
+func (h *LeaseHandler) ListRenewalOffers(w http.ResponseWriter, r *http.Request) {
+	offers, err := h.queries.ListLeaseRenewalOffers(r.Context())
+	if err != nil {
+		log.Printf("[RENEWAL_OFFER] Failed listing offers: %v", err)
+		http.Error(w, "Failed to fetch renewal offers", http.StatusInternalServerError)
+		return
+	}
+	resp := make([]RenewalOfferResponse, 0, len(offers))
+	for _, offer := range offers {
+		resp = append(resp, toRenewalOfferResponse(offer))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[RENEWAL_OFFER] Error encoding response: %v", err)
+	}
+}
+
+// CreateRenewalOffer sends a renewal offer for an active lease with custom options, or the default
+// options when none are given. An open offer for the same lease is withdrawn first.
+func (h *LeaseHandler) CreateRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+
+	var req RenewalOfferRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid renewal offer request", http.StatusBadRequest)
+			return
+		}
+	}
+
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lease, err := h.queries.GetLeaseByID(ctx, leaseID)
+	if err != nil {
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
+	if lease.Status != db.LeaseStatusActive {
+		http.Error(w, "Only active leases can be offered a renewal", http.StatusBadRequest)
+		return
+	}
+	if len(req.Options) == 0 {
+		req.Options = defaultRenewalOptions(effectiveLeaseRent(lease, lease.LeaseEndDate.Time))
+	}
+	if err := validateRenewalOptions(req.Options); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tenant, err := h.queries.GetUserByID(ctx, lease.TenantID)
+	if err != nil {
+		http.Error(w, "Tenant not found", http.StatusInternalServerError)
+		return
+	}
+
+	if open, err := h.queries.GetLatestLeaseRenewalOffer(ctx, leaseID); err == nil && open.Status == db.RenewalOfferStatusPending {
+		if err := h.queries.UpdateLeaseRenewalOfferStatus(ctx, db.UpdateLeaseRenewalOfferStatusParams{
+			ID:     open.ID,
+			Status: db.RenewalOfferStatusWithdrawn,
+		}); err != nil {
+			log.Printf("[RENEWAL_OFFER] Failed withdrawing offer %d: %v", open.ID, err)
+			http.Error(w, "Failed to replace the open renewal offer", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	offer, err := h.createRenewalOffer(ctx, leaseID, tenant, lease.LeaseEndDate.Time, req.Options, pgtype.Int8{Int64: adminID, Valid: true})
+	if err != nil {
+		log.Printf("[RENEWAL_OFFER] %v", err)
+		http.Error(w, "Failed to create renewal offer", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toRenewalOfferResponse(offer)); err != nil {
+		log.Printf("[RENEWAL_OFFER] Error encoding response: %v", err)
+	}
+}
+
+// WithdrawRenewalOffer cancels an offer the tenant has not answered yet
+func (h *LeaseHandler) WithdrawRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.ParseInt(chi.URLParam(r, "offerID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid offer ID", http.StatusBadRequest)
+		return
+	}
+	offer, err := h.queries.RespondToLeaseRenewalOffer(r.Context(), db.RespondToLeaseRenewalOfferParams{
+		ID:     offerID,
+		Status: db.RenewalOfferStatusWithdrawn,
+	})
+	if err != nil {
+		http.Error(w, "No open renewal offer with that ID", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toRenewalOfferResponse(offer)); err != nil {
+		log.Printf("[RENEWAL_OFFER] Error encoding response: %v", err)
+	}
+}
+
+// GetRenewalOffer shows the offer behind the emailed link
+func (h *LeaseHandler) GetRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	offer, err := h.queries.GetLeaseRenewalOfferByToken(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		http.Error(w, "Renewal offer not found", http.StatusNotFound)
+		return
+	}
+	lease, err := h.queries.GetLeaseByID(r.Context(), offer.LeaseID)
+	if err != nil {
+		http.Error(w, "Renewal offer not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"offer":          toRenewalOfferResponse(offer),
+		"lease_end_date": lease.LeaseEndDate.Time.Format("2006-01-02"),
+		"current_rent":   effectiveLeaseRent(lease, time.Now()),
+	}); err != nil {
+		log.Printf("[RENEWAL_OFFER] Error encoding response: %v", err)
+	}
+}
+
+// AcceptRenewalOffer records the tenant's choice and generates the renewal lease through the same path as
+// an admin renewal. The new lease starts the day after the current one ends and is sent out for signing.
+func (h *LeaseHandler) AcceptRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req struct {
+		Option int `json:"option"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid acceptance request", http.StatusBadRequest)
+		return
+	}
+
+	offer, err := h.queries.GetLeaseRenewalOfferByToken(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		http.Error(w, "Renewal offer not found", http.StatusNotFound)
+		return
+	}
+	var options []RenewalOption
+	if err := json.Unmarshal(offer.Options, &options); err != nil || req.Option < 0 || req.Option >= len(options) {
+		http.Error(w, "Invalid renewal option", http.StatusBadRequest)
+		return
+	}
+	option := options[req.Option]
+
+	lease, err := h.queries.GetLeaseByID(ctx, offer.LeaseID)
+	if err != nil {
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
+	if status, message, closed := renewalOfferClosed(offer, lease.Status, time.Now().UTC()); closed {
+		http.Error(w, message, status)
+		return
+	}
+	landlord, err := h.queries.GetUserByID(ctx, lease.LandlordID)
+	if err != nil {
+		log.Printf("[RENEWAL_OFFER] Landlord %d of lease %d not found: %v", lease.LandlordID, lease.ID, err)
+		http.Error(w, "Failed to load lease landlord", http.StatusInternalServerError)
+		return
+	}
+	apartment, err := h.queries.GetApartment(ctx, lease.ApartmentID)
+	if err != nil {
+		http.Error(w, "Apartment not found", http.StatusInternalServerError)
+		return
+	}
+
+	// Claim the offer first so a double submit cannot create two leases. The claim checks the offer and
+	// lease again, in case either changed since they were loaded.
+	if _, err := h.queries.AcceptLeaseRenewalOffer(ctx, db.AcceptLeaseRenewalOfferParams{
+		ID:             offer.ID,
+		AcceptedOption: pgtype.Int4{Int32: int32(req.Option), Valid: true},
+	}); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("[RENEWAL_OFFER] Failed accepting offer %d: %v", offer.ID, err)
+			http.Error(w, "Failed to accept renewal offer", http.StatusInternalServerError)
+			return
+		}
+		if current, err := h.queries.GetLeaseRenewalOffer(ctx, offer.ID); err == nil {
+			offer = current
+		}
+		if current, err := h.queries.GetLeaseByID(ctx, offer.LeaseID); err == nil {
+			lease = current
+		}
+		status, message, closed := renewalOfferClosed(offer, lease.Status, time.Now().UTC())
+		if !closed {
+			status, message = http.StatusConflict, "This renewal offer is no longer open"
+		}
+		http.Error(w, message, status)
+		return
+	}
+
+	renewal := LeaseUpsertRequest{
+		TenantID:        lease.TenantID,
+		ApartmentID:     lease.ApartmentID,
+		RentAmount:      option.RentAmount,
+		Status:          string(db.LeaseStatusPendingApproval),
+		DocumentTitle:   fmt.Sprintf("Lease Renewal - Unit %d", apartment.UnitNumber.Int64),
+		CreatedBy:       landlord.ID,
+		UpdatedBy:       landlord.ID,
+		LeaseNumber:     lease.LeaseNumber + 1,
+		PreviousLeaseID: &lease.ID,
+		PropertyAddress: strconv.FormatInt(apartment.UnitNumber.Int64, 10),
+		AddendumIDs:     h.attachedAddendumIDs(ctx, lease.ID),
+	}
+	start := lease.LeaseEndDate.Time.AddDate(0, 0, 1)
+	renewal.StartDate = start.Format("2006-01-02")
+	renewal.EndDate = start.AddDate(0, option.TermMonths, -1).Format("2006-01-02")
+	if occupants, err := h.queries.ListLeaseTenants(ctx, lease.ID); err == nil {
+		for _, o := range occupants {
+			if !o.IsPrimary {
+				renewal.OccupantIDs = append(renewal.OccupantIDs, o.ID)
+			}
+		}
+	}
+	if guarantors, err := h.queries.ListLeaseGuarantors(ctx, lease.ID); err == nil {
+		for _, g := range guarantors {
+			renewal.Guarantors = append(renewal.Guarantors, LeaseGuarantorRequest{Name: g.Name, Email: g.Email, Relationship: g.Relationship})
+		}
+	}
+
+	renewedLeaseID := h.createLeaseVersion(w, r, renewal, LeaseParty{
+		UserID: landlord.ID,
+		Name:   fmt.Sprintf("%s %s", landlord.FirstName, landlord.LastName),
+		Email:  landlord.Email,
+	})
+	if renewedLeaseID == 0 {
+		// Let the tenant try again
+		if err := h.queries.UpdateLeaseRenewalOfferStatus(ctx, db.UpdateLeaseRenewalOfferStatusParams{
+			ID:     offer.ID,
+			Status: db.RenewalOfferStatusPending,
+		}); err != nil {
+			log.Printf("[RENEWAL_OFFER] Failed reopening offer %d: %v", offer.ID, err)
+		}
+		return
+	}
+
+	if err := h.queries.SetLeaseRenewalOfferRenewedLease(ctx, db.SetLeaseRenewalOfferRenewedLeaseParams{
+		ID:             offer.ID,
+		RenewedLeaseID: pgtype.Int8{Int64: renewedLeaseID, Valid: true},
+	}); err != nil {
+		log.Printf("[RENEWAL_OFFER] Failed linking offer %d to lease %d: %v", offer.ID, renewedLeaseID, err)
+	}
+	if err := h.queries.SetLeaseDocumentType(ctx, db.SetLeaseDocumentTypeParams{
+		ID:           renewedLeaseID,
+		DocumentType: db.TypeExtension,
+	}); err != nil {
+		log.Printf("[RENEWAL_OFFER] Failed marking lease %d as an extension: %v", renewedLeaseID, err)
+	}
+
+	// Send the new lease out for signing
+	if tenant, err := h.queries.GetUserByID(ctx, lease.TenantID); err == nil {
+		if renewed, err := h.queries.GetLeaseByID(ctx, renewedLeaseID); err == nil && renewed.TenantSigningUrl.Valid {
+			body := fmt.Sprintf("Hello %s,\n\nThank you for renewing. Your new lease for %d months at $%.2f per month is ready to sign:\n%s\n",
+				tenant.FirstName, option.TermMonths, option.RentAmount, renewed.TenantSigningUrl.String)
+			if err := smtp.SendEmail(tenant.Email, "Your renewal lease is ready to sign", body); err != nil {
+				log.Printf("[RENEWAL_OFFER] Failed emailing renewal lease %d: %v", renewedLeaseID, err)
+			}
+		}
+	}
+	log.Printf("[RENEWAL_OFFER] Offer %d accepted; renewal lease %d created", offer.ID, renewedLeaseID)
+}
+
+// DeclineRenewalOffer records that the tenant will not renew and tells the landlord
+func (h *LeaseHandler) DeclineRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	offer, err := h.queries.GetLeaseRenewalOfferByToken(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		http.Error(w, "Renewal offer not found", http.StatusNotFound)
+		return
+	}
+	offer, err = h.queries.RespondToLeaseRenewalOffer(ctx, db.RespondToLeaseRenewalOfferParams{
+		ID:     offer.ID,
+		Status: db.RenewalOfferStatusDeclined,
+	})
+	if err != nil {
+		http.Error(w, "This renewal offer is no longer open", http.StatusConflict)
+		return
+	}
+
+	if lease, err := h.queries.GetLeaseByID(ctx, offer.LeaseID); err == nil {
+		if landlord, err := h.queries.GetUserByID(ctx, lease.LandlordID); err == nil {
+			body := fmt.Sprintf("The tenant on lease %d declined the renewal offer. The lease ends on %s.\n",
+				lease.ID, lease.LeaseEndDate.Time.Format("2006-01-02"))
+			if err := smtp.SendEmail(landlord.Email, fmt.Sprintf("Renewal declined for lease %d", lease.ID), body); err != nil {
+				log.Printf("[RENEWAL_OFFER] Failed notifying landlord about declined offer %d: %v", offer.ID, err)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toRenewalOfferResponse(offer)); err != nil {
+		log.Printf("[RENEWAL_OFFER] Error encoding response: %v", err)
+	}
+}
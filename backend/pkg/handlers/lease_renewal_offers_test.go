@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// renewalOffer is an offer on lease 9 that expires the given time from now
+func renewalOffer(status db.RenewalOfferStatus, expiresIn time.Duration) db.LeaseRenewalOffer {
+	return db.LeaseRenewalOffer{
+		ID:        4,
+		LeaseID:   9,
+		Token:     "token",
+		Status:    status,
+		Options:   []byte(`[{"term_months":12,"rent_amount":1545}]`),
+		ExpiresAt: pgtype.Timestamp{Time: time.Now().UTC().Add(expiresIn), Valid: true},
+	}
+}
+
+func acceptRenewalOffer(t *testing.T, h *LeaseHandler) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.AcceptRenewalOffer(rec, newTestRequest(t, http.MethodPost, "/renewal-offers/token/accept", map[string]int{"option": 0}, "token", "token"))
+	return rec
+}
+
+func TestAcceptRenewalOfferRefusesClosedOffers(t *testing.T) {
+	terminated := activeLease()
+	terminated.Status = db.LeaseStatusTerminated
+	tests := []struct {
+		name  string
+		offer db.LeaseRenewalOffer
+		lease db.GetLeaseByIDRow
+		want  int
+	}{
+		{"expired offer", renewalOffer(db.RenewalOfferStatusPending, -time.Hour), activeLease(), http.StatusGone},
+		{"offer marked expired", renewalOffer(db.RenewalOfferStatusExpired, time.Hour), activeLease(), http.StatusGone},
+		{"declined offer", renewalOffer(db.RenewalOfferStatusDeclined, time.Hour), activeLease(), http.StatusConflict},
+		{"terminated lease", renewalOffer(db.RenewalOfferStatusPending, time.Hour), terminated, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fdb := newTestHandler(t)
+			fdb.returns("GetLeaseRenewalOfferByToken", tt.offer)
+			fdb.returns("GetLeaseByID", tt.lease)
+
+			rec := acceptRenewalOffer(t, h)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+			if calls := fdb.called("AcceptLeaseRenewalOffer"); len(calls) != 0 {
+				t.Errorf("closed offer claimed: %+v", calls)
+			}
+			if calls := fdb.called("RenewLease"); len(calls) != 0 {
+				t.Errorf("renewal lease created for a closed offer: %+v", calls)
+			}
+		})
+	}
+}
+
+func TestAcceptRenewalOfferThatExpiresWhileAccepting(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	signInAdmin(fdb)
+	fdb.returns("GetLeaseRenewalOfferByToken", renewalOffer(db.RenewalOfferStatusPending, time.Hour))
+	fdb.returns("GetLeaseByID", activeLease())
+	fdb.returns("GetApartment", db.GetApartmentRow{ID: 3})
+	// The claim finds nothing to update because the offer expired in the meantime
+	fdb.returns("GetLeaseRenewalOffer", renewalOffer(db.RenewalOfferStatusExpired, -time.Minute))
+
+	rec := acceptRenewalOffer(t, h)
+	if rec.Code != http.StatusGone {
+		t.Fatalf("status = %d, want 410: %s", rec.Code, rec.Body.String())
+	}
+	if calls := fdb.called("AcceptLeaseRenewalOffer"); len(calls) != 1 {
+		t.Errorf("AcceptLeaseRenewalOffer calls = %+v, want one claim", calls)
+	}
+	if calls := fdb.called("RenewLease"); len(calls) != 0 {
+		t.Errorf("renewal lease created for an expired offer: %+v", calls)
+	}
+}
+
+func TestRenewalOfferExpiry(t *testing.T) {
+	end := time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+	if got, want := renewalOfferExpiry(end), time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("renewalOfferExpiry(%s) = %s, want %s", end.Format("2006-01-02"), got, want)
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/leasestate"
+	"github.com/jackc/pgx/v5"
+)
+
+// leaseSaga tracks the remote side effects of creating a lease or notice. Each remote step that succeeds
+// registers how to undo itself; when a later step fails, compensate undoes them newest first so nothing is
+// left at the e-sign provider that the database does not know about.
+type leaseSaga struct {
+	name  string
+	steps []sagaCompensation
+}
+
+type sagaCompensation struct {
+	name string
+	undo func() error
+}
+
+func newLeaseSaga(name string) *leaseSaga {
+	return &leaseSaga{name: name}
+}
+
+// onFailure registers how to undo a step that has just succeeded
+func (s *leaseSaga) onFailure(name string, undo func() error) {
+	s.steps = append(s.steps, sagaCompensation{name: name, undo: undo})
+}
+
+// compensate undoes every registered step, newest first. A compensation that fails is logged and the rest
+// still run; the reconciliation job picks up whatever is left.
+func (s *leaseSaga) compensate(cause error) {
+	log.Printf("[SAGA] %s failed, undoing %d step(s): %v", s.name, len(s.steps), cause)
+	for i := len(s.steps) - 1; i >= 0; i-- {
+		step := s.steps[i]
+		if err := step.undo(); err != nil {
+			log.Printf("[SAGA] %s: failed to %s: %v", s.name, step.name, err)
+			continue
+		}
+		log.Printf("[SAGA] %s: %s", s.name, step.name)
+	}
+	s.steps = nil
+}
+
+// deleteDocumentOnFailure removes an uploaded document from the e-sign provider if the saga fails
+func (h *LeaseHandler) deleteDocumentOnFailure(saga *leaseSaga, docID string) {
+	saga.onFailure(fmt.Sprintf("delete document %s", docID), func() error {
+		return h.documenso_client.DeleteDocument(docID)
+	})
+}
+
+// txBeginner starts the transactions inTx runs in, a *pgxpool.Pool outside of tests
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// inTx runs fn with a copy of the handler whose queries all go through one transaction. The transaction
+// commits when fn returns nil and rolls back otherwise.
+func (h *LeaseHandler) inTx(ctx context.Context, fn func(tx *LeaseHandler) error) error {
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && err != pgx.ErrTxClosed {
+			log.Printf("[SAGA] Rollback failed: %v", err)
+		}
+	}()
+
+	txh := *h
+	txh.queries = h.queries.WithTx(tx)
+	if err := fn(&txh); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ReconcileLeaseDocuments repairs leases whose document at the e-sign provider no longer exists, like one
+// left behind by a compensation that failed or a document deleted by hand. An unsigned lease without its
+// document can never be signed and is canceled. A signed lease whose document is still there gets our own
+// copy of the signed PDF, so it no longer depends on the provider; one that lost both is reported.
+func (h *LeaseHandler) ReconcileLeaseDocuments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	leases, err := h.queries.ListLeasesForDocumentReconciliation(ctx)
+	if err != nil {
+		log.Printf("[LEASE_RECONCILE] Failed listing leases: %v", err)
+		http.Error(w, "Failed to fetch leases", http.StatusInternalServerError)
+		return
+	}
+
+	var canceled, stored, missing, failed int
+	for _, lease := range leases {
+		exists, err := h.documenso_client.VerifyDocumentExists(lease.ExternalDocID)
+		if err != nil {
+			log.Printf("[LEASE_RECONCILE] Failed checking document %s of lease %d: %v", lease.ExternalDocID, lease.ID, err)
+			failed++
+			continue
+		}
+		unsigned := leasestate.Initial(lease.Status)
+		switch {
+		case unsigned && exists:
+			continue
+		case unsigned:
+			if err := h.transitionLease(ctx, lease.ID, lease.Status, db.LeaseStatusCanceled, leasestate.EventDocumentMissing,
+				fmt.Sprintf("Document %s no longer exists at the e-sign provider", lease.ExternalDocID), 0); err != nil {
+				log.Printf("[LEASE_RECONCILE] Failed canceling lease %d: %v", lease.ID, err)
+				failed++
+				continue
+			}
+			canceled++
+		case exists:
+			if _, err := h.storeSignedLeasePDF(ctx, lease.ID, lease.ExternalDocID); err != nil {
+				log.Printf("[LEASE_RECONCILE] Failed storing signed copy of lease %d: %v", lease.ID, err)
+				failed++
+				continue
+			}
+			stored++
+		default:
+			log.Printf("[LEASE_RECONCILE] Signed lease %d lost document %s and has no stored copy", lease.ID, lease.ExternalDocID)
+			missing++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]int{
+		"checked":  len(leases),
+		"canceled": canceled,
+		"stored":   stored,
+		"missing":  missing,
+		"failed":   failed,
+	}); err != nil {
+		log.Printf("[LEASE_RECONCILE] Error encoding response: %v", err)
+	}
+}
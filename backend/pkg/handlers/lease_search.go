@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/leasestate"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultLeasePageSize = 50
+	maxLeasePageSize     = 200
+)
+
+// likeEscaper makes a search term match literally inside an ILIKE pattern, so % and _ typed by the admin
+// are not wildcards
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// leaseCursor is the position after the last lease of a page. Value holds the sort column of that lease
+// (a date or a rent amount), which is empty when sorting by creation.
+type leaseCursor struct {
+	Sort  string `json:"s"`
+	Dir   string `json:"d"`
+	Value string `json:"v,omitempty"`
+	ID    int64  `json:"id"`
+}
+
+func encodeLeaseCursor(c leaseCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeLeaseCursor(s string) (leaseCursor, error) {
+	var c leaseCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errors.New("invalid cursor")
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return c, errors.New("invalid cursor")
+	}
+	return c, nil
+}
+
+// leaseSearchParams turns the query string of GET /admin/leases into search parameters. It returns the
+// page size separately; the query is asked for one extra row to tell whether another page follows.
+func leaseSearchParams(query url.Values, today time.Time) (db.SearchLeasesParams, int, error) {
+	params := db.SearchLeasesParams{SortBy: "created", SortDir: "desc"}
+
+	if s := query.Get("sort"); s != "" {
+		switch s {
+		case "created", "start_date", "end_date", "rent":
+			params.SortBy = s
+		default:
+			return params, 0, fmt.Errorf("sort must be one of created, start_date, end_date or rent")
+		}
+	}
+	if d := query.Get("order"); d != "" {
+		if d != "asc" && d != "desc" {
+			return params, 0, fmt.Errorf("order must be asc or desc")
+		}
+		params.SortDir = d
+	}
+
+	limit := defaultLeasePageSize
+	if l := query.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxLeasePageSize {
+			return params, 0, fmt.Errorf("limit must be between 1 and %d", maxLeasePageSize)
+		}
+		limit = n
+	}
+	params.RowLimit = int32(limit + 1)
+
+	var err error
+	if s := query.Get("status"); s != "" {
+		// expires_soon is not stored; it is an active lease ending within the next 60 days
+		if s == leasestate.StatusExpiresSoon {
+			params.Status = db.NullLeaseStatus{LeaseStatus: db.LeaseStatusActive, Valid: true}
+			params.EndFrom = pgtype.Date{Time: today, Valid: true}
+			params.EndTo = pgtype.Date{Time: today.AddDate(0, 0, leasestate.ExpiresSoonDays), Valid: true}
+		} else {
+			status, err := parseLeaseStatus(s)
+			if err != nil {
+				return params, 0, err
+			}
+			params.Status = db.NullLeaseStatus{LeaseStatus: status, Valid: true}
+		}
+	}
+	if params.TenantID, err = queryInt8(query, "tenant_id"); err != nil {
+		return params, 0, err
+	}
+	if params.ApartmentID, err = queryInt8(query, "apartment_id"); err != nil {
+		return params, 0, err
+	}
+	if params.BuildingID, err = queryInt8(query, "building_id"); err != nil {
+		return params, 0, err
+	}
+	if t := query.Get("tenant"); t != "" {
+		params.TenantSearch = pgtype.Text{String: likeEscaper.Replace(t), Valid: true}
+	}
+
+	if params.StartFrom, err = queryDate(query, "start_from"); err != nil {
+		return params, 0, err
+	}
+	if params.StartTo, err = queryDate(query, "start_to"); err != nil {
+		return params, 0, err
+	}
+	endFrom, err := queryDate(query, "end_from")
+	if err != nil {
+		return params, 0, err
+	}
+	endTo, err := queryDate(query, "end_to")
+	if err != nil {
+		return params, 0, err
+	}
+	params.EndFrom = laterDate(params.EndFrom, endFrom)
+	params.EndTo = earlierDate(params.EndTo, endTo)
+	if d := query.Get("expires_within_days"); d != "" {
+		days, err := strconv.Atoi(d)
+		if err != nil || days < 0 {
+			return params, 0, fmt.Errorf("expires_within_days must be a non-negative number of days")
+		}
+		params.EndFrom = laterDate(params.EndFrom, pgtype.Date{Time: today, Valid: true})
+		params.EndTo = earlierDate(params.EndTo, pgtype.Date{Time: today.AddDate(0, 0, days), Valid: true})
+	}
+
+	if params.RentMin, err = queryAmount(query, "rent_min"); err != nil {
+		return params, 0, err
+	}
+	if params.RentMax, err = queryAmount(query, "rent_max"); err != nil {
+		return params, 0, err
+	}
+
+	if c := query.Get("cursor"); c != "" {
+		cursor, err := decodeLeaseCursor(c)
+		if err != nil {
+			return params, 0, err
+		}
+		if cursor.Sort != params.SortBy || cursor.Dir != params.SortDir {
+			return params, 0, errors.New("cursor was issued for a different sort order")
+		}
+		params.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		switch cursor.Sort {
+		case "start_date", "end_date":
+			date, err := time.Parse("2006-01-02", cursor.Value)
+			if err != nil {
+				return params, 0, errors.New("invalid cursor")
+			}
+			params.CursorDate = pgtype.Date{Time: date, Valid: true}
+		case "rent":
+			amount, err := strconv.ParseFloat(cursor.Value, 64)
+			if err != nil {
+				return params, 0, errors.New("invalid cursor")
+			}
+			params.CursorRent = utils.ConvertFloatToPgNumeric(amount)
+		}
+	}
+
+	return params, limit, nil
+}
+
+// nextLeaseCursor points after the given lease in the order the page was sorted by
+func nextLeaseCursor(params db.SearchLeasesParams, last db.SearchLeasesRow) string {
+	cursor := leaseCursor{Sort: params.SortBy, Dir: params.SortDir, ID: last.ID}
+	switch params.SortBy {
+	case "start_date":
+		cursor.Value = last.LeaseStartDate.Time.Format("2006-01-02")
+	case "end_date":
+		cursor.Value = last.LeaseEndDate.Time.Format("2006-01-02")
+	case "rent":
+		cursor.Value = strconv.FormatFloat(utils.ConvertPgNumericToFloat(last.RentAmount), 'f', 2, 64)
+	}
+	return encodeLeaseCursor(cursor)
+}
+
+func parseLeaseStatus(s string) (db.LeaseStatus, error) {
+	switch status := db.LeaseStatus(s); status {
+	case db.LeaseStatusDraft, db.LeaseStatusPendingApproval, db.LeaseStatusActive, db.LeaseStatusMonthToMonth,
+		db.LeaseStatusExpired, db.LeaseStatusTerminated, db.LeaseStatusRenewed, db.LeaseStatusSuperseded, db.LeaseStatusCanceled:
+		return status, nil
+	}
+	return "", fmt.Errorf("unknown lease status %q", s)
+}
+
+func queryInt8(query url.Values, name string) (pgtype.Int8, error) {
+	s := query.Get(name)
+	if s == "" {
+		return pgtype.Int8{}, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return pgtype.Int8{}, fmt.Errorf("invalid %s", name)
+	}
+	return pgtype.Int8{Int64: n, Valid: true}, nil
+}
+
+func queryDate(query url.Values, name string) (pgtype.Date, error) {
+	s := query.Get(name)
+	if s == "" {
+		return pgtype.Date{}, nil
+	}
+	date, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return pgtype.Date{}, fmt.Errorf("%s must be a date like 2006-01-02", name)
+	}
+	return pgtype.Date{Time: date, Valid: true}, nil
+}
+
+func queryAmount(query url.Values, name string) (pgtype.Numeric, error) {
+	s := query.Get(name)
+	if s == "" {
+		return pgtype.Numeric{}, nil
+	}
+	amount, err := strconv.ParseFloat(s, 64)
+	if err != nil || amount < 0 {
+		return pgtype.Numeric{}, fmt.Errorf("%s must be a non-negative amount", name)
+	}
+	return utils.ConvertFloatToPgNumeric(amount), nil
+}
+
+// laterDate and earlierDate narrow an optional date bound, treating an unset bound as open
+func laterDate(a, b pgtype.Date) pgtype.Date {
+	if !a.Valid || (b.Valid && b.Time.After(a.Time)) {
+		return b
+	}
+	return a
+}
+
+func earlierDate(a, b pgtype.Date) pgtype.Date {
+	if !a.Valid || (b.Valid && b.Time.Before(a.Time)) {
+		return b
+	}
+	return a
+}
+
+// nextLeasePageURL keeps the request's filters and swaps in the cursor of the following page
+func nextLeasePageURL(r *http.Request, cursor string) string {
+	query := r.URL.Query()
+	query.Set("cursor", cursor)
+	return r.URL.Path + "?" + query.Encode()
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var searchToday = time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func TestLeaseCursorRoundTrip(t *testing.T) {
+	want := leaseCursor{Sort: "rent", Dir: "asc", Value: "1500.00", ID: 42}
+	got, err := decodeLeaseCursor(encodeLeaseCursor(want))
+	if err != nil {
+		t.Fatalf("decodeLeaseCursor: %v", err)
+	}
+	if got != want {
+		t.Errorf("decoded cursor = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeLeaseCursorRejectsGarbage(t *testing.T) {
+	for _, cursor := range []string{"not base64!", encodeLeaseCursor(leaseCursor{Sort: "created", Dir: "desc"}), "bm90IGpzb24"} {
+		if _, err := decodeLeaseCursor(cursor); err == nil {
+			t.Errorf("decodeLeaseCursor(%q) succeeded, want an error", cursor)
+		}
+	}
+}
+
+func TestLeaseSearchParamsSortWhitelist(t *testing.T) {
+	for _, sort := range []string{"created", "start_date", "end_date", "rent"} {
+		params, _, err := leaseSearchParams(url.Values{"sort": {sort}, "order": {"asc"}}, searchToday)
+		if err != nil {
+			t.Errorf("sort=%s: %v", sort, err)
+			continue
+		}
+		if params.SortBy != sort || params.SortDir != "asc" {
+			t.Errorf("sort=%s: sorted by %s %s", sort, params.SortBy, params.SortDir)
+		}
+	}
+	for _, query := range []url.Values{
+		{"sort": {"tenant_id; DROP TABLE leases"}},
+		{"sort": {"l.id"}},
+		{"order": {"sideways"}},
+	} {
+		if _, _, err := leaseSearchParams(query, searchToday); err == nil {
+			t.Errorf("leaseSearchParams(%v) succeeded, want an error", query)
+		}
+	}
+}
+
+func TestLeaseSearchParamsContinuesAfterCursor(t *testing.T) {
+	last := db.SearchLeasesRow{ID: 7, RentAmount: utils.ConvertFloatToPgNumeric(1500), LeaseEndDate: pgtype.Date{Time: searchToday, Valid: true}}
+
+	params, _, err := leaseSearchParams(url.Values{"sort": {"rent"}, "order": {"asc"}}, searchToday)
+	if err != nil {
+		t.Fatalf("leaseSearchParams: %v", err)
+	}
+	next, _, err := leaseSearchParams(url.Values{"sort": {"rent"}, "order": {"asc"}, "cursor": {nextLeaseCursor(params, last)}}, searchToday)
+	if err != nil {
+		t.Fatalf("leaseSearchParams with cursor: %v", err)
+	}
+	if next.CursorID.Int64 != 7 || utils.ConvertPgNumericToFloat(next.CursorRent) != 1500 {
+		t.Errorf("cursor = id %d rent %v, want after lease 7 at 1500", next.CursorID.Int64, utils.ConvertPgNumericToFloat(next.CursorRent))
+	}
+
+	// A cursor only makes sense in the order it was issued for
+	if _, _, err := leaseSearchParams(url.Values{"sort": {"end_date"}, "order": {"asc"}, "cursor": {nextLeaseCursor(params, last)}}, searchToday); err == nil {
+		t.Error("cursor issued for rent order accepted when sorting by end date")
+	}
+}
+
+func TestLeaseSearchParamsEscapesTenantSearch(t *testing.T) {
+	params, _, err := leaseSearchParams(url.Values{"tenant": {`50%_off\`}}, searchToday)
+	if err != nil {
+		t.Fatalf("leaseSearchParams: %v", err)
+	}
+	if want := `50\%\_off\\`; params.TenantSearch.String != want {
+		t.Errorf("tenant search = %q, want %q", params.TenantSearch.String, want)
+	}
+}
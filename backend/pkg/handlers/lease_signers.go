@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/leasestate"
+	"github.com/careecodes/RentDaddy/internal/smtp"
+	"github.com/careecodes/RentDaddy/pkg/handlers/documenso"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// LeaseSignerResponse is the signing state of one recipient on a lease document
+type LeaseSignerResponse struct {
+	ID              int64  `json:"id"`
+	Role            string `json:"role"`
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+	Status          string `json:"status"`
+	SignedAt        string `json:"signed_at,omitempty"`
+	RejectionReason string `json:"rejection_reason,omitempty"`
+}
+
+func toLeaseSignerResponses(signers []db.LeaseSigner) []LeaseSignerResponse {
+	resp := make([]LeaseSignerResponse, 0, len(signers))
+	for _, s := range signers {
+		item := LeaseSignerResponse{
+			ID:              s.ID,
+			Role:            string(s.Role),
+			Name:            s.Name,
+			Email:           s.Email,
+			Status:          string(s.Status),
+			RejectionReason: s.RejectionReason.String,
+		}
+		if s.SignedAt.Valid {
+			item.SignedAt = s.SignedAt.Time.Format(time.RFC3339)
+		}
+		resp = append(resp, item)
+	}
+	return resp
+}
+
+// GetLeaseSigners returns who has signed, who is pending and who rejected a lease document
+func (h *LeaseHandler) GetLeaseSigners(w http.ResponseWriter, r *http.Request) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+
+	signers, err := h.queries.ListLeaseSigners(r.Context(), leaseID)
+	if err != nil {
+		log.Printf("[LEASE_SIGNERS] Failed listing signers for lease %d: %v", leaseID, err)
+		http.Error(w, "Failed to fetch lease signers", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toLeaseSignerResponses(signers)); err != nil {
+		log.Printf("[LEASE_SIGNERS] Error encoding response: %v", err)
+	}
+}
+
+// recordLeaseSigners stores every occupant, guarantor and the landlord as pending signers of a freshly
+// uploaded lease document. signingURLs is keyed by lowercase email.
+func (h *LeaseHandler) recordLeaseSigners(ctx context.Context, leaseID int64, occupants []LeaseParty, guarantors []LeaseGuarantorRequest, signingURLs map[string]string, landlordID int64, landlordName, landlordEmail string) error {
+	signers := make([]db.UpsertLeaseSignerParams, 0, len(occupants)+len(guarantors)+1)
+	for _, occupant := range occupants {
+		signingURL := signingURLs[strings.ToLower(occupant.Email)]
+		signers = append(signers, db.UpsertLeaseSignerParams{
+			LeaseID:    leaseID,
+			UserID:     pgtype.Int8{Int64: occupant.UserID, Valid: true},
+			Role:       db.LeaseSignerRoleTenant,
+			Name:       occupant.Name,
+			Email:      occupant.Email,
+			SigningUrl: pgtype.Text{String: signingURL, Valid: signingURL != ""},
+		})
+	}
+	for _, guarantor := range guarantors {
+		signingURL := signingURLs[strings.ToLower(guarantor.Email)]
+		signers = append(signers, db.UpsertLeaseSignerParams{
+			LeaseID:    leaseID,
+			Role:       db.LeaseSignerRoleGuarantor,
+			Name:       guarantor.Name,
+			Email:      guarantor.Email,
+			SigningUrl: pgtype.Text{String: signingURL, Valid: signingURL != ""},
+		})
+	}
+	landlordSigningURL := signingURLs[strings.ToLower(landlordEmail)]
+	signers = append(signers, db.UpsertLeaseSignerParams{
+		LeaseID:    leaseID,
+		UserID:     pgtype.Int8{Int64: landlordID, Valid: landlordID != 0},
+		Role:       db.LeaseSignerRoleLandlord,
+		Name:       landlordName,
+		Email:      landlordEmail,
+		SigningUrl: pgtype.Text{String: landlordSigningURL, Valid: landlordSigningURL != ""},
+	})
+	for _, signer := range signers {
+		if _, err := h.queries.UpsertLeaseSigner(ctx, signer); err != nil {
+			return fmt.Errorf("failed recording %s signer %s for lease %d: %w", signer.Role, signer.Email, leaseID, err)
+		}
+	}
+	return nil
+}
+
+// processDocumensoEvent applies a Documenso webhook event to the notice to vacate or lease that owns the document
+func (h *LeaseHandler) processDocumensoEvent(ctx context.Context, event documenso.WebhookEvent) error {
+	documentID := event.Payload.DocumentID()
+	if notice, err := h.queries.GetVacateNoticeByExternalDocID(ctx, pgtype.Text{String: documentID, Valid: true}); err == nil {
+		return h.processVacateNoticeEvent(ctx, notice, event)
+	}
+	lease, err := h.queries.GetLeaseByExternalDocID(ctx, documentID)
+	if err != nil {
+		return fmt.Errorf("no lease found for doc ID %s: %w", documentID, err)
+	}
+
+	switch event.Event {
+	case documenso.EventDocumentSent:
+		if lease.Status == db.LeaseStatusDraft {
+			return h.setLeaseStatusFromWebhook(ctx, lease, db.LeaseStatusPendingApproval, leasestate.EventSent, string(event.Event))
+		}
+	case documenso.EventDocumentOpened:
+		log.Printf("[WEBHOOK] Document %s for lease %d was opened", documentID, lease.ID)
+	case documenso.EventDocumentSigned:
+		if _, err := h.syncLeaseSigners(ctx, lease.ID, event.Payload.AllRecipients()); err != nil {
+			return err
+		}
+		if lease.Status == db.LeaseStatusDraft {
+			return h.setLeaseStatusFromWebhook(ctx, lease, db.LeaseStatusPendingApproval, leasestate.EventSent, string(event.Event))
+		}
+	case documenso.EventDocumentCompleted:
+		if _, err := h.syncLeaseSigners(ctx, lease.ID, event.Payload.AllRecipients()); err != nil {
+			return err
+		}
+		// Every occupant must have signed before the lease goes active
+		signers, err := h.queries.ListLeaseSigners(ctx, lease.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list signers for lease %d: %w", lease.ID, err)
+		}
+		for _, signer := range signers {
+			if signer.Status != db.SigningStatusSigned {
+				return fmt.Errorf("document %s completed but %s has not signed lease %d", documentID, signer.Email, lease.ID)
+			}
+		}
+		return h.activateSignedLease(ctx, lease, documentID)
+	case documenso.EventDocumentRejected:
+		rejections, err := h.syncLeaseSigners(ctx, lease.ID, event.Payload.AllRecipients())
+		if err != nil {
+			return err
+		}
+		if lease.Status == db.LeaseStatusDraft || lease.Status == db.LeaseStatusPendingApproval {
+			if err := h.setLeaseStatusFromWebhook(ctx, lease, db.LeaseStatusCanceled, leasestate.EventRejected, string(event.Event)); err != nil {
+				return err
+			}
+			// Only notify on the transition so a replayed event does not email twice
+			if err := h.notifyLeaseRejected(ctx, lease, rejections); err != nil {
+				log.Printf("[WEBHOOK] Failed to send rejection notification for lease %d: %v", lease.ID, err)
+			}
+			// The canceled lease no longer holds the apartment for an approved applicant
+			h.offerApartment(ctx, lease.ApartmentID)
+		}
+	case documenso.EventDocumentCancelled:
+		if lease.Status == db.LeaseStatusDraft || lease.Status == db.LeaseStatusPendingApproval {
+			if err := h.setLeaseStatusFromWebhook(ctx, lease, db.LeaseStatusCanceled, leasestate.EventSigningCancelled, string(event.Event)); err != nil {
+				return err
+			}
+			h.offerApartment(ctx, lease.ApartmentID)
+		}
+	default:
+		log.Printf("[WEBHOOK] Ignoring event %s for document %s", event.Event, documentID)
+	}
+	return nil
+}
+
+// syncLeaseSigners copies each recipient's signing status onto the lease signers.
+// It returns the recipients that rejected the document.
+func (h *LeaseHandler) syncLeaseSigners(ctx context.Context, leaseID int64, recipients []documenso.WebhookRecipient) ([]documenso.WebhookRecipient, error) {
+	var rejections []documenso.WebhookRecipient
+	for _, recipient := range recipients {
+		switch recipient.SigningStatus {
+		case documenso.SigningStatusSigned:
+			signedAt := time.Now().UTC()
+			if recipient.SignedAt != nil {
+				signedAt = recipient.SignedAt.UTC()
+			}
+			updated, err := h.queries.MarkLeaseSignerSigned(ctx, db.MarkLeaseSignerSignedParams{
+				LeaseID:  leaseID,
+				Email:    recipient.Email,
+				SignedAt: pgtype.Timestamp{Time: signedAt, Valid: true},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to mark %s signed on lease %d: %w", recipient.Email, leaseID, err)
+			}
+			if updated > 0 {
+				log.Printf("[WEBHOOK] %s signed lease %d", recipient.Email, leaseID)
+			}
+		case documenso.SigningStatusRejected:
+			rejections = append(rejections, recipient)
+			_, err := h.queries.MarkLeaseSignerRejected(ctx, db.MarkLeaseSignerRejectedParams{
+				LeaseID:         leaseID,
+				Email:           recipient.Email,
+				RejectionReason: pgtype.Text{String: recipient.RejectionReason, Valid: recipient.RejectionReason != ""},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to mark %s rejected on lease %d: %w", recipient.Email, leaseID, err)
+			}
+		}
+	}
+	return rejections, nil
+}
+
+func (h *LeaseHandler) setLeaseStatusFromWebhook(ctx context.Context, lease db.GetLeaseByExternalDocIDRow, status db.LeaseStatus, event leasestate.Event, webhookEvent string) error {
+	if err := h.transitionLease(ctx, lease.ID, lease.Status, status, event, "E-sign provider reported "+webhookEvent, lease.LandlordID); err != nil {
+		return err
+	}
+	log.Printf("[WEBHOOK] Lease %d marked as %s", lease.ID, status)
+	return nil
+}
+
+// activateSignedLease marks a fully signed lease active, takes the apartment off the market
+// and keeps our own copy of the signed document
+func (h *LeaseHandler) activateSignedLease(ctx context.Context, lease db.GetLeaseByExternalDocIDRow, documentID string) error {
+	// Try to find matching landlord by lease.LandlordID
+	landlord, err := h.queries.GetUserByID(ctx, lease.LandlordID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch landlord from lease ID %d: %w", lease.ID, err)
+	}
+
+	log.Printf("[WEBHOOK] Document %s signed, marking lease %d as active", documentID, lease.ID)
+
+	err = h.inTx(ctx, func(tx *LeaseHandler) error {
+		if err := tx.transitionLease(ctx, lease.ID, lease.Status, db.LeaseStatusActive, leasestate.EventSigned, "Signed by every signer", landlord.ID); err != nil {
+			return err
+		}
+		return tx.retireAmendedLease(ctx, lease.ID, landlord.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[WEBHOOK] Lease %d marked as active", lease.ID)
+
+	if lease.ApartmentID != 0 {
+		apartment, err := h.queries.GetApartment(ctx, lease.ApartmentID)
+		if err != nil {
+			return fmt.Errorf("failed to get apartment with ID %d: %w", lease.ApartmentID, err)
+		}
+
+		err = h.queries.UpdateApartment(ctx, db.UpdateApartmentParams{
+			ID:           apartment.ID,
+			Price:        apartment.Price,
+			ManagementID: apartment.ManagementID,
+			Availability: false,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update apartment availability: %w", err)
+		}
+
+		log.Printf("[WEBHOOK] Updated apartment ID %d to unavailable", apartment.ID)
+		h.withdrawApartmentOffer(ctx, apartment.ID)
+	}
+
+	if key, err := h.storeSignedLeasePDF(ctx, lease.ID, documentID); err != nil {
+		log.Printf("[WEBHOOK] Failed storing signed copy of lease %d: %v", lease.ID, err)
+	} else {
+		log.Printf("[WEBHOOK] Stored signed copy of lease %d at %s", lease.ID, key)
+	}
+
+	downloadURL, err := h.documenso_client.GetDocumentDownloadURL(documentID)
+	if err != nil {
+		log.Printf("[WEBHOOK] Failed to get signed document URL: %v", err)
+		return nil
+	}
+	unescapedURL := downloadURL
+	if decodedURL, decodeErr := url.QueryUnescape(downloadURL); decodeErr == nil {
+		unescapedURL = decodedURL
+	}
+	err = h.queries.UpdateSignedLeasePdfS3URL(ctx, db.UpdateSignedLeasePdfS3URLParams{
+		ID:         lease.ID,
+		LeasePdfS3: pgtype.Text{String: unescapedURL, Valid: true},
+	})
+	if err != nil {
+		log.Printf("[WEBHOOK] Failed to update signed document URL: %v", err)
+	} else {
+		log.Printf("[WEBHOOK] Updated lease %d with signed document URL %v", lease.ID, unescapedURL)
+	}
+	return nil
+}
+
+// retireAmendedLease ends the lease an amendment replaces once the amendment is in force. Until then the
+// original stays active, so an amendment that is never signed leaves the tenancy as it was.
+func (h *LeaseHandler) retireAmendedLease(ctx context.Context, amendedLeaseID, adminID int64) error {
+	amendment, err := h.queries.GetLeaseAmendmentByAmendedLease(ctx, amendedLeaseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up what lease %d amends: %w", amendedLeaseID, err)
+	}
+	original, err := h.queries.GetLeaseByID(ctx, amendment.LeaseID)
+	if err != nil {
+		return fmt.Errorf("failed to load lease %d amended by lease %d: %w", amendment.LeaseID, amendedLeaseID, err)
+	}
+	if leasestate.Final(original.Status) {
+		return nil
+	}
+	retired := db.LeaseStatusSuperseded
+	if leasestate.Initial(original.Status) {
+		retired = db.LeaseStatusCanceled
+	}
+	return h.transitionLease(ctx, original.ID, original.Status, retired, leasestate.EventAmended,
+		fmt.Sprintf("Superseded by signed amendment %d", amendedLeaseID), adminID)
+}
+
+// notifyLeaseRejected emails the administrator, or the lease's landlord when none is set, who rejected a
+// lease and why
+func (h *LeaseHandler) notifyLeaseRejected(ctx context.Context, lease db.GetLeaseByExternalDocIDRow, rejections []documenso.WebhookRecipient) error {
+	leaseID := lease.ID
+	adminEmail := os.Getenv("ADMIN_EMAIL")
+	if adminEmail == "" {
+		landlord, err := h.landlordOfLease(ctx, lease.ApartmentID, lease.LandlordID)
+		if err != nil {
+			return err
+		}
+		adminEmail = landlord.Signer.Email
+	}
+
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("Lease %d was rejected and has been canceled.\n\n", leaseID))
+	for _, r := range rejections {
+		reason := r.RejectionReason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		body.WriteString(fmt.Sprintf("- %s (%s): %s\n", r.Name, r.Email, reason))
+	}
+	if len(rejections) == 0 {
+		body.WriteString("Documenso did not report which recipient rejected the document.\n")
+	}
+	body.WriteString("\nPlease review the lease terms and create a new lease if needed.\n")
+
+	return smtp.SendEmail(adminEmail, fmt.Sprintf("Lease %d rejected", leaseID), body.String())
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/pkg/handlers/documenso"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func signingLease(status db.LeaseStatus) db.GetLeaseByExternalDocIDRow {
+	return db.GetLeaseByExternalDocIDRow{ID: 7, ExternalDocID: "42", LandlordID: testAdmin.ID, ApartmentID: 3, Status: status}
+}
+
+func signingEvent(event string, recipients ...documenso.WebhookRecipient) documenso.WebhookEvent {
+	return documenso.WebhookEvent{Event: event, Payload: documenso.WebhookDocument{ID: 42, Recipients: recipients}}
+}
+
+func leaseSigner(email string, status db.SigningStatus) db.LeaseSigner {
+	return db.LeaseSigner{LeaseID: 7, Role: db.LeaseSignerRoleTenant, Name: email, Email: email, Status: status}
+}
+
+func TestGetLeaseSigners(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	signedAt := time.Date(2025, time.March, 3, 14, 0, 0, 0, time.UTC)
+	signed := leaseSigner("tenant@example.com", db.SigningStatusSigned)
+	signed.SignedAt = pgtype.Timestamp{Time: signedAt, Valid: true}
+	rejected := leaseSigner("guarantor@example.com", db.SigningStatusRejected)
+	rejected.Role = db.LeaseSignerRoleGuarantor
+	rejected.RejectionReason = pgtype.Text{String: "Wrong rent", Valid: true}
+	fdb.returns("ListLeaseSigners", []db.LeaseSigner{signed, rejected})
+
+	rec := httptest.NewRecorder()
+	h.GetLeaseSigners(rec, newTestRequest(t, http.MethodGet, "/admin/leases/7/signers", nil, "leaseID", "7"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var got []LeaseSignerResponse
+	decodeResponse(t, rec, &got)
+	if len(got) != 2 {
+		t.Fatalf("got %d signers, want 2", len(got))
+	}
+	if got[0].Status != "signed" || got[0].SignedAt != signedAt.Format(time.RFC3339) {
+		t.Errorf("signed signer = %+v", got[0])
+	}
+	if got[1].Role != "guarantor" || got[1].Status != "rejected" || got[1].RejectionReason != "Wrong rent" {
+		t.Errorf("rejected signer = %+v", got[1])
+	}
+	if calls := fdb.called("ListLeaseSigners"); len(calls) != 1 || calls[0].Args[0] != int64(7) {
+		t.Errorf("ListLeaseSigners calls = %+v, want one for lease 7", calls)
+	}
+}
+
+func TestGetLeaseSignersRejectsBadLeaseID(t *testing.T) {
+	h, _ := newTestHandler(t)
+	rec := httptest.NewRecorder()
+	h.GetLeaseSigners(rec, newTestRequest(t, http.MethodGet, "/admin/leases/x/signers", nil, "leaseID", "x"))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestSignedEventRecordsEachSignature(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.returns("GetLeaseByExternalDocID", signingLease(db.LeaseStatusDraft))
+	fdb.returns("MarkLeaseSignerSigned", int64(1))
+	fdb.returns("TransitionLeaseStatus", db.LeaseStatusHistory{})
+
+	signedAt := time.Date(2025, time.March, 3, 14, 0, 0, 0, time.UTC)
+	err := h.processDocumensoEvent(context.Background(), signingEvent(documenso.EventDocumentSigned,
+		documenso.WebhookRecipient{Email: "tenant@example.com", SigningStatus: documenso.SigningStatusSigned, SignedAt: &signedAt},
+		documenso.WebhookRecipient{Email: "landlord@example.com", SigningStatus: "NOT_SIGNED"},
+	))
+	if err != nil {
+		t.Fatalf("processDocumensoEvent: %v", err)
+	}
+
+	marks := fdb.called("MarkLeaseSignerSigned")
+	if len(marks) != 1 || marks[0].Args[1] != "tenant@example.com" {
+		t.Fatalf("MarkLeaseSignerSigned calls = %+v, want only the tenant", marks)
+	}
+	if at := marks[0].Args[2].(pgtype.Timestamp); !at.Time.Equal(signedAt) {
+		t.Errorf("signed at %s, want the time the provider reported", at.Time)
+	}
+	transitions := fdb.called("TransitionLeaseStatus")
+	if len(transitions) != 1 || transitions[0].Args[0] != db.LeaseStatusPendingApproval {
+		t.Errorf("TransitionLeaseStatus calls = %+v, want the draft moved to pending_approval", transitions)
+	}
+}
+
+func TestCompletedEventWaitsForEverySigner(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.returns("GetLeaseByExternalDocID", signingLease(db.LeaseStatusPendingApproval))
+	fdb.returns("ListLeaseSigners", []db.LeaseSigner{
+		leaseSigner("tenant@example.com", db.SigningStatusSigned),
+		leaseSigner("roommate@example.com", db.SigningStatusPending),
+	})
+
+	err := h.processDocumensoEvent(context.Background(), signingEvent(documenso.EventDocumentCompleted,
+		documenso.WebhookRecipient{Email: "tenant@example.com", SigningStatus: documenso.SigningStatusSigned},
+	))
+	if err == nil {
+		t.Fatal("a completed event with an unsigned occupant should fail")
+	}
+	if calls := fdb.called("TransitionLeaseStatus"); len(calls) != 0 {
+		t.Errorf("lease changed status before every signer signed: %+v", calls)
+	}
+}
+
+func TestCompletedEventActivatesFullySignedLease(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.returns("GetLeaseByExternalDocID", signingLease(db.LeaseStatusPendingApproval))
+	fdb.returns("ListLeaseSigners", []db.LeaseSigner{
+		leaseSigner("tenant@example.com", db.SigningStatusSigned),
+		leaseSigner("landlord@example.com", db.SigningStatusSigned),
+	})
+	signInAdmin(fdb)
+	fdb.returns("TransitionLeaseStatus", db.LeaseStatusHistory{})
+	fdb.returns("GetApartment", db.GetApartmentRow{ID: 3, Availability: true})
+
+	err := h.processDocumensoEvent(context.Background(), signingEvent(documenso.EventDocumentCompleted,
+		documenso.WebhookRecipient{Email: "tenant@example.com", SigningStatus: documenso.SigningStatusSigned},
+		documenso.WebhookRecipient{Email: "landlord@example.com", SigningStatus: documenso.SigningStatusSigned},
+	))
+	if err != nil {
+		t.Fatalf("processDocumensoEvent: %v", err)
+	}
+
+	transitions := fdb.called("TransitionLeaseStatus")
+	if len(transitions) != 1 || transitions[0].Args[0] != db.LeaseStatusActive {
+		t.Fatalf("TransitionLeaseStatus calls = %+v, want the lease activated", transitions)
+	}
+	updates := fdb.called("UpdateApartment")
+	if len(updates) != 1 || updates[0].Args[3] != false {
+		t.Errorf("UpdateApartment calls = %+v, want the apartment taken off the market", updates)
+	}
+}
+
+func TestRejectedEventCancelsLease(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.returns("GetLeaseByExternalDocID", signingLease(db.LeaseStatusPendingApproval))
+	fdb.returns("TransitionLeaseStatus", db.LeaseStatusHistory{})
+	t.Setenv("ADMIN_EMAIL", "admin@example.com")
+
+	err := h.processDocumensoEvent(context.Background(), signingEvent(documenso.EventDocumentRejected,
+		documenso.WebhookRecipient{Email: "tenant@example.com", SigningStatus: documenso.SigningStatusRejected, RejectionReason: "Wrong rent"},
+	))
+	if err != nil {
+		t.Fatalf("processDocumensoEvent: %v", err)
+	}
+
+	rejections := fdb.called("MarkLeaseSignerRejected")
+	if len(rejections) != 1 || rejections[0].Args[2] != (pgtype.Text{String: "Wrong rent", Valid: true}) {
+		t.Errorf("MarkLeaseSignerRejected calls = %+v, want the tenant's reason", rejections)
+	}
+	transitions := fdb.called("TransitionLeaseStatus")
+	if len(transitions) != 1 || transitions[0].Args[0] != db.LeaseStatusCanceled {
+		t.Errorf("TransitionLeaseStatus calls = %+v, want the lease canceled", transitions)
+	}
+}
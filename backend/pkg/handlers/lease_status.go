@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/leasestate"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// errLeaseStatusChanged is returned when a lease left the expected status before a change could be applied
+var errLeaseStatusChanged = errors.New("lease status changed in the meantime")
+
+// LeaseStatusChangeResponse is one entry of a lease's status history
+type LeaseStatusChangeResponse struct {
+	ID            int64  `json:"id"`
+	FromStatus    string `json:"from_status,omitempty"`
+	ToStatus      string `json:"to_status"`
+	Event         string `json:"event"`
+	Reason        string `json:"reason,omitempty"`
+	ChangedBy     *int64 `json:"changed_by,omitempty"`
+	ChangedByName string `json:"changed_by_name,omitempty"`
+	ChangedAt     string `json:"changed_at"`
+}
+
+func toLeaseStatusChangeResponses(rows []db.ListLeaseStatusHistoryRow) []LeaseStatusChangeResponse {
+	resp := make([]LeaseStatusChangeResponse, 0, len(rows))
+	for _, row := range rows {
+		item := LeaseStatusChangeResponse{
+			ID:        row.ID,
+			ToStatus:  string(row.ToStatus),
+			Event:     row.Event,
+			Reason:    row.Reason.String,
+			ChangedAt: row.ChangedAt.Time.Format(time.RFC3339),
+		}
+		if row.FromStatus.Valid {
+			item.FromStatus = string(row.FromStatus.LeaseStatus)
+		}
+		if row.ChangedBy.Valid {
+			item.ChangedBy = &row.ChangedBy.Int64
+			item.ChangedByName = row.ChangedByFirstName.String + " " + row.ChangedByLastName.String
+		}
+		resp = append(resp, item)
+	}
+	return resp
+}
+
+// transitionLease moves a lease from one status to another if the lease lifecycle allows it, and records who
+// made the change and why. A lease already in the target status is left alone. adminID 0 means a
+// scheduled job made the change.
+func (h *LeaseHandler) transitionLease(ctx context.Context, leaseID int64, from, to db.LeaseStatus, event leasestate.Event, reason string, adminID int64) error {
+	if from == to {
+		return nil
+	}
+	if err := leasestate.Check(from, to); err != nil {
+		return fmt.Errorf("lease %d: %w", leaseID, err)
+	}
+	_, err := h.queries.TransitionLeaseStatus(ctx, db.TransitionLeaseStatusParams{
+		ToStatus:   to,
+		ChangedBy:  pgtype.Int8{Int64: adminID, Valid: adminID != 0},
+		LeaseID:    leaseID,
+		FromStatus: from,
+		Event:      string(event),
+		Reason:     pgtype.Text{String: reason, Valid: reason != ""},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("lease %d is no longer %s: %w", leaseID, from, errLeaseStatusChanged)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set lease %d to %s: %w", leaseID, to, err)
+	}
+	log.Printf("[LEASE_STATUS] Lease %d moved from %s to %s (%s)", leaseID, from, to, event)
+	return nil
+}
+
+// recordLeaseCreated starts the status history of a new lease
+func (h *LeaseHandler) recordLeaseCreated(ctx context.Context, leaseID int64, status db.LeaseStatus, adminID int64) error {
+	if err := h.queries.RecordLeaseCreated(ctx, db.RecordLeaseCreatedParams{
+		LeaseID:   leaseID,
+		ToStatus:  status,
+		ChangedBy: pgtype.Int8{Int64: adminID, Valid: adminID != 0},
+	}); err != nil {
+		return fmt.Errorf("failed recording creation of lease %d: %w", leaseID, err)
+	}
+	return nil
+}
+
+// leaseTransitionFailed writes the response for a status change that could not be made. Changes the lease
+// lifecycle forbids, or that lost a race with another change, are conflicts.
+func leaseTransitionFailed(w http.ResponseWriter, err error) {
+	if isLeaseTransitionConflict(err) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Error(w, "Failed to update lease status", http.StatusInternalServerError)
+}
+
+func isLeaseTransitionConflict(err error) bool {
+	var invalid *leasestate.ErrInvalidTransition
+	return errors.As(err, &invalid) || errors.Is(err, errLeaseStatusChanged)
+}
+
+// GetLeaseStatusHistory returns every status a lease has been in, oldest first, and where it can go next
+func (h *LeaseHandler) GetLeaseStatusHistory(w http.ResponseWriter, r *http.Request) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+	lease, err := h.queries.GetLeaseByID(r.Context(), leaseID)
+	if err != nil {
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
+	history, err := h.queries.ListLeaseStatusHistory(r.Context(), leaseID)
+	if err != nil {
+		log.Printf("[LEASE_STATUS] Failed listing status history of lease %d: %v", leaseID, err)
+		http.Error(w, "Failed to fetch lease status history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"lease_id":            leaseID,
+		"status":              lease.Status,
+		"allowed_transitions": leasestate.Next(lease.Status),
+		"history":             toLeaseStatusChangeResponses(history),
+	}); err != nil {
+		log.Printf("[LEASE_STATUS] Error encoding response: %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/rent"
+	"github.com/careecodes/RentDaddy/internal/templates"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type LeaseTemplateRequest struct {
+	Name    string                         `json:"name"`
+	Content templates.LeaseTemplateContent `json:"content"`
+}
+
+type LeaseTemplateResponse struct {
+	ID        int64                          `json:"id"`
+	Name      string                         `json:"name"`
+	Version   int32                          `json:"version"`
+	Content   templates.LeaseTemplateContent `json:"content"`
+	CreatedAt string                         `json:"created_at"`
+}
+
+func toLeaseTemplateResponse(t db.LeaseTemplate) LeaseTemplateResponse {
+	resp := LeaseTemplateResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Version:   t.Version,
+		CreatedAt: t.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+	}
+	if err := json.Unmarshal(t.Content, &resp.Content); err != nil {
+		log.Printf("[LEASE_TEMPLATES] Template %d has unreadable content: %v", t.ID, err)
+	}
+	return resp
+}
+
+// ListLeaseTemplates returns every version of every lease template, newest version first
+func (h *LeaseHandler) ListLeaseTemplates(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.queries.ListLeaseTemplates(r.Context())
+	if err != nil {
+		log.Printf("[LEASE_TEMPLATES] Failed listing templates: %v", err)
+		http.Error(w, "Failed to fetch lease templates", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]LeaseTemplateResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, toLeaseTemplateResponse(row))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"templates":    resp,
+		"placeholders": templates.LeasePlaceholders,
+	}); err != nil {
+		log.Printf("[LEASE_TEMPLATES] Error encoding response: %v", err)
+	}
+}
+
+// GetLeaseTemplate returns one template version
+func (h *LeaseHandler) GetLeaseTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := strconv.ParseInt(chi.URLParam(r, "templateID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	row, err := h.queries.GetLeaseTemplate(r.Context(), templateID)
+	if err != nil {
+		http.Error(w, "Lease template not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toLeaseTemplateResponse(row)); err != nil {
+		log.Printf("[LEASE_TEMPLATES] Error encoding response: %v", err)
+	}
+}
+
+// CreateLeaseTemplate saves a new version of a template. Existing versions are never changed,
+// so leases already generated keep pointing at the text they were signed with.
+func (h *LeaseHandler) CreateLeaseTemplate(w http.ResponseWriter, r *http.Request) {
+	var req LeaseTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid template request", http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "Template name is required", http.StatusBadRequest)
+		return
+	}
+	if err := req.Content.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	content, err := json.Marshal(req.Content)
+	if err != nil {
+		http.Error(w, "Invalid template content", http.StatusBadRequest)
+		return
+	}
+	row, err := h.createLeaseTemplateVersion(r.Context(), db.CreateLeaseTemplateVersionParams{
+		Name:      req.Name,
+		Content:   content,
+		CreatedBy: pgtype.Int8{Int64: adminID, Valid: true},
+	})
+	if err != nil {
+		log.Printf("[LEASE_TEMPLATES] Failed saving template %s: %v", req.Name, err)
+		http.Error(w, "Failed to save lease template", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[LEASE_TEMPLATES] Saved %s version %d", row.Name, row.Version)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toLeaseTemplateResponse(row)); err != nil {
+		log.Printf("[LEASE_TEMPLATES] Error encoding response: %v", err)
+	}
+}
+
+// PreviewLeaseTemplate renders unsaved template content with sample data so admins can check the layout
+func (h *LeaseHandler) PreviewLeaseTemplate(w http.ResponseWriter, r *http.Request) {
+	var req LeaseTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid template request", http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now().AddDate(0, 1, 0)
+	pdfData, _, err := templates.RenderLease(req.Content, templates.LeaseData{
+		Landlord:        templates.LeaseParty{Name: "Pat Manager"},
+		LandlordName:    "Example Property Management LLC",
+		LandlordAddress: "500 Example Avenue, Suite 100",
+		LandlordLicense: "PM-000000",
+		Tenants:         []templates.LeaseParty{{Name: "Jane Tenant"}, {Name: "John Tenant"}},
+		Guarantors:      []templates.LeaseGuarantor{{Name: "Pat Guarantor", Relationship: "parent"}},
+		PropertyAddress: "123 Example Street",
+		UnitNumber:      "2145",
+		RentAmount:      1500,
+		StartDate:       start,
+		EndDate:         start.AddDate(1, 0, -1),
+		BuildingRules:   "Quiet hours are 10pm to 7am.",
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="lease-preview.pdf"`)
+	if _, err := w.Write(pdfData); err != nil {
+		log.Printf("[LEASE_TEMPLATES] Error writing preview: %v", err)
+	}
+}
+
+// maxTemplateVersionAttempts bounds the retries when versions of one template are saved at the same time
+const maxTemplateVersionAttempts = 3
+
+// createLeaseTemplateVersion saves the next version of a template. Two saves at once pick the same version
+// number and one of them hits the unique (name, version) index, so that one is retried with the next number.
+func (h *LeaseHandler) createLeaseTemplateVersion(ctx context.Context, params db.CreateLeaseTemplateVersionParams) (db.LeaseTemplate, error) {
+	for attempt := 1; ; attempt++ {
+		row, err := h.queries.CreateLeaseTemplateVersion(ctx, params)
+		var pgErr *pgconn.PgError
+		if attempt < maxTemplateVersionAttempts && errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			log.Printf("[LEASE_TEMPLATES] Version of %s taken by a concurrent save, retrying", params.Name)
+			continue
+		}
+		return row, err
+	}
+}
+
+// resolveLeaseTemplate returns the pinned template version when templateID is set, otherwise the newest
+// version of the named template. The standard template is created from the built-in default the first
+// time it is needed.
+func (h *LeaseHandler) resolveLeaseTemplate(ctx context.Context, templateID int64, name string) (db.LeaseTemplate, templates.LeaseTemplateContent, error) {
+	var row db.LeaseTemplate
+	var err error
+	if templateID != 0 {
+		row, err = h.queries.GetLeaseTemplate(ctx, templateID)
+	} else {
+		if name == "" {
+			name = templates.DefaultLeaseTemplateName
+		}
+		row, err = h.queries.GetLatestLeaseTemplate(ctx, name)
+		if errors.Is(err, pgx.ErrNoRows) && name == templates.DefaultLeaseTemplateName {
+			content, _ := json.Marshal(templates.DefaultLeaseTemplate())
+			row, err = h.createLeaseTemplateVersion(ctx, db.CreateLeaseTemplateVersionParams{
+				Name:    name,
+				Content: content,
+			})
+		}
+	}
+	if err != nil {
+		return row, templates.LeaseTemplateContent{}, fmt.Errorf("lease template not found: %w", err)
+	}
+
+	content, err := templates.ParseLeaseTemplate(row.Content)
+	if err != nil {
+		return row, content, fmt.Errorf("lease template %s v%d: %w", row.Name, row.Version, err)
+	}
+	return row, content, nil
+}
+
+// leaseTemplateData collects the placeholder values for a lease, including the unit number and
+// building rules of the apartment and the attached addenda
+func (h *LeaseHandler) leaseTemplateData(ctx context.Context, title string, landlord leaseLandlord, occupants []LeaseParty, guarantors []LeaseGuarantorRequest,
+	addenda []db.LeaseAddenda, apartmentID int64, propertyAddress string, rentAmount float64, escalation rent.Schedule, startDate, endDate time.Time,
+) templates.LeaseData {
+	data := templates.LeaseData{
+		Title:           title,
+		Landlord:        templates.LeaseParty{Name: landlord.Signer.Name, Email: landlord.Signer.Email},
+		LandlordName:    landlord.LegalName,
+		LandlordAddress: landlord.Address,
+		LandlordLicense: landlord.LicenseNumber,
+		PropertyAddress: propertyAddress,
+		RentAmount:      rentAmount,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		Addenda:         toTemplateAddenda(addenda),
+		RentSchedule:    rent.Table(rentAmount, startDate, endDate, escalation),
+	}
+	proration := leaseProration(rentAmount, escalation, startDate, endDate, h.apartmentProrationMethod(ctx, apartmentID))
+	data.MoveIn, data.MoveOut = proration.MoveIn, proration.MoveOut
+	for _, occupant := range occupants {
+		data.Tenants = append(data.Tenants, templates.LeaseParty{Name: occupant.Name, Email: occupant.Email})
+	}
+	for _, g := range guarantors {
+		data.Guarantors = append(data.Guarantors, templates.LeaseGuarantor{Name: g.Name, Email: g.Email, Relationship: g.Relationship})
+	}
+
+	if apartmentID != 0 {
+		details, err := h.queries.GetApartmentLeaseDetails(ctx, apartmentID)
+		if err != nil {
+			log.Printf("[LEASE_TEMPLATES] Could not load unit details for apartment %d: %v", apartmentID, err)
+		} else {
+			if details.UnitNumber.Valid {
+				data.UnitNumber = strconv.FormatInt(details.UnitNumber.Int64, 10)
+			}
+			data.BuildingRules = details.BuildingRules.String
+		}
+	}
+	return data
+}
+
+// recordLeaseTemplate stores which template version a lease was generated from
+func (h *LeaseHandler) recordLeaseTemplate(ctx context.Context, leaseID int64, leaseTemplate db.LeaseTemplate) error {
+	if err := h.queries.SetLeaseTemplate(ctx, db.SetLeaseTemplateParams{
+		ID:              leaseID,
+		LeaseTemplateID: pgtype.Int8{Int64: leaseTemplate.ID, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("failed recording template %d on lease %d: %w", leaseTemplate.ID, leaseID, err)
+	}
+	return nil
+}
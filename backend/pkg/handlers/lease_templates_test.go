@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestCreateLeaseTemplateVersionRetriesTakenVersion(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	attempts := 0
+	fdb.on("CreateLeaseTemplateVersion", func([]any) (any, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, &pgconn.PgError{Code: "23505", ConstraintName: "lease_templates_name_version_unique"}
+		}
+		return db.LeaseTemplate{ID: 2, Name: "standard", Version: 3}, nil
+	})
+
+	row, err := h.createLeaseTemplateVersion(context.Background(), db.CreateLeaseTemplateVersionParams{Name: "standard"})
+	if err != nil {
+		t.Fatalf("createLeaseTemplateVersion: %v", err)
+	}
+	if attempts != 2 || row.Version != 3 {
+		t.Errorf("attempts = %d, version = %d; want the second attempt's version 3", attempts, row.Version)
+	}
+}
+
+func TestCreateLeaseTemplateVersionGivesUp(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.fails("CreateLeaseTemplateVersion", &pgconn.PgError{Code: "23505"})
+
+	if _, err := h.createLeaseTemplateVersion(context.Background(), db.CreateLeaseTemplateVersionParams{Name: "standard"}); err == nil {
+		t.Fatal("want an error once every attempt collided")
+	}
+	if calls := fdb.called("CreateLeaseTemplateVersion"); len(calls) != maxTemplateVersionAttempts {
+		t.Errorf("tried %d times, want %d", len(calls), maxTemplateVersionAttempts)
+	}
+}
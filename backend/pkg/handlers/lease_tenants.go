@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// LeaseParty is a person who signs a lease document
+type LeaseParty struct {
+	UserID int64  `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+}
+
+// LeaseOccupantResponse is one adult on a lease
+type LeaseOccupantResponse struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+func toLeaseOccupantResponses(tenants []db.ListLeaseTenantsRow) []LeaseOccupantResponse {
+	resp := make([]LeaseOccupantResponse, 0, len(tenants))
+	for _, t := range tenants {
+		resp = append(resp, LeaseOccupantResponse{
+			ID:        t.ID,
+			Name:      fmt.Sprintf("%s %s", t.FirstName, t.LastName),
+			Email:     t.Email,
+			IsPrimary: t.IsPrimary,
+		})
+	}
+	return resp
+}
+
+// loadLeaseOccupants returns the primary tenant followed by every additional occupant, read from
+// the database so names and emails match what the e-sign provider will see. Duplicates are dropped.
+func (h *LeaseHandler) loadLeaseOccupants(ctx context.Context, primary db.GetUserByIDRow, occupantIDs []int64) ([]LeaseParty, error) {
+	occupants := []LeaseParty{{
+		UserID: primary.ID,
+		Name:   fmt.Sprintf("%s %s", primary.FirstName, primary.LastName),
+		Email:  primary.Email,
+	}}
+	seen := map[int64]bool{primary.ID: true}
+
+	for _, id := range occupantIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		user, err := h.queries.GetUserByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("occupant %d not found: %w", id, err)
+		}
+		if user.Role != db.RoleTenant {
+			return nil, fmt.Errorf("occupant %d is not a tenant", id)
+		}
+		occupants = append(occupants, LeaseParty{
+			UserID: user.ID,
+			Name:   fmt.Sprintf("%s %s", user.FirstName, user.LastName),
+			Email:  user.Email,
+		})
+	}
+	return occupants, nil
+}
+
+// findOccupantConflict returns the first occupant who is already on an active lease overlapping the given term.
+// The lease being amended or renewed, if any, is not a conflict since the new one replaces it.
+func (h *LeaseHandler) findOccupantConflict(ctx context.Context, occupants []LeaseParty, replacedLeaseID *int64, startDate, endDate time.Time) (LeaseParty, int64, bool) {
+	for _, occupant := range occupants {
+		conflict, err := h.queries.GetConflictingOccupantLease(ctx, db.GetConflictingOccupantLeaseParams{
+			TenantID:        occupant.UserID,
+			LeaseStartDate:  pgtype.Date{Time: startDate, Valid: true},
+			LeaseEndDate:    pgtype.Date{Time: endDate, Valid: true},
+			ExcludedLeaseID: pgtype.Int8{Int64: derefOrZero(replacedLeaseID), Valid: replacedLeaseID != nil},
+		})
+		if err == nil && conflict.ID != 0 {
+			return occupant, conflict.ID, true
+		}
+	}
+	return LeaseParty{}, 0, false
+}
+
+// recordLeaseTenants links every occupant to the lease. The first occupant is the primary tenant.
+func (h *LeaseHandler) recordLeaseTenants(ctx context.Context, leaseID int64, occupants []LeaseParty) error {
+	for i, occupant := range occupants {
+		if err := h.queries.AddLeaseTenant(ctx, db.AddLeaseTenantParams{
+			LeaseID:   leaseID,
+			TenantID:  occupant.UserID,
+			IsPrimary: i == 0,
+		}); err != nil {
+			return fmt.Errorf("failed adding tenant %d to lease %d: %w", occupant.UserID, leaseID, err)
+		}
+	}
+	return nil
+}
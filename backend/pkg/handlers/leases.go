@@ -2,7 +2,6 @@ package handlers
 
 // PLEASE USE THIS TO GET LANDLORD EMAIL, ALSO CHECK FOR LANDLORD NAME AND ID extractEmailFromContext
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -18,12 +17,18 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/jung-kurt/gofpdf"
 
 	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/leasestate"
+	"github.com/careecodes/RentDaddy/internal/rent"
+	"github.com/careecodes/RentDaddy/internal/screening"
 	"github.com/careecodes/RentDaddy/internal/smtp"
+	"github.com/careecodes/RentDaddy/internal/storage"
+	"github.com/careecodes/RentDaddy/internal/templates"
+	"github.com/careecodes/RentDaddy/internal/utils"
 	"github.com/careecodes/RentDaddy/middleware"
 
 	"github.com/go-chi/chi/v5"
@@ -50,12 +55,14 @@ var tempDir = os.Getenv("TEMP_DIR")
 
 // LeaseHandler encapsulates dependencies for lease-related handlers
 type LeaseHandler struct {
-	pool             *pgxpool.Pool
+	pool             txBeginner
 	queries          *db.Queries
-	documenso_client *documenso.DocumensoClient
-	landlordID       int64
-	landlordName     string
-	landlordEmail    string
+	documenso_client documenso.DocumensoClientInterface
+	documents        storage.Store
+	documentURLs     *storage.URLSigner
+	screening        screening.Provider
+	// screeningOptional lets approvals go ahead without a screening; set only by SCREENING_PROVIDER=none
+	screeningOptional bool
 }
 
 // LeaseWithSignersRequest represents the request for creating a lease with signers
@@ -69,6 +76,22 @@ type LeaseWithSignersRequest struct {
 	TenantName  string `json:"tenant_name"`
 	TenantEmail string `json:"tenant_email"`
 
+	// Additional adults on the lease; each one signs the document
+	OccupantIDs []int64 `json:"occupant_ids,omitempty"`
+
+	// Guarantors sign a guaranty addendum and are stored against the lease
+	Guarantors []LeaseGuarantorRequest `json:"guarantors,omitempty"`
+
+	// Lease template to render; template_id pins a version, otherwise the newest version of template_name is used
+	TemplateID   int64  `json:"template_id,omitempty"`
+	TemplateName string `json:"template_name,omitempty"`
+
+	// Library addenda appended to the lease, in page order
+	AddendumIDs []int64 `json:"addendum_ids,omitempty"`
+
+	// Rent step-ups during the lease, printed as a rent table
+	RentEscalation *rent.Schedule `json:"rent_escalation,omitempty"`
+
 	// Property information
 	PropertyAddress string  `json:"property_address"`
 	RentAmount      float64 `json:"rent_amount"`
@@ -97,23 +120,34 @@ type LeaseValidationResult struct {
 }
 
 type LeaseUpsertRequest struct {
-	TenantID        int64   `json:"tenant_id"`
-	LandlordID      int64   `json:"landlord_id,omitempty"` // Optional for admin, will be set by middleware
-	ApartmentID     int64   `json:"apartment_id"`
-	StartDate       string  `json:"start_date"`
-	EndDate         string  `json:"end_date"`
-	RentAmount      float64 `json:"rent_amount"`
-	Status          string  `json:"lease_status"`
-	ExternalDocID   string  `json:"external_doc_id,omitempty"`
-	DocumentTitle   string  `json:"document_title"`
-	CreatedBy       int64   `json:"created_by,omitempty"` // Will be set by middleware
-	UpdatedBy       int64   `json:"updated_by,omitempty"` // Will be set by middleware
-	LeaseNumber     int64   `json:"lease_number"`
-	PreviousLeaseID *int64  `json:"previous_lease_id,omitempty"`
-	ReplaceExisting bool    `json:"replace_existing,omitempty"`
-	TenantName      string  `json:"tenant_name"`
-	TenantEmail     string  `json:"tenant_email"`
-	PropertyAddress string  `json:"property_address"`
+	TenantID               int64                   `json:"tenant_id"`
+	LandlordID             int64                   `json:"landlord_id,omitempty"` // Optional for admin, will be set by middleware
+	ApartmentID            int64                   `json:"apartment_id"`
+	StartDate              string                  `json:"start_date"`
+	EndDate                string                  `json:"end_date"`
+	RentAmount             float64                 `json:"rent_amount"`
+	Status                 string                  `json:"lease_status"`
+	ExternalDocID          string                  `json:"external_doc_id,omitempty"`
+	DocumentTitle          string                  `json:"document_title"`
+	CreatedBy              int64                   `json:"created_by,omitempty"` // Will be set by middleware
+	UpdatedBy              int64                   `json:"updated_by,omitempty"` // Will be set by middleware
+	LeaseNumber            int64                   `json:"lease_number"`
+	PreviousLeaseID        *int64                  `json:"previous_lease_id,omitempty"`
+	ReplaceExisting        bool                    `json:"replace_existing,omitempty"`
+	TenantName             string                  `json:"tenant_name"`
+	TenantEmail            string                  `json:"tenant_email"`
+	PropertyAddress        string                  `json:"property_address"`
+	OccupantIDs            []int64                 `json:"occupant_ids,omitempty"` // Additional adults on the lease besides tenant_id
+	Guarantors             []LeaseGuarantorRequest `json:"guarantors,omitempty"`
+	TemplateID             int64                   `json:"template_id,omitempty"`              // Pins a lease template version
+	TemplateName           string                  `json:"template_name,omitempty"`            // Newest version of this template when no ID is given
+	AddendumIDs            []int64                 `json:"addendum_ids,omitempty"`             // Library addenda appended in page order; amendments keep the current ones when omitted
+	RentEscalation         *rent.Schedule          `json:"rent_escalation,omitempty"`          // Rent step-ups; amendments and renewals keep the current schedule when omitted
+	Deposit                *DepositRequest         `json:"deposit,omitempty"`                  // Security deposit collected for the lease
+	NoticePeriodDays       *int32                  `json:"notice_period_days,omitempty"`       // Days of notice needed to move out; 30 when omitted
+	EarlyTerminationFee    *float64                `json:"early_termination_fee,omitempty"`    // Owed when the tenant moves out before the end date
+	HoldoverPolicy         *string                 `json:"holdover_policy,omitempty"`          // Overrides the building's policy for staying past the end date
+	HoldoverPremiumPercent *float64                `json:"holdover_premium_percent,omitempty"` // Added to the last rent while month-to-month
 }
 
 // Helper for Create Lease Request Struct
@@ -166,37 +200,87 @@ func NewLeaseHandler(pool *pgxpool.Pool, queries *db.Queries) *LeaseHandler {
 		log.Printf("Documenso API Key: not set")
 	}
 
-	// Use safer masking for webhook secret
+	// Never log any part of the webhook secret
 	if webhookSecret != "" {
-		log.Printf("Documenso Webhook Secret: %s", documenso.MaskSecret(webhookSecret))
+		log.Printf("Documenso Webhook Secret: configured")
 	} else {
 		log.Printf("Documenso Webhook Secret: not set")
 	}
 
-	// Default fallback values
-	currentLandlordID := int64(100) // Default to the original hardcoded value
-	currentLandlordName := "First Landlord"
-
-	// Use SMTP_FROM as the landlord email if available
-	currentLandlordEmail := os.Getenv("SMTP_FROM")
-	if currentLandlordEmail == "" {
-		currentLandlordEmail = "ezra@gitfor.ge" // Standard fallback value
-	}
-
 	if tempDir == "" {
 		tempDir = "/app/temp" // Default fallback
 	}
 
+	screeningProvider, screeningOptional := newScreeningProvider()
+
 	return &LeaseHandler{
-		pool:             pool,
-		queries:          queries,
-		documenso_client: documenso.NewDocumensoClient(baseURL, apiKey),
-		landlordID:       currentLandlordID,
-		landlordName:     currentLandlordName,
-		landlordEmail:    currentLandlordEmail,
+		pool:              pool,
+		queries:           queries,
+		documenso_client:  newSigningProvider(baseURL, apiKey, webhookSecret),
+		documents:         newDocumentStore(),
+		documentURLs:      newDocumentURLSigner(),
+		screening:         screeningProvider,
+		screeningOptional: screeningOptional,
 	}
 }
 
+// newSigningProvider picks the e-signature provider from ESIGN_PROVIDER. "local" runs the
+// in-process signer (development and tests), anything else uses Documenso.
+func newSigningProvider(baseURL, apiKey, webhookSecret string) documenso.DocumensoClientInterface {
+	if os.Getenv("ESIGN_PROVIDER") != "local" {
+		return documenso.NewDocumensoClient(baseURL, apiKey)
+	}
+
+	dir := os.Getenv("LOCAL_SIGNER_DIR")
+	if dir == "" {
+		dir = filepath.Join(tempDir, "local-signer")
+	}
+	publicURL := os.Getenv("LOCAL_SIGNER_PUBLIC_URL")
+	if publicURL == "" {
+		publicURL = utils.GetAbsoluteUrl("/esign/local")
+	}
+	webhookURL := os.Getenv("LOCAL_SIGNER_WEBHOOK_URL")
+	if webhookURL == "" {
+		webhookURL = utils.GetAbsoluteUrl("/webhooks/documenso")
+	}
+
+	signer, err := documenso.NewLocalSigner(dir, publicURL, webhookURL, webhookSecret)
+	if err != nil {
+		// Falling back to Documenso would send real documents out for signing when offline signing was asked for
+		log.Fatalf("[LEASE_HANDLER] ESIGN_PROVIDER is local but the local signer could not start: %v", err)
+	}
+	return signer
+}
+
+// usingLocalSigner reports whether leases are signed with the in-process signer
+func (h *LeaseHandler) usingLocalSigner() bool {
+	_, ok := h.documenso_client.(*documenso.LocalSigner)
+	return ok
+}
+
+// LocalSignerHandler returns the signing pages of the local signer, or nil when Documenso is in use
+func (h *LeaseHandler) LocalSignerHandler() http.Handler {
+	if signer, ok := h.documenso_client.(*documenso.LocalSigner); ok {
+		return signer.Handler()
+	}
+	return nil
+}
+
+// refreshDocumensoClient swaps in a Documenso client using the given API key.
+// It is a no-op when the local signer is in use.
+func (h *LeaseHandler) refreshDocumensoClient(apiKey string) {
+	if h.usingLocalSigner() {
+		return
+	}
+
+	baseURL := os.Getenv("DOCUMENSO_API_URL")
+	if baseURL == "" {
+		baseURL = "http://documenso:3000"
+		log.Printf("[DOCUMENSO_CONFIG] No API URL in environment, using default: %s", baseURL)
+	}
+	h.documenso_client = documenso.NewDocumensoClient(baseURL, apiKey)
+}
+
 func (h *LeaseHandler) AmendLease(w http.ResponseWriter, r *http.Request) {
 	var req LeaseUpsertRequest
 	log.Printf("[LEASE_AMEND] Incoming payload: %+v", req)
@@ -293,8 +377,32 @@ func (h *LeaseHandler) AmendLease(w http.ResponseWriter, r *http.Request) {
 	req.UpdatedBy = int64(landlordID)
 	req.ReplaceExisting = true
 	req.Status = "draft" // All amendments start as draft
+	if req.AddendumIDs == nil {
+		req.AddendumIDs = h.attachedAddendumIDs(ctx, existingLease.ID)
+	}
+	if req.RentEscalation == nil {
+		req.RentEscalation = h.storedRentEscalation(ctx, existingLease.ID)
+	}
+	h.inheritTerminationTerms(ctx, existingLease.ID, &req)
+	h.inheritHoldoverPolicy(ctx, existingLease.ID, &req)
+
+	// Snapshot the current terms so the amendment can record what changed
+	before, err := h.loadLeaseTerms(ctx, existingLease.ID)
+	if err != nil {
+		log.Printf("[LEASE_AMEND] %v", err)
+		http.Error(w, "Failed to load lease for amendment", http.StatusInternalServerError)
+		return
+	}
 
-	// Conditionally delete old Documenso document only on apartment change
+	// Upsert new lease record with landlord context
+	amendedLeaseID := h.handleLeaseUpsertWithContext(w, r, req)
+	if amendedLeaseID == 0 {
+		// The upsert already wrote the error; keep the original lease and its document as they were
+		return
+	}
+	h.recordLeaseAmendment(ctx, before, amendedLeaseID, landlordID)
+
+	// Conditionally delete old Documenso document only on apartment change, once the amendment is saved
 	if isApartmentChange && existingLease.ExternalDocID != "" {
 		log.Printf("[LEASE_AMEND] Deleting previous Documenso document ID %s", existingLease.ExternalDocID)
 		if err := h.documenso_client.DeleteDocument(existingLease.ExternalDocID); err != nil {
@@ -305,24 +413,11 @@ func (h *LeaseHandler) AmendLease(w http.ResponseWriter, r *http.Request) {
 		log.Printf("[LEASE_AMEND] Skipping Documenso deletion (no apartment change or no external doc ID)")
 	}
 
-	// Upsert new lease record with landlord context
-	h.handleLeaseUpsertWithContext(w, r, req)
-	landlordID, _, _, err = h.GetLandlordInfo(r)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	// Cancel the original lease after amending (if not already terminated, expired, or canceled)
-	if existingLease.Status != db.LeaseStatusTerminated &&
-		existingLease.Status != db.LeaseStatusExpired &&
-		existingLease.Status != db.LeaseStatusCanceled {
-
-		_, err := h.queries.UpdateLeaseStatus(ctx, db.UpdateLeaseStatusParams{
-			ID:        existingLease.ID,
-			Status:    db.LeaseStatusCanceled,
-			UpdatedBy: int64(landlordID),
-		})
+	// A draft original is canceled now; one in force stays active until the amendment is signed and
+	// activateSignedLease retires it
+	if leasestate.Initial(existingLease.Status) {
+		err := h.transitionLease(ctx, existingLease.ID, existingLease.Status, db.LeaseStatusCanceled,
+			leasestate.EventAmended, fmt.Sprintf("Replaced by amended lease %d", amendedLeaseID), int64(landlordID))
 		if err != nil {
 			log.Printf("[LEASE_AMEND] Failed to cancel original lease ID %d: %v", existingLease.ID, err)
 		} else {
@@ -340,6 +435,17 @@ func (h *LeaseHandler) TerminateLease(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx := r.Context()
 
+	// The body is optional; it carries the deductions from the security deposit
+	var req TerminateLeaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid termination request", http.StatusBadRequest)
+		return
+	}
+	if err := validateDepositDeductions(req.Deductions); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// No need to get landlordID from context - it's passed as parameter
 	landlordID, _, _, err := h.GetLandlordInfo(r)
 	if err != nil {
@@ -347,53 +453,135 @@ func (h *LeaseHandler) TerminateLease(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	terminatedLease, err := h.queries.TerminateLease(ctx, db.TerminateLeaseParams{
-		UpdatedBy: int64(landlordID),
-		ID:        int64(leaseID),
-	})
+	now := time.Now()
+	moveOut := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	termination, err := h.terminateLease(ctx, int64(leaseID), moveOut, 0, landlordID, leasestate.EventTerminated, req.Reason)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		log.Printf("[LEASE_TERMINATE] Failed to terminate lease %d: %v", leaseID, err)
-		http.Error(w, "Could not terminate lease", http.StatusInternalServerError)
+		leaseTransitionFailed(w, err)
 		return
 	}
-
 	log.Printf("[LEASE_TERMINATE] Lease %d manually terminated by admin %d", leaseID, landlordID)
-	err = h.queries.UpdateApartment(ctx, db.UpdateApartmentParams{
-		ID:           terminatedLease.ApartmentID,
-		ManagementID: terminatedLease.LandlordID,
-		Availability: true,
-	})
-	if err != nil {
-		log.Printf("[LEASE_TERMINATE] Failed to update apartment availability: %v", err)
-		return
+	// Terminating right away overrides any notice still running
+	if notice, err := h.queries.GetOpenVacateNotice(ctx, int64(leaseID)); err == nil {
+		if _, err := h.cancelVacateNotice(ctx, notice.ID); err != nil {
+			log.Printf("[LEASE_TERMINATE] Failed cancelling notice %d: %v", notice.ID, err)
+		}
 	}
+	// The admin has itemized the deductions already, so the statement goes out with the termination
+	deposit := h.settleDeposit(ctx, int64(leaseID), moveOut, req.Deductions, landlordID)
 
-	log.Printf("[LEASE_TERMINATE] Updated apartment ID %d to unavailable", terminatedLease.ApartmentID)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
 		"message":                        "Lease terminated successfully",
 		string(db.LeaseStatusTerminated): true,
-		"lease_id":                       terminatedLease.ID,
-		"status":                         terminatedLease.Status,
+		"lease_id":                       termination.Lease.ID,
+		"status":                         termination.Lease.Status,
+		"final_month_proration":          termination.FinalMonth,
+		"deposit_disposition":            deposit,
 	}); err != nil {
 		log.Printf("Error encoding response: %v", err)
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
 
-func (h *LeaseHandler) handleLeaseUpsertWithContext(w http.ResponseWriter, r *http.Request, req LeaseUpsertRequest) {
+// leaseTermination is what was settled when a lease ended
+type leaseTermination struct {
+	Lease      db.GetLeaseByIDRow
+	FinalMonth *rent.Proration
+	FeeCharged float64
+}
+
+// terminateLease ends a lease on moveOut and puts the apartment back on the market. It bills only the days
+// of the final month the tenant had the unit and any early termination fee. The deposit is left to the
+// caller, which either settles it or holds it until the unit is inspected.
+func (h *LeaseHandler) terminateLease(ctx context.Context, leaseID int64, moveOut time.Time, fee float64, adminID int64, event leasestate.Event, reason string) (leaseTermination, error) {
+	terminatedLease, err := h.queries.GetLeaseByID(ctx, leaseID)
+	if err != nil {
+		return leaseTermination{}, err
+	}
+	// Checked up front so terminating twice is refused rather than settling the lease again
+	if err := leasestate.Check(terminatedLease.Status, db.LeaseStatusTerminated); err != nil {
+		return leaseTermination{}, fmt.Errorf("lease %d: %w", leaseID, err)
+	}
+	if err := h.transitionLease(ctx, leaseID, terminatedLease.Status, db.LeaseStatusTerminated, event, reason, adminID); err != nil {
+		return leaseTermination{}, err
+	}
+	terminatedLease.Status = db.LeaseStatusTerminated
+	termination := leaseTermination{Lease: terminatedLease}
+
+	// The apartment is loaded first so its price and manager survive the update
+	if apartment, err := h.queries.GetApartment(ctx, terminatedLease.ApartmentID); err != nil {
+		log.Printf("[LEASE_TERMINATE] Failed to get apartment with ID %d: %v", terminatedLease.ApartmentID, err)
+	} else if err := h.queries.UpdateApartment(ctx, db.UpdateApartmentParams{
+		ID:           apartment.ID,
+		Price:        apartment.Price,
+		ManagementID: apartment.ManagementID,
+		Availability: true,
+	}); err != nil {
+		log.Printf("[LEASE_TERMINATE] Failed to update apartment availability: %v", err)
+	} else {
+		log.Printf("[LEASE_TERMINATE] Updated apartment ID %d to available", terminatedLease.ApartmentID)
+		// Let the next prospect on the waitlist know
+		h.offerApartment(ctx, terminatedLease.ApartmentID)
+	}
+
+	termination.FinalMonth = h.settleTerminationMonth(ctx, leaseID, moveOut, adminID)
+	if fee > 0 {
+		if _, err := h.queries.CreateLedgerEntry(ctx, db.CreateLedgerEntryParams{
+			LeaseID:     leaseID,
+			TenantID:    terminatedLease.TenantID,
+			EntryType:   db.LedgerEntryTypeCharge,
+			Amount:      utils.ConvertFloatToPgNumeric(fee),
+			Description: "Early termination fee",
+			DueDate:     pgtype.Date{Time: moveOut, Valid: true},
+			EntryDate:   pgtype.Date{Time: moveOut, Valid: true},
+			CreatedBy:   pgtype.Int8{Int64: adminID, Valid: adminID != 0},
+		}); err != nil {
+			log.Printf("[LEASE_TERMINATE] Failed charging early termination fee on lease %d: %v", leaseID, err)
+		} else {
+			termination.FeeCharged = fee
+		}
+	}
+	return termination, nil
+}
+
+func (h *LeaseHandler) handleLeaseUpsertWithContext(w http.ResponseWriter, r *http.Request, req LeaseUpsertRequest) int64 {
 	// Validate admin user from the context
 	log.Print("Validating admin user...")
 
 	landlordID, landlordName, landlordEmail, err := h.GetLandlordInfo(r)
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
+		return 0
 	}
 
 	// Use the admin user from database as the landlord
 	log.Print("Using admin user from database as landlord...")
+	return h.createLeaseVersion(w, r, req, LeaseParty{UserID: landlordID, Name: landlordName, Email: landlordEmail})
+}
+
+// createLeaseVersion generates, uploads and stores a lease with the landlord of the apartment's building and
+// writes the response. fallback signs for apartments without a landlord. It returns the new lease ID, or 0
+// after writing an error.
+func (h *LeaseHandler) createLeaseVersion(w http.ResponseWriter, r *http.Request, req LeaseUpsertRequest, fallback LeaseParty) int64 {
+	if !leasestate.Initial(db.LeaseStatus(req.Status)) {
+		http.Error(w, "A new lease must start as draft or pending_approval", http.StatusBadRequest)
+		return 0
+	}
+
+	landlord, err := h.apartmentLandlord(r.Context(), req.ApartmentID, fallback)
+	if err != nil {
+		log.Printf("[LEASE_UPSERT] %v", err)
+		http.Error(w, "Failed to load the landlord of the apartment", http.StatusInternalServerError)
+		return 0
+	}
+	landlordID, landlordName, landlordEmail := landlord.UserID, landlord.Signer.Name, landlord.Signer.Email
 
 	// Get the tenant's actual email from the database to ensure consistency
 	// ALWAYS use DB as source of truth for tenant email - CRITICAL for Documenso integration
@@ -401,7 +589,7 @@ func (h *LeaseHandler) handleLeaseUpsertWithContext(w http.ResponseWriter, r *ht
 	if err != nil {
 		log.Printf("[LEASE_UPSERT] Error fetching tenant info from database: %v", err)
 		http.Error(w, "Failed to fetch tenant information", http.StatusInternalServerError)
-		return
+		return 0
 	}
 	
 	// Use the tenant's email from the database for consistency
@@ -421,6 +609,44 @@ func (h *LeaseHandler) handleLeaseUpsertWithContext(w http.ResponseWriter, r *ht
 	// Use the database email regardless of what was provided in the request
 	req.TenantEmail = tenantEmail
 
+	occupants, err := h.loadLeaseOccupants(r.Context(), tenant, req.OccupantIDs)
+	if err != nil {
+		log.Printf("[LEASE_UPSERT] Invalid occupants: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0
+	}
+	if err := validateLeaseGuarantors(req.Guarantors, occupants, landlordEmail); err != nil {
+		log.Printf("[LEASE_UPSERT] Invalid guarantors: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0
+	}
+	addenda, err := h.loadLeaseAddenda(r.Context(), req.AddendumIDs)
+	if err != nil {
+		log.Printf("[LEASE_UPSERT] Invalid addenda: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0
+	}
+	escalation, err := leaseRentEscalation(req.RentEscalation)
+	if err != nil {
+		log.Printf("[LEASE_UPSERT] Invalid rent escalation: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0
+	}
+	if req.Deposit != nil {
+		if _, err := validateDeposit(*req.Deposit); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return 0
+		}
+	}
+	if err := validateTerminationTerms(req.NoticePeriodDays, req.EarlyTerminationFee); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0
+	}
+	if err := validateHoldoverPolicy(HoldoverPolicyRequest{Policy: req.HoldoverPolicy, PremiumPercent: req.HoldoverPremiumPercent}); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0
+	}
+
 	log.Println("[LEASE_UPSERT] Starting lease upsert handler")
 
 	// Parse and validate dates
@@ -429,27 +655,21 @@ func (h *LeaseHandler) handleLeaseUpsertWithContext(w http.ResponseWriter, r *ht
 	if err != nil {
 		log.Printf("[LEASE_UPSERT] Invalid start date format: %v", err)
 		http.Error(w, "Invalid start date", http.StatusBadRequest)
-		return
+		return 0
 	}
 
 	endDate, err := time.Parse("2006-01-02", req.EndDate)
 	if err != nil {
 		log.Printf("[LEASE_UPSERT] Invalid end date format: %v", err)
 		http.Error(w, "Invalid end date", http.StatusBadRequest)
-		return
+		return 0
 	}
 
-	// Check for conflicting leases
-	conflict, err := h.queries.GetConflictingActiveLease(r.Context(), db.GetConflictingActiveLeaseParams{
-		TenantID:       req.TenantID,
-		LeaseStartDate: pgtype.Date{Time: startDate, Valid: true},
-		LeaseEndDate:   pgtype.Date{Time: endDate, Valid: true},
-	})
-
-	if err == nil && conflict.ID != 0 {
-		log.Printf("Tenant %d already has an active lease during the requested period", req.TenantID)
-		http.Error(w, "Tenant already has an active lease during this period", http.StatusConflict)
-		return
+	// Check for conflicting leases for every occupant
+	if occupant, conflictID, found := h.findOccupantConflict(r.Context(), occupants, req.PreviousLeaseID, startDate, endDate); found {
+		log.Printf("Tenant %d already has an active lease %d during the requested period", occupant.UserID, conflictID)
+		http.Error(w, fmt.Sprintf("Tenant %s already has an active lease during this period", occupant.Name), http.StatusConflict)
+		return 0
 	}
 
 	// Check for duplicate leases
@@ -461,49 +681,68 @@ func (h *LeaseHandler) handleLeaseUpsertWithContext(w http.ResponseWriter, r *ht
 	})
 
 	// If a duplicate is found, provide a more detailed error
+	var replaced *db.GetDuplicateLeaseRow
+	replacedStatus := db.LeaseStatusTerminated
 	if err == nil && existing.ID != 0 {
 		log.Printf("[LEASE_UPSERT] Duplicate lease ID %d already exists", existing.ID)
 		if req.ReplaceExisting {
-			// Terminate the existing lease instead of "archiving" it
-			_, err = h.queries.TerminateLease(r.Context(), db.TerminateLeaseParams{
-				UpdatedBy: req.CreatedBy,
-				ID:        existing.ID,
-			})
-			if err != nil {
-				log.Printf("[LEASE_UPSERT] Failed to terminate existing lease: %v", err)
-				http.Error(w, "Failed to replace existing lease", http.StatusInternalServerError)
-				return
+			// Terminate the existing lease instead of "archiving" it; one that was never signed is canceled.
+			// It is only changed together with saving the new lease, once the upload has worked.
+			if existing.Status == db.LeaseStatusDraft || existing.Status == db.LeaseStatusPendingApproval {
+				replacedStatus = db.LeaseStatusCanceled
+			}
+			if err := leasestate.Check(existing.Status, replacedStatus); err != nil {
+				log.Printf("[LEASE_UPSERT] Cannot replace existing lease %d: %v", existing.ID, err)
+				leaseTransitionFailed(w, err)
+				return 0
 			}
+			replaced = &existing
 		} else {
 			log.Printf("[LEASE_UPSERT] Duplicate lease exists for tenant %d, apartment %d with status %s",
 				req.TenantID, req.ApartmentID, req.Status)
 			http.Error(w, fmt.Sprintf("A lease already exists with ID: %d. Set replace_existing=true to override.", existing.ID), http.StatusConflict)
-			return
+			return 0
 		}
 	}
 
+	leaseTemplate, templateContent, err := h.resolveLeaseTemplate(r.Context(), req.TemplateID, req.TemplateName)
+	if err != nil {
+		log.Printf("[LEASE_UPSERT] %v", err)
+		http.Error(w, "Lease template not found or invalid", http.StatusBadRequest)
+		return 0
+	}
+
 	// Generate the lease PDF using the landlord and tenant info from database
-	pdfData, err := h.GenerateComprehensiveLeaseAgreement(
+	pdfData, signatureFields, err := h.GenerateComprehensiveLeaseAgreement(templateContent, h.leaseTemplateData(
+		r.Context(),
 		req.DocumentTitle,
-		landlordName,
-		tenantName, // Use tenant name from database for consistency
+		landlord,
+		occupants, // Use tenant names from database for consistency
+		req.Guarantors,
+		addenda,
+		req.ApartmentID,
 		req.PropertyAddress,
 		req.RentAmount,
+		escalation,
 		startDate,
 		endDate,
-	)
+	))
 	if err != nil {
 		log.Printf("[LEASE_UPSERT] Error generating lease PDF: %v", err)
 		http.Error(w, "Failed to generate lease PDF", http.StatusInternalServerError)
-		return
+		return 0
 	}
 
 	log.Printf("[LEASE_UPSERT] Generated PDF for %s (%s)", tenantName, req.PropertyAddress)
 
-	// Upload to Documenso and populate fields
+	// Upload to Documenso and populate fields. Anything that fails from here on deletes the uploaded
+	// document again, and the database is only written once, in a single transaction.
 	log.Println("[LEASE_UPSERT] Uploading lease PDF to Documenso")
-	docID, _, landlordSigningURL, tenantSigningURL, s3bucket, err := h.handleDocumensoUploadAndSetup(
+	saga := newLeaseSaga("lease creation")
+	docID, signingURLs, s3bucket, err := h.handleDocumensoUploadAndSetup(
+		saga,
 		pdfData,
+		signatureFields,
 		LeaseWithSignersRequest{
 			TenantName:      tenantName, // Use tenant name from database
 			TenantEmail:     tenantEmail, // Use tenant email from database
@@ -512,15 +751,20 @@ func (h *LeaseHandler) handleLeaseUpsertWithContext(w http.ResponseWriter, r *ht
 			StartDate:       startDate.Format("2006-01-02"),
 			EndDate:         endDate.Format("2006-01-02"),
 			DocumentTitle:   req.DocumentTitle,
+			Guarantors:      req.Guarantors,
 		},
+		occupants,
 		landlordName,
 		landlordEmail,
 	)
 	if err != nil {
 		log.Printf("[LEASE_UPSERT] Documenso upload error: %v", err)
+		saga.compensate(err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return 0
 	}
+	tenantSigningURL := signingURLs[strings.ToLower(tenantEmail)]
+	landlordSigningURL := signingURLs[strings.ToLower(landlordEmail)]
 
 	log.Printf("[LEASE_UPSERT] Documenso Document ID: %s", docID)
 	log.Printf("[LEASE_UPSERT] Signing URL: %s", docID)
@@ -557,12 +801,58 @@ func (h *LeaseHandler) handleLeaseUpsertWithContext(w http.ResponseWriter, r *ht
 	}
 
 	log.Printf(" [LEASE_UPSERT] Status: %v ", leaseParams.Status)
-	row, err := h.queries.RenewLease(r.Context(), leaseParams)
+	var row db.RenewLeaseRow
+	err = h.inTx(r.Context(), func(tx *LeaseHandler) error {
+		if replaced != nil {
+			if err := tx.transitionLease(r.Context(), replaced.ID, replaced.Status, replacedStatus,
+				leasestate.EventReplaced, "Replaced by a new lease for the same tenant and unit", req.CreatedBy); err != nil {
+				return err
+			}
+		}
+		var err error
+		if row, err = tx.queries.RenewLease(r.Context(), leaseParams); err != nil {
+			return fmt.Errorf("failed to insert lease: %w", err)
+		}
+		if err := tx.recordLeaseCreated(r.Context(), row.ID, leaseParams.Status, req.CreatedBy); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseTenants(r.Context(), row.ID, occupants); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseTemplate(r.Context(), row.ID, leaseTemplate); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseAddenda(r.Context(), row.ID, addenda); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseRentEscalation(r.Context(), row.ID, escalation); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseDeposit(r.Context(), row.ID, req.Deposit, req.CreatedBy); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseTerminationTerms(r.Context(), row.ID, req.NoticePeriodDays, req.EarlyTerminationFee); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseHoldoverPolicy(r.Context(), row.ID, HoldoverPolicyRequest{Policy: req.HoldoverPolicy, PremiumPercent: req.HoldoverPremiumPercent}); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseGuarantors(r.Context(), row.ID, req.Guarantors); err != nil {
+			return err
+		}
+		return tx.recordLeaseSigners(r.Context(), row.ID, occupants, req.Guarantors, signingURLs, landlord.Signer.UserID, landlordName, landlordEmail)
+	})
 	if err != nil {
 		log.Printf("[LEASE_UPSERT] Database insert error: %v", err)
+		saga.compensate(err)
+		if isLeaseTransitionConflict(err) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return 0
+		}
 		http.Error(w, "Failed to save lease", http.StatusInternalServerError)
-		return
+		return 0
 	}
+	h.recordGeneratedLeasePDF(r.Context(), row.ID, pdfData)
 
 	// Respond to client with success
 	log.Printf("[LEASE_UPSERT] Lease created/renewed successfully with ID: %d", row.ID)
@@ -572,6 +862,7 @@ func (h *LeaseHandler) handleLeaseUpsertWithContext(w http.ResponseWriter, r *ht
 		"external_doc_id": docID,
 		"sign_url":        h.documenso_client.GetSigningURL(docID),
 		"status":          req.Status,
+		"proration":       leaseProration(req.RentAmount, escalation, startDate, endDate, h.apartmentProrationMethod(r.Context(), req.ApartmentID)),
 		"message":         "Lease created/renewed successfully with signing url.",
 	}
 
@@ -580,10 +871,13 @@ func (h *LeaseHandler) handleLeaseUpsertWithContext(w http.ResponseWriter, r *ht
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		log.Printf("Error encoding response: %v", err)
-		return
 	}
+	return row.ID
 }
 
+// GetLeases lists leases for the admin. It filters by status, tenant_id, tenant (name or email), apartment_id,
+// building_id, start_from/start_to, end_from/end_to, rent_min/rent_max and expires_within_days, sorts by
+// sort (created, start_date, end_date, rent) and order, and pages with limit and the cursor from the Link header.
 func (h *LeaseHandler) GetLeases(w http.ResponseWriter, r *http.Request) {
 	log.Printf("Retrieving leases...")
 
@@ -596,7 +890,7 @@ func (h *LeaseHandler) GetLeases(w http.ResponseWriter, r *http.Request) {
 	apiKeyConfigured := (err == nil && apiKeyConfig.Value != "")
 	webhookConfigured := (err2 == nil && webhookConfig.Value != "")
 
-	if !apiKeyConfigured || !webhookConfigured {
+	if (!apiKeyConfigured || !webhookConfigured) && !h.usingLocalSigner() {
 		// Documenso not configured, return an error with instructions
 		log.Printf("[LEASES] Documenso not configured (API key: %v, Webhook: %v), rejecting lease page access",
 			apiKeyConfigured, webhookConfigured)
@@ -617,33 +911,28 @@ func (h *LeaseHandler) GetLeases(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// If we get here, Documenso is configured, proceed as normal
-	leases, err := h.queries.ListLeases(ctx)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	params, limit, err := leaseSearchParams(r.URL.Query(), today)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	leases, err := h.queries.SearchLeases(ctx, params)
 	if err != nil {
 		log.Printf("Error retrieving leases: %v", err)
 		http.Error(w, "Failed to fetch leases", http.StatusInternalServerError)
 		return
 	}
-	publicHost := os.Getenv("DOCUMENSO_PUBLIC_URL")
-	if publicHost == "" {
-		publicHost = strings.Replace(h.documenso_client.BaseURL, "/api/v1", "", 1)
+	if len(leases) > limit {
+		leases = leases[:limit]
+		next := nextLeaseCursor(params, leases[len(leases)-1])
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextLeasePageURL(r, next)))
+		w.Header().Set("X-Next-Cursor", next)
 	}
-
-	publicHost = strings.TrimSuffix(publicHost, "/")
 	leaseResponses := make([]map[string]interface{}, 0)
 
 	for _, lease := range leases {
-		// Fetch tenant name
-		tenant, err := h.queries.GetUserByID(r.Context(), lease.TenantID)
-		if err != nil {
-			log.Printf("Warning: Could not fetch tenant name for ID %d", lease.TenantID)
-		}
-		log.Printf("Fetched tenant %s", tenant.FirstName)
-		// Fetch apartment details
-		apartment, err := h.queries.GetApartment(r.Context(), lease.ApartmentID)
-		if err != nil {
-			log.Printf("Warning: Could not fetch apartment %d", lease.ApartmentID)
-		}
-		log.Printf("Fetched apartment %v", apartment.UnitNumber)
 		// IMPORTANT: Check specifically for terminated status first
 		var status string
 		if lease.Status == db.LeaseStatusTerminated {
@@ -659,20 +948,42 @@ func (h *LeaseHandler) GetLeases(w http.ResponseWriter, r *http.Request) {
 			}
 			status = h.GetLeaseStatus(dbLease)
 		}
-		adminDocURL := fmt.Sprintf("%s/documents/%s", publicHost, lease.ExternalDocID)
+		adminDocURL := h.documenso_client.GetDocumentAdminURL(lease.ExternalDocID)
+		signers, err := h.queries.ListLeaseSigners(r.Context(), lease.ID)
+		if err != nil {
+			log.Printf("Warning: Could not fetch signers for lease %d", lease.ID)
+		}
+		occupants, err := h.queries.ListLeaseTenants(r.Context(), lease.ID)
+		if err != nil {
+			log.Printf("Warning: Could not fetch occupants for lease %d", lease.ID)
+		}
+		guarantors, err := h.queries.ListLeaseGuarantors(r.Context(), lease.ID)
+		if err != nil {
+			log.Printf("Warning: Could not fetch guarantors for lease %d", lease.ID)
+		}
+		addenda, err := h.queries.ListLeaseAttachedAddenda(r.Context(), lease.ID)
+		if err != nil {
+			log.Printf("Warning: Could not fetch addenda for lease %d", lease.ID)
+		}
 		// Add data to response array
 
 		leaseResponses = append(leaseResponses, map[string]interface{}{
 			"id":             lease.ID,
 			"tenantId":       lease.TenantID,
 			"apartmentId":    lease.ApartmentID, // This is the key addition
-			"tenantName":     tenant.FirstName + " " + tenant.LastName,
-			"apartment":      apartment.UnitNumber,
+			"tenantName":     lease.TenantFirstName + " " + lease.TenantLastName,
+			"tenantEmail":    lease.TenantEmail,
+			"apartment":      lease.UnitNumber,
+			"buildingId":     lease.BuildingID,
 			"leaseStartDate": lease.LeaseStartDate.Time.Format("2006-01-02"),
 			"leaseEndDate":   lease.LeaseEndDate.Time.Format("2006-01-02"),
 			"rentAmount":     lease.RentAmount.Int.String(),
 			"status":         status,
 			"admin_doc_url":  adminDocURL,
+			"signers":        toLeaseSignerResponses(signers),
+			"occupants":      toLeaseOccupantResponses(occupants),
+			"guarantors":     toLeaseGuarantorResponses(guarantors),
+			"addenda":        toLeaseAttachedAddendumResponses(addenda),
 		})
 
 	}
@@ -688,29 +999,7 @@ func (h *LeaseHandler) GetLeases(w http.ResponseWriter, r *http.Request) {
 // GetLeaseStatus is a helper method that returns the current status of a lease
 // This centralizes lease status calculation logic and can be used anywhere a status check is needed
 func (h *LeaseHandler) GetLeaseStatus(lease db.Lease) string {
-	switch string(lease.Status) {
-	case string(db.LeaseStatusTerminated),
-		string(db.LeaseStatusDraft),
-		string(db.LeaseStatusPendingApproval),
-		string(db.LeaseStatusCanceled),
-		string(db.LeaseStatusRenewed),
-		string(db.LeaseStatusExpired):
-		return string(lease.Status)
-	}
-
-	// Fallback to date-based logic
-	today := time.Now()
-	leaseEnd := lease.LeaseEndDate.Time
-
-	if leaseEnd.Before(today) {
-		return string(db.LeaseStatusExpired)
-	}
-
-	if leaseEnd.Sub(today).Hours()/24 <= 60 {
-		return "expires_soon"
-	}
-
-	return string(db.LeaseStatusActive)
+	return leasestate.Reported(lease.Status, lease.LeaseEndDate.Time, time.Now())
 }
 
 // UpdateAllLeaseStatuses handles updating expired lease statuses only
@@ -718,6 +1007,15 @@ func (h *LeaseHandler) GetLeaseStatus(lease db.Lease) string {
 func (h *LeaseHandler) UpdateAllLeaseStatuses(w http.ResponseWriter, r *http.Request) {
 	log.Println("[LEASE_STATUS_UPDATE] Starting daily lease status update")
 
+	// Leases whose renewal has started are done before anything else looks at their end date
+	renewed, err := h.queries.MarkRenewedLeases(r.Context())
+	if err != nil {
+		log.Printf("[LEASE_STATUS_UPDATE] Failed to mark renewed leases: %v", err)
+	}
+
+	// Leases whose lease or building allows holdover go month-to-month instead of expiring
+	converted := h.convertHoldoverLeases(r.Context())
+
 	// Only expire leases that have ended today
 	result, err := h.queries.ExpireLeasesEndingToday(r.Context())
 	if err != nil {
@@ -734,6 +1032,12 @@ func (h *LeaseHandler) UpdateAllLeaseStatuses(w http.ResponseWriter, r *http.Req
 	} else {
 		fmt.Fprintf(w, "Successfully expired %d lease(s)", result.ExpiredCount)
 	}
+	if converted > 0 {
+		fmt.Fprintf(w, ", converted %d lease(s) to month-to-month", converted)
+	}
+	if renewed > 0 {
+		fmt.Fprintf(w, ", marked %d lease(s) renewed", renewed)
+	}
 }
 
 func (h *LeaseHandler) GetTenantsWithoutLease(w http.ResponseWriter, r *http.Request) {
@@ -825,98 +1129,11 @@ func (h *LeaseHandler) ValidateLeaseRequest(r *http.Request) (*LeaseValidationRe
 	}, nil
 }
 
-// GenerateComprehensiveLeaseAgreement generates a full lease agreement PDF.
-func (h *LeaseHandler) GenerateComprehensiveLeaseAgreement(title, landlordName, tenantName, propertyAddress string, rentAmount float64, startDate, endDate time.Time) ([]byte, error) {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetMargins(20, 20, 20)
-	pdf.AddPage()
-
-	log.Printf("Inside GenerateComprehensiveLeaseAgreement for doc title %v", title)
-	// Title
-	pdf.SetFont("Arial", "B", 16)
-	pdf.Cell(10, 10, title)
-	pdf.Ln(15)
-
-	// Agreement date
-	pdf.SetFont("Arial", "", 12)
-	pdf.Cell(20, 10, fmt.Sprintf("This Lease Agreement is entered into on %s", time.Now().Format("January 2, 2006")))
-	pdf.Ln(10)
-
-	// Landlord section
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(40, 10, "LANDLORD: ")
-	pdf.Ln(10)
-	pdf.SetFont("Arial", "", 12)
-	pdf.Cell(80, 10, landlordName)
-	pdf.Ln(15)
-
-	// Tenant section
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(40, 10, "TENANT")
-	pdf.Ln(10)
-	pdf.SetFont("Arial", "", 12)
-	pdf.Cell(0, 10, tenantName)
-	pdf.Ln(15)
-
-	// Property section
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(40, 10, "PROPERTY")
-	pdf.Ln(10)
-	pdf.SetFont("Arial", "", 12)
-	pdf.MultiCell(0, 6, propertyAddress, "", "", false)
-	pdf.Ln(10)
-
-	// Lease term section
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(40, 10, "LEASE TERM")
-	pdf.Ln(10)
-	pdf.SetFont("Arial", "", 12)
-	pdf.Cell(40, 10, "Fixed Lease: From")
-	pdf.Cell(60, 10, startDate.Format("January 2, 2006"))
-	pdf.Cell(20, 10, "To")
-	pdf.Cell(60, 10, endDate.Format("January 2, 2006"))
-	pdf.Ln(10)
-
-	// Rent section
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(40, 10, "RENT")
-	pdf.Ln(10)
-	pdf.SetFont("Arial", "", 12)
-	pdf.Cell(40, 10, fmt.Sprintf("Monthly Rent: $%.2f", rentAmount))
-	pdf.Ln(10)
-
-	// Basic terms section
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(40, 10, "BASIC TERMS")
-	pdf.Ln(10)
-	pdf.SetFont("Arial", "", 10)
-	pdf.MultiCell(0, 5, "1. Tenant shall maintain the Property in good condition.\n"+
-		"2. Rent is due on the 1st of each month.\n"+
-		"3. A security deposit equal to one month's rent is required.\n"+
-		"4. Tenant shall not disturb neighbors.\n"+
-		"5. Landlord may enter with 24 hours notice for inspections or repairs.", "", "", false)
-	pdf.Ln(10)
-
-	// Signatures section
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(40, 10, "SIGNATURES")
-	pdf.Ln(15)
-
-	// Landlord signature
-	pdf.SetFont("Arial", "", 12)
-	pdf.Cell(80, 10, "Landlord Signature:")
-	pdf.Cell(80, 10, "Tenant Signature:")
-	pdf.Ln(20)
-	pdf.Ln(5)
-
-	pdf.Cell(80, 10, "Date:")
-	pdf.Cell(80, 10, "Date:")
-
-	var buf bytes.Buffer
-	if err := pdf.Output(&buf); err != nil {
-		return nil, fmt.Errorf("failed to generate lease PDF: %w", err)
-	}
-	return buf.Bytes(), nil
+// GenerateComprehensiveLeaseAgreement renders the lease PDF from a lease template version and returns
+// where each signer's fields belong.
+func (h *LeaseHandler) GenerateComprehensiveLeaseAgreement(content templates.LeaseTemplateContent, data templates.LeaseData) ([]byte, []templates.SignatureField, error) {
+	log.Printf("Inside GenerateComprehensiveLeaseAgreement for doc title %v", data.Title)
+	return templates.RenderLease(content, data)
 }
 
 // Updated SavePDFToDisk function to create a full lease PDF
@@ -951,35 +1168,40 @@ func SavePDFToDisk(pdfData []byte, title, tenantName string) error {
 	return nil // Success
 }
 
-func (h *LeaseHandler) handleDocumensoUploadAndSetup(pdfData []byte, req LeaseWithSignersRequest, landlordName, landlordEmail string) (docID string,
-	tenantRecipientID int,
-	tenantSigningURL string, landlordSigningURL string, leasePdfS3 string,
+// handleDocumensoUploadAndSetup uploads the lease with every occupant and the landlord as signers, places
+// each signer's fields and returns the signing URLs keyed by lowercase email.
+func (h *LeaseHandler) handleDocumensoUploadAndSetup(saga *leaseSaga, pdfData []byte, fields []templates.SignatureField, req LeaseWithSignersRequest, occupants []LeaseParty, landlordName, landlordEmail string) (docID string,
+	signingURLs map[string]string, leasePdfS3 string,
 	err error,
 ) {
 	log.Printf("Uploading lease %v to Documenso...\n", req.DocumentTitle)
 
-	if err != nil {
-		return "", 0, "", "", "", err
-	}
-
-	signers := []documenso.Signer{
-		{
-			Name:  req.TenantName,
-			Email: req.TenantEmail,
+	signers := make([]documenso.Signer, 0, len(occupants)+1)
+	for _, occupant := range occupants {
+		signers = append(signers, documenso.Signer{
+			Name:  occupant.Name,
+			Email: occupant.Email,
 			Role:  documenso.SignerRoleSigner,
-		},
-		{
-			Name:  landlordName,
-			Email: landlordEmail,
+		})
+	}
+	for _, guarantor := range req.Guarantors {
+		signers = append(signers, documenso.Signer{
+			Name:  guarantor.Name,
+			Email: guarantor.Email,
 			Role:  documenso.SignerRoleSigner,
-		},
+		})
 	}
+	signers = append(signers, documenso.Signer{
+		Name:  landlordName,
+		Email: landlordEmail,
+		Role:  documenso.SignerRoleSigner,
+	})
 
 	log.Printf("[Upload/Setup] Signers: %+v", signers)
 
 	if landlordName == "" {
 		log.Println("Warning: Landlord name is empty")
-		return "", 0, "", "", "", errors.New("landlord name is required")
+		return "", nil, "", errors.New("landlord name is required")
 	}
 
 	// Set document title
@@ -988,11 +1210,22 @@ func (h *LeaseHandler) handleDocumensoUploadAndSetup(pdfData []byte, req LeaseWi
 		documentTitle = req.DocumentTitle
 	}
 
-	log.Printf("Uploading lease %v to Documenso...\n", documentTitle)
+	return h.uploadForSigning(saga, pdfData, documentTitle, fields, signers)
+}
+
+// uploadForSigning uploads a rendered document to the e-sign provider, places each signer's fields and
+// returns the signing URLs keyed by lowercase email. The upload is registered with the saga, so a document
+// whose fields cannot be placed, or whose lease is never saved, is deleted when the caller compensates.
+func (h *LeaseHandler) uploadForSigning(saga *leaseSaga, pdfData []byte, documentTitle string, fields []templates.SignatureField, signers []documenso.Signer) (docID string,
+	signingURLs map[string]string, pdfS3 string,
+	err error,
+) {
+	log.Printf("Uploading %v to Documenso...\n", documentTitle)
 	docID, recipientInfoMap, s3bucket, err := h.documenso_client.UploadDocumentWithSigners(pdfData, documentTitle, signers)
 	if err != nil {
-		return "", 0, "", "", "", fmt.Errorf("upload to Documenso failed: %w", err)
+		return "", nil, "", fmt.Errorf("upload to Documenso failed: %w", err)
 	}
+	h.deleteDocumentOnFailure(saga, docID)
 	// To avoid overhead disabling saving PDF to disk  - please keep this code for future debugging purposes.
 	// // Save PDF to disk in background
 	// go func() {
@@ -1001,87 +1234,39 @@ func (h *LeaseHandler) handleDocumensoUploadAndSetup(pdfData []byte, req LeaseWi
 	// 	}
 	// }()
 
-	// Add a longer delay to ensure document is fully processed
-	time.Sleep(5 * time.Second)
-
-	// Get valid recipient IDs directly from the document endpoint
-	docURL := fmt.Sprintf("%s/documents/%s", h.documenso_client.BaseURL, docID)
-	docReq, err := http.NewRequest("GET", docURL, nil)
-	if err != nil {
-		return docID, 0, "", "", "", nil // Return docID even if we can't add fields
+	signingURLs = make(map[string]string, len(recipientInfoMap))
+	for email, info := range recipientInfoMap {
+		signingURLs[strings.ToLower(email)] = info.SigningURL
 	}
-	docReq.Header.Set("Authorization", "Bearer "+h.documenso_client.ApiKey)
 
-	docResp, err := h.documenso_client.Client.Do(docReq)
+	// Get valid recipient IDs back from the provider
+	recipients, err := h.documenso_client.GetRecipients(docID)
 	if err != nil {
-		log.Printf("Warning: Failed to get document details: %v", err)
-		return docID, 0, "", "", "", nil
-	}
-	defer docResp.Body.Close()
-
-	// Parse the document to get valid recipient IDs
-	var docResponse struct {
-		Recipients []struct {
-			Id    int    `json:"id"`
-			Email string `json:"email"`
-		} `json:"recipients"`
-	}
-
-	if err := json.NewDecoder(docResp.Body).Decode(&docResponse); err != nil {
-		log.Printf("Warning: Failed to parse document response: %v", err)
-		return docID, 0, "", "", "", nil
+		// Without recipient IDs no fields can be placed and nobody could sign
+		return "", nil, "", fmt.Errorf("failed to get recipients of document %s: %w", docID, err)
 	}
 
 	// Map emails to recipient IDs
 	validRecipientIDs := make(map[string]int)
-	for _, r := range docResponse.Recipients {
-		validRecipientIDs[r.Email] = r.Id
-		log.Printf("Found valid recipient ID: %d for email: %s", r.Id, r.Email)
+	for _, r := range recipients {
+		validRecipientIDs[strings.ToLower(r.Email)] = r.ID
+		log.Printf("Found valid recipient ID: %d for email: %s", r.ID, r.Email)
 	}
 
-	// Now add signature fields using valid recipient IDs
-	tenantID, hasTenant := validRecipientIDs[req.TenantEmail]
-	if hasTenant {
-		if err := h.documenso_client.AddSignatureField(docID, tenantID, 47, 78, 35, 5); err != nil {
-			log.Printf("Warning: Failed to add tenant signature: %v", err)
-		} else {
-			log.Printf("Successfully added signature field for tenant (ID: %d)", tenantID)
-		}
-	} else {
-		log.Printf("Warning: Could not find valid tenant ID for email %s", req.TenantEmail)
-	}
-	if hasTenant {
-		if err := h.documenso_client.AddSignatureField(docID, tenantID, 47, 88, 35, 5, "DATE"); err != nil {
-			log.Printf("Warning: Failed to add tenant signature: %v", err)
-		} else {
-			log.Printf("Successfully added signature field for tenant (ID: %d)", tenantID)
-		}
-	} else {
-		log.Printf("Warning: Could not find valid tenant ID for email %s", req.TenantEmail)
-	}
-
-	landlordID, hasLandlord := validRecipientIDs[landlordEmail]
-	if hasLandlord {
-		if err := h.documenso_client.AddSignatureField(docID, landlordID, 7, 78, 35, 5); err != nil {
-			log.Printf("Warning: Failed to add landlord signature: %v", err)
-		} else {
-			log.Printf("Successfully added signature field for landlord (ID: %d)", landlordID)
+	// Now add signature and date fields using valid recipient IDs
+	for _, field := range fields {
+		recipientID, ok := validRecipientIDs[strings.ToLower(field.Email)]
+		if !ok {
+			log.Printf("Warning: Could not find valid recipient ID for email %s", field.Email)
+			continue
 		}
-	} else {
-		log.Printf("Warning: Could not find valid landlord ID for email %s", landlordEmail)
-	}
-
-	if hasLandlord {
-		if err := h.documenso_client.AddSignatureField(docID, landlordID, 7, 88, 35, 5, "DATE"); err != nil {
-			log.Printf("Warning: Failed to add landlord date field: %v", err)
-		} else {
-			log.Printf("Successfully added date field for landlord (ID: %d)", landlordID)
+		if err := h.documenso_client.AddSignatureField(docID, recipientID, field.Page, field.X, field.Y, field.Width, field.Height, field.Type); err != nil {
+			return "", nil, "", fmt.Errorf("failed to add %s field for %s: %w", field.Type, field.Email, err)
 		}
-	} else {
-		log.Printf("Warning: Could not find valid landlord ID for email %s", landlordEmail)
+		log.Printf("Successfully added %s field for %s (ID: %d)", field.Type, field.Email, recipientID)
 	}
 
-	return docID, tenantID, recipientInfoMap[req.TenantEmail].SigningURL, recipientInfoMap[landlordEmail].SigningURL, s3bucket, nil
+	return docID, signingURLs, s3bucket, nil
 }
 
 func (h *LeaseHandler) RenewLease(w http.ResponseWriter, r *http.Request) {
@@ -1113,6 +1298,15 @@ func (h *LeaseHandler) RenewLease(w http.ResponseWriter, r *http.Request) {
 		return
 	} else {
 		req.LeaseNumber = lease.LeaseNumber + 1
+		// Without an explicit rent the renewal continues at whatever the old lease charges on the new start date
+		if startDate, err := time.Parse("2006-01-02", req.StartDate); err == nil && req.RentAmount == 0 {
+			req.RentAmount = effectiveLeaseRent(lease, startDate)
+		}
+		if req.RentEscalation == nil {
+			req.RentEscalation = h.storedRentEscalation(ctx, lease.ID)
+		}
+		h.inheritTerminationTerms(ctx, lease.ID, &req)
+		h.inheritHoldoverPolicy(ctx, lease.ID, &req)
 	}
 
 	if tenant, err := h.queries.GetUserByID(ctx, req.TenantID); err != nil {
@@ -1190,11 +1384,18 @@ func (h *LeaseHandler) CreateFullLeaseAgreementRenewal(w http.ResponseWriter, r
 	ctx := r.Context()
 	// Get landlord ID and email from middleware-injected context
 	// landlordID, landlordEmail, landlordName, err := middleware.GetLandlordFromContext(ctx)
-	_, landlordName, landlordEmail, err := h.GetLandlordInfo(r)
+	adminID, adminName, adminEmail, err := h.GetLandlordInfo(r)
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
+	landlord, err := h.apartmentLandlord(ctx, req.ApartmentID, LeaseParty{UserID: adminID, Name: adminName, Email: adminEmail})
+	if err != nil {
+		log.Printf("[LEASE_RENEWAL] %v", err)
+		http.Error(w, "Failed to load the landlord of the apartment", http.StatusInternalServerError)
+		return
+	}
+	landlordName, landlordEmail := landlord.Signer.Name, landlord.Signer.Email
 	tenantID := req.TenantID
 	
 	// Get the tenant's actual data from the database to ensure consistency
@@ -1219,16 +1420,52 @@ func (h *LeaseHandler) CreateFullLeaseAgreementRenewal(w http.ResponseWriter, r
 			req.TenantEmail, tenantEmail)
 	}
 
+	occupants, err := h.loadLeaseOccupants(ctx, tenant, req.OccupantIDs)
+	if err != nil {
+		log.Printf("[LEASE_RENEWAL] Invalid occupants: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateLeaseGuarantors(req.Guarantors, occupants, landlordEmail); err != nil {
+		log.Printf("[LEASE_RENEWAL] Invalid guarantors: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	addenda, err := h.loadLeaseAddenda(ctx, req.AddendumIDs)
+	if err != nil {
+		log.Printf("[LEASE_RENEWAL] Invalid addenda: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	escalation, err := leaseRentEscalation(req.RentEscalation)
+	if err != nil {
+		log.Printf("[LEASE_RENEWAL] Invalid rent escalation: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	leaseTemplate, templateContent, err := h.resolveLeaseTemplate(ctx, req.TemplateID, req.TemplateName)
+	if err != nil {
+		log.Printf("[LEASE_RENEWAL] %v", err)
+		http.Error(w, "Lease template not found or invalid", http.StatusBadRequest)
+		return
+	}
+
 	// 4. Generate the full lease PDF
-	pdfData, err := h.GenerateComprehensiveLeaseAgreement(
+	pdfData, signatureFields, err := h.GenerateComprehensiveLeaseAgreement(templateContent, h.leaseTemplateData(
+		ctx,
 		req.DocumentTitle,
-		landlordName,
-		tenantName, // Use tenant name from database for consistency
+		landlord,
+		occupants, // Use tenant names from database for consistency
+		req.Guarantors,
+		addenda,
+		req.ApartmentID,
 		req.PropertyAddress,
 		req.RentAmount,
+		escalation,
 		startDate,
 		endDate,
-	)
+	))
 	if err != nil {
 		log.Printf("Error generating lease PDF: %v", err)
 		http.Error(w, "Failed to generate lease PDF", http.StatusInternalServerError)
@@ -1247,48 +1484,42 @@ func (h *LeaseHandler) CreateFullLeaseAgreementRenewal(w http.ResponseWriter, r
 		DocumentTitle:   req.DocumentTitle,
 		TenantID:        req.TenantID,
 		ApartmentID:     req.ApartmentID,
+		OccupantIDs:     req.OccupantIDs,
+		Guarantors:      req.Guarantors,
 	}
 	
-	docID, _, tenantSigningURL, landlordSigningURL, s3bucket, err := h.handleDocumensoUploadAndSetup(
+	saga := newLeaseSaga("lease renewal")
+	docID, signingURLs, s3bucket, err := h.handleDocumensoUploadAndSetup(
+		saga,
 		pdfData,
+		signatureFields,
 		signerReq, // Use our modified request with correct tenant info
+		occupants,
 		landlordName,
 		landlordEmail,
 	)
 	if err != nil {
 		log.Printf("Documenso processing error: %v", err)
+		saga.compensate(err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	tenantSigningURL := signingURLs[strings.ToLower(tenantEmail)]
+	landlordSigningURL := signingURLs[strings.ToLower(landlordEmail)]
 
-	maxLeaseNumberRaw, err := h.queries.GetMaxLeaseNumber(ctx, tenantID)
-	if err != nil {
-		log.Printf("Failed to get max lease number for tenant %d: %v", tenantID, err)
-		http.Error(w, "Could not generate lease number", http.StatusInternalServerError)
-		return
-	}
-	maxLeaseNumber := maxLeaseNumberRaw.(int64)
-	maxLeaseNumber = maxLeaseNumber + 1
-
-	landlordID, _, _, err := h.GetLandlordInfo(r)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
 	// 9. Create the lease record in the database
 	leaseParams := db.RenewLeaseParams{
-		LeaseNumber:    maxLeaseNumber,
 		ExternalDocID:  docID,
 		TenantID:       req.TenantID,
-		LandlordID:     int64(landlordID),
+		LandlordID:     landlord.UserID,
 		ApartmentID:    req.ApartmentID,
 		LeaseStartDate: pgtype.Date{Time: startDate, Valid: true},
 		LeaseEndDate:   pgtype.Date{Time: endDate, Valid: true},
 		RentAmount:     pgtype.Numeric{Int: big.NewInt(int64(req.RentAmount * 100)), Exp: -2, Valid: true},
 		Status:         db.LeaseStatus(db.LeaseStatusPendingApproval),
 		LeasePdfS3:     pgtype.Text{String: s3bucket, Valid: true},
-		CreatedBy:      int64(landlordID), // Use landlord ID from database
-		UpdatedBy:      int64(landlordID),
+		CreatedBy:      adminID,
+		UpdatedBy:      adminID,
 		TenantSigningUrl: pgtype.Text{
 			String: tenantSigningURL,
 			Valid:  tenantSigningURL != "",
@@ -1299,28 +1530,50 @@ func (h *LeaseHandler) CreateFullLeaseAgreementRenewal(w http.ResponseWriter, r
 		},
 	}
 
-	leaseID, err := h.queries.RenewLease(r.Context(), db.RenewLeaseParams{
-		LeaseNumber:        leaseParams.LeaseNumber,
-		ExternalDocID:      leaseParams.ExternalDocID,
-		TenantID:           leaseParams.TenantID,
-		LandlordID:         leaseParams.LandlordID,
-		ApartmentID:        leaseParams.ApartmentID,
-		LeaseStartDate:     leaseParams.LeaseStartDate,
-		LeaseEndDate:       leaseParams.LeaseEndDate,
-		RentAmount:         leaseParams.RentAmount,
-		Status:             leaseParams.Status,
-		LeasePdfS3:         leaseParams.LeasePdfS3,
-		CreatedBy:          leaseParams.CreatedBy,
-		UpdatedBy:          leaseParams.UpdatedBy,
-		TenantSigningUrl:   leaseParams.TenantSigningUrl,
-		LandlordSigningUrl: leaseParams.LandlordSigningUrl,
+	var leaseID db.RenewLeaseRow
+	err = h.inTx(ctx, func(tx *LeaseHandler) error {
+		maxLeaseNumberRaw, err := tx.queries.GetMaxLeaseNumber(ctx, tenantID)
+		if err != nil {
+			return fmt.Errorf("could not generate lease number for tenant %d: %w", tenantID, err)
+		}
+		leaseParams.LeaseNumber = maxLeaseNumberRaw.(int64) + 1
+
+		if leaseID, err = tx.queries.RenewLease(ctx, leaseParams); err != nil {
+			return fmt.Errorf("failed to insert lease: %w", err)
+		}
+		if err := tx.recordLeaseCreated(ctx, leaseID.ID, leaseParams.Status, leaseParams.CreatedBy); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseTenants(ctx, leaseID.ID, occupants); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseTemplate(ctx, leaseID.ID, leaseTemplate); err != nil {
+			return err
+		}
+		if err := tx.queries.SetLeaseDocumentType(ctx, db.SetLeaseDocumentTypeParams{
+			ID:           leaseID.ID,
+			DocumentType: db.TypeExtension,
+		}); err != nil {
+			return fmt.Errorf("failed marking lease %d as an extension: %w", leaseID.ID, err)
+		}
+		if err := tx.recordLeaseAddenda(ctx, leaseID.ID, addenda); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseRentEscalation(ctx, leaseID.ID, escalation); err != nil {
+			return err
+		}
+		if err := tx.recordLeaseGuarantors(ctx, leaseID.ID, req.Guarantors); err != nil {
+			return err
+		}
+		return tx.recordLeaseSigners(ctx, leaseID.ID, occupants, req.Guarantors, signingURLs, landlord.Signer.UserID, landlordName, landlordEmail)
 	})
 	if err != nil {
 		log.Printf("Error renewing lease in database: %v", err)
+		saga.compensate(err)
 		http.Error(w, "Failed to renew lease in database", http.StatusInternalServerError)
-
 		return
 	}
+	h.recordGeneratedLeasePDF(ctx, leaseID.ID, pdfData)
 
 	// 10. Return success response with lease details
 	resp := map[string]interface{}{
@@ -1387,6 +1640,9 @@ func (h *LeaseHandler) NotifyExpiringLeases(w http.ResponseWriter, r *http.Reque
 				continue
 			}
 
+			// Start the renewal offer or send the next reminder
+			renewalOffer := h.advanceRenewalOffer(r.Context(), lease, tenant, int(daysUntilExpiration))
+
 			// Format lease info for notification
 			expiringLeases = append(expiringLeases, map[string]interface{}{
 				"lease_id":        lease.ID,
@@ -1395,10 +1651,14 @@ func (h *LeaseHandler) NotifyExpiringLeases(w http.ResponseWriter, r *http.Reque
 				"apartment":       apartment.UnitNumber,
 				"days_remaining":  int(daysUntilExpiration),
 				"expiration_date": lease.LeaseEndDate.Time.Format("2006-01-02"),
+				"renewal_offer":   renewalOffer,
 			})
 		}
 	}
 
+	// Offers still open after their lease ended are closed
+	expiredOffers := h.expireRenewalOffers(r.Context(), today)
+
 	// Send notification emails if leases are expiring soon
 	if len(expiringLeases) > 0 {
 		// Send email to administrator
@@ -1412,9 +1672,10 @@ func (h *LeaseHandler) NotifyExpiringLeases(w http.ResponseWriter, r *http.Reque
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"expiring_leases_count": len(expiringLeases),
-		"expiring_leases":       expiringLeases,
-		"message":               "Expiring lease notification check completed",
+		"expiring_leases_count":  len(expiringLeases),
+		"expiring_leases":        expiringLeases,
+		"expired_renewal_offers": expiredOffers,
+		"message":                "Expiring lease notification check completed",
 	}); err != nil {
 		log.Printf("[LEASE_NOTIFY] Error encoding response: %v", err)
 	}
@@ -1439,10 +1700,11 @@ func (h *LeaseHandler) sendExpiringLeasesNotification(expiringLeases []map[strin
 		body.WriteString(fmt.Sprintf("  Tenant: %v\n", lease["tenant_name"]))
 		body.WriteString(fmt.Sprintf("  Apartment: %v\n", lease["apartment"]))
 		body.WriteString(fmt.Sprintf("  Expiration Date: %v\n", lease["expiration_date"]))
-		body.WriteString(fmt.Sprintf("  Days Remaining: %v\n\n", lease["days_remaining"]))
+		body.WriteString(fmt.Sprintf("  Days Remaining: %v\n", lease["days_remaining"]))
+		body.WriteString(fmt.Sprintf("  Renewal Offer: %v\n\n", lease["renewal_offer"]))
 	}
 
-	body.WriteString("\nPlease log in to the management system to initiate lease renewals for these tenants.\n")
+	body.WriteString("\nRenewal offers are emailed to tenants automatically. Log in to the management system to review or change them.\n")
 
 	// Send the email
 	return smtp.SendEmail(adminEmail, subject, body.String())
@@ -1508,217 +1770,97 @@ func (h *LeaseHandler) DocumensoGetDocumentURL(w http.ResponseWriter, r *http.Re
 	}
 }
 
-// PdfS3GetDocumentURL retrieves the stored S3 URL for a lease PDF
-func (h *LeaseHandler) PdfS3GetDocumentURL(w http.ResponseWriter, r *http.Request) {
-	// Get lease ID from URL parameter
-	leaseIDStr := chi.URLParam(r, "leaseID")
-	if leaseIDStr == "" {
-		http.Error(w, "Missing lease ID in URL", http.StatusBadRequest)
-		return
-	}
-
-	leaseID, err := strconv.ParseInt(leaseIDStr, 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid lease ID format", http.StatusBadRequest)
-		return
-	}
-
-	// Get lease details from database
-	lease, err := h.queries.GetLeaseByID(r.Context(), leaseID)
-	if err != nil {
-		log.Printf("[PDF_S3_URL] Error fetching lease %d: %v", leaseID, err)
-		http.Error(w, "Lease not found", http.StatusNotFound)
-		return
-	}
-
-	// Check if lease has an S3 URL
-	if !lease.LeasePdfS3.Valid || lease.LeasePdfS3.String == "" {
-		http.Error(w, "Lease has no associated PDF URL", http.StatusNotFound)
-		return
-	}
+const (
+	// documensoWebhookReplayWindow is how far the signing time of a delivery may be from now
+	documensoWebhookReplayWindow = 5 * time.Minute
+	// documensoSecretRotationGrace is how long the previous webhook secret is still accepted after a rotation
+	documensoSecretRotationGrace = 24 * time.Hour
+)
 
-	// Return the S3 URL to the client
-	response := map[string]interface{}{
-		"lease_id":     leaseID,
-		"lease_pdf_s3": lease.LeasePdfS3.String,
-		"status":       lease.Status,
+// documensoWebhookSecrets returns the secrets a Documenso delivery may be signed with. They are read
+// from app_config on every request so a rotated secret takes effect without a restart.
+func (h *LeaseHandler) documensoWebhookSecrets(ctx context.Context) []string {
+	var secrets []string
+	if current, err := h.queries.GetConfigByKey(ctx, "documenso_webhook_secret"); err == nil && current.Value != "" {
+		secrets = append(secrets, current.Value)
+	} else if env := os.Getenv("DOCUMENSO_WEBHOOK_SECRET"); env != "" {
+		secrets = append(secrets, env)
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("[PDF_S3_URL] Error encoding response: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
+	previous, err := h.queries.GetConfigByKey(ctx, "documenso_webhook_secret_previous")
+	if err == nil && previous.Value != "" && time.Since(previous.UpdatedAt.Time) < documensoSecretRotationGrace {
+		secrets = append(secrets, previous.Value)
 	}
+	return secrets
 }
 
 func (h *LeaseHandler) DocumensoWebhookHandler(w http.ResponseWriter, r *http.Request) {
 	// Read the request body
-	body, err := io.ReadAll(r.Body)
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
 	if err != nil {
 		log.Printf("[WEBHOOK] Error reading request body: %v", err)
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
 	}
 
+	// Verify the delivery before trusting anything in it
+	secrets := h.documensoWebhookSecrets(r.Context())
+	if len(secrets) == 0 {
+		log.Printf("[WEBHOOK] Warning: no Documenso webhook secret configured, accepting unsigned webhook")
+	} else if err := documenso.VerifyWebhook(r.Header, body, secrets, time.Now(), documensoWebhookReplayWindow); err != nil {
+		log.Printf("[WEBHOOK] Rejected Documenso webhook: %v", err)
+		recordRejectedWebhookEvent(r.Context(), h.queries, webhookSourceDocumenso, r.Header, body, err)
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
 	// Parse the JSON payload
-	var webhookData map[string]interface{}
-	if err := json.Unmarshal(body, &webhookData); err != nil {
+	var event documenso.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
 		log.Printf("[WEBHOOK] Error parsing webhook JSON: %v", err)
 		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
 		return
 	}
-	// Read the webhook secret from environment variable
-	webhookSecret := os.Getenv("DOCUMENSO_WEBHOOK_SECRET")
-	if webhookSecret == "" {
-		log.Printf("[WEBHOOK] Warning: DOCUMENSO_WEBHOOK_SECRET not set")
-	}
-
-	// Read the signature from the header
-	receivedSignature := r.Header.Get("X-Documenso-Secret")
-
-	// Only verify signature if we have a secret configured
-	if webhookSecret != "" && receivedSignature != "" {
-		// Compare with the provided signature
-		if webhookSecret != receivedSignature {
-			log.Printf("[WEBHOOK] Invalid signature. Expected: %s, Received: %s", webhookSecret, receivedSignature)
-			http.Error(w, "Invalid signature", http.StatusUnauthorized)
-			return
-		}
-		log.Printf("[WEBHOOK] Signature validation successful")
-	}
 	log.Printf("[WEBHOOK] Extracting webhook data...")
-	// Extract the event type
-	eventType, ok := webhookData["event"].(string)
-	if !ok {
+	if event.Event == "" {
 		log.Printf("[WEBHOOK] Missing event type in webhook payload")
 		http.Error(w, "Invalid webhook format", http.StatusBadRequest)
 		return
 	}
+	if event.Payload.ID == 0 {
+		log.Printf("[WEBHOOK] Missing document identifier in webhook payload")
+		http.Error(w, "Invalid webhook format", http.StatusBadRequest)
+		return
+	}
 
-	log.Printf("[WEBHOOK] Received Documenso webhook event: %s", eventType)
+	log.Printf("[WEBHOOK] Received Documenso webhook event: %s", event.Event)
 
-	// We only care about DOCUMENT_COMPLETED events
-	if eventType != "DOCUMENT_COMPLETED" {
-		log.Printf("[WEBHOOK] Ignoring non-completion event: %s", eventType)
+	// Log the delivery so a failure while processing can be replayed
+	ctx := context.Background()
+	logged, duplicate, err := recordWebhookEvent(ctx, h.queries, webhookSourceDocumenso, "", event.Event, r.Header, body)
+	if err != nil {
+		log.Printf("[WEBHOOK] Failed recording webhook event: %v", err)
+	}
+	if duplicate {
+		log.Printf("[WEBHOOK] Delivery %s already processed or being processed, skipping", logged.DeliveryID)
 		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte(`{"status":"acknowledged"}`)); err != nil {
+		if _, err := w.Write([]byte(`{"status":"duplicate"}`)); err != nil {
 			log.Printf("Error writing response: %v", err)
 		}
 		return
 	}
 
-	// Extract document ID from the payload
-	payload, ok := webhookData["payload"].(map[string]interface{})
-	if !ok {
-		log.Printf("[WEBHOOK] Missing payload data in webhook")
-		http.Error(w, "Invalid webhook format", http.StatusBadRequest)
-		return
-	}
-
-	// Get id
-	var documentID string
-	if idValue, ok := payload["id"].(float64); ok {
-		documentID = strconv.FormatFloat(idValue, 'f', 0, 64)
-	} else {
-		log.Printf("[WEBHOOK] Missing document identifier in webhook payload")
-		http.Error(w, "Invalid webhook format", http.StatusBadRequest)
-		return
-	}
-
 	// Acknowledge receipt of the webhook immediately
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write([]byte(`{"status":"processing"}`)); err != nil {
 		log.Printf("Error writing response: %v", err)
 	}
 
-	// Process the webhook asynchronously
-	// go func() {
-	ctx := context.Background()
-	lease, err := h.queries.GetLeaseByExternalDocID(ctx, documentID)
-	if err != nil {
-		log.Printf("[WEBHOOK] No lease found for doc ID %s: %v", documentID, err)
-		return
-	}
-
-	// Try to find matching landlord by lease.LandlordID
-	landlord, err := h.queries.GetUserByID(ctx, lease.LandlordID)
+	err = h.processDocumensoEvent(ctx, event)
+	finishWebhookEvent(ctx, h.queries, logged.ID, err)
 	if err != nil {
-		log.Printf("[WEBHOOK] Failed to fetch landlord from lease ID %d: %v", lease.ID, err)
-		return
-	}
-	landlordID := landlord.ID
-
-	// Get landlord ID from middleware-injected context
-	// landlord, err := h.queries.ListUsersByRole(ctx, db.RoleAdmin)
-	// if err != nil {
-	// 	http.Error(w, "Unauthorized", http.StatusUnauthorized)
-	// 	return
-	// }
-	// 1. Get the lease associated with this document ID
-
-	log.Printf("[WEBHOOK] Document %s signed, marking lease %d as active", documentID, lease.ID)
-
-	// 2. Update the lease status to active
-	updatedLease, err := h.queries.UpdateLeaseStatus(ctx, db.UpdateLeaseStatusParams{
-		ID:        lease.ID,
-		Status:    db.LeaseStatusActive,
-		UpdatedBy: int64(landlordID),
-	})
-	if err != nil {
-		log.Printf("[WEBHOOK] Failed to update lease status: %v", err)
-		return
-	}
-
-	log.Printf("[WEBHOOK] Lease %d marked as active", updatedLease.ID)
-
-	// 3. Check if there's an apartment associated with this lease
-	if updatedLease.ApartmentID != 0 {
-		// Get the current apartment info
-		apartment, err := h.queries.GetApartment(ctx, updatedLease.ApartmentID)
-		if err != nil {
-			log.Printf("[WEBHOOK] Failed to get apartment with ID %d: %v", updatedLease.ApartmentID, err)
-			return
-		}
-
-		// Update the apartment to unavailable
-
-		err = h.queries.UpdateApartment(ctx, db.UpdateApartmentParams{
-			ID:           apartment.ID,
-			Price:        apartment.Price,
-			ManagementID: apartment.ManagementID,
-			Availability: false,
-		})
-		if err != nil {
-			log.Printf("[WEBHOOK] Failed to update apartment availability: %v", err)
-			return
-		}
-
-		log.Printf("[WEBHOOK] Updated apartment ID %d to unavailable", apartment.ID)
-	}
-
-	// 4. Download the signed document from Documenso if needed
-	downloadURL, err := h.documenso_client.GetDocumentDownloadURL(documentID)
-	if err == nil {
-		unescapedURL := downloadURL
-		decodedURL, decodeErr := url.QueryUnescape(downloadURL)
-		if decodeErr == nil {
-			unescapedURL = decodedURL
-		}
-		log.Printf("[WEBHOOK] Document download URL: %s", downloadURL)
-		// Update the lease record with the signed document URL
-		err = h.queries.UpdateSignedLeasePdfS3URL(ctx, db.UpdateSignedLeasePdfS3URLParams{
-			ID:         updatedLease.ID,
-			LeasePdfS3: pgtype.Text{String: unescapedURL, Valid: true},
-		})
-
-		if err != nil {
-			log.Printf("[WEBHOOK] Failed to update signed document URL: %v", err)
-		} else {
-			log.Printf("[WEBHOOK] Updated lease %d with signed document URL %v", updatedLease.ID, unescapedURL)
-		}
+		log.Printf("[WEBHOOK] Failed processing %s for document %d: %v", event.Event, event.Payload.ID, err)
 	}
-	// }()
 }
 
 // SendLease updates a lease from draft to pending_approval state
@@ -1758,11 +1900,16 @@ func (h *LeaseHandler) SendLease(w http.ResponseWriter, r *http.Request) {
 		log.Printf("[LEASE_SEND] GetUserByID failed: %v", err)
 		return
 	}
-	_, _, landlordEmail, err := h.GetLandlordInfo(r)
-	if err != nil {
+	if _, _, _, err := h.GetLandlordInfo(r); err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
+	landlordEmail, err := h.leaseLandlordSignerEmail(ctx, lease)
+	if err != nil {
+		http.Error(w, "Landlord not found", http.StatusInternalServerError)
+		log.Printf("[LEASE_SEND] %v", err)
+		return
+	}
 
 	// Check if the lease already has signing URLs
 	if lease.TenantSigningUrl.Valid && lease.TenantSigningUrl.String != "" &&
@@ -1781,14 +1928,10 @@ func (h *LeaseHandler) SendLease(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Update lease status to pending_approval with proper updated_by field
-		_, err = h.queries.UpdateLeaseStatus(ctx, db.UpdateLeaseStatusParams{
-			ID:        leaseID,
-			Status:    db.LeaseStatusPendingApproval,
-			UpdatedBy: landlordID,
-		})
+		err = h.transitionLease(ctx, leaseID, lease.Status, db.LeaseStatusPendingApproval, leasestate.EventSent, "Sent for signing", landlordID)
 		if err != nil {
-			http.Error(w, "Failed to update lease status", http.StatusInternalServerError)
-			log.Printf("[LEASE_SEND] UpdateLeaseStatus failed: %v", err)
+			leaseTransitionFailed(w, err)
+			log.Printf("[LEASE_SEND] Status change failed: %v", err)
 			return
 		}
 
@@ -1811,51 +1954,36 @@ func (h *LeaseHandler) SendLease(w http.ResponseWriter, r *http.Request) {
 
 	// ALWAYS refresh the API key from the database before sending the document
 	// This ensures we have the latest credentials even if they were updated elsewhere
-	apiKeyConfig, err := h.queries.GetConfigByKey(ctx, "documenso_api_key")
-	if err != nil {
-		log.Printf("[LEASE_SEND] Failed to fetch API key from database: %v", err)
-		http.Error(w, "Failed to retrieve API credentials", http.StatusInternalServerError)
-		return
-	}
+	if !h.usingLocalSigner() {
+		apiKeyConfig, err := h.queries.GetConfigByKey(ctx, "documenso_api_key")
+		if err != nil {
+			log.Printf("[LEASE_SEND] Failed to fetch API key from database: %v", err)
+			http.Error(w, "Failed to retrieve API credentials", http.StatusInternalServerError)
+			return
+		}
 
-	if apiKeyConfig.Value == "" {
-		log.Printf("[LEASE_SEND] API key in database is empty")
-		http.Error(w, "Documenso API key not configured", http.StatusInternalServerError)
-		return
-	}
+		if apiKeyConfig.Value == "" {
+			log.Printf("[LEASE_SEND] API key in database is empty")
+			http.Error(w, "Documenso API key not configured", http.StatusInternalServerError)
+			return
+		}
 
-	// Always use the database API key for consistency
-	if len(apiKeyConfig.Value) > 4 {
-		log.Printf("[LEASE_SEND] Using API key from database: %s... (length: %d)",
-			apiKeyConfig.Value[:4], len(apiKeyConfig.Value))
-	} else {
-		log.Printf("[LEASE_SEND] WARNING: API key from database is suspiciously short: %d chars",
-			len(apiKeyConfig.Value))
-	}
+		// Always use the database API key for consistency
+		if len(apiKeyConfig.Value) > 4 {
+			log.Printf("[LEASE_SEND] Using API key from database: %s... (length: %d)",
+				apiKeyConfig.Value[:4], len(apiKeyConfig.Value))
+		} else {
+			log.Printf("[LEASE_SEND] WARNING: API key from database is suspiciously short: %d chars",
+				len(apiKeyConfig.Value))
+		}
 
-	// Get webhook secret too (will be needed for future verification)
-	webhookConfig, err := h.queries.GetConfigByKey(ctx, "documenso_webhook_secret")
-	if err != nil {
-		log.Printf("[LEASE_SEND] Failed to fetch webhook secret: %v", err)
-		// Non-fatal, continue anyway
-	} else if webhookConfig.Value != "" && len(webhookConfig.Value) > 4 {
-		log.Printf("[LEASE_SEND] Found webhook secret in database: %s... (length: %d)",
-			webhookConfig.Value[:4], len(webhookConfig.Value))
-	}
+		// Always create a fresh client with the database credentials
+		h.refreshDocumensoClient(apiKeyConfig.Value)
 
-	// Always create a fresh client with the database credentials
-	baseURL := os.Getenv("DOCUMENSO_API_URL")
-	if baseURL == "" {
-		baseURL = "http://documenso:3000"
-		log.Printf("[LEASE_SEND] No API URL in environment, using default: %s", baseURL)
+		// Update environment variable too for consistency
+		os.Setenv("DOCUMENSO_API_KEY", apiKeyConfig.Value)
 	}
 
-	log.Printf("[LEASE_SEND] Creating new Documenso client with URL: %s", baseURL)
-	h.documenso_client = documenso.NewDocumensoClient(baseURL, apiKeyConfig.Value)
-
-	// Update environment variable too for consistency
-	os.Setenv("DOCUMENSO_API_KEY", apiKeyConfig.Value)
-
 	// 4. Get both signing URLs from Documenso
 	tenantSigningURL, landlordSigningURL, err := h.documenso_client.GetSigningURLs(lease.ExternalDocID, tenant.Email, landlordEmail)
 	if err != nil {
@@ -1874,6 +2002,37 @@ func (h *LeaseHandler) SendLease(w http.ResponseWriter, r *http.Request) {
 		log.Printf("[LEASE_SEND] Failed to persist signing URLs: %v", err)
 		// continue; not fatal
 	}
+	for email, signingURL := range map[string]string{tenant.Email: tenantSigningURL, landlordEmail: landlordSigningURL} {
+		if err := h.queries.UpdateLeaseSignerSigningURL(ctx, db.UpdateLeaseSignerSigningURLParams{
+			LeaseID:    leaseID,
+			Email:      email,
+			SigningUrl: pgtype.Text{String: signingURL, Valid: signingURL != ""},
+		}); err != nil {
+			log.Printf("[LEASE_SEND] Failed to persist signing URL for %s: %v", email, err)
+		}
+	}
+
+	// Co-occupants and guarantors sign through their own links
+	occupants, _ := h.queries.ListLeaseTenants(ctx, leaseID)
+	guarantors, _ := h.queries.ListLeaseGuarantors(ctx, leaseID)
+	if len(occupants) > 1 || len(guarantors) > 0 {
+		recipients, err := h.documenso_client.GetRecipients(lease.ExternalDocID)
+		if err != nil {
+			log.Printf("[LEASE_SEND] Failed to fetch occupant signing URLs: %v", err)
+		}
+		for _, recipient := range recipients {
+			if recipient.SigningURL == "" || strings.EqualFold(recipient.Email, tenant.Email) || strings.EqualFold(recipient.Email, landlordEmail) {
+				continue
+			}
+			if err := h.queries.UpdateLeaseSignerSigningURL(ctx, db.UpdateLeaseSignerSigningURLParams{
+				LeaseID:    leaseID,
+				Email:      recipient.Email,
+				SigningUrl: pgtype.Text{String: recipient.SigningURL, Valid: true},
+			}); err != nil {
+				log.Printf("[LEASE_SEND] Failed to persist signing URL for %s: %v", recipient.Email, err)
+			}
+		}
+	}
 
 	landlordID, _, _, err := h.GetLandlordInfo(r)
 	if err != nil {
@@ -1882,23 +2041,19 @@ func (h *LeaseHandler) SendLease(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 6. Update lease status to pending_approval
-	updatedLease, err := h.queries.UpdateLeaseStatus(ctx, db.UpdateLeaseStatusParams{
-		ID:        leaseID,
-		Status:    db.LeaseStatusPendingApproval,
-		UpdatedBy: landlordID,
-	})
+	err = h.transitionLease(ctx, leaseID, lease.Status, db.LeaseStatusPendingApproval, leasestate.EventSent, "Sent for signing", landlordID)
 	if err != nil {
-		http.Error(w, "Failed to update lease status", http.StatusInternalServerError)
-		log.Printf("[LEASE_SEND] UpdateLeaseStatus failed: %v", err)
+		leaseTransitionFailed(w, err)
+		log.Printf("[LEASE_SEND] Status change failed: %v", err)
 		return
 	}
 
 	// 7. Respond with success
 	resp := map[string]interface{}{
 		"lease_id":        leaseID,
-		"status":          updatedLease.Status,
+		"status":          db.LeaseStatusPendingApproval,
 		"sign_url":        tenantSigningURL,
-		"external_doc_id": updatedLease.ExternalDocID,
+		"external_doc_id": lease.ExternalDocID,
 		"message":         "Lease sent for signing",
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -1929,10 +2084,13 @@ func (h *LeaseHandler) GetSignedLeaseURL(w http.ResponseWriter, r *http.Request)
 
 	var pdfURL string
 	status := string(lease.Status)
-	isSignedStatus := status == string(db.LeaseStatusActive) || status == string(db.LeaseStatusExpired) || status == string(db.LeaseStatusTerminated)
+	isSignedStatus := status == string(db.LeaseStatusActive) || status == string(db.LeaseStatusMonthToMonth) || status == string(db.LeaseStatusExpired) || status == string(db.LeaseStatusTerminated)
 
-	// If the lease has a PDF URL stored, use it
-	if lease.LeasePdfS3.Valid && lease.LeasePdfS3.String != "" {
+	// Prefer our own stored copy, then whatever the e-sign provider handed back
+	if key, signed, err := h.leaseDocumentKey(r.Context(), lease); err == nil && (signed || !isSignedStatus) {
+		pdfURL, _ = h.documentURLs.URL(key, documentURLTTL, time.Now())
+		log.Printf("[LEASE_URL] Using stored copy of lease %d with status %s", leaseID, status)
+	} else if lease.LeasePdfS3.Valid && lease.LeasePdfS3.String != "" {
 		pdfURL = lease.LeasePdfS3.String
 		log.Printf("[LEASE_URL] Using stored PDF URL for lease %d with status %s", leaseID, status)
 	} else if lease.ExternalDocID != "" && isSignedStatus {
@@ -2011,38 +2169,13 @@ func (h *LeaseHandler) GetTenantLeaseStatusAndURLByUserID(w http.ResponseWriter,
 	}
 }
 
-// IsDocumensoAvailable checks if the Documenso service is available
+// IsDocumensoAvailable checks if the e-signature service is available
 func (h *LeaseHandler) IsDocumensoAvailable(ctx context.Context) bool {
-	// Use the baseURL to check service availability
-	// We'll attempt to access the API root with a short timeout
-	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	// Create a simple GET request to the API
-	req, err := http.NewRequestWithContext(checkCtx, "GET", h.documenso_client.BaseURL, nil)
-	if err != nil {
-		log.Printf("[DOCUMENSO_HEALTH] Failed to create request: %v", err)
-		return false
-	}
-
-	req.Header.Set("Authorization", "Bearer "+h.documenso_client.ApiKey)
-
-	// Execute the request
-	resp, err := h.documenso_client.Client.Do(req)
-	if err != nil {
+	if err := h.documenso_client.Ping(ctx); err != nil {
 		log.Printf("[DOCUMENSO_HEALTH] Service check failed: %v", err)
 		return false
 	}
-	defer resp.Body.Close()
-
-	// Check if we got a successful status code
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		log.Printf("[DOCUMENSO_HEALTH] Service available, status: %d", resp.StatusCode)
-		return true
-	}
-
-	log.Printf("[DOCUMENSO_HEALTH] Service check returned status: %d", resp.StatusCode)
-	return false
+	return true
 }
 
 // RetryWithDocumensoCheck wraps an operation that depends on Documenso with availability checking
@@ -2152,8 +2285,8 @@ func (h *LeaseHandler) UpdateDocumensoConfig(w http.ResponseWriter, r *http.Requ
 	os.Setenv("DOCUMENSO_WEBHOOK_SECRET", config.WebhookSecret)
 
 	// Log with limited key visibility for security
-	log.Printf("[DOCUMENSO_CONFIG] Updated API key to %s... and webhook secret to %s...",
-		config.ApiKey[:4], config.WebhookSecret[:4])
+	log.Printf("[DOCUMENSO_CONFIG] Updated API key to %s and rotated the webhook secret",
+		documenso.MaskSecret(config.ApiKey))
 
 	// Save configuration to database for persistence across restarts
 	ctx := r.Context()
@@ -2189,6 +2322,20 @@ func (h *LeaseHandler) UpdateDocumensoConfig(w http.ResponseWriter, r *http.Requ
 		log.Printf("[DOCUMENSO_CONFIG] API key saved to database successfully")
 	}
 
+	// Keep the old webhook secret valid for a while so deliveries signed before the rotation still verify
+	if current, err := h.queries.GetConfigByKey(ctx, "documenso_webhook_secret"); err == nil &&
+		current.Value != "" && current.Value != config.WebhookSecret {
+		_, err = h.queries.UpsertConfig(ctx, db.UpsertConfigParams{
+			Key:         "documenso_webhook_secret_previous",
+			Value:       current.Value,
+			Description: pgtype.Text{String: fmt.Sprintf("Previous Documenso webhook secret, accepted for %s after rotation", documensoSecretRotationGrace), Valid: true},
+			UserID:      pgtype.Int8{Int64: landlordID, Valid: true},
+		})
+		if err != nil {
+			log.Printf("[DOCUMENSO_CONFIG] Warning: Failed to keep previous webhook secret: %v", err)
+		}
+	}
+
 	// Update webhook secret in database with admin user ID
 	_, err = h.queries.UpsertConfig(ctx, db.UpsertConfigParams{
 		Key:         "documenso_webhook_secret",
@@ -2203,12 +2350,10 @@ func (h *LeaseHandler) UpdateDocumensoConfig(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Update the Documenso client with the new API key
-	baseURL := os.Getenv("DOCUMENSO_API_URL")
-	if baseURL == "" {
-		baseURL = "http://documenso:3000"
-		log.Printf("[DOCUMENSO_CONFIG] No API URL in environment, using default: %s", baseURL)
+	h.refreshDocumensoClient(config.ApiKey)
+	if signer, ok := h.documenso_client.(*documenso.LocalSigner); ok {
+		signer.SetWebhookSecret(config.WebhookSecret)
 	}
-	h.documenso_client = documenso.NewDocumensoClient(baseURL, config.ApiKey)
 	log.Printf("[DOCUMENSO_CONFIG] Updated Documenso client with new API key")
 
 	// Return success response
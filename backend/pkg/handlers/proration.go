@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/rent"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ProrationRequest asks for the partial-month rent of a lease. TerminationDate is optional and prorates
+// the month the lease ends early in; Method overrides the building's proration method.
+type ProrationRequest struct {
+	ApartmentID     int64          `json:"apartment_id"`
+	RentAmount      float64        `json:"rent_amount"`
+	StartDate       string         `json:"start_date"`
+	EndDate         string         `json:"end_date"`
+	TerminationDate string         `json:"termination_date,omitempty"`
+	Method          string         `json:"method,omitempty"`
+	RentEscalation  *rent.Schedule `json:"rent_escalation,omitempty"`
+}
+
+// LeaseProration is the partial-month rent at either end of a lease. A nil entry means that month is
+// billed in full.
+type LeaseProration struct {
+	Method      rent.ProrationMethod `json:"method"`
+	MoveIn      *rent.Proration      `json:"move_in"`
+	MoveOut     *rent.Proration      `json:"move_out"`
+	Termination *rent.Proration      `json:"termination,omitempty"`
+}
+
+// apartmentProrationMethod returns the proration method of the building an apartment is in
+func (h *LeaseHandler) apartmentProrationMethod(ctx context.Context, apartmentID int64) rent.ProrationMethod {
+	method, err := h.queries.GetApartmentProrationMethod(ctx, apartmentID)
+	if err != nil {
+		log.Printf("[PRORATION] Could not load proration method for apartment %d, using actual days: %v", apartmentID, err)
+		return rent.ActualDays
+	}
+	return rent.ProrationMethod(method)
+}
+
+// leaseProration prorates the first and last month of a lease at the rent in effect in each month
+func leaseProration(rentAmount float64, escalation rent.Schedule, startDate, endDate time.Time, method rent.ProrationMethod) LeaseProration {
+	proration := LeaseProration{Method: method}
+	if p, ok := rent.MoveIn(rent.EffectiveRent(rentAmount, startDate, escalation, startDate), startDate, method); ok {
+		proration.MoveIn = &p
+	}
+	if p, ok := rent.MoveOut(rent.EffectiveRent(rentAmount, startDate, escalation, endDate), endDate, method); ok {
+		proration.MoveOut = &p
+	}
+	return proration
+}
+
+// CalculateProration works out the partial-month rent for a prospective or existing lease
+func (h *LeaseHandler) CalculateProration(w http.ResponseWriter, r *http.Request) {
+	var req ProrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid proration request", http.StatusBadRequest)
+		return
+	}
+	if req.RentAmount <= 0 {
+		http.Error(w, "Rent amount must be positive", http.StatusBadRequest)
+		return
+	}
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		http.Error(w, "Invalid start date", http.StatusBadRequest)
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil || !endDate.After(startDate) {
+		http.Error(w, "Invalid end date", http.StatusBadRequest)
+		return
+	}
+	escalation, err := leaseRentEscalation(req.RentEscalation)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	method, err := rent.ParseProrationMethod(req.Method)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Method == "" && req.ApartmentID != 0 {
+		method = h.apartmentProrationMethod(r.Context(), req.ApartmentID)
+	}
+
+	proration := leaseProration(req.RentAmount, escalation, startDate, endDate, method)
+	if req.TerminationDate != "" {
+		terminationDate, err := time.Parse("2006-01-02", req.TerminationDate)
+		if err != nil || terminationDate.Before(startDate) || terminationDate.After(endDate) {
+			http.Error(w, "Termination date must fall within the lease", http.StatusBadRequest)
+			return
+		}
+		monthly := rent.EffectiveRent(req.RentAmount, startDate, escalation, terminationDate)
+		if p, ok := rent.MoveOut(monthly, terminationDate, method); ok {
+			proration.Termination = &p
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(proration); err != nil {
+		log.Printf("[PRORATION] Error encoding response: %v", err)
+	}
+}
+
+// settleTerminationMonth bills only the days of the final month the tenant had the unit. If that month was
+// already charged in full the unused days are credited back; if it was not charged yet the prorated rent is.
+func (h *LeaseHandler) settleTerminationMonth(ctx context.Context, leaseID int64, terminatedOn time.Time, adminID int64) *rent.Proration {
+	lease, err := h.queries.GetLeaseByID(ctx, leaseID)
+	if err != nil {
+		log.Printf("[PRORATION] Could not load terminated lease %d: %v", leaseID, err)
+		return nil
+	}
+	if terminatedOn.Before(lease.LeaseStartDate.Time) {
+		return nil
+	}
+	if _, holdover := holdoverRentOn(lease, terminatedOn); terminatedOn.After(lease.LeaseEndDate.Time) && !holdover {
+		return nil
+	}
+
+	method := h.apartmentProrationMethod(ctx, lease.ApartmentID)
+	final, ok := rent.MoveOut(effectiveLeaseRent(lease, terminatedOn), terminatedOn, method)
+	if !ok {
+		return nil
+	}
+	periodStart := time.Date(terminatedOn.Year(), terminatedOn.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if lease.LeaseStartDate.Time.After(periodStart) {
+		// The lease started this month, so only the days it actually ran are owed
+		final = rent.Prorate(final.MonthlyRent, lease.LeaseStartDate.Time, terminatedOn, method)
+	}
+
+	entries, err := h.queries.ListLedgerEntriesByLease(ctx, leaseID)
+	if err != nil {
+		log.Printf("[PRORATION] Could not load ledger of lease %d: %v", leaseID, err)
+		return &final
+	}
+	for _, entry := range entries {
+		if entry.EntryType != db.LedgerEntryTypeCharge || !entry.PeriodStart.Valid || !entry.PeriodStart.Time.Equal(periodStart) {
+			continue
+		}
+		credit := utils.ConvertPgNumericToFloat(entry.Amount) - final.Amount
+		if credit <= 0 {
+			return &final
+		}
+		if _, err := h.queries.CreateLedgerEntry(ctx, db.CreateLedgerEntryParams{
+			LeaseID:     leaseID,
+			TenantID:    lease.TenantID,
+			EntryType:   db.LedgerEntryTypeCredit,
+			Amount:      utils.ConvertFloatToPgNumeric(credit),
+			Description: fmt.Sprintf("Early termination credit for %s (%d of %d days used)", periodStart.Format("January 2006"), final.Days, final.DaysInMonth),
+			EntryDate:   pgtype.Date{Time: terminatedOn, Valid: true},
+			CreatedBy:   pgtype.Int8{Int64: adminID, Valid: true},
+		}); err != nil {
+			log.Printf("[PRORATION] Failed crediting lease %d for early termination: %v", leaseID, err)
+		}
+		return &final
+	}
+
+	if _, err := h.queries.CreateRentCharge(ctx, db.CreateRentChargeParams{
+		LeaseID:     leaseID,
+		TenantID:    lease.TenantID,
+		Amount:      utils.ConvertFloatToPgNumeric(final.Amount),
+		Description: fmt.Sprintf("Prorated rent for %s (%d of %d days)", periodStart.Format("January 2006"), final.Days, final.DaysInMonth),
+		PeriodStart: pgtype.Date{Time: periodStart, Valid: true},
+		DueDate:     pgtype.Date{Time: periodStart, Valid: true},
+	}); err != nil {
+		log.Printf("[PRORATION] Failed charging final month of lease %d: %v", leaseID, err)
+	}
+	return &final
+}
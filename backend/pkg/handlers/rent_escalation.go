@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/rent"
+	"github.com/careecodes/RentDaddy/internal/utils"
+)
+
+// leaseRentEscalation validates the schedule sent with a lease request. A missing schedule keeps the
+// base rent for the whole lease.
+func leaseRentEscalation(schedule *rent.Schedule) (rent.Schedule, error) {
+	if schedule == nil {
+		return rent.Schedule{}, nil
+	}
+	if err := schedule.Validate(); err != nil {
+		return rent.Schedule{}, err
+	}
+	return *schedule, nil
+}
+
+// parseRentEscalation reads the schedule stored on a lease row
+func parseRentEscalation(leaseID int64, raw []byte) rent.Schedule {
+	var schedule rent.Schedule
+	if len(raw) == 0 {
+		return schedule
+	}
+	if err := json.Unmarshal(raw, &schedule); err != nil {
+		log.Printf("[RENT_ESCALATION] Lease %d has an unreadable escalation schedule: %v", leaseID, err)
+		return rent.Schedule{}
+	}
+	return schedule
+}
+
+// storedRentEscalation returns the schedule of a lease so a new version can carry it over
+func (h *LeaseHandler) storedRentEscalation(ctx context.Context, leaseID int64) *rent.Schedule {
+	lease, err := h.queries.GetLeaseByID(ctx, leaseID)
+	if err != nil {
+		log.Printf("[RENT_ESCALATION] Could not load lease %d: %v", leaseID, err)
+		return nil
+	}
+	schedule := parseRentEscalation(lease.ID, lease.RentEscalation)
+	if schedule.IsZero() {
+		return nil
+	}
+	return &schedule
+}
+
+// effectiveLeaseRent returns the monthly rent a lease charges on the given date, including month-to-month
+// holdover rent past the end date
+func effectiveLeaseRent(lease db.GetLeaseByIDRow, on time.Time) float64 {
+	if holdover, ok := holdoverRentOn(lease, on); ok {
+		return holdover
+	}
+	return rent.EffectiveRent(
+		utils.ConvertPgNumericToFloat(lease.RentAmount),
+		lease.LeaseStartDate.Time,
+		parseRentEscalation(lease.ID, lease.RentEscalation),
+		on,
+	)
+}
+
+// recordLeaseRentEscalation stores the schedule on a newly created lease
+func (h *LeaseHandler) recordLeaseRentEscalation(ctx context.Context, leaseID int64, schedule rent.Schedule) error {
+	if schedule.IsZero() {
+		return nil
+	}
+	raw, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed encoding rent escalation for lease %d: %w", leaseID, err)
+	}
+	if err := h.queries.SetLeaseRentEscalation(ctx, db.SetLeaseRentEscalationParams{
+		ID:             leaseID,
+		RentEscalation: raw,
+	}); err != nil {
+		return fmt.Errorf("failed recording rent escalation on lease %d: %w", leaseID, err)
+	}
+	return nil
+}
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/rent"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/careecodes/RentDaddy/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+/*
+
+Rent Ledger Summary:
+Every active lease is billed once per calendar month for the rent in effect that month,
+following the lease's escalation schedule when it has one. Months the lease only partly
+covers are prorated. Charges are generated by the cron job (or manually by an admin) and
+are idempotent per lease and month. Payments and credits are recorded against a lease and reduce the balance.
+
+*/
+
+// LedgerEntryRequest is the body for recording a payment, charge or credit on a lease
+type LedgerEntryRequest struct {
+	EntryType     string  `json:"entry_type,omitempty"` // charge, payment or credit
+	Amount        float64 `json:"amount"`
+	Date          string  `json:"date,omitempty"`     // Format: YYYY-MM-DD, defaults to today
+	DueDate       string  `json:"due_date,omitempty"` // Format: YYYY-MM-DD, charges only
+	PaymentMethod string  `json:"payment_method,omitempty"`
+	Reference     string  `json:"reference,omitempty"`
+	Description   string  `json:"description,omitempty"`
+}
+
+// LedgerResponse is a set of ledger entries together with the resulting balance
+type LedgerResponse struct {
+	LeaseID      int64                 `json:"lease_id,omitempty"`
+	TenantID     int64                 `json:"tenant_id"`
+	TotalCharges float64               `json:"total_charges"`
+	TotalPaid    float64               `json:"total_paid"`
+	Balance      float64               `json:"balance"`
+	Entries      []LedgerEntryResponse `json:"entries"`
+}
+
+// LedgerEntryResponse is a ledger entry with the balance owed once it was posted
+type LedgerEntryResponse struct {
+	db.RentLedgerEntry
+	Balance float64 `json:"balance"`
+}
+
+// TenantBalanceResponse is the outstanding balance of one tenant billed on the ledger
+type TenantBalanceResponse struct {
+	TenantID  int64  `json:"tenant_id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	rent.Totals
+}
+
+// postLedger posts entries in ledger order. Every balance is worked out here, so the ledger, the
+// balances list and the deposit statement cannot disagree.
+func postLedger(entries []db.RentLedgerEntry) (rent.Totals, []float64) {
+	postings := make([]rent.Posting, len(entries))
+	for i, e := range entries {
+		postings[i] = rent.Posting{Type: string(e.EntryType), Amount: utils.ConvertPgNumericToFloat(e.Amount)}
+	}
+	return rent.Post(postings)
+}
+
+// newLedgerResponse posts entries in ledger order and totals them
+func newLedgerResponse(leaseID, tenantID int64, entries []db.RentLedgerEntry) LedgerResponse {
+	totals, running := postLedger(entries)
+
+	resp := LedgerResponse{
+		LeaseID:      leaseID,
+		TenantID:     tenantID,
+		TotalCharges: totals.Charges,
+		TotalPaid:    totals.Paid,
+		Balance:      totals.Balance,
+		Entries:      make([]LedgerEntryResponse, len(entries)),
+	}
+	for i, e := range entries {
+		resp.Entries[i] = LedgerEntryResponse{RentLedgerEntry: e, Balance: running[i]}
+	}
+	return resp
+}
+
+// GetLeaseLedger returns every ledger entry for a lease with the running balance and the current balance
+func (h *LeaseHandler) GetLeaseLedger(w http.ResponseWriter, r *http.Request) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+
+	lease, err := h.queries.GetLeaseByID(r.Context(), leaseID)
+	if err != nil {
+		log.Printf("[LEDGER] Lease %d not found: %v", leaseID, err)
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
+
+	entries, err := h.queries.ListLedgerEntriesByLease(r.Context(), leaseID)
+	if err != nil {
+		log.Printf("[LEDGER] Failed listing entries for lease %d: %v", leaseID, err)
+		http.Error(w, "Failed to fetch ledger", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newLedgerResponse(leaseID, lease.TenantID, entries))
+}
+
+// RecordLeasePayment records a payment received from the tenant of a lease
+func (h *LeaseHandler) RecordLeasePayment(w http.ResponseWriter, r *http.Request) {
+	var req LedgerEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.EntryType = string(db.LedgerEntryTypePayment)
+	if req.Description == "" {
+		req.Description = "Payment received"
+	}
+
+	h.createLedgerEntry(w, r, req)
+}
+
+// CreateLeaseLedgerEntry records a manual charge or credit against a lease
+func (h *LeaseHandler) CreateLeaseLedgerEntry(w http.ResponseWriter, r *http.Request) {
+	var req LedgerEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	switch db.LedgerEntryType(req.EntryType) {
+	case db.LedgerEntryTypeCharge, db.LedgerEntryTypeCredit, db.LedgerEntryTypePayment:
+	default:
+		http.Error(w, "entry_type must be one of charge, payment or credit", http.StatusBadRequest)
+		return
+	}
+	if req.Description == "" {
+		http.Error(w, "description is required", http.StatusBadRequest)
+		return
+	}
+
+	h.createLedgerEntry(w, r, req)
+}
+
+func (h *LeaseHandler) createLedgerEntry(w http.ResponseWriter, r *http.Request, req LedgerEntryRequest) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+
+	if req.Amount <= 0 {
+		http.Error(w, "amount must be greater than zero", http.StatusBadRequest)
+		return
+	}
+
+	entryDate := time.Now().UTC()
+	if req.Date != "" {
+		entryDate, err = time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			http.Error(w, "Invalid date format, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var dueDate pgtype.Date
+	if req.DueDate != "" {
+		parsed, err := time.Parse("2006-01-02", req.DueDate)
+		if err != nil {
+			http.Error(w, "Invalid due_date format, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		dueDate = pgtype.Date{Time: parsed, Valid: true}
+	}
+
+	lease, err := h.queries.GetLeaseByID(r.Context(), leaseID)
+	if err != nil {
+		log.Printf("[LEDGER] Lease %d not found: %v", leaseID, err)
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
+
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	entry, err := h.queries.CreateLedgerEntry(r.Context(), db.CreateLedgerEntryParams{
+		LeaseID:       leaseID,
+		TenantID:      lease.TenantID,
+		EntryType:     db.LedgerEntryType(req.EntryType),
+		Amount:        utils.ConvertFloatToPgNumeric(req.Amount),
+		Description:   req.Description,
+		DueDate:       dueDate,
+		EntryDate:     pgtype.Date{Time: entryDate, Valid: true},
+		PaymentMethod: pgtype.Text{String: req.PaymentMethod, Valid: req.PaymentMethod != ""},
+		Reference:     pgtype.Text{String: req.Reference, Valid: req.Reference != ""},
+		CreatedBy:     pgtype.Int8{Int64: adminID, Valid: true},
+	})
+	if err != nil {
+		log.Printf("[LEDGER] Failed recording %s for lease %d: %v", req.EntryType, leaseID, err)
+		http.Error(w, "Failed to record ledger entry", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[LEDGER] Recorded %s of %.2f on lease %d", req.EntryType, req.Amount, leaseID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// GetLedgerBalances returns the outstanding balance of every tenant with ledger activity, largest first
+func (h *LeaseHandler) GetLedgerBalances(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.queries.ListTenantLedgerPostings(r.Context())
+	if err != nil {
+		log.Printf("[LEDGER] Failed listing tenant balances: %v", err)
+		http.Error(w, "Failed to fetch balances", http.StatusInternalServerError)
+		return
+	}
+
+	// Rows come grouped by tenant in ledger order
+	balances := []TenantBalanceResponse{}
+	for start := 0; start < len(rows); {
+		end := start
+		var postings []rent.Posting
+		for ; end < len(rows) && rows[end].TenantID == rows[start].TenantID; end++ {
+			postings = append(postings, rent.Posting{Type: string(rows[end].EntryType), Amount: utils.ConvertPgNumericToFloat(rows[end].Amount)})
+		}
+		totals, _ := rent.Post(postings)
+		balances = append(balances, TenantBalanceResponse{
+			TenantID:  rows[start].TenantID,
+			FirstName: rows[start].FirstName,
+			LastName:  rows[start].LastName,
+			Email:     rows[start].Email,
+			Totals:    totals,
+		})
+		start = end
+	}
+	sort.SliceStable(balances, func(i, j int) bool { return balances[i].Balance > balances[j].Balance })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(balances)
+}
+
+// GenerateRentCharges bills every active lease for the month given by ?month=YYYY-MM
+// (the current month by default). Safe to call repeatedly.
+func (h *LeaseHandler) GenerateRentCharges(w http.ResponseWriter, r *http.Request) {
+	period := time.Now().UTC()
+	if month := r.URL.Query().Get("month"); month != "" {
+		parsed, err := time.Parse("2006-01", month)
+		if err != nil {
+			http.Error(w, "Invalid month format, expected YYYY-MM", http.StatusBadRequest)
+			return
+		}
+		period = parsed
+	}
+
+	created, err := h.generateRentCharges(r.Context(), period)
+	if err != nil {
+		log.Printf("[LEDGER] Failed generating rent charges: %v", err)
+		http.Error(w, "Failed to generate rent charges", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"period":          period.Format("2006-01"),
+		"charges_created": created,
+	})
+}
+
+func (h *LeaseHandler) generateRentCharges(ctx context.Context, period time.Time) (int64, error) {
+	periodStart := time.Date(period.Year(), period.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, -1)
+
+	leases, err := h.queries.ListBillableLeases(ctx, db.ListBillableLeasesParams{
+		LeaseStartDate: pgtype.Date{Time: periodEnd, Valid: true},
+		LeaseEndDate:   pgtype.Date{Time: periodStart, Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("listing billable leases: %w", err)
+	}
+
+	var created int64
+	for _, lease := range leases {
+		// Month-to-month leases bill the full holdover rent for every month after the end date
+		if lease.Status == db.LeaseStatusMonthToMonth && lease.LeaseEndDate.Time.Before(periodStart) {
+			rows, err := h.queries.CreateRentCharge(ctx, db.CreateRentChargeParams{
+				LeaseID:     lease.ID,
+				TenantID:    lease.TenantID,
+				Amount:      lease.HoldoverRent,
+				Description: fmt.Sprintf("Month-to-month rent for %s", periodStart.Format("January 2006")),
+				PeriodStart: pgtype.Date{Time: periodStart, Valid: true},
+				DueDate:     pgtype.Date{Time: periodStart, Valid: true},
+			})
+			if err != nil {
+				log.Printf("[LEDGER] Failed creating month-to-month charge for lease %d: %v", lease.ID, err)
+				continue
+			}
+			created += rows
+			continue
+		}
+
+		// Bill the rent in effect on the first day of the period the lease covers
+		billedFrom, billedTo := periodStart, periodEnd
+		if lease.LeaseStartDate.Time.After(billedFrom) {
+			billedFrom = lease.LeaseStartDate.Time
+		}
+		if lease.LeaseEndDate.Time.Before(billedTo) {
+			billedTo = lease.LeaseEndDate.Time
+		}
+		amount := rent.EffectiveRent(
+			utils.ConvertPgNumericToFloat(lease.RentAmount),
+			lease.LeaseStartDate.Time,
+			parseRentEscalation(lease.ID, lease.RentEscalation),
+			billedFrom,
+		)
+		description := fmt.Sprintf("Rent for %s", periodStart.Format("January 2006"))
+
+		// Move-in and move-out months are prorated with the building's daily-rate method
+		if !billedFrom.Equal(periodStart) || !billedTo.Equal(periodEnd) {
+			p := rent.Prorate(amount, billedFrom, billedTo, rent.ProrationMethod(lease.ProrationMethod))
+			amount = p.Amount
+			description = fmt.Sprintf("Prorated rent for %s (%d of %d days)", periodStart.Format("January 2006"), p.Days, p.DaysInMonth)
+		}
+
+		rows, err := h.queries.CreateRentCharge(ctx, db.CreateRentChargeParams{
+			LeaseID:     lease.ID,
+			TenantID:    lease.TenantID,
+			Amount:      utils.ConvertFloatToPgNumeric(amount),
+			Description: description,
+			PeriodStart: pgtype.Date{Time: periodStart, Valid: true},
+			DueDate:     pgtype.Date{Time: periodStart, Valid: true},
+		})
+		if err != nil {
+			log.Printf("[LEDGER] Failed creating rent charge for lease %d: %v", lease.ID, err)
+			continue
+		}
+		created += rows
+	}
+
+	log.Printf("[LEDGER] Created %d rent charges for %s (%d billable leases)", created, periodStart.Format("2006-01"), len(leases))
+	return created, nil
+}
+
+// TenantGetLedger returns the ledger entries of every lease the signed-in tenant is on, whether they are
+// the primary tenant billed or a co-occupant
+func (h *LeaseHandler) TenantGetLedger(w http.ResponseWriter, r *http.Request) {
+	tenantCtx := middleware.GetUserCtx(r)
+	if tenantCtx == nil {
+		log.Printf("[LEDGER] Failed no tenant context")
+		http.Error(w, "Error no tenant context", http.StatusUnauthorized)
+		return
+	}
+
+	var tenantMetadata ClerkUserPublicMetaData
+	if err := json.Unmarshal(tenantCtx.PublicMetadata, &tenantMetadata); err != nil {
+		log.Printf("[LEDGER] Failed parsing JSON: %v", err)
+		http.Error(w, "Error parsing JSON", http.StatusInternalServerError)
+		return
+	}
+	tenantID := int64(tenantMetadata.DbId)
+
+	entries, err := h.queries.ListLedgerEntriesByLeaseMember(r.Context(), tenantID)
+	if err != nil {
+		log.Printf("[LEDGER] Failed listing entries for tenant %d: %v", tenantID, err)
+		http.Error(w, "Failed to fetch ledger", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newLedgerResponse(0, tenantID, entries))
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/careecodes/RentDaddy/middleware"
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+// ledgerPosting is one entry of the balances listing for tenant
+func ledgerPosting(tenant db.GetUserByIDRow, entryType db.LedgerEntryType, amount float64) db.ListTenantLedgerPostingsRow {
+	return db.ListTenantLedgerPostingsRow{
+		TenantID:  tenant.ID,
+		FirstName: tenant.FirstName,
+		LastName:  tenant.LastName,
+		Email:     tenant.Email,
+		EntryType: entryType,
+		Amount:    utils.ConvertFloatToPgNumeric(amount),
+	}
+}
+
+func TestGetLedgerBalancesTotalsEachTenantInCents(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	owing := db.GetUserByIDRow{ID: 5, FirstName: "Olive", LastName: "Owing", Email: "olive@example.com"}
+	paidUp := db.GetUserByIDRow{ID: 7, FirstName: "Paul", LastName: "Paid", Email: "paul@example.com"}
+	fdb.returns("ListTenantLedgerPostings", []db.ListTenantLedgerPostingsRow{
+		ledgerPosting(owing, db.LedgerEntryTypeCharge, 1000.10),
+		ledgerPosting(owing, db.LedgerEntryTypePayment, 0.1),
+		ledgerPosting(owing, db.LedgerEntryTypePayment, 0.2),
+		ledgerPosting(paidUp, db.LedgerEntryTypeCharge, 1200),
+		ledgerPosting(paidUp, db.LedgerEntryTypePayment, 1200),
+	})
+
+	rec := httptest.NewRecorder()
+	h.GetLedgerBalances(rec, newTestRequest(t, http.MethodGet, "/admin/ledger/balances", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var got []TenantBalanceResponse
+	decodeResponse(t, rec, &got)
+	if len(got) != 2 {
+		t.Fatalf("balances = %+v, want one per tenant", got)
+	}
+	if got[0].TenantID != owing.ID || got[0].Balance != 999.80 || got[0].Paid != 0.30 {
+		t.Errorf("first balance = %+v, want tenant 5 owing 999.80 after paying 0.30", got[0])
+	}
+	if got[1].TenantID != paidUp.ID || got[1].Balance != 0 {
+		t.Errorf("second balance = %+v, want tenant 7 paid up", got[1])
+	}
+}
+
+func TestTenantGetLedgerIncludesCoOccupantLeases(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.returns("ListLedgerEntriesByLeaseMember", []db.RentLedgerEntry{
+		{ID: 1, LeaseID: 9, TenantID: testTenant.ID, EntryType: db.LedgerEntryTypeCharge, Amount: utils.ConvertFloatToPgNumeric(1500)},
+		{ID: 2, LeaseID: 9, TenantID: testTenant.ID, EntryType: db.LedgerEntryTypePayment, Amount: utils.ConvertFloatToPgNumeric(500)},
+	})
+
+	// The co-occupant is on lease 9, which bills the primary tenant
+	const coOccupantID = 12
+	metadata, _ := json.Marshal(ClerkUserPublicMetaData{DbId: coOccupantID, Role: db.RoleTenant})
+	r := newTestRequest(t, http.MethodGet, "/tenant/ledger", nil)
+	r = r.WithContext(context.WithValue(r.Context(), middleware.UserKey, &clerk.User{ID: "user_cooccupant", PublicMetadata: metadata}))
+
+	rec := httptest.NewRecorder()
+	h.TenantGetLedger(rec, r)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if calls := fdb.called("ListLedgerEntriesByLeaseMember"); len(calls) != 1 || calls[0].Args[0] != int64(coOccupantID) {
+		t.Errorf("ListLedgerEntriesByLeaseMember calls = %+v, want the leases of tenant %d", calls, coOccupantID)
+	}
+	var got LedgerResponse
+	decodeResponse(t, rec, &got)
+	if len(got.Entries) != 2 || got.Balance != 1000 {
+		t.Errorf("ledger = %+v, want both entries of lease 9 and 1000 owing", got)
+	}
+}
@@ -0,0 +1,915 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/smtp"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/invitation"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// maxApplicationDocuments caps the files sent with one application
+	maxApplicationDocuments = 10
+	// maxApplicationBodyBytes caps a whole application, documents included. The endpoint is public, so this
+	// is all an anonymous caller can make the server hold.
+	maxApplicationBodyBytes = 10 << 20
+)
+
+// applicationDocumentTypes are the files an application may carry, recognised by their content rather than
+// the name or type the browser sent
+var applicationDocumentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+// RentalApplicationRequest is what a prospect sends to apply for a unit. Sent as multipart, it is the JSON
+// "application" field and the files are "documents".
+type RentalApplicationRequest struct {
+	FirstName     string                        `json:"first_name"`
+	LastName      string                        `json:"last_name"`
+	Email         string                        `json:"email"`
+	Phone         string                        `json:"phone"`
+	DesiredMoveIn string                        `json:"desired_move_in"` // Format: YYYY-MM-DD
+	MonthlyIncome float64                       `json:"monthly_income"`
+	Employer      string                        `json:"employer"`
+	Message       string                        `json:"message"`
+	Members       []ApplicationMemberRequest    `json:"members,omitempty"`    // Everyone else who would live in the unit
+	References    []ApplicationReferenceRequest `json:"references,omitempty"` // Previous landlords, employers
+}
+
+type ApplicationMemberRequest struct {
+	Name          string  `json:"name"`
+	Relationship  string  `json:"relationship"`
+	IsAdult       bool    `json:"is_adult"`
+	MonthlyIncome float64 `json:"monthly_income"`
+}
+
+type ApplicationReferenceRequest struct {
+	Name         string `json:"name"`
+	Relationship string `json:"relationship"`
+	Phone        string `json:"phone"`
+	Email        string `json:"email"`
+}
+
+type ApplicationDocumentResponse struct {
+	ID          int64     `json:"id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RentalApplicationResponse is an application in the review queue. Members, references, documents and the
+// latest screening are only filled in when a single application is fetched.
+type RentalApplicationResponse struct {
+	ID              int64                         `json:"id"`
+	ApartmentID     int64                         `json:"apartment_id"`
+	Status          string                        `json:"status"`
+	FirstName       string                        `json:"first_name"`
+	LastName        string                        `json:"last_name"`
+	Email           string                        `json:"email"`
+	Phone           string                        `json:"phone"`
+	DesiredMoveIn   string                        `json:"desired_move_in,omitempty"`
+	MonthlyIncome   float64                       `json:"monthly_income"`
+	Employer        string                        `json:"employer"`
+	Message         string                        `json:"message"`
+	DecisionReason  string                        `json:"decision_reason,omitempty"`
+	Conditions      string                        `json:"conditions,omitempty"`
+	DecidedBy       *int64                        `json:"decided_by,omitempty"`
+	DecidedAt       string                        `json:"decided_at,omitempty"`
+	TenantID        *int64                        `json:"tenant_id,omitempty"`
+	LeaseID         *int64                        `json:"lease_id,omitempty"`
+	CreatedAt       string                        `json:"created_at"`
+	HouseholdIncome float64                       `json:"household_income,omitempty"` // Applicant and members together
+	Members         []ApplicationMemberRequest    `json:"members,omitempty"`
+	References      []ApplicationReferenceRequest `json:"references,omitempty"`
+	Documents       []ApplicationDocumentResponse `json:"documents,omitempty"`
+	Screening       *ScreeningResponse            `json:"screening,omitempty"`
+}
+
+// RentalApplicationDecisionRequest decides an application. Approving invites the applicant and creates a
+// draft lease with the given terms; conditional approval needs the conditions the applicant must still meet.
+type RentalApplicationDecisionRequest struct {
+	Decision   string                 `json:"decision"` // approve, conditional or deny
+	Reason     string                 `json:"reason"`
+	Conditions string                 `json:"conditions"`
+	Lease      *ApplicationLeaseTerms `json:"lease,omitempty"`
+}
+
+// ApplicationLeaseTerms are the terms of the draft lease created on approval. The lease starts on the desired
+// move-in date (or today), runs a year and charges the unit's price unless given otherwise.
+type ApplicationLeaseTerms struct {
+	StartDate    string          `json:"start_date"`
+	EndDate      string          `json:"end_date"`
+	RentAmount   float64         `json:"rent_amount"`
+	TemplateID   int64           `json:"template_id,omitempty"`
+	TemplateName string          `json:"template_name,omitempty"`
+	AddendumIDs  []int64         `json:"addendum_ids,omitempty"`
+	Deposit      *DepositRequest `json:"deposit,omitempty"`
+}
+
+func toRentalApplicationResponse(a db.RentalApplication) RentalApplicationResponse {
+	resp := RentalApplicationResponse{
+		ID:             a.ID,
+		ApartmentID:    a.ApartmentID,
+		Status:         string(a.Status),
+		FirstName:      a.FirstName,
+		LastName:       a.LastName,
+		Email:          a.Email,
+		Phone:          a.Phone,
+		MonthlyIncome:  utils.ConvertPgNumericToFloat(a.MonthlyIncome),
+		Employer:       a.Employer,
+		Message:        a.Message,
+		DecisionReason: a.DecisionReason,
+		Conditions:     a.Conditions,
+		CreatedAt:      a.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+	}
+	if a.DesiredMoveIn.Valid {
+		resp.DesiredMoveIn = a.DesiredMoveIn.Time.Format("2006-01-02")
+	}
+	if a.DecidedAt.Valid {
+		resp.DecidedAt = a.DecidedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	for _, ref := range []struct {
+		src pgtype.Int8
+		dst **int64
+	}{{a.DecidedBy, &resp.DecidedBy}, {a.TenantID, &resp.TenantID}, {a.LeaseID, &resp.LeaseID}} {
+		if ref.src.Valid {
+			id := ref.src.Int64
+			*ref.dst = &id
+		}
+	}
+	return resp
+}
+
+// validateRentalApplication trims the application and checks what the review needs
+func validateRentalApplication(req *RentalApplicationRequest) error {
+	req.FirstName = strings.TrimSpace(req.FirstName)
+	req.LastName = strings.TrimSpace(req.LastName)
+	req.Email = strings.TrimSpace(req.Email)
+	req.Phone = strings.TrimSpace(req.Phone)
+	if req.FirstName == "" || req.LastName == "" {
+		return errors.New("first_name and last_name are required")
+	}
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		return errors.New("a valid email is required")
+	}
+	if req.DesiredMoveIn != "" {
+		if _, err := time.Parse("2006-01-02", req.DesiredMoveIn); err != nil {
+			return errors.New("desired_move_in must be a date like 2006-01-02")
+		}
+	}
+	if req.MonthlyIncome < 0 {
+		return errors.New("monthly_income cannot be negative")
+	}
+	for i := range req.Members {
+		m := &req.Members[i]
+		m.Name = strings.TrimSpace(m.Name)
+		if m.Name == "" {
+			return fmt.Errorf("member %d needs a name", i+1)
+		}
+		if m.MonthlyIncome < 0 {
+			return fmt.Errorf("member %s cannot have a negative monthly_income", m.Name)
+		}
+	}
+	for i := range req.References {
+		ref := &req.References[i]
+		ref.Name = strings.TrimSpace(ref.Name)
+		if ref.Name == "" {
+			return fmt.Errorf("reference %d needs a name", i+1)
+		}
+		if ref.Phone == "" && ref.Email == "" {
+			return fmt.Errorf("reference %s needs a phone or email", ref.Name)
+		}
+	}
+	return nil
+}
+
+// apartmentAvailable reports whether the apartment is listed as available and has no current lease
+func (h *LeaseHandler) apartmentAvailable(ctx context.Context, apartmentID int64) (bool, error) {
+	apartments, err := h.queries.GetApartmentsWithoutLease(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range apartments {
+		if a.ID == apartmentID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// readApplicationParts reads a multipart application one part at a time as it arrives, so nothing is spooled
+// to disk. Files must be a PDF, JPEG or PNG.
+func readApplicationParts(mr *multipart.Reader) (RentalApplicationRequest, []applicationUpload, error) {
+	var req RentalApplicationRequest
+	var uploads []applicationUpload
+	var haveApplication bool
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return req, nil, fmt.Errorf("invalid upload: %w", err)
+		}
+		switch part.FormName() {
+		case "application":
+			if err := json.NewDecoder(part).Decode(&req); err != nil {
+				return req, nil, fmt.Errorf("invalid application: %w", err)
+			}
+			haveApplication = true
+		case "documents":
+			if len(uploads) == maxApplicationDocuments {
+				return req, nil, fmt.Errorf("at most %d documents can be uploaded", maxApplicationDocuments)
+			}
+			u, err := readApplicationDocument(part)
+			if err != nil {
+				return req, nil, err
+			}
+			uploads = append(uploads, u)
+		}
+		part.Close()
+	}
+	if !haveApplication {
+		return req, nil, errors.New("invalid application")
+	}
+	return req, uploads, nil
+}
+
+// readApplicationDocument reads one uploaded file and checks what it really is
+func readApplicationDocument(part *multipart.Part) (applicationUpload, error) {
+	fileName := sanitizeFileName(part.FileName())
+	data, err := io.ReadAll(part)
+	if err != nil {
+		return applicationUpload{}, fmt.Errorf("could not read %s: %w", fileName, err)
+	}
+	if len(data) == 0 {
+		return applicationUpload{}, fmt.Errorf("%s is empty", fileName)
+	}
+	contentType := http.DetectContentType(data)
+	if !applicationDocumentTypes[contentType] {
+		return applicationUpload{}, fmt.Errorf("%s must be a PDF, JPEG or PNG", fileName)
+	}
+	return applicationUpload{fileName: fileName, contentType: contentType, data: data}, nil
+}
+
+type applicationUpload struct {
+	fileName    string
+	contentType string
+	data        []byte
+	storageKey  string
+}
+
+// errStoringApplicationDocuments is returned by saveRentalApplication when the uploaded files could not be stored
+var errStoringApplicationDocuments = errors.New("failed to store application documents")
+
+// readRentalApplicationRequest reads an application sent as JSON, or as a multipart form with the application
+// JSON under "application" and the files under "documents". Invalid requests are answered here.
+func readRentalApplicationRequest(w http.ResponseWriter, r *http.Request) (RentalApplicationRequest, []applicationUpload, bool) {
+	var req RentalApplicationRequest
+	var uploads []applicationUpload
+	r.Body = http.MaxBytesReader(w, r.Body, maxApplicationBodyBytes)
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		var mr *multipart.Reader
+		if mr, err = r.MultipartReader(); err == nil {
+			req, uploads, err = readApplicationParts(mr)
+		}
+	} else if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		err = fmt.Errorf("invalid application: %w", err)
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, fmt.Sprintf("Applications are limited to %d MB including documents", maxApplicationBodyBytes>>20), http.StatusRequestEntityTooLarge)
+		return req, nil, false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return req, nil, false
+	}
+	if err := validateRentalApplication(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return req, nil, false
+	}
+	return req, uploads, true
+}
+
+// checkCanApply reports whether the apartment takes applications from email. When it does not, because it is
+// no longer available or the applicant already has an application under review for it, the request is answered.
+func (h *LeaseHandler) checkCanApply(ctx context.Context, w http.ResponseWriter, apartmentID int64, email string) bool {
+	available, err := h.apartmentAvailable(ctx, apartmentID)
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed checking availability of apartment %d: %v", apartmentID, err)
+		http.Error(w, "Failed to check the apartment", http.StatusInternalServerError)
+		return false
+	}
+	if !available {
+		http.Error(w, "This apartment is not available", http.StatusNotFound)
+		return false
+	}
+	open, err := h.queries.CountOpenRentalApplications(ctx, db.CountOpenRentalApplicationsParams{
+		ApartmentID: apartmentID,
+		Email:       email,
+	})
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed checking open applications for apartment %d: %v", apartmentID, err)
+		http.Error(w, "Failed to save application", http.StatusInternalServerError)
+		return false
+	}
+	if open > 0 {
+		http.Error(w, "An application from this email is already being reviewed for this apartment", http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// saveRentalApplication stores the uploaded files, then writes the application with its household, references
+// and documents in one transaction together with whatever also writes. The files are removed again if that fails.
+func (h *LeaseHandler) saveRentalApplication(ctx context.Context, apartmentID int64, req RentalApplicationRequest, uploads []applicationUpload, also func(tx *LeaseHandler, app db.RentalApplication) error) (db.RentalApplication, error) {
+	removeUploads := func() {
+		for _, u := range uploads {
+			if u.storageKey == "" {
+				continue
+			}
+			if err := h.documents.Delete(ctx, u.storageKey); err != nil {
+				log.Printf("[APPLICATIONS] Failed removing orphaned upload %s: %v", u.storageKey, err)
+			}
+		}
+	}
+	for i := range uploads {
+		token := make([]byte, 8)
+		if _, err := rand.Read(token); err != nil {
+			removeUploads()
+			return db.RentalApplication{}, fmt.Errorf("%w: %v", errStoringApplicationDocuments, err)
+		}
+		key := fmt.Sprintf("applications/%s/%s", hex.EncodeToString(token), uploads[i].fileName)
+		if err := h.documents.Put(ctx, key, uploads[i].data, uploads[i].contentType); err != nil {
+			removeUploads()
+			return db.RentalApplication{}, fmt.Errorf("%w: %s: %v", errStoringApplicationDocuments, key, err)
+		}
+		uploads[i].storageKey = key
+	}
+
+	var app db.RentalApplication
+	err := h.inTx(ctx, func(tx *LeaseHandler) error {
+		var err error
+		app, err = tx.queries.CreateRentalApplication(ctx, db.CreateRentalApplicationParams{
+			ApartmentID:   apartmentID,
+			FirstName:     req.FirstName,
+			LastName:      req.LastName,
+			Email:         req.Email,
+			Phone:         req.Phone,
+			DesiredMoveIn: dateOrNull(req.DesiredMoveIn),
+			MonthlyIncome: utils.ConvertFloatToPgNumeric(req.MonthlyIncome),
+			Employer:      strings.TrimSpace(req.Employer),
+			Message:       strings.TrimSpace(req.Message),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert application: %w", err)
+		}
+		for _, m := range req.Members {
+			if err := tx.queries.CreateRentalApplicationMember(ctx, db.CreateRentalApplicationMemberParams{
+				ApplicationID: app.ID,
+				Name:          m.Name,
+				Relationship:  strings.TrimSpace(m.Relationship),
+				IsAdult:       m.IsAdult,
+				MonthlyIncome: utils.ConvertFloatToPgNumeric(m.MonthlyIncome),
+			}); err != nil {
+				return fmt.Errorf("failed to insert member %s: %w", m.Name, err)
+			}
+		}
+		for _, ref := range req.References {
+			if err := tx.queries.CreateRentalApplicationReference(ctx, db.CreateRentalApplicationReferenceParams{
+				ApplicationID: app.ID,
+				Name:          ref.Name,
+				Relationship:  strings.TrimSpace(ref.Relationship),
+				Phone:         strings.TrimSpace(ref.Phone),
+				Email:         strings.TrimSpace(ref.Email),
+			}); err != nil {
+				return fmt.Errorf("failed to insert reference %s: %w", ref.Name, err)
+			}
+		}
+		for _, u := range uploads {
+			if _, err := tx.queries.CreateRentalApplicationDocument(ctx, db.CreateRentalApplicationDocumentParams{
+				ApplicationID: app.ID,
+				FileName:      u.fileName,
+				ContentType:   u.contentType,
+				SizeBytes:     int64(len(u.data)),
+				StorageKey:    u.storageKey,
+			}); err != nil {
+				return fmt.Errorf("failed to insert document %s: %w", u.fileName, err)
+			}
+		}
+		if also != nil {
+			return also(tx, app)
+		}
+		return nil
+	})
+	if err != nil {
+		removeUploads()
+		return db.RentalApplication{}, err
+	}
+	return app, nil
+}
+
+// SubmitRentalApplication takes an application for an available unit. It is public; prospects have no account yet.
+func (h *LeaseHandler) SubmitRentalApplication(w http.ResponseWriter, r *http.Request) {
+	apartmentID, err := strconv.ParseInt(chi.URLParam(r, "apartmentID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid apartment ID", http.StatusBadRequest)
+		return
+	}
+	req, uploads, ok := readRentalApplicationRequest(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	if !h.checkCanApply(ctx, w, apartmentID, req.Email) {
+		return
+	}
+
+	app, err := h.saveRentalApplication(ctx, apartmentID, req, uploads, nil)
+	if errors.Is(err, errStoringApplicationDocuments) {
+		log.Printf("[APPLICATIONS] %v", err)
+		http.Error(w, "Failed to store documents", http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed saving application for apartment %d: %v", apartmentID, err)
+		http.Error(w, "Failed to save application", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[APPLICATIONS] Application %d for apartment %d received with %d document(s)", app.ID, apartmentID, len(uploads))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"application_id": app.ID,
+		"status":         app.Status,
+	}); err != nil {
+		log.Printf("[APPLICATIONS] Error encoding response: %v", err)
+	}
+}
+
+func dateOrNull(s string) pgtype.Date {
+	date, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return pgtype.Date{}
+	}
+	return pgtype.Date{Time: date, Valid: true}
+}
+
+// ListRentalApplications is the review queue, oldest first, filtered by status and apartment_id
+func (h *LeaseHandler) ListRentalApplications(w http.ResponseWriter, r *http.Request) {
+	var params db.ListRentalApplicationsParams
+	query := r.URL.Query()
+	if s := query.Get("status"); s != "" {
+		switch status := db.ApplicationStatus(s); status {
+		case db.ApplicationStatusSubmitted, db.ApplicationStatusConditional, db.ApplicationStatusApproved, db.ApplicationStatusDenied:
+			params.Status = db.NullApplicationStatus{ApplicationStatus: status, Valid: true}
+		default:
+			http.Error(w, fmt.Sprintf("unknown application status %q", s), http.StatusBadRequest)
+			return
+		}
+	}
+	var err error
+	if params.ApartmentID, err = queryInt8(query, "apartment_id"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	apps, err := h.queries.ListRentalApplications(r.Context(), params)
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed listing applications: %v", err)
+		http.Error(w, "Failed to fetch applications", http.StatusInternalServerError)
+		return
+	}
+	resp := make([]RentalApplicationResponse, 0, len(apps))
+	for _, a := range apps {
+		resp = append(resp, toRentalApplicationResponse(a))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[APPLICATIONS] Error encoding response: %v", err)
+	}
+}
+
+// loadRentalApplication writes the error response itself when the application does not exist
+func (h *LeaseHandler) loadRentalApplication(w http.ResponseWriter, r *http.Request) (db.RentalApplication, bool) {
+	applicationID, err := strconv.ParseInt(chi.URLParam(r, "applicationID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid application ID", http.StatusBadRequest)
+		return db.RentalApplication{}, false
+	}
+	app, err := h.queries.GetRentalApplication(r.Context(), applicationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Application not found", http.StatusNotFound)
+		return db.RentalApplication{}, false
+	}
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed loading application %d: %v", applicationID, err)
+		http.Error(w, "Failed to fetch application", http.StatusInternalServerError)
+		return db.RentalApplication{}, false
+	}
+	return app, true
+}
+
+// GetRentalApplication returns an application with its household, references, documents and latest screening
+func (h *LeaseHandler) GetRentalApplication(w http.ResponseWriter, r *http.Request) {
+	app, ok := h.loadRentalApplication(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	resp := toRentalApplicationResponse(app)
+	resp.HouseholdIncome = resp.MonthlyIncome
+
+	members, err := h.queries.ListRentalApplicationMembers(ctx, app.ID)
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed listing members of application %d: %v", app.ID, err)
+		http.Error(w, "Failed to fetch application", http.StatusInternalServerError)
+		return
+	}
+	for _, m := range members {
+		income := utils.ConvertPgNumericToFloat(m.MonthlyIncome)
+		resp.HouseholdIncome += income
+		resp.Members = append(resp.Members, ApplicationMemberRequest{Name: m.Name, Relationship: m.Relationship, IsAdult: m.IsAdult, MonthlyIncome: income})
+	}
+	references, err := h.queries.ListRentalApplicationReferences(ctx, app.ID)
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed listing references of application %d: %v", app.ID, err)
+		http.Error(w, "Failed to fetch application", http.StatusInternalServerError)
+		return
+	}
+	for _, ref := range references {
+		resp.References = append(resp.References, ApplicationReferenceRequest{Name: ref.Name, Relationship: ref.Relationship, Phone: ref.Phone, Email: ref.Email})
+	}
+	docs, err := h.queries.ListRentalApplicationDocuments(ctx, app.ID)
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed listing documents of application %d: %v", app.ID, err)
+		http.Error(w, "Failed to fetch application", http.StatusInternalServerError)
+		return
+	}
+	for _, d := range docs {
+		resp.Documents = append(resp.Documents, ApplicationDocumentResponse{
+			ID:          d.ID,
+			FileName:    d.FileName,
+			ContentType: d.ContentType,
+			SizeBytes:   d.SizeBytes,
+			CreatedAt:   d.CreatedAt.Time,
+		})
+	}
+	screened, err := h.latestScreening(ctx, app.ID)
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed loading screening of application %d: %v", app.ID, err)
+		http.Error(w, "Failed to fetch application", http.StatusInternalServerError)
+		return
+	}
+	if screened != nil {
+		latest := toScreeningResponse(*screened)
+		resp.Screening = &latest
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[APPLICATIONS] Error encoding response: %v", err)
+	}
+}
+
+// GetRentalApplicationDocumentURL hands an admin a short-lived link to a file sent with an application
+func (h *LeaseHandler) GetRentalApplicationDocumentURL(w http.ResponseWriter, r *http.Request) {
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	app, ok := h.loadRentalApplication(w, r)
+	if !ok {
+		return
+	}
+	documentID, err := documentIDParam(r)
+	if err != nil {
+		http.Error(w, "Invalid document ID", http.StatusBadRequest)
+		return
+	}
+	doc, err := h.queries.GetRentalApplicationDocument(r.Context(), db.GetRentalApplicationDocumentParams{
+		ID:            documentID,
+		ApplicationID: app.ID,
+	})
+	if err != nil {
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return
+	}
+
+	log.Printf("[APPLICATIONS] Admin %d opened document %d of application %d", adminID, doc.ID, app.ID)
+	link, expires := h.documentURLs.URL(doc.StorageKey, documentURLTTL, time.Now())
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(DocumentURLResponse{DocumentID: doc.ID, URL: link, ExpiresAt: expires}); err != nil {
+		log.Printf("[APPLICATIONS] Error encoding response: %v", err)
+	}
+}
+
+// DecideRentalApplication approves, conditionally approves or denies an application under review. The
+// applicant is emailed the decision. Approval invites them and creates their draft lease, and answers with
+// that lease. The decision records the latest screening; with a screening provider configured, approval
+// needs one that ran every check, and overriding a failed screening needs a reason.
+func (h *LeaseHandler) DecideRentalApplication(w http.ResponseWriter, r *http.Request) {
+	adminID, adminName, adminEmail, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	app, ok := h.loadRentalApplication(w, r)
+	if !ok {
+		return
+	}
+	var req RentalApplicationDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid decision request", http.StatusBadRequest)
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	req.Conditions = strings.TrimSpace(req.Conditions)
+	screened, err := h.latestScreening(r.Context(), app.ID)
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed loading screening of application %d: %v", app.ID, err)
+		http.Error(w, "Failed to load screening", http.StatusInternalServerError)
+		return
+	}
+
+	var status db.ApplicationStatus
+	switch req.Decision {
+	case "approve":
+		h.approveRentalApplication(w, r, app, req, screened, LeaseParty{UserID: adminID, Name: adminName, Email: adminEmail})
+		return
+	case "conditional":
+		if req.Conditions == "" {
+			http.Error(w, "conditions are required for a conditional approval", http.StatusBadRequest)
+			return
+		}
+		status = db.ApplicationStatusConditional
+	case "deny":
+		status = db.ApplicationStatusDenied
+	default:
+		http.Error(w, "decision must be approve, conditional or deny", http.StatusBadRequest)
+		return
+	}
+
+	decided, err := h.queries.DecideRentalApplication(r.Context(), db.DecideRentalApplicationParams{
+		ID:             app.ID,
+		Status:         status,
+		DecisionReason: req.Reason,
+		Conditions:     req.Conditions,
+		DecidedBy:      pgtype.Int8{Int64: adminID, Valid: true},
+		ScreeningID:    screeningID(screened),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, fmt.Sprintf("Application was already %s", app.Status), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed deciding application %d: %v", app.ID, err)
+		http.Error(w, "Failed to save decision", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[APPLICATIONS] Admin %d set application %d to %s", adminID, app.ID, status)
+	if err := sendApplicationDecisionEmail(decided, screened); err != nil {
+		log.Printf("[APPLICATIONS] Failed emailing decision on application %d: %v", app.ID, err)
+	}
+	if status == db.ApplicationStatusDenied {
+		// The application no longer holds the apartment, so it can go to the waitlist
+		h.offerApartment(r.Context(), app.ApartmentID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toRentalApplicationResponse(decided)); err != nil {
+		log.Printf("[APPLICATIONS] Error encoding response: %v", err)
+	}
+}
+
+// approveRentalApplication gives the applicant a tenant account and an invitation to sign up, then creates
+// their draft lease. If the lease cannot be created the invitation is revoked, the account removed and the
+// application goes back to the queue.
+func (h *LeaseHandler) approveRentalApplication(w http.ResponseWriter, r *http.Request, app db.RentalApplication, req RentalApplicationDecisionRequest, screened *db.RentalApplicationScreening, admin LeaseParty) {
+	ctx := r.Context()
+	if !h.screeningOptional {
+		if screened == nil && h.screening == nil {
+			http.Error(w, "No screening provider is configured, so the applicant cannot be screened for approval", http.StatusServiceUnavailable)
+			return
+		}
+		if screened == nil || !screeningCoversAllChecks(*screened) {
+			http.Error(w, "Screen the applicant for credit, criminal and eviction history before approving", http.StatusConflict)
+			return
+		}
+		if screened.Recommendation == db.ScreeningRecommendationFail && req.Reason == "" {
+			http.Error(w, "A reason is required to approve an applicant who failed screening", http.StatusBadRequest)
+			return
+		}
+	}
+	apartment, err := h.queries.GetApartment(ctx, app.ApartmentID)
+	if err != nil {
+		http.Error(w, "Apartment not found", http.StatusNotFound)
+		return
+	}
+	available, err := h.apartmentAvailable(ctx, app.ApartmentID)
+	if err != nil {
+		log.Printf("[APPLICATIONS] Failed checking availability of apartment %d: %v", app.ApartmentID, err)
+		http.Error(w, "Failed to check the apartment", http.StatusInternalServerError)
+		return
+	}
+	if !available {
+		http.Error(w, "The apartment is no longer available", http.StatusConflict)
+		return
+	}
+
+	terms := ApplicationLeaseTerms{}
+	if req.Lease != nil {
+		terms = *req.Lease
+	}
+	if terms.StartDate == "" {
+		start := time.Now().Truncate(24 * time.Hour)
+		if app.DesiredMoveIn.Valid && app.DesiredMoveIn.Time.After(start) {
+			start = app.DesiredMoveIn.Time
+		}
+		terms.StartDate = start.Format("2006-01-02")
+	}
+	if terms.EndDate == "" {
+		start, err := time.Parse("2006-01-02", terms.StartDate)
+		if err != nil {
+			http.Error(w, "Invalid start date", http.StatusBadRequest)
+			return
+		}
+		terms.EndDate = start.AddDate(1, 0, -1).Format("2006-01-02")
+	}
+	if terms.RentAmount == 0 {
+		terms.RentAmount = utils.ConvertPgNumericToFloat(apartment.Price)
+	}
+
+	// Claim the application first so a double submit cannot create two leases
+	saga := newLeaseSaga("application approval")
+	if _, err := h.queries.DecideRentalApplication(ctx, db.DecideRentalApplicationParams{
+		ID:             app.ID,
+		Status:         db.ApplicationStatusApproved,
+		DecisionReason: req.Reason,
+		Conditions:     app.Conditions,
+		DecidedBy:      pgtype.Int8{Int64: admin.UserID, Valid: true},
+		ScreeningID:    screeningID(screened),
+	}); errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, fmt.Sprintf("Application was already %s", app.Status), http.StatusConflict)
+		return
+	} else if err != nil {
+		log.Printf("[APPLICATIONS] Failed approving application %d: %v", app.ID, err)
+		http.Error(w, "Failed to save decision", http.StatusInternalServerError)
+		return
+	}
+	saga.onFailure(fmt.Sprintf("reopen application %d", app.ID), func() error {
+		return h.queries.ReopenRentalApplication(context.Background(), db.ReopenRentalApplicationParams{ID: app.ID, Status: app.Status})
+	})
+
+	tenantID, err := h.applicantAccount(ctx, saga, app)
+	if err != nil {
+		log.Printf("[APPLICATIONS] %v", err)
+		saga.compensate(err)
+		http.Error(w, "Failed to invite the applicant", http.StatusInternalServerError)
+		return
+	}
+
+	leaseID := h.createLeaseVersion(w, r, LeaseUpsertRequest{
+		TenantID:        tenantID,
+		ApartmentID:     app.ApartmentID,
+		StartDate:       terms.StartDate,
+		EndDate:         terms.EndDate,
+		RentAmount:      terms.RentAmount,
+		Status:          string(db.LeaseStatusDraft),
+		DocumentTitle:   fmt.Sprintf("Lease - Unit %d", apartment.UnitNumber.Int64),
+		CreatedBy:       admin.UserID,
+		UpdatedBy:       admin.UserID,
+		LeaseNumber:     1,
+		PropertyAddress: strconv.FormatInt(apartment.UnitNumber.Int64, 10),
+		TemplateID:      terms.TemplateID,
+		TemplateName:    terms.TemplateName,
+		AddendumIDs:     terms.AddendumIDs,
+		Deposit:         terms.Deposit,
+	}, admin)
+	if leaseID == 0 {
+		saga.compensate(fmt.Errorf("draft lease for application %d was not created", app.ID))
+		return
+	}
+
+	if err := h.queries.SetRentalApplicationLease(ctx, db.SetRentalApplicationLeaseParams{
+		ID:       app.ID,
+		TenantID: pgtype.Int8{Int64: tenantID, Valid: true},
+		LeaseID:  pgtype.Int8{Int64: leaseID, Valid: true},
+	}); err != nil {
+		log.Printf("[APPLICATIONS] Failed linking application %d to lease %d: %v", app.ID, leaseID, err)
+	}
+	log.Printf("[APPLICATIONS] Admin %d approved application %d, draft lease %d for tenant %d", admin.UserID, app.ID, leaseID, tenantID)
+	app.Status = db.ApplicationStatusApproved
+	app.DecisionReason = req.Reason
+	if err := sendApplicationDecisionEmail(app, screened); err != nil {
+		log.Printf("[APPLICATIONS] Failed emailing decision on application %d: %v", app.ID, err)
+	}
+}
+
+// applicantAccount returns the tenant account of an approved applicant. An applicant without one gets an
+// account that is claimed when they accept the Clerk invitation sent here.
+func (h *LeaseHandler) applicantAccount(ctx context.Context, saga *leaseSaga, app db.RentalApplication) (int64, error) {
+	tenantID := int64(0)
+	existing, err := h.queries.GetTenantByEmail(ctx, app.Email)
+	switch {
+	case err == nil && !strings.HasPrefix(existing.ClerkID, "application_"):
+		// Already signed up, like a former tenant
+		return existing.ID, nil
+	case err == nil:
+		tenantID = existing.ID
+	case errors.Is(err, pgx.ErrNoRows):
+		account, err := h.queries.CreateApplicantUser(ctx, db.CreateApplicantUserParams{
+			ClerkID:   fmt.Sprintf("application_%d", app.ID),
+			FirstName: app.FirstName,
+			LastName:  app.LastName,
+			Email:     app.Email,
+			Phone:     pgtype.Text{String: app.Phone, Valid: app.Phone != ""},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to create account for application %d: %w", app.ID, err)
+		}
+		tenantID = account.ID
+		saga.onFailure(fmt.Sprintf("delete applicant account %d", tenantID), func() error {
+			return h.queries.DeleteApplicantUser(context.Background(), tenantID)
+		})
+	default:
+		return 0, fmt.Errorf("failed to look up tenant %s: %w", app.Email, err)
+	}
+
+	metadata, err := json.Marshal(ClerkUserPublicMetaData{DbId: int32(tenantID), Role: db.RoleTenant})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode invitation metadata: %w", err)
+	}
+	metadataRaw := json.RawMessage(metadata)
+	invite, err := invitation.Create(ctx, &invitation.CreateParams{
+		EmailAddress:   app.Email,
+		PublicMetadata: &metadataRaw,
+		IgnoreExisting: clerk.Bool(true),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to invite %s: %w", app.Email, err)
+	}
+	saga.onFailure(fmt.Sprintf("revoke invitation %s", invite.ID), func() error {
+		_, err := invitation.Revoke(context.Background(), invite.ID)
+		return err
+	})
+	return tenantID, nil
+}
+
+// sendApplicationDecisionEmail tells the applicant what was decided. A denial or conditional approval made on
+// a screening that did not pass names the screening provider, as an adverse action notice must.
+func sendApplicationDecisionEmail(app db.RentalApplication, screened *db.RentalApplicationScreening) error {
+	var subject string
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("Hello %s %s,\n\n", app.FirstName, app.LastName))
+	switch app.Status {
+	case db.ApplicationStatusApproved:
+		subject = "Your rental application was approved"
+		body.WriteString("Your rental application was approved. You will receive an invitation to create your tenant account, and your lease will be sent to you for signing.\n")
+	case db.ApplicationStatusConditional:
+		subject = "Your rental application was conditionally approved"
+		body.WriteString("Your rental application was conditionally approved. Before we can offer you a lease we need the following:\n\n")
+		body.WriteString(app.Conditions + "\n")
+	case db.ApplicationStatusDenied:
+		subject = "Your rental application"
+		body.WriteString("Thank you for applying. Unfortunately we are unable to approve your rental application.\n")
+	default:
+		return nil
+	}
+	if app.DecisionReason != "" {
+		body.WriteString(fmt.Sprintf("\n%s\n", app.DecisionReason))
+	}
+	if screened != nil && screened.Recommendation != db.ScreeningRecommendationPass && app.Status != db.ApplicationStatusApproved {
+		body.WriteString(fmt.Sprintf("\nThis decision was based in whole or in part on information in a consumer report from %s "+
+			"(reference %s). %s did not make this decision and cannot explain why it was made. You have the right to a free "+
+			"copy of your report from %s if you ask within 60 days, and to dispute the accuracy or completeness of anything in it.\n",
+			screened.Provider, screened.Reference, screened.Provider, screened.Provider))
+	}
+	return smtp.SendEmail(app.Email, subject, body.String())
+}
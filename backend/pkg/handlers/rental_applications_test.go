@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/go-chi/chi/v5"
+)
+
+// applicationDocument is a file sent with an application
+type applicationDocument struct {
+	name string
+	data []byte
+}
+
+// newApplicationRequest is a multipart application for apartment 3 with the given documents
+func newApplicationRequest(t *testing.T, docs ...applicationDocument) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("application", `{"first_name":"Pat","last_name":"Prospect","email":"pat@example.com","monthly_income":4000}`); err != nil {
+		t.Fatalf("writing application: %v", err)
+	}
+	for _, doc := range docs {
+		part, err := form.CreateFormFile("documents", doc.name)
+		if err != nil {
+			t.Fatalf("adding %s: %v", doc.name, err)
+		}
+		part.Write(doc.data)
+	}
+	form.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/apartments/3/applications", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("apartmentID", "3")
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+}
+
+// answerApplication answers the lookups made when apartment 3 takes an application
+func answerApplication(fdb *fakeDB) {
+	fdb.returns("GetApartmentsWithoutLease", []db.GetApartmentsWithoutLeaseRow{{ID: 3}})
+	fdb.returns("CountOpenRentalApplications", int64(0))
+	fdb.returns("CreateRentalApplication", db.RentalApplication{ID: 8, ApartmentID: 3, Status: db.ApplicationStatusSubmitted})
+	fdb.returns("CreateRentalApplicationDocument", db.RentalApplicationDocument{})
+}
+
+func TestSubmitRentalApplicationStoresSniffedDocuments(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerApplication(fdb)
+
+	// Named like a JPEG, but the content is a PDF
+	rec := httptest.NewRecorder()
+	h.SubmitRentalApplication(rec, newApplicationRequest(t, applicationDocument{"paystub.jpg", []byte("%PDF-1.7\nstub")}))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body.String())
+	}
+	docs := fdb.called("CreateRentalApplicationDocument")
+	if len(docs) != 1 || docs[0].Args[2] != "application/pdf" {
+		t.Errorf("CreateRentalApplicationDocument calls = %+v, want one document stored as application/pdf", docs)
+	}
+}
+
+func TestSubmitRentalApplicationRejectsOtherFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  applicationDocument
+	}{
+		{"html named as a PDF", applicationDocument{"statement.pdf", []byte("<html><script>alert(1)</script></html>")}},
+		{"svg", applicationDocument{"id.svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)}},
+		{"empty file", applicationDocument{"id.png", nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fdb := newTestHandler(t)
+			answerApplication(fdb)
+
+			rec := httptest.NewRecorder()
+			h.SubmitRentalApplication(rec, newApplicationRequest(t, tt.doc))
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body.String())
+			}
+			if calls := fdb.called("CreateRentalApplication"); len(calls) != 0 {
+				t.Errorf("application saved with a rejected file: %+v", calls)
+			}
+		})
+	}
+}
+
+func TestSubmitRentalApplicationCapsTheBody(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerApplication(fdb)
+	large := append([]byte("%PDF-1.7\n"), bytes.Repeat([]byte("x"), maxApplicationBodyBytes)...)
+
+	rec := httptest.NewRecorder()
+	h.SubmitRentalApplication(rec, newApplicationRequest(t, applicationDocument{"large.pdf", large}))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "10 MB") {
+		t.Errorf("body = %q, want the limit named", rec.Body.String())
+	}
+	if calls := fdb.called("CreateRentalApplication"); len(calls) != 0 {
+		t.Errorf("oversized application saved: %+v", calls)
+	}
+}
+
+func TestApproveRentalApplicationWithoutScreeningProvider(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	signInAdmin(fdb)
+	fdb.returns("GetRentalApplication", db.RentalApplication{ID: 8, ApartmentID: 3, Status: db.ApplicationStatusSubmitted})
+
+	rec := httptest.NewRecorder()
+	h.DecideRentalApplication(rec, newTestRequest(t, http.MethodPost, "/admin/applications/8/decision",
+		RentalApplicationDecisionRequest{Decision: "approve"}, "applicationID", "8"))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503: %s", rec.Code, rec.Body.String())
+	}
+	if calls := fdb.called("GetApartment"); len(calls) != 0 {
+		t.Errorf("unscreened applicant approved without a screening provider: %+v", calls)
+	}
+}
+
+func TestApproveRentalApplicationWithScreeningTurnedOff(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	h.screeningOptional = true
+	signInAdmin(fdb)
+	fdb.returns("GetRentalApplication", db.RentalApplication{ID: 8, ApartmentID: 3, Status: db.ApplicationStatusSubmitted})
+
+	rec := httptest.NewRecorder()
+	h.DecideRentalApplication(rec, newTestRequest(t, http.MethodPost, "/admin/applications/8/decision",
+		RentalApplicationDecisionRequest{Decision: "approve"}, "applicationID", "8"))
+	if calls := fdb.called("GetApartment"); len(calls) != 1 {
+		t.Errorf("approval with screening turned off stopped at the screening gate: %d %s", rec.Code, rec.Body.String())
+	}
+}
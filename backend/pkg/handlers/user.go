@@ -433,26 +433,6 @@ func (u UserHandler) DeleteTenant(w http.ResponseWriter, r *http.Request) {
 // ADMIN END
 
 // TENANT START
-func (u UserHandler) TenantGetDocuments(w http.ResponseWriter, r *http.Request) {
-	documents, err := u.queries.ListLeases(r.Context())
-	if err != nil {
-		log.Printf("[USER_HANDLER] Failed querying documents for tenant: %v", err)
-		http.Error(w, "Error querying documents for tenant", http.StatusInternalServerError)
-		return
-	}
-
-	jsonDocments, err := json.Marshal(documents)
-	if err != nil {
-		log.Printf("[USER_HANDLER] Failed querying documents for tenant: %v", err)
-		http.Error(w, "Error querying documents for tenant", http.StatusInternalServerError)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Header().Set("Content-Type", "application/json")
-	w.Write([]byte(jsonDocments))
-}
-
 func (u UserHandler) TenantGetWorkOrders(w http.ResponseWriter, r *http.Request) {
 	tenantCtx := middleware.GetUserCtx(r)
 	if tenantCtx == nil {
@@ -0,0 +1,530 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/leasestate"
+	"github.com/careecodes/RentDaddy/internal/rent"
+	"github.com/careecodes/RentDaddy/internal/templates"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/careecodes/RentDaddy/middleware"
+	"github.com/careecodes/RentDaddy/pkg/handlers/documenso"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// VacateNoticeRequest gives notice to end a lease. Without a move-out date the tenant leaves as soon as
+// the notice period allows. InitiatedBy and WaiveFee are only honored for admins; initiated_by "tenant"
+// records a notice the tenant gave outside the app.
+type VacateNoticeRequest struct {
+	MoveOutDate string `json:"move_out_date,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	InitiatedBy string `json:"initiated_by,omitempty"`
+	WaiveFee    bool   `json:"waive_fee,omitempty"`
+}
+
+// VacateNoticeResponse is a notice to vacate and where it stands
+type VacateNoticeResponse struct {
+	ID               int64             `json:"id"`
+	LeaseID          int64             `json:"lease_id"`
+	InitiatedBy      string            `json:"initiated_by"`
+	NoticeDate       string            `json:"notice_date"`
+	MoveOutDate      string            `json:"move_out_date"`
+	EarlyTermination bool              `json:"early_termination"`
+	TerminationFee   float64           `json:"termination_fee"`
+	Reason           string            `json:"reason,omitempty"`
+	Status           string            `json:"status"`
+	SigningURLs      map[string]string `json:"signing_urls"`
+}
+
+func toVacateNoticeResponse(notice db.LeaseVacateNotice) VacateNoticeResponse {
+	signingURLs := map[string]string{}
+	if err := json.Unmarshal(notice.SigningUrls, &signingURLs); err != nil {
+		log.Printf("[VACATE_NOTICE] Notice %d has unreadable signing URLs: %v", notice.ID, err)
+	}
+	return VacateNoticeResponse{
+		ID:               notice.ID,
+		LeaseID:          notice.LeaseID,
+		InitiatedBy:      string(notice.InitiatedBy),
+		NoticeDate:       notice.NoticeDate.Time.Format("2006-01-02"),
+		MoveOutDate:      notice.MoveOutDate.Time.Format("2006-01-02"),
+		EarlyTermination: notice.EarlyTermination,
+		TerminationFee:   utils.ConvertPgNumericToFloat(notice.TerminationFee),
+		Reason:           notice.Reason,
+		Status:           string(notice.Status),
+		SigningURLs:      signingURLs,
+	}
+}
+
+// validateTerminationTerms checks the notice period and early termination fee sent with a lease
+func validateTerminationTerms(noticePeriodDays *int32, earlyTerminationFee *float64) error {
+	if noticePeriodDays != nil && *noticePeriodDays < 0 {
+		return errors.New("notice_period_days cannot be negative")
+	}
+	if earlyTerminationFee != nil && *earlyTerminationFee < 0 {
+		return errors.New("early_termination_fee cannot be negative")
+	}
+	return nil
+}
+
+// inheritTerminationTerms fills in the terms a new lease version leaves out from the lease it replaces
+func (h *LeaseHandler) inheritTerminationTerms(ctx context.Context, leaseID int64, req *LeaseUpsertRequest) {
+	lease, err := h.queries.GetLeaseByID(ctx, leaseID)
+	if err != nil {
+		log.Printf("[VACATE_NOTICE] Could not load lease %d: %v", leaseID, err)
+		return
+	}
+	if req.NoticePeriodDays == nil {
+		req.NoticePeriodDays = &lease.NoticePeriodDays
+	}
+	if req.EarlyTerminationFee == nil && lease.EarlyTerminationFee.Valid {
+		fee := utils.ConvertPgNumericToFloat(lease.EarlyTerminationFee)
+		req.EarlyTerminationFee = &fee
+	}
+}
+
+// recordLeaseTerminationTerms stores the notice period and early termination fee on a newly created lease
+func (h *LeaseHandler) recordLeaseTerminationTerms(ctx context.Context, leaseID int64, noticePeriodDays *int32, earlyTerminationFee *float64) error {
+	if noticePeriodDays == nil && earlyTerminationFee == nil {
+		return nil
+	}
+	params := db.SetLeaseTerminationTermsParams{ID: leaseID, NoticePeriodDays: 30}
+	if noticePeriodDays != nil {
+		params.NoticePeriodDays = *noticePeriodDays
+	}
+	if earlyTerminationFee != nil {
+		params.EarlyTerminationFee = utils.ConvertFloatToPgNumeric(*earlyTerminationFee)
+	}
+	if err := h.queries.SetLeaseTerminationTerms(ctx, params); err != nil {
+		return fmt.Errorf("failed recording termination terms on lease %d: %w", leaseID, err)
+	}
+	return nil
+}
+
+// sendVacateNotice works out the move-out date and fee from the lease terms, renders the notice and sends
+// it to every tenant and the landlord for signature
+func (h *LeaseHandler) sendVacateNotice(ctx context.Context, lease db.GetLeaseByIDRow, initiator db.NoticeInitiator, requestedBy int64, requested time.Time, reason string, waiveFee bool) (db.LeaseVacateNotice, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	// A month-to-month lease has no end date left to hold the tenant to
+	leaseEnd := lease.LeaseEndDate.Time
+	if lease.Status == db.LeaseStatusMonthToMonth {
+		leaseEnd = time.Time{}
+	}
+	moveOut, early := rent.VacateDate(today, requested, leaseEnd, int(lease.NoticePeriodDays))
+
+	// The fee is what a tenant owes for leaving early; a landlord ending the lease does not charge it
+	var fee float64
+	if early && initiator == db.NoticeInitiatorTenant && !waiveFee {
+		fee = utils.ConvertPgNumericToFloat(lease.EarlyTerminationFee)
+	}
+
+	landlord, err := h.landlordOfLease(ctx, lease.ApartmentID, lease.LandlordID)
+	if err != nil {
+		return db.LeaseVacateNotice{}, fmt.Errorf("landlord of lease %d not found: %w", lease.ID, err)
+	}
+	occupants, err := h.queries.ListLeaseTenants(ctx, lease.ID)
+	if err != nil || len(occupants) == 0 {
+		return db.LeaseVacateNotice{}, fmt.Errorf("no tenants on lease %d to sign the notice", lease.ID)
+	}
+
+	data := templates.VacateNoticeData{
+		Landlord:         templates.LeaseParty{Name: landlord.Signer.Name, Email: landlord.Signer.Email},
+		InitiatedBy:      string(initiator),
+		NoticeDate:       today,
+		MoveOutDate:      moveOut,
+		LeaseEndDate:     leaseEnd,
+		NoticePeriodDays: int(lease.NoticePeriodDays),
+		EarlyTermination: early,
+		TerminationFee:   fee,
+		Reason:           reason,
+	}
+	if details, err := h.queries.GetApartmentLeaseDetails(ctx, lease.ApartmentID); err == nil && details.UnitNumber.Valid {
+		data.UnitNumber = strconv.FormatInt(details.UnitNumber.Int64, 10)
+	}
+	signers := make([]documenso.Signer, 0, len(occupants)+1)
+	for _, o := range occupants {
+		tenant := templates.LeaseParty{Name: fmt.Sprintf("%s %s", o.FirstName, o.LastName), Email: o.Email}
+		data.Tenants = append(data.Tenants, tenant)
+		signers = append(signers, documenso.Signer{Name: tenant.Name, Email: tenant.Email, Role: documenso.SignerRoleSigner})
+	}
+	signers = append(signers, documenso.Signer{Name: data.Landlord.Name, Email: data.Landlord.Email, Role: documenso.SignerRoleSigner})
+
+	pdfData, fields, err := templates.RenderVacateNotice(data)
+	if err != nil {
+		return db.LeaseVacateNotice{}, err
+	}
+	// Do not leave a document out for signature that no notice refers to
+	saga := newLeaseSaga(fmt.Sprintf("vacate notice for lease %d", lease.ID))
+	docID, signingURLs, _, err := h.uploadForSigning(saga, pdfData, fmt.Sprintf("Notice to Vacate - Lease %d", lease.ID), fields, signers)
+	if err != nil {
+		saga.compensate(err)
+		return db.LeaseVacateNotice{}, err
+	}
+	if _, err := h.documenso_client.SendDocument(docID); err != nil {
+		log.Printf("[VACATE_NOTICE] Failed sending notice document %s to signers: %v", docID, err)
+	}
+	rawURLs, err := json.Marshal(signingURLs)
+	if err != nil {
+		err = fmt.Errorf("failed to encode signing URLs: %w", err)
+		saga.compensate(err)
+		return db.LeaseVacateNotice{}, err
+	}
+
+	notice, err := h.queries.CreateVacateNotice(ctx, db.CreateVacateNoticeParams{
+		LeaseID:          lease.ID,
+		InitiatedBy:      initiator,
+		RequestedBy:      pgtype.Int8{Int64: requestedBy, Valid: requestedBy != 0},
+		NoticeDate:       pgtype.Date{Time: today, Valid: true},
+		MoveOutDate:      pgtype.Date{Time: moveOut, Valid: true},
+		EarlyTermination: early,
+		TerminationFee:   utils.ConvertFloatToPgNumeric(fee),
+		Reason:           reason,
+		ExternalDocID:    pgtype.Text{String: docID, Valid: true},
+		SigningUrls:      rawURLs,
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to save notice for lease %d: %w", lease.ID, err)
+		saga.compensate(err)
+		return db.LeaseVacateNotice{}, err
+	}
+	log.Printf("[VACATE_NOTICE] %s gave notice on lease %d, moving out %s", initiator, lease.ID, moveOut.Format("2006-01-02"))
+	return notice, nil
+}
+
+// handleVacateNotice checks a lease can take a notice and sends one, writing the response
+func (h *LeaseHandler) handleVacateNotice(w http.ResponseWriter, r *http.Request, lease db.GetLeaseByIDRow, initiator db.NoticeInitiator, requestedBy int64, req VacateNoticeRequest) {
+	ctx := r.Context()
+	if lease.Status != db.LeaseStatusActive && lease.Status != db.LeaseStatusMonthToMonth {
+		http.Error(w, "Notice can only be given on an active or month-to-month lease", http.StatusBadRequest)
+		return
+	}
+	var requested time.Time
+	if req.MoveOutDate != "" {
+		parsed, err := time.Parse("2006-01-02", req.MoveOutDate)
+		if err != nil {
+			http.Error(w, "move_out_date must be a YYYY-MM-DD date", http.StatusBadRequest)
+			return
+		}
+		requested = parsed
+	}
+	if _, err := h.queries.GetOpenVacateNotice(ctx, lease.ID); err == nil {
+		http.Error(w, "Notice has already been given on this lease", http.StatusConflict)
+		return
+	}
+
+	notice, err := h.sendVacateNotice(ctx, lease, initiator, requestedBy, requested, strings.TrimSpace(req.Reason), req.WaiveFee)
+	if err != nil {
+		log.Printf("[VACATE_NOTICE] %v", err)
+		http.Error(w, "Failed to send notice to vacate", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toVacateNoticeResponse(notice)); err != nil {
+		log.Printf("[VACATE_NOTICE] Error encoding response: %v", err)
+	}
+}
+
+// tenantFromContext returns the database ID of the signed-in tenant
+func tenantFromContext(r *http.Request) (int64, error) {
+	tenantCtx := middleware.GetUserCtx(r)
+	if tenantCtx == nil {
+		return 0, errors.New("no tenant context")
+	}
+	var tenantMetadata ClerkUserPublicMetaData
+	if err := json.Unmarshal(tenantCtx.PublicMetadata, &tenantMetadata); err != nil {
+		return 0, fmt.Errorf("failed parsing tenant metadata: %w", err)
+	}
+	return int64(tenantMetadata.DbId), nil
+}
+
+// TenantGiveVacateNotice lets a tenant give notice on their active lease
+func (h *LeaseHandler) TenantGiveVacateNotice(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenantFromContext(r)
+	if err != nil {
+		log.Printf("[VACATE_NOTICE] %v", err)
+		http.Error(w, "Error no tenant context", http.StatusUnauthorized)
+		return
+	}
+	var req VacateNoticeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid notice request", http.StatusBadRequest)
+		return
+	}
+
+	leases, err := h.queries.ListTenantLeases(r.Context(), tenantID)
+	if err != nil {
+		log.Printf("[VACATE_NOTICE] Failed listing leases of tenant %d: %v", tenantID, err)
+		http.Error(w, "Failed to fetch leases", http.StatusInternalServerError)
+		return
+	}
+	for _, l := range leases {
+		if l.Status != db.LeaseStatusActive && l.Status != db.LeaseStatusMonthToMonth {
+			continue
+		}
+		lease, err := h.queries.GetLeaseByID(r.Context(), l.ID)
+		if err != nil {
+			http.Error(w, "Lease not found", http.StatusNotFound)
+			return
+		}
+		h.handleVacateNotice(w, r, lease, db.NoticeInitiatorTenant, tenantID, VacateNoticeRequest{
+			MoveOutDate: req.MoveOutDate,
+			Reason:      req.Reason,
+		})
+		return
+	}
+	http.Error(w, "You have no active lease to give notice on", http.StatusNotFound)
+}
+
+// TenantGetVacateNotices lists the notices on the tenant's leases with only their own signing link
+func (h *LeaseHandler) TenantGetVacateNotices(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenantFromContext(r)
+	if err != nil {
+		log.Printf("[VACATE_NOTICE] %v", err)
+		http.Error(w, "Error no tenant context", http.StatusUnauthorized)
+		return
+	}
+	tenant, err := h.queries.GetUserByID(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "Tenant not found", http.StatusNotFound)
+		return
+	}
+	notices, err := h.queries.ListTenantVacateNotices(r.Context(), tenantID)
+	if err != nil {
+		log.Printf("[VACATE_NOTICE] Failed listing notices of tenant %d: %v", tenantID, err)
+		http.Error(w, "Failed to fetch notices", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]VacateNoticeResponse, 0, len(notices))
+	for _, notice := range notices {
+		n := toVacateNoticeResponse(notice)
+		own := map[string]string{}
+		if signingURL, ok := n.SigningURLs[strings.ToLower(tenant.Email)]; ok {
+			own[strings.ToLower(tenant.Email)] = signingURL
+		}
+		n.SigningURLs = own
+		resp = append(resp, n)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[VACATE_NOTICE] Error encoding response: %v", err)
+	}
+}
+
+// CreateVacateNotice gives notice on a lease from the admin side
+func (h *LeaseHandler) CreateVacateNotice(w http.ResponseWriter, r *http.Request) {
+	leaseID, err := strconv.ParseInt(chi.URLParam(r, "leaseID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lease ID", http.StatusBadRequest)
+		return
+	}
+	var req VacateNoticeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid notice request", http.StatusBadRequest)
+		return
+	}
+	initiator := db.NoticeInitiatorLandlord
+	switch req.InitiatedBy {
+	case "", string(db.NoticeInitiatorLandlord):
+	case string(db.NoticeInitiatorTenant):
+		initiator = db.NoticeInitiatorTenant
+	default:
+		http.Error(w, "initiated_by must be tenant or landlord", http.StatusBadRequest)
+		return
+	}
+
+	adminID, _, _, err := h.GetLandlordInfo(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	lease, err := h.queries.GetLeaseByID(r.Context(), leaseID)
+	if err != nil {
+		http.Error(w, "Lease not found", http.StatusNotFound)
+		return
+	}
+	h.handleVacateNotice(w, r, lease, initiator, adminID, req)
+}
+
+// ListVacateNotices lists notices to vacate, optionally only those with ?status=
+func (h *LeaseHandler) ListVacateNotices(w http.ResponseWriter, r *http.Request) {
+	var status db.NullNoticeStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		status = db.NullNoticeStatus{NoticeStatus: db.NoticeStatus(s), Valid: true}
+	}
+	notices, err := h.queries.ListVacateNotices(r.Context(), status)
+	if err != nil {
+		log.Printf("[VACATE_NOTICE] Failed listing notices: %v", err)
+		http.Error(w, "Failed to fetch notices", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]VacateNoticeResponse, 0, len(notices))
+	for _, notice := range notices {
+		resp = append(resp, toVacateNoticeResponse(notice))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[VACATE_NOTICE] Error encoding response: %v", err)
+	}
+}
+
+// cancelVacateNotice withdraws an open notice and pulls its document back from the signers
+func (h *LeaseHandler) cancelVacateNotice(ctx context.Context, noticeID int64) (db.LeaseVacateNotice, error) {
+	notice, err := h.queries.CancelVacateNotice(ctx, noticeID)
+	if err != nil {
+		return db.LeaseVacateNotice{}, err
+	}
+	if notice.SignedAt.Valid || !notice.ExternalDocID.Valid {
+		return notice, nil
+	}
+	if err := h.documenso_client.DeleteDocument(notice.ExternalDocID.String); err != nil {
+		log.Printf("[VACATE_NOTICE] Failed deleting unsigned notice document %s: %v", notice.ExternalDocID.String, err)
+	}
+	return notice, nil
+}
+
+// CancelVacateNotice withdraws a notice before its move-out date
+func (h *LeaseHandler) CancelVacateNotice(w http.ResponseWriter, r *http.Request) {
+	noticeID, err := strconv.ParseInt(chi.URLParam(r, "noticeID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid notice ID", http.StatusBadRequest)
+		return
+	}
+	notice, err := h.cancelVacateNotice(r.Context(), noticeID)
+	if err != nil {
+		http.Error(w, "No open notice with that ID", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toVacateNoticeResponse(notice)); err != nil {
+		log.Printf("[VACATE_NOTICE] Error encoding response: %v", err)
+	}
+}
+
+// processVacateNoticeEvent applies a Documenso webhook event to the notice that owns the document. A notice
+// is scheduled once everyone has signed and dropped if anyone rejects it.
+func (h *LeaseHandler) processVacateNoticeEvent(ctx context.Context, notice db.LeaseVacateNotice, event documenso.WebhookEvent) error {
+	switch event.Event {
+	case documenso.EventDocumentCompleted:
+		scheduled, err := h.queries.MarkVacateNoticeScheduled(ctx, notice.ID)
+		if err != nil {
+			return fmt.Errorf("failed to schedule notice %d: %w", notice.ID, err)
+		}
+		if scheduled > 0 {
+			log.Printf("[WEBHOOK] Notice %d signed, lease %d ends %s", notice.ID, notice.LeaseID, notice.MoveOutDate.Time.Format("2006-01-02"))
+			h.recordSignedVacateNotice(ctx, notice)
+		}
+	case documenso.EventDocumentRejected, documenso.EventDocumentCancelled:
+		if notice.Status != db.NoticeStatusPendingSignature {
+			return nil
+		}
+		if _, err := h.queries.CancelVacateNotice(ctx, notice.ID); err != nil {
+			return fmt.Errorf("failed to cancel notice %d: %w", notice.ID, err)
+		}
+		for _, recipient := range event.Payload.AllRecipients() {
+			if recipient.SigningStatus == documenso.SigningStatusRejected {
+				log.Printf("[WEBHOOK] %s rejected notice %d: %s", recipient.Email, notice.ID, recipient.RejectionReason)
+			}
+		}
+		log.Printf("[WEBHOOK] Notice %d on lease %d cancelled", notice.ID, notice.LeaseID)
+	default:
+		log.Printf("[WEBHOOK] Ignoring event %s for notice %d", event.Event, notice.ID)
+	}
+	return nil
+}
+
+// recordSignedVacateNotice keeps the signed notice and files it in the vault for the tenants on the lease
+func (h *LeaseHandler) recordSignedVacateNotice(ctx context.Context, notice db.LeaseVacateNotice) {
+	if !notice.ExternalDocID.Valid {
+		return
+	}
+	pdfData, err := h.documenso_client.DownloadDocument(notice.ExternalDocID.String)
+	if err != nil {
+		log.Printf("[VACATE_NOTICE] Failed downloading signed notice %d: %v", notice.ID, err)
+		return
+	}
+	key := fmt.Sprintf("notices/%d/signed.pdf", notice.ID)
+	if err := h.documents.Put(ctx, key, pdfData, "application/pdf"); err != nil {
+		log.Printf("[VACATE_NOTICE] Failed storing signed notice %d: %v", notice.ID, err)
+		return
+	}
+	params := db.CreateDocumentParams{
+		Category:    db.DocumentCategoryNotice,
+		Title:       fmt.Sprintf("Notice to vacate by %s", notice.MoveOutDate.Time.Format("January 2, 2006")),
+		FileName:    "notice-to-vacate.pdf",
+		ContentType: "application/pdf",
+		SizeBytes:   int64(len(pdfData)),
+		StorageKey:  key,
+		LeaseID:     pgtype.Int8{Int64: notice.LeaseID, Valid: true},
+	}
+	if lease, err := h.queries.GetLeaseByID(ctx, notice.LeaseID); err == nil {
+		params.ApartmentID = pgtype.Int8{Int64: lease.ApartmentID, Valid: lease.ApartmentID != 0}
+	}
+	h.recordDocument(ctx, params)
+}
+
+// ProcessVacateNotices terminates every lease whose signed notice reaches its move-out date, charging the
+// early termination fee. The deposit is held as pending disposition until an admin itemizes the deductions
+// or its statement comes due.
+func (h *LeaseHandler) ProcessVacateNotices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	notices, err := h.queries.ListDueVacateNotices(ctx, pgtype.Date{Time: today, Valid: true})
+	if err != nil {
+		log.Printf("[VACATE_NOTICE] Failed listing due notices: %v", err)
+		http.Error(w, "Failed to fetch notices", http.StatusInternalServerError)
+		return
+	}
+
+	var terminated, failed int
+	for _, notice := range notices {
+		lease, err := h.queries.GetLeaseByID(ctx, notice.LeaseID)
+		if err != nil {
+			log.Printf("[VACATE_NOTICE] Lease %d of notice %d not found: %v", notice.LeaseID, notice.ID, err)
+			failed++
+			continue
+		}
+		if lease.Status == db.LeaseStatusActive || lease.Status == db.LeaseStatusMonthToMonth {
+			fee := utils.ConvertPgNumericToFloat(notice.TerminationFee)
+			if _, err := h.terminateLease(ctx, lease.ID, notice.MoveOutDate.Time, fee, lease.LandlordID,
+				leasestate.EventVacated, fmt.Sprintf("Moved out on %s under notice to vacate %d", notice.MoveOutDate.Time.Format("2006-01-02"), notice.ID)); err != nil {
+				log.Printf("[VACATE_NOTICE] Failed terminating lease %d for notice %d: %v", lease.ID, notice.ID, err)
+				failed++
+				continue
+			}
+			h.openDepositDisposition(ctx, lease.ID, notice.MoveOutDate.Time, lease.LandlordID)
+			terminated++
+		} else {
+			log.Printf("[VACATE_NOTICE] Lease %d is already %s, closing notice %d", lease.ID, lease.Status, notice.ID)
+		}
+		if err := h.queries.CompleteVacateNotice(ctx, notice.ID); err != nil {
+			log.Printf("[VACATE_NOTICE] Failed completing notice %d: %v", notice.ID, err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]int{
+		"due":        len(notices),
+		"terminated": terminated,
+		"failed":     failed,
+	}); err != nil {
+		log.Printf("[VACATE_NOTICE] Error encoding response: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestProcessVacateNoticesHoldsTheDeposit(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerDeposit(fdb)
+	fdb.returns("ListDueVacateNotices", []db.LeaseVacateNotice{{
+		ID:             6,
+		LeaseID:        9,
+		MoveOutDate:    pgtype.Date{Time: testMoveOut, Valid: true},
+		TerminationFee: utils.ConvertFloatToPgNumeric(0),
+		Status:         db.NoticeStatusScheduled,
+	}})
+	fdb.returns("TransitionLeaseStatus", db.LeaseStatusHistory{})
+	fdb.returns("OpenDepositDisposition", depositStatement(db.DepositDispositionStatusPendingDisposition))
+
+	rec := httptest.NewRecorder()
+	h.ProcessVacateNotices(rec, newTestRequest(t, http.MethodPost, "/cron/leases/vacate-notices", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	if transitions := fdb.called("TransitionLeaseStatus"); len(transitions) != 1 || transitions[0].Args[0] != db.LeaseStatusTerminated {
+		t.Errorf("TransitionLeaseStatus calls = %+v, want lease 9 terminated", transitions)
+	}
+	opened := fdb.called("OpenDepositDisposition")
+	if len(opened) != 1 || opened[0].Args[3] != int64(9) {
+		t.Fatalf("OpenDepositDisposition calls = %+v, want the deposit of lease 9 held", opened)
+	}
+	if moveOut := opened[0].Args[0].(pgtype.Date); !moveOut.Time.Equal(testMoveOut) {
+		t.Errorf("held from %s, want the move-out date %s", moveOut.Time, testMoveOut)
+	}
+	if calls := fdb.called("CreateDepositDisposition"); len(calls) != 0 {
+		t.Errorf("statement issued at move-out before the unit was inspected: %+v", calls)
+	}
+}
+
+func TestProcessVacateNoticesKeepsTheApartmentPrice(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerDeposit(fdb)
+	fdb.returns("ListDueVacateNotices", []db.LeaseVacateNotice{{
+		ID:             6,
+		LeaseID:        9,
+		MoveOutDate:    pgtype.Date{Time: testMoveOut, Valid: true},
+		TerminationFee: utils.ConvertFloatToPgNumeric(0),
+		Status:         db.NoticeStatusScheduled,
+	}})
+	fdb.returns("TransitionLeaseStatus", db.LeaseStatusHistory{})
+	fdb.returns("GetApartment", db.GetApartmentRow{ID: 3, Price: utils.ConvertFloatToPgNumeric(1450), ManagementID: testAdmin.ID})
+
+	rec := httptest.NewRecorder()
+	h.ProcessVacateNotices(rec, newTestRequest(t, http.MethodPost, "/cron/leases/vacate-notices", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	updates := fdb.called("UpdateApartment")
+	if len(updates) != 1 || updates[0].Args[3] != true {
+		t.Fatalf("UpdateApartment calls = %+v, want the apartment back on the market", updates)
+	}
+	if price := utils.ConvertPgNumericToFloat(updates[0].Args[1].(pgtype.Numeric)); price != 1450 {
+		t.Errorf("apartment price = %v, want the 1450 it was listed at", price)
+	}
+	if manager := updates[0].Args[2]; manager != testAdmin.ID {
+		t.Errorf("apartment manager = %v, want %d", manager, testAdmin.ID)
+	}
+}
@@ -0,0 +1,705 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/internal/smtp"
+	"github.com/careecodes/RentDaddy/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// waitlistOfferTTL is how long a prospect has to answer an offer before it passes to the next one in line
+const waitlistOfferTTL = 48 * time.Hour
+
+// WaitlistEntryRequest puts a prospect on the waitlist for units in a building, with a bedroom count or of a
+// unit type. Criteria left out match any unit; at least one is needed.
+type WaitlistEntryRequest struct {
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	Email      string `json:"email"`
+	Phone      string `json:"phone"`
+	BuildingID *int64 `json:"building_id,omitempty"`
+	Bedrooms   *int16 `json:"bedrooms,omitempty"`
+	UnitType   string `json:"unit_type,omitempty"`
+}
+
+type WaitlistEntryResponse struct {
+	ID         int64  `json:"id"`
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	Email      string `json:"email"`
+	Phone      string `json:"phone"`
+	BuildingID *int64 `json:"building_id,omitempty"`
+	Bedrooms   *int16 `json:"bedrooms,omitempty"`
+	UnitType   string `json:"unit_type,omitempty"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type WaitlistOfferResponse struct {
+	ID            int64  `json:"id"`
+	EntryID       int64  `json:"entry_id"`
+	ApartmentID   int64  `json:"apartment_id"`
+	Status        string `json:"status"`
+	ExpiresAt     string `json:"expires_at"`
+	ApplicationID *int64 `json:"application_id,omitempty"`
+	RespondedAt   string `json:"responded_at,omitempty"`
+	CreatedAt     string `json:"created_at"`
+}
+
+func validateWaitlistEntry(req *WaitlistEntryRequest) error {
+	req.FirstName = strings.TrimSpace(req.FirstName)
+	req.LastName = strings.TrimSpace(req.LastName)
+	req.Email = strings.TrimSpace(req.Email)
+	req.Phone = strings.TrimSpace(req.Phone)
+	req.UnitType = strings.TrimSpace(req.UnitType)
+	if req.FirstName == "" || req.LastName == "" {
+		return errors.New("first_name and last_name are required")
+	}
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		return errors.New("a valid email is required")
+	}
+	if req.BuildingID == nil && req.Bedrooms == nil && req.UnitType == "" {
+		return errors.New("choose a building_id, bedrooms or unit_type to wait for")
+	}
+	if req.Bedrooms != nil && (*req.Bedrooms < 0 || *req.Bedrooms > 10) {
+		return errors.New("bedrooms must be between 0 and 10")
+	}
+	return nil
+}
+
+func newWaitlistToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func waitlistOfferURL(token string) string {
+	return utils.GetAbsoluteUrl("/waitlist/offers/" + token)
+}
+
+func waitlistLeaveURL(token string) string {
+	return utils.GetAbsoluteUrl("/waitlist/" + token)
+}
+
+func toWaitlistEntryResponse(e db.WaitlistEntry) WaitlistEntryResponse {
+	resp := WaitlistEntryResponse{
+		ID:        e.ID,
+		FirstName: e.FirstName,
+		LastName:  e.LastName,
+		Email:     e.Email,
+		Phone:     e.Phone,
+		UnitType:  e.UnitType.String,
+		Status:    string(e.Status),
+		CreatedAt: e.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+	}
+	if e.BuildingID.Valid {
+		resp.BuildingID = &e.BuildingID.Int64
+	}
+	if e.Bedrooms.Valid {
+		resp.Bedrooms = &e.Bedrooms.Int16
+	}
+	return resp
+}
+
+func toWaitlistOfferResponse(o db.WaitlistOffer) WaitlistOfferResponse {
+	resp := WaitlistOfferResponse{
+		ID:          o.ID,
+		EntryID:     o.EntryID,
+		ApartmentID: o.ApartmentID,
+		Status:      string(o.Status),
+		ExpiresAt:   o.ExpiresAt.Time.Format("2006-01-02 15:04:05"),
+		CreatedAt:   o.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+	}
+	if o.ApplicationID.Valid {
+		resp.ApplicationID = &o.ApplicationID.Int64
+	}
+	if o.RespondedAt.Valid {
+		resp.RespondedAt = o.RespondedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+// describeApartment names an apartment in emails to prospects, like "unit 2145 (2 bedrooms, loft)"
+func describeApartment(a db.GetApartmentRow) string {
+	var details []string
+	if a.Bedrooms.Valid {
+		switch a.Bedrooms.Int16 {
+		case 0:
+			details = append(details, "studio")
+		case 1:
+			details = append(details, "1 bedroom")
+		default:
+			details = append(details, fmt.Sprintf("%d bedrooms", a.Bedrooms.Int16))
+		}
+	}
+	if a.UnitType.Valid && a.UnitType.String != "" {
+		details = append(details, a.UnitType.String)
+	}
+	name := fmt.Sprintf("unit %d", a.UnitNumber.Int64)
+	if len(details) > 0 {
+		name += " (" + strings.Join(details, ", ") + ")"
+	}
+	return name
+}
+
+func sendWaitlistOfferEmail(entry db.WaitlistEntry, apartment db.GetApartmentRow, offer db.WaitlistOffer) error {
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("Hello %s %s,\n\n", entry.FirstName, entry.LastName))
+	body.WriteString(fmt.Sprintf("An apartment you are waiting for is available: %s at $%.2f per month.\n\n",
+		describeApartment(apartment), utils.ConvertPgNumericToFloat(apartment.Price)))
+	body.WriteString(fmt.Sprintf("It is held for you until %s UTC. Accept it by applying, or decline it, here:\n%s\n",
+		offer.ExpiresAt.Time.Format("Monday, January 2 at 3:04 PM"), waitlistOfferURL(offer.Token)))
+	body.WriteString("\nIf we do not hear from you by then, the apartment is offered to the next person on the waitlist and you keep your place.\n")
+	body.WriteString(fmt.Sprintf("\nTo leave the waitlist: %s\n", waitlistLeaveURL(entry.Token)))
+	return smtp.SendEmail(entry.Email, "An apartment you are waiting for is available", body.String())
+}
+
+// offerApartment offers an available apartment to the prospect who has waited longest for one like it and
+// emails them the offer. Nothing is offered while the apartment has a pending offer or an application holding
+// it. It returns the offer made, or nil when nobody was offered the apartment.
+func (h *LeaseHandler) offerApartment(ctx context.Context, apartmentID int64) *db.WaitlistOffer {
+	available, err := h.apartmentAvailable(ctx, apartmentID)
+	if err != nil {
+		log.Printf("[WAITLIST] Failed checking availability of apartment %d: %v", apartmentID, err)
+		return nil
+	}
+	if !available {
+		return nil
+	}
+	if _, err := h.queries.GetPendingWaitlistOfferForApartment(ctx, apartmentID); err == nil {
+		return nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("[WAITLIST] Failed checking offers for apartment %d: %v", apartmentID, err)
+		return nil
+	}
+	holds, err := h.queries.CountApplicationsHoldingApartment(ctx, apartmentID)
+	if err != nil {
+		log.Printf("[WAITLIST] Failed checking applications for apartment %d: %v", apartmentID, err)
+		return nil
+	}
+	if holds > 0 {
+		return nil
+	}
+
+	token, err := newWaitlistToken()
+	if err != nil {
+		log.Printf("[WAITLIST] Could not create offer token: %v", err)
+		return nil
+	}
+	var entry db.WaitlistEntry
+	var offer *db.WaitlistOffer
+	err = h.inTx(ctx, func(tx *LeaseHandler) error {
+		next, err := tx.queries.NextWaitlistEntryForApartment(ctx, apartmentID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find the next prospect: %w", err)
+		}
+		created, err := tx.queries.CreateWaitlistOffer(ctx, db.CreateWaitlistOfferParams{
+			EntryID:     next.ID,
+			ApartmentID: apartmentID,
+			Token:       token,
+			ExpiresAt:   pgtype.Timestamp{Time: time.Now().UTC().Add(waitlistOfferTTL), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save offer for entry %d: %w", next.ID, err)
+		}
+		if err := tx.queries.SetWaitlistEntryStatus(ctx, db.SetWaitlistEntryStatusParams{
+			ID:     next.ID,
+			Status: db.WaitlistEntryStatusOffered,
+		}); err != nil {
+			return fmt.Errorf("failed to mark entry %d offered: %w", next.ID, err)
+		}
+		entry, offer = next, &created
+		return nil
+	})
+	if err != nil {
+		log.Printf("[WAITLIST] Failed offering apartment %d: %v", apartmentID, err)
+		return nil
+	}
+	if offer == nil {
+		return nil
+	}
+
+	log.Printf("[WAITLIST] Offered apartment %d to waitlist entry %d until %s", apartmentID, entry.ID, offer.ExpiresAt.Time.Format("2006-01-02 15:04"))
+	if apartment, err := h.queries.GetApartment(ctx, apartmentID); err != nil {
+		log.Printf("[WAITLIST] Apartment %d not found for offer %d: %v", apartmentID, offer.ID, err)
+	} else if err := sendWaitlistOfferEmail(entry, apartment, *offer); err != nil {
+		log.Printf("[WAITLIST] Failed emailing offer %d to %s: %v", offer.ID, entry.Email, err)
+	}
+	return offer
+}
+
+// endWaitlistOffer closes a pending offer and puts the prospect back in line in their old place, unless they
+// left the waitlist. It returns pgx.ErrNoRows when the offer was already answered.
+func (h *LeaseHandler) endWaitlistOffer(ctx context.Context, offerID int64, status db.WaitlistOfferStatus) (db.WaitlistOffer, error) {
+	var offer db.WaitlistOffer
+	err := h.inTx(ctx, func(tx *LeaseHandler) error {
+		var err error
+		if offer, err = tx.queries.RespondToWaitlistOffer(ctx, db.RespondToWaitlistOfferParams{
+			ID:     offerID,
+			Status: status,
+		}); err != nil {
+			return err
+		}
+		return tx.queries.ReturnWaitlistEntryToQueue(ctx, offer.EntryID)
+	})
+	if err != nil {
+		return offer, err
+	}
+	log.Printf("[WAITLIST] Offer %d of apartment %d %s", offer.ID, offer.ApartmentID, status)
+	return offer, nil
+}
+
+// notifyWaitlistEntry emails a prospect about their offer ending, reminding them they keep their place
+func (h *LeaseHandler) notifyWaitlistEntry(ctx context.Context, entryID int64, subject, message string) {
+	entry, err := h.queries.GetWaitlistEntry(ctx, entryID)
+	if err != nil {
+		log.Printf("[WAITLIST] Waitlist entry %d not found: %v", entryID, err)
+		return
+	}
+	body := fmt.Sprintf("Hello %s %s,\n\n%s\n", entry.FirstName, entry.LastName, message)
+	if entry.Status == db.WaitlistEntryStatusWaiting {
+		body += fmt.Sprintf("\nYou keep your place on the waitlist. To leave it: %s\n", waitlistLeaveURL(entry.Token))
+	}
+	if err := smtp.SendEmail(entry.Email, subject, body); err != nil {
+		log.Printf("[WAITLIST] Failed emailing waitlist entry %d: %v", entry.ID, err)
+	}
+}
+
+// withdrawApartmentOffer withdraws the pending offer of an apartment that was just leased
+func (h *LeaseHandler) withdrawApartmentOffer(ctx context.Context, apartmentID int64) {
+	pending, err := h.queries.GetPendingWaitlistOfferForApartment(ctx, apartmentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return
+	}
+	if err != nil {
+		log.Printf("[WAITLIST] Failed checking offers for apartment %d: %v", apartmentID, err)
+		return
+	}
+	offer, err := h.endWaitlistOffer(ctx, pending.ID, db.WaitlistOfferStatusWithdrawn)
+	if err != nil {
+		log.Printf("[WAITLIST] Failed withdrawing offer %d: %v", pending.ID, err)
+		return
+	}
+	h.notifyWaitlistEntry(ctx, offer.EntryID, "The apartment offered to you is no longer available",
+		"The apartment we offered you has been leased, so the offer is withdrawn.")
+}
+
+// JoinWaitlist puts a prospect on the waitlist. It is public; prospects have no account yet.
+func (h *LeaseHandler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	var req WaitlistEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid waitlist request", http.StatusBadRequest)
+		return
+	}
+	if err := validateWaitlistEntry(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+	params := db.CreateWaitlistEntryParams{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		UnitType:  pgtype.Text{String: req.UnitType, Valid: req.UnitType != ""},
+	}
+	if req.BuildingID != nil {
+		if _, err := h.queries.GetBuilding(ctx, *req.BuildingID); err != nil {
+			http.Error(w, "Building not found", http.StatusBadRequest)
+			return
+		}
+		params.BuildingID = pgtype.Int8{Int64: *req.BuildingID, Valid: true}
+	}
+	if req.Bedrooms != nil {
+		params.Bedrooms = pgtype.Int2{Int16: *req.Bedrooms, Valid: true}
+	}
+	token, err := newWaitlistToken()
+	if err != nil {
+		http.Error(w, "Failed to join the waitlist", http.StatusInternalServerError)
+		return
+	}
+	params.Token = token
+
+	entry, err := h.queries.CreateWaitlistEntry(ctx, params)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		http.Error(w, "This email is already waiting for the same kind of unit", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("[WAITLIST] Failed adding %s to the waitlist: %v", req.Email, err)
+		http.Error(w, "Failed to join the waitlist", http.StatusInternalServerError)
+		return
+	}
+
+	body := fmt.Sprintf("Hello %s %s,\n\nYou are on the waitlist. When a matching apartment becomes available we will email you an offer, "+
+		"which is held for you for %d hours.\n\nTo leave the waitlist: %s\n", entry.FirstName, entry.LastName, int(waitlistOfferTTL.Hours()), waitlistLeaveURL(entry.Token))
+	if err := smtp.SendEmail(entry.Email, "You are on the waitlist", body); err != nil {
+		log.Printf("[WAITLIST] Failed emailing waitlist entry %d: %v", entry.ID, err)
+	}
+	log.Printf("[WAITLIST] Waitlist entry %d added", entry.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toWaitlistEntryResponse(entry)); err != nil {
+		log.Printf("[WAITLIST] Error encoding response: %v", err)
+	}
+}
+
+// removeWaitlistEntry takes a prospect off the waitlist, withdrawing their pending offer so the apartment
+// goes to the next one in line
+func (h *LeaseHandler) removeWaitlistEntry(ctx context.Context, entryID int64) (db.WaitlistEntry, error) {
+	entry, err := h.queries.WithdrawWaitlistEntry(ctx, entryID)
+	if err != nil {
+		return entry, err
+	}
+	if pending, err := h.queries.GetPendingWaitlistOfferForEntry(ctx, entryID); err == nil {
+		if _, err := h.endWaitlistOffer(ctx, pending.ID, db.WaitlistOfferStatusWithdrawn); err != nil {
+			log.Printf("[WAITLIST] Failed withdrawing offer %d: %v", pending.ID, err)
+		} else {
+			h.offerApartment(ctx, pending.ApartmentID)
+		}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("[WAITLIST] Failed checking offers of entry %d: %v", entryID, err)
+	}
+	log.Printf("[WAITLIST] Waitlist entry %d withdrawn", entryID)
+	return entry, nil
+}
+
+// LeaveWaitlist takes a prospect off the waitlist through the link emailed to them
+func (h *LeaseHandler) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	entry, err := h.queries.GetWaitlistEntryByToken(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		http.Error(w, "Waitlist entry not found", http.StatusNotFound)
+		return
+	}
+	entryID := entry.ID
+	if entry, err = h.removeWaitlistEntry(ctx, entryID); errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "You are no longer on the waitlist", http.StatusConflict)
+		return
+	} else if err != nil {
+		log.Printf("[WAITLIST] Failed withdrawing entry %d: %v", entryID, err)
+		http.Error(w, "Failed to leave the waitlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toWaitlistEntryResponse(entry)); err != nil {
+		log.Printf("[WAITLIST] Error encoding response: %v", err)
+	}
+}
+
+// openWaitlistOffer loads the offer behind an emailed link and answers the request itself unless the offer can
+// still be answered. An offer found past its expiry is expired here and passed to the next prospect.
+func (h *LeaseHandler) openWaitlistOffer(w http.ResponseWriter, r *http.Request) (db.WaitlistOffer, db.WaitlistEntry, bool) {
+	ctx := r.Context()
+	offer, err := h.queries.GetWaitlistOfferByToken(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		http.Error(w, "Offer not found", http.StatusNotFound)
+		return offer, db.WaitlistEntry{}, false
+	}
+	if offer.Status != db.WaitlistOfferStatusPending {
+		http.Error(w, fmt.Sprintf("This offer was %s", offer.Status), http.StatusConflict)
+		return offer, db.WaitlistEntry{}, false
+	}
+	if !time.Now().UTC().Before(offer.ExpiresAt.Time) {
+		if _, err := h.endWaitlistOffer(ctx, offer.ID, db.WaitlistOfferStatusExpired); err == nil {
+			h.offerApartment(ctx, offer.ApartmentID)
+		}
+		http.Error(w, "This offer has expired", http.StatusGone)
+		return offer, db.WaitlistEntry{}, false
+	}
+	entry, err := h.queries.GetWaitlistEntry(ctx, offer.EntryID)
+	if err != nil {
+		log.Printf("[WAITLIST] Waitlist entry %d of offer %d not found: %v", offer.EntryID, offer.ID, err)
+		http.Error(w, "Offer not found", http.StatusNotFound)
+		return offer, entry, false
+	}
+	return offer, entry, true
+}
+
+// GetWaitlistOffer shows the offer behind the emailed link with the apartment offered
+func (h *LeaseHandler) GetWaitlistOffer(w http.ResponseWriter, r *http.Request) {
+	offer, err := h.queries.GetWaitlistOfferByToken(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		http.Error(w, "Offer not found", http.StatusNotFound)
+		return
+	}
+	apartment, err := h.queries.GetApartment(r.Context(), offer.ApartmentID)
+	if err != nil {
+		http.Error(w, "Offer not found", http.StatusNotFound)
+		return
+	}
+	resp := toWaitlistOfferResponse(offer)
+	if offer.Status == db.WaitlistOfferStatusPending && !time.Now().UTC().Before(offer.ExpiresAt.Time) {
+		// Not swept yet, but it can no longer be accepted
+		resp.Status = string(db.WaitlistOfferStatusExpired)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"offer":       resp,
+		"unit_number": apartment.UnitNumber.Int64,
+		"rent":        utils.ConvertPgNumericToFloat(apartment.Price),
+		"bedrooms":    apartment.Bedrooms,
+		"unit_type":   apartment.UnitType.String,
+	}); err != nil {
+		log.Printf("[WAITLIST] Error encoding response: %v", err)
+	}
+}
+
+// AcceptWaitlistOffer accepts an offer by applying for the apartment, with the same request as
+// SubmitRentalApplication. The application holds the apartment while it is reviewed.
+func (h *LeaseHandler) AcceptWaitlistOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	offer, entry, ok := h.openWaitlistOffer(w, r)
+	if !ok {
+		return
+	}
+	req, uploads, ok := readRentalApplicationRequest(w, r)
+	if !ok {
+		return
+	}
+	if !strings.EqualFold(req.Email, entry.Email) {
+		http.Error(w, "Apply with the email address the offer was sent to", http.StatusBadRequest)
+		return
+	}
+	if !h.checkCanApply(ctx, w, offer.ApartmentID, req.Email) {
+		return
+	}
+
+	app, err := h.saveRentalApplication(ctx, offer.ApartmentID, req, uploads, func(tx *LeaseHandler, app db.RentalApplication) error {
+		if _, err := tx.queries.RespondToWaitlistOffer(ctx, db.RespondToWaitlistOfferParams{
+			ID:     offer.ID,
+			Status: db.WaitlistOfferStatusAccepted,
+		}); err != nil {
+			return fmt.Errorf("failed to accept offer %d: %w", offer.ID, err)
+		}
+		if err := tx.queries.SetWaitlistOfferApplication(ctx, db.SetWaitlistOfferApplicationParams{
+			ID:            offer.ID,
+			ApplicationID: pgtype.Int8{Int64: app.ID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("failed to link offer %d to application %d: %w", offer.ID, app.ID, err)
+		}
+		return tx.queries.SetWaitlistEntryStatus(ctx, db.SetWaitlistEntryStatusParams{
+			ID:     entry.ID,
+			Status: db.WaitlistEntryStatusAccepted,
+		})
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "This offer is no longer open", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, errStoringApplicationDocuments) {
+		log.Printf("[WAITLIST] %v", err)
+		http.Error(w, "Failed to store documents", http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		log.Printf("[WAITLIST] Failed accepting offer %d: %v", offer.ID, err)
+		http.Error(w, "Failed to save application", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[WAITLIST] Offer %d accepted with application %d for apartment %d", offer.ID, app.ID, offer.ApartmentID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"application_id": app.ID,
+		"status":         app.Status,
+	}); err != nil {
+		log.Printf("[WAITLIST] Error encoding response: %v", err)
+	}
+}
+
+// DeclineWaitlistOffer declines an offer; the prospect keeps their place and the apartment goes to the next one
+func (h *LeaseHandler) DeclineWaitlistOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	pending, _, ok := h.openWaitlistOffer(w, r)
+	if !ok {
+		return
+	}
+	offer, err := h.endWaitlistOffer(ctx, pending.ID, db.WaitlistOfferStatusDeclined)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "This offer is no longer open", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("[WAITLIST] Failed declining offer %d: %v", pending.ID, err)
+		http.Error(w, "Failed to decline the offer", http.StatusInternalServerError)
+		return
+	}
+	h.offerApartment(ctx, offer.ApartmentID)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toWaitlistOfferResponse(offer)); err != nil {
+		log.Printf("[WAITLIST] Error encoding response: %v", err)
+	}
+}
+
+// ListWaitlist returns the waitlist in the order prospects joined, optionally filtered by ?status=
+func (h *LeaseHandler) ListWaitlist(w http.ResponseWriter, r *http.Request) {
+	var status db.NullWaitlistEntryStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		switch entryStatus := db.WaitlistEntryStatus(s); entryStatus {
+		case db.WaitlistEntryStatusWaiting, db.WaitlistEntryStatusOffered, db.WaitlistEntryStatusAccepted, db.WaitlistEntryStatusWithdrawn:
+			status = db.NullWaitlistEntryStatus{WaitlistEntryStatus: entryStatus, Valid: true}
+		default:
+			http.Error(w, fmt.Sprintf("unknown waitlist status %q", s), http.StatusBadRequest)
+			return
+		}
+	}
+	entries, err := h.queries.ListWaitlistEntries(r.Context(), status)
+	if err != nil {
+		log.Printf("[WAITLIST] Failed listing waitlist: %v", err)
+		http.Error(w, "Failed to fetch waitlist", http.StatusInternalServerError)
+		return
+	}
+	resp := make([]WaitlistEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, toWaitlistEntryResponse(e))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[WAITLIST] Error encoding response: %v", err)
+	}
+}
+
+// ListWaitlistOffers returns waitlist offers newest first, optionally filtered by ?status=
+func (h *LeaseHandler) ListWaitlistOffers(w http.ResponseWriter, r *http.Request) {
+	var status db.NullWaitlistOfferStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		switch offerStatus := db.WaitlistOfferStatus(s); offerStatus {
+		case db.WaitlistOfferStatusPending, db.WaitlistOfferStatusAccepted, db.WaitlistOfferStatusDeclined,
+			db.WaitlistOfferStatusExpired, db.WaitlistOfferStatusWithdrawn:
+			status = db.NullWaitlistOfferStatus{WaitlistOfferStatus: offerStatus, Valid: true}
+		default:
+			http.Error(w, fmt.Sprintf("unknown offer status %q", s), http.StatusBadRequest)
+			return
+		}
+	}
+	offers, err := h.queries.ListWaitlistOffers(r.Context(), status)
+	if err != nil {
+		log.Printf("[WAITLIST] Failed listing offers: %v", err)
+		http.Error(w, "Failed to fetch offers", http.StatusInternalServerError)
+		return
+	}
+	resp := make([]WaitlistOfferResponse, 0, len(offers))
+	for _, o := range offers {
+		resp = append(resp, toWaitlistOfferResponse(o))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[WAITLIST] Error encoding response: %v", err)
+	}
+}
+
+// RemoveWaitlistEntry takes a prospect off the waitlist on an admin's behalf
+func (h *LeaseHandler) RemoveWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := strconv.ParseInt(chi.URLParam(r, "entryID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid waitlist entry ID", http.StatusBadRequest)
+		return
+	}
+	entry, err := h.removeWaitlistEntry(r.Context(), entryID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "No waiting entry with that ID", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("[WAITLIST] Failed withdrawing entry %d: %v", entryID, err)
+		http.Error(w, "Failed to remove waitlist entry", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toWaitlistEntryResponse(entry)); err != nil {
+		log.Printf("[WAITLIST] Error encoding response: %v", err)
+	}
+}
+
+// OfferApartment offers an available apartment to the waitlist now rather than on the next sweep
+func (h *LeaseHandler) OfferApartment(w http.ResponseWriter, r *http.Request) {
+	apartmentID, err := strconv.ParseInt(chi.URLParam(r, "apartmentID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid apartment ID", http.StatusBadRequest)
+		return
+	}
+	offer := h.offerApartment(r.Context(), apartmentID)
+	if offer == nil {
+		http.Error(w, "Nobody was offered the apartment: it is not available, already offered or held by an application, or nobody on the waitlist matches it", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toWaitlistOfferResponse(*offer)); err != nil {
+		log.Printf("[WAITLIST] Error encoding response: %v", err)
+	}
+}
+
+// ProcessWaitlistOffers expires every offer that was not answered in time, then offers each available
+// apartment that has no offer to the next prospect in line. The sweep also picks up apartments that became
+// available without a lease ending, like ones marked available by hand or whose applicant was turned down.
+func (h *LeaseHandler) ProcessWaitlistOffers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	due, err := h.queries.ListExpiredWaitlistOffers(ctx, pgtype.Timestamp{Time: time.Now().UTC(), Valid: true})
+	if err != nil {
+		log.Printf("[WAITLIST] Failed listing expired offers: %v", err)
+		http.Error(w, "Failed to fetch offers", http.StatusInternalServerError)
+		return
+	}
+	expired := 0
+	for _, o := range due {
+		if _, err := h.endWaitlistOffer(ctx, o.ID, db.WaitlistOfferStatusExpired); err != nil {
+			log.Printf("[WAITLIST] Failed expiring offer %d: %v", o.ID, err)
+			continue
+		}
+		h.notifyWaitlistEntry(ctx, o.EntryID, "Your apartment offer expired",
+			"We did not hear from you in time, so the apartment we offered you has gone to the next person on the waitlist.")
+		expired++
+	}
+
+	apartments, err := h.queries.GetApartmentsWithoutLease(ctx)
+	if err != nil {
+		log.Printf("[WAITLIST] Failed listing available apartments: %v", err)
+		http.Error(w, "Failed to fetch apartments", http.StatusInternalServerError)
+		return
+	}
+	offered := 0
+	for _, a := range apartments {
+		if h.offerApartment(ctx, a.ID) != nil {
+			offered++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]int{
+		"expired": expired,
+		"offered": offered,
+	}); err != nil {
+		log.Printf("[WAITLIST] Error encoding response: %v", err)
+	}
+}
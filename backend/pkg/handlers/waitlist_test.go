@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var testWaitlistEntry = db.WaitlistEntry{ID: 6, FirstName: "Pat", LastName: "Prospect", Email: "pat@example.com", Status: db.WaitlistEntryStatusOffered, Token: "leave"}
+
+// waitlistOffer is testWaitlistEntry's offer of apartment 3 that expires the given time from now
+func waitlistOffer(status db.WaitlistOfferStatus, expiresIn time.Duration) db.WaitlistOffer {
+	return db.WaitlistOffer{
+		ID:          4,
+		EntryID:     testWaitlistEntry.ID,
+		ApartmentID: 3,
+		Token:       "offer",
+		Status:      status,
+		ExpiresAt:   pgtype.Timestamp{Time: time.Now().UTC().Add(expiresIn), Valid: true},
+	}
+}
+
+func TestOfferApartmentGoesToTheNextProspect(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.returns("GetApartmentsWithoutLease", []db.GetApartmentsWithoutLeaseRow{{ID: 3}})
+	fdb.returns("CountApplicationsHoldingApartment", int64(0))
+	fdb.returns("NextWaitlistEntryForApartment", testWaitlistEntry)
+	fdb.returns("CreateWaitlistOffer", waitlistOffer(db.WaitlistOfferStatusPending, waitlistOfferTTL))
+
+	rec := httptest.NewRecorder()
+	h.OfferApartment(rec, newTestRequest(t, http.MethodPost, "/admin/waitlist/apartments/3/offer", nil, "apartmentID", "3"))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body.String())
+	}
+	offers := fdb.called("CreateWaitlistOffer")
+	if len(offers) != 1 || offers[0].Args[0] != testWaitlistEntry.ID || offers[0].Args[1] != int64(3) {
+		t.Fatalf("CreateWaitlistOffer calls = %+v, want apartment 3 offered to entry 6", offers)
+	}
+	if expires := offers[0].Args[3].(pgtype.Timestamp).Time; expires.Sub(time.Now().UTC()) > waitlistOfferTTL {
+		t.Errorf("offer expires at %s, want within %s", expires, waitlistOfferTTL)
+	}
+	statuses := fdb.called("SetWaitlistEntryStatus")
+	if len(statuses) != 1 || statuses[0].Args[1] != db.WaitlistEntryStatusOffered {
+		t.Errorf("SetWaitlistEntryStatus calls = %+v, want entry 6 marked offered", statuses)
+	}
+	if fdb.committed != 1 {
+		t.Errorf("committed %d transactions, want 1", fdb.committed)
+	}
+}
+
+func TestOfferApartmentHeldByAnApplication(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.returns("GetApartmentsWithoutLease", []db.GetApartmentsWithoutLeaseRow{{ID: 3}})
+	fdb.returns("CountApplicationsHoldingApartment", int64(1))
+	fdb.returns("NextWaitlistEntryForApartment", testWaitlistEntry)
+
+	rec := httptest.NewRecorder()
+	h.OfferApartment(rec, newTestRequest(t, http.MethodPost, "/admin/waitlist/apartments/3/offer", nil, "apartmentID", "3"))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409: %s", rec.Code, rec.Body.String())
+	}
+	if calls := fdb.called("CreateWaitlistOffer"); len(calls) != 0 {
+		t.Errorf("apartment offered while an application holds it: %+v", calls)
+	}
+}
+
+func TestJoinWaitlistTwice(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.fails("CreateWaitlistEntry", &pgconn.PgError{Code: "23505"})
+	bedrooms := int16(2)
+
+	rec := httptest.NewRecorder()
+	h.JoinWaitlist(rec, newTestRequest(t, http.MethodPost, "/waitlist", WaitlistEntryRequest{
+		FirstName: "Pat", LastName: "Prospect", Email: "pat@example.com", Bedrooms: &bedrooms,
+	}))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// acceptWaitlistOffer applies for the offered apartment as email
+func acceptWaitlistOffer(t *testing.T, h *LeaseHandler, email string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.AcceptWaitlistOffer(rec, newTestRequest(t, http.MethodPost, "/waitlist/offers/offer/accept", RentalApplicationRequest{
+		FirstName: "Pat", LastName: "Prospect", Email: email, MonthlyIncome: 4000,
+	}, "token", "offer"))
+	return rec
+}
+
+func TestAcceptWaitlistOffer(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerApplication(fdb)
+	fdb.returns("GetWaitlistOfferByToken", waitlistOffer(db.WaitlistOfferStatusPending, time.Hour))
+	fdb.returns("GetWaitlistEntry", testWaitlistEntry)
+	fdb.returns("RespondToWaitlistOffer", waitlistOffer(db.WaitlistOfferStatusAccepted, time.Hour))
+
+	rec := acceptWaitlistOffer(t, h, "Pat@example.com")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body.String())
+	}
+	if responses := fdb.called("RespondToWaitlistOffer"); len(responses) != 1 || responses[0].Args[1] != db.WaitlistOfferStatusAccepted {
+		t.Errorf("RespondToWaitlistOffer calls = %+v, want offer 4 accepted", responses)
+	}
+	if links := fdb.called("SetWaitlistOfferApplication"); len(links) != 1 || links[0].Args[1] != (pgtype.Int8{Int64: 8, Valid: true}) {
+		t.Errorf("SetWaitlistOfferApplication calls = %+v, want offer 4 linked to application 8", links)
+	}
+	if statuses := fdb.called("SetWaitlistEntryStatus"); len(statuses) != 1 || statuses[0].Args[1] != db.WaitlistEntryStatusAccepted {
+		t.Errorf("SetWaitlistEntryStatus calls = %+v, want entry 6 accepted", statuses)
+	}
+	if fdb.committed != 1 {
+		t.Errorf("committed %d transactions, want the application and offer saved together", fdb.committed)
+	}
+}
+
+func TestAcceptWaitlistOfferFromAnotherEmail(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerApplication(fdb)
+	fdb.returns("GetWaitlistOfferByToken", waitlistOffer(db.WaitlistOfferStatusPending, time.Hour))
+	fdb.returns("GetWaitlistEntry", testWaitlistEntry)
+
+	rec := acceptWaitlistOffer(t, h, "someone@example.com")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body.String())
+	}
+	if calls := fdb.called("CreateRentalApplication"); len(calls) != 0 {
+		t.Errorf("application saved for another email: %+v", calls)
+	}
+}
+
+func TestAcceptExpiredWaitlistOffer(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	answerApplication(fdb)
+	fdb.returns("GetWaitlistOfferByToken", waitlistOffer(db.WaitlistOfferStatusPending, -time.Minute))
+	fdb.returns("RespondToWaitlistOffer", waitlistOffer(db.WaitlistOfferStatusExpired, -time.Minute))
+	fdb.returns("CountApplicationsHoldingApartment", int64(0))
+
+	rec := acceptWaitlistOffer(t, h, testWaitlistEntry.Email)
+	if rec.Code != http.StatusGone {
+		t.Fatalf("status = %d, want 410: %s", rec.Code, rec.Body.String())
+	}
+	if responses := fdb.called("RespondToWaitlistOffer"); len(responses) != 1 || responses[0].Args[1] != db.WaitlistOfferStatusExpired {
+		t.Errorf("RespondToWaitlistOffer calls = %+v, want the offer expired", responses)
+	}
+	if calls := fdb.called("CreateRentalApplication"); len(calls) != 0 {
+		t.Errorf("application saved for an expired offer: %+v", calls)
+	}
+	// The apartment passes to the next prospect in line
+	if calls := fdb.called("NextWaitlistEntryForApartment"); len(calls) != 1 {
+		t.Errorf("NextWaitlistEntryForApartment calls = %+v, want the apartment offered on", calls)
+	}
+}
+
+func TestDeclinedWaitlistOfferCannotBeAccepted(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.returns("GetWaitlistOfferByToken", waitlistOffer(db.WaitlistOfferStatusDeclined, time.Hour))
+
+	rec := acceptWaitlistOffer(t, h, testWaitlistEntry.Email)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409: %s", rec.Code, rec.Body.String())
+	}
+}
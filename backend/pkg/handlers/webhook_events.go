@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/pkg/handlers/documenso"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	webhookSourceClerk     = "clerk"
+	webhookSourceDocumenso = "documenso"
+)
+
+// maxWebhookBodyBytes bounds a delivery; rejected deliveries are stored too, so it also bounds what an
+// unauthenticated caller can write to the log
+const maxWebhookBodyBytes = 1 << 20
+
+// Headers that carry credentials are never written to the webhook log
+var redactedWebhookHeaders = map[string]bool{
+	"Authorization":      true,
+	"Cookie":             true,
+	"X-Documenso-Secret": true,
+}
+
+type WebhookEventHandler struct {
+	pool         *pgxpool.Pool
+	queries      *db.Queries
+	leaseHandler *LeaseHandler
+}
+
+func NewWebhookEventHandler(pool *pgxpool.Pool, queries *db.Queries, leaseHandler *LeaseHandler) *WebhookEventHandler {
+	return &WebhookEventHandler{
+		pool:         pool,
+		queries:      queries,
+		leaseHandler: leaseHandler,
+	}
+}
+
+type WebhookEventResponse struct {
+	ID          int64  `json:"id"`
+	Source      string `json:"source"`
+	DeliveryID  string `json:"delivery_id"`
+	EventType   string `json:"event_type"`
+	Status      string `json:"status"`
+	Attempts    int32  `json:"attempts"`
+	LastError   string `json:"last_error,omitempty"`
+	ProcessedAt string `json:"processed_at,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func toWebhookEventResponse(event db.WebhookEvent) WebhookEventResponse {
+	resp := WebhookEventResponse{
+		ID:         event.ID,
+		Source:     event.Source,
+		DeliveryID: event.DeliveryID,
+		EventType:  event.EventType,
+		Status:     string(event.Status),
+		Attempts:   event.Attempts,
+		LastError:  event.LastError.String,
+		CreatedAt:  event.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+	}
+	if event.ProcessedAt.Valid {
+		resp.ProcessedAt = event.ProcessedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+// errWebhookEventClaimed is returned when a logged delivery is already processed or being processed
+var errWebhookEventClaimed = errors.New("webhook event was already processed or is being processed")
+
+// recordWebhookEvent stores an inbound delivery and claims it for processing. duplicate is true when the
+// same delivery was already processed, or a concurrent redelivery is processing it right now, and it
+// must not be applied again.
+func recordWebhookEvent(ctx context.Context, queries *db.Queries, source, deliveryID, eventType string, headers http.Header, body []byte) (event db.WebhookEvent, duplicate bool, err error) {
+	if deliveryID == "" {
+		sum := sha256.Sum256(body)
+		deliveryID = hex.EncodeToString(sum[:])
+	}
+
+	event, err = queries.CreateWebhookEvent(ctx, db.CreateWebhookEventParams{
+		Source:     source,
+		DeliveryID: deliveryID,
+		EventType:  eventType,
+		Headers:    webhookHeadersJSON(headers),
+		Body:       string(body),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// ON CONFLICT DO NOTHING returns no row: this delivery was seen before
+		event, err = queries.GetWebhookEventByDelivery(ctx, db.GetWebhookEventByDeliveryParams{
+			Source:     source,
+			DeliveryID: deliveryID,
+		})
+	}
+	if err != nil {
+		return db.WebhookEvent{DeliveryID: deliveryID}, false, err
+	}
+
+	event, err = claimWebhookEvent(ctx, queries, event)
+	if errors.Is(err, errWebhookEventClaimed) {
+		return event, true, nil
+	}
+	return event, false, err
+}
+
+// recordRejectedWebhookEvent keeps a delivery that failed verification, so a genuine delivery that was
+// turned away can be found and resent. It is stored under its own delivery ID and never blocks a verified
+// delivery of the same body.
+func recordRejectedWebhookEvent(ctx context.Context, queries *db.Queries, source string, headers http.Header, body []byte, reason error) {
+	sum := sha256.Sum256(body)
+	if err := queries.RecordRejectedWebhookEvent(ctx, db.RecordRejectedWebhookEventParams{
+		Source:     source,
+		DeliveryID: "rejected:" + hex.EncodeToString(sum[:]),
+		Headers:    webhookHeadersJSON(headers),
+		Body:       string(body),
+		LastError:  pgtype.Text{String: reason.Error(), Valid: true},
+	}); err != nil {
+		log.Printf("[WEBHOOK_EVENTS] Failed recording rejected %s delivery: %v", source, err)
+	}
+}
+
+// webhookHeadersJSON returns the headers of a delivery without the ones carrying credentials
+func webhookHeadersJSON(headers http.Header) []byte {
+	kept := make(map[string]string, len(headers))
+	for name, values := range headers {
+		if redactedWebhookHeaders[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		kept[name] = strings.Join(values, ", ")
+	}
+	headerJSON, err := json.Marshal(kept)
+	if err != nil {
+		return []byte("{}")
+	}
+	return headerJSON
+}
+
+// claimWebhookEvent marks a logged delivery as being processed. Only one caller can claim a delivery, so
+// a live redelivery and an admin replay racing each other cannot both apply it.
+func claimWebhookEvent(ctx context.Context, queries *db.Queries, event db.WebhookEvent) (db.WebhookEvent, error) {
+	claimed, err := queries.ClaimWebhookEvent(ctx, event.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return event, errWebhookEventClaimed
+	}
+	if err != nil {
+		return event, fmt.Errorf("failed to claim webhook event %d: %w", event.ID, err)
+	}
+	return claimed, nil
+}
+
+// finishWebhookEvent records the outcome of processing a logged delivery
+func finishWebhookEvent(ctx context.Context, queries *db.Queries, eventID int64, processErr error) {
+	if eventID == 0 {
+		return
+	}
+
+	var err error
+	if processErr == nil {
+		err = queries.MarkWebhookEventProcessed(ctx, eventID)
+	} else {
+		err = queries.MarkWebhookEventFailed(ctx, db.MarkWebhookEventFailedParams{
+			ID:        eventID,
+			LastError: pgtype.Text{String: processErr.Error(), Valid: true},
+		})
+	}
+	if err != nil {
+		log.Printf("[WEBHOOK_EVENTS] Failed updating webhook event %d: %v", eventID, err)
+	}
+}
+
+// ListWebhookEvents returns logged deliveries, optionally filtered by ?source= and ?status=
+func (h *WebhookEventHandler) ListWebhookEvents(w http.ResponseWriter, r *http.Request) {
+	params := db.ListWebhookEventsParams{RowLimit: 100}
+	if source := r.URL.Query().Get("source"); source != "" {
+		params.Source = pgtype.Text{String: source, Valid: true}
+	}
+	if status := r.URL.Query().Get("status"); status != "" {
+		switch db.WebhookEventStatus(status) {
+		case db.WebhookEventStatusReceived, db.WebhookEventStatusProcessing, db.WebhookEventStatusProcessed,
+			db.WebhookEventStatusFailed, db.WebhookEventStatusRejected:
+			params.Status = db.NullWebhookEventStatus{WebhookEventStatus: db.WebhookEventStatus(status), Valid: true}
+		default:
+			http.Error(w, "Invalid status", http.StatusBadRequest)
+			return
+		}
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > 500 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		params.RowLimit = int32(n)
+	}
+
+	events, err := h.queries.ListWebhookEvents(r.Context(), params)
+	if err != nil {
+		log.Printf("[WEBHOOK_EVENTS] Failed listing webhook events: %v", err)
+		http.Error(w, "Failed to fetch webhook events", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]WebhookEventResponse, 0, len(events))
+	for _, event := range events {
+		resp = append(resp, toWebhookEventResponse(event))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[WEBHOOK_EVENTS] Error encoding response: %v", err)
+	}
+}
+
+// ReplayWebhookEvent re-processes a single logged delivery
+func (h *WebhookEventHandler) ReplayWebhookEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "eventID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.queries.GetWebhookEvent(r.Context(), eventID)
+	if err != nil {
+		http.Error(w, "Webhook event not found", http.StatusNotFound)
+		return
+	}
+
+	replayErr := h.replay(r.Context(), event)
+	if errors.Is(replayErr, errWebhookEventClaimed) {
+		http.Error(w, "Webhook event was already processed or is being processed", http.StatusConflict)
+		return
+	}
+	event, err = h.queries.GetWebhookEvent(r.Context(), eventID)
+	if err != nil {
+		log.Printf("[WEBHOOK_EVENTS] Failed reloading webhook event %d: %v", eventID, err)
+		http.Error(w, "Failed to fetch webhook event", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if replayErr != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	if err := json.NewEncoder(w).Encode(toWebhookEventResponse(event)); err != nil {
+		log.Printf("[WEBHOOK_EVENTS] Error encoding response: %v", err)
+	}
+}
+
+// ReplayFailedWebhookEvents re-processes every failed delivery, oldest first
+func (h *WebhookEventHandler) ReplayFailedWebhookEvents(w http.ResponseWriter, r *http.Request) {
+	events, err := h.queries.ListWebhookEvents(r.Context(), db.ListWebhookEventsParams{
+		Status:   db.NullWebhookEventStatus{WebhookEventStatus: db.WebhookEventStatusFailed, Valid: true},
+		RowLimit: 500,
+	})
+	if err != nil {
+		log.Printf("[WEBHOOK_EVENTS] Failed listing failed webhook events: %v", err)
+		http.Error(w, "Failed to fetch webhook events", http.StatusInternalServerError)
+		return
+	}
+
+	processed, failed, skipped := 0, 0, 0
+	for i := len(events) - 1; i >= 0; i-- {
+		err := h.replay(r.Context(), events[i])
+		switch {
+		case errors.Is(err, errWebhookEventClaimed):
+			skipped++
+		case err != nil:
+			failed++
+		default:
+			processed++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"replayed":  len(events),
+		"processed": processed,
+		"failed":    failed,
+		"skipped":   skipped,
+	}); err != nil {
+		log.Printf("[WEBHOOK_EVENTS] Error encoding response: %v", err)
+	}
+}
+
+// replay runs the stored body through the same processing as a live delivery. It returns
+// errWebhookEventClaimed when the delivery was processed in the meantime or is being processed.
+// Signatures are not checked again; deliveries that failed verification are logged as rejected and
+// cannot be claimed.
+func (h *WebhookEventHandler) replay(ctx context.Context, event db.WebhookEvent) error {
+	event, err := claimWebhookEvent(ctx, h.queries, event)
+	if err != nil {
+		return err
+	}
+	log.Printf("[WEBHOOK_EVENTS] Replaying %s event %d (%s)", event.Source, event.ID, event.EventType)
+
+	switch event.Source {
+	case webhookSourceClerk:
+		var payload ClerkWebhookPayload
+		if err = json.Unmarshal([]byte(event.Body), &payload); err == nil {
+			err = processClerkEvent(ctx, payload, h.queries)
+		}
+	case webhookSourceDocumenso:
+		var payload documenso.WebhookEvent
+		if err = json.Unmarshal([]byte(event.Body), &payload); err == nil {
+			err = h.leaseHandler.processDocumensoEvent(ctx, payload)
+		}
+	default:
+		err = fmt.Errorf("unknown webhook source %q", event.Source)
+	}
+
+	finishWebhookEvent(ctx, h.queries, event.ID, err)
+	if err != nil {
+		log.Printf("[WEBHOOK_EVENTS] Replay of event %d failed: %v", event.ID, err)
+	}
+	return err
+}
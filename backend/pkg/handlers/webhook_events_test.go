@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	db "github.com/careecodes/RentDaddy/internal/db/generated"
+	"github.com/careecodes/RentDaddy/pkg/handlers/documenso"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestRecordWebhookEventClaimsNewDelivery(t *testing.T) {
+	_, fdb := newTestHandler(t)
+	fdb.returns("CreateWebhookEvent", db.WebhookEvent{ID: 5, Status: db.WebhookEventStatusReceived})
+	fdb.returns("ClaimWebhookEvent", db.WebhookEvent{ID: 5, Status: db.WebhookEventStatusProcessing})
+
+	event, duplicate, err := recordWebhookEvent(context.Background(), db.New(fdb), webhookSourceDocumenso, "", "DOCUMENT_COMPLETED", http.Header{}, []byte(`{}`))
+	if err != nil || duplicate {
+		t.Fatalf("recordWebhookEvent = duplicate %v, err %v; want a claimed delivery", duplicate, err)
+	}
+	if event.Status != db.WebhookEventStatusProcessing {
+		t.Errorf("status = %s, want processing", event.Status)
+	}
+}
+
+func TestRecordWebhookEventSkipsDeliveryClaimedElsewhere(t *testing.T) {
+	_, fdb := newTestHandler(t)
+	// A concurrent redelivery logged the event first and is still processing it
+	fdb.fails("CreateWebhookEvent", pgx.ErrNoRows)
+	fdb.returns("GetWebhookEventByDelivery", db.WebhookEvent{ID: 5, Status: db.WebhookEventStatusProcessing})
+
+	_, duplicate, err := recordWebhookEvent(context.Background(), db.New(fdb), webhookSourceDocumenso, "", "DOCUMENT_COMPLETED", http.Header{}, []byte(`{}`))
+	if err != nil || !duplicate {
+		t.Fatalf("recordWebhookEvent = duplicate %v, err %v; want a duplicate", duplicate, err)
+	}
+	if calls := fdb.called("ClaimWebhookEvent"); len(calls) != 1 || calls[0].Args[0] != int64(5) {
+		t.Errorf("ClaimWebhookEvent calls = %+v, want one for event 5", calls)
+	}
+}
+
+func TestReplayWebhookEventConflictsWhenClaimed(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	events := &WebhookEventHandler{queries: h.queries, leaseHandler: h}
+	fdb.returns("GetWebhookEvent", db.WebhookEvent{ID: 5, Source: webhookSourceDocumenso, Status: db.WebhookEventStatusProcessed})
+
+	rec := httptest.NewRecorder()
+	events.ReplayWebhookEvent(rec, newTestRequest(t, http.MethodPost, "/admin/webhooks/5/replay", nil, "eventID", "5"))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want 409", rec.Code)
+	}
+	if calls := fdb.called("MarkWebhookEventProcessed"); len(calls) != 0 {
+		t.Errorf("a delivery that could not be claimed was processed again: %+v", calls)
+	}
+	if !errors.Is(events.replay(context.Background(), db.WebhookEvent{ID: 5}), errWebhookEventClaimed) {
+		t.Error("replay of an unclaimable delivery should report errWebhookEventClaimed")
+	}
+}
+
+func TestDocumensoWebhookLogsRejectedDelivery(t *testing.T) {
+	h, fdb := newTestHandler(t)
+	fdb.on("GetConfigByKey", func(args []any) (any, error) {
+		if args[0] == "documenso_webhook_secret" {
+			return db.AppConfig{Key: "documenso_webhook_secret", Value: "current"}, nil
+		}
+		return nil, pgx.ErrNoRows
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/documenso", strings.NewReader(`{"event":"DOCUMENT_COMPLETED","payload":{"id":42}}`))
+	r.Header.Set(documenso.SecretHeader, "wrong")
+	rec := httptest.NewRecorder()
+	h.DocumensoWebhookHandler(rec, r)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", rec.Code)
+	}
+	rejected := fdb.called("RecordRejectedWebhookEvent")
+	if len(rejected) != 1 {
+		t.Fatalf("RecordRejectedWebhookEvent calls = %+v, want the delivery logged", rejected)
+	}
+	if id := rejected[0].Args[1].(string); !strings.HasPrefix(id, "rejected:") {
+		t.Errorf("rejected delivery stored as %q, want it kept apart from verified deliveries", id)
+	}
+	if headers := string(rejected[0].Args[3].([]byte)); strings.Contains(headers, "wrong") {
+		t.Errorf("stored headers %s include the secret that was sent", headers)
+	}
+	if calls := fdb.called("CreateWebhookEvent"); len(calls) != 0 {
+		t.Errorf("a rejected delivery was logged for processing: %+v", calls)
+	}
+}
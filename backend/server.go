@@ -62,7 +62,7 @@ func main() {
 		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
 		// Add more headers if needed by your frontend
 		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Requested-With"},
-		ExposedHeaders: []string{"Link"},
+		ExposedHeaders: []string{"Link", "X-Next-Cursor"},
 		// Set this to true if your frontend needs to send credentials
 		AllowCredentials: true,
 		MaxAge:           300,
@@ -91,8 +91,10 @@ func main() {
 	workOrderHandler := handlers.NewWorkOrderHandler(pool, queries)
 	apartmentHandler := handlers.NewApartmentHandler(pool, queries)
 	buildingHandler := handlers.NewBuildingHandler(pool, queries)
+	landlordHandler := handlers.NewLandlordHandler(pool, queries)
 	chatbotHandler := handlers.NewChatBotHandler(pool, queries)
 	complaintHandler := handlers.NewComplaintHandler(pool, queries)
+	webhookEventHandler := handlers.NewWebhookEventHandler(pool, queries, leaseHandler)
 
 	// Webhooks
 	r.Post("/webhooks/clerk", func(w http.ResponseWriter, r *http.Request) {
@@ -100,11 +102,48 @@ func main() {
 	})
 	r.Post("/webhooks/documenso", leaseHandler.DocumensoWebhookHandler)
 
+	// Local e-signature pages (only when ESIGN_PROVIDER=local)
+	if signer := leaseHandler.LocalSignerHandler(); signer != nil {
+		r.Mount("/esign/local", signer)
+	}
+
+	// Stored documents, opened through short-lived signed links handed out by the API
+	r.Get("/files/*", leaseHandler.ServeDocument)
+
+	// Renewal offers, opened from the link emailed to the tenant
+	r.Route("/renewal-offers/{token}", func(r chi.Router) {
+		r.Get("/", leaseHandler.GetRenewalOffer)
+		r.Post("/accept", leaseHandler.AcceptRenewalOffer)
+		r.Post("/decline", leaseHandler.DeclineRenewalOffer)
+	})
+
+	// Rental applications from prospects, who have no account yet
+	r.Route("/apartments", func(r chi.Router) {
+		r.Get("/available", leaseHandler.GetApartmentsWithoutLease)
+		r.With(middleware.RateLimit(5, time.Hour)).Post("/{apartmentID}/applications", leaseHandler.SubmitRentalApplication)
+	})
+
+	// Unit waitlist for prospects, and the offers emailed to them when a matching apartment frees up
+	r.Route("/waitlist", func(r chi.Router) {
+		r.Post("/", leaseHandler.JoinWaitlist)
+		r.Delete("/{token}", leaseHandler.LeaveWaitlist)
+		r.Route("/offers/{token}", func(r chi.Router) {
+			r.Get("/", leaseHandler.GetWaitlistOffer)
+			r.Post("/accept", leaseHandler.AcceptWaitlistOffer)
+			r.Post("/decline", leaseHandler.DeclineWaitlistOffer)
+		})
+	})
+
 	// Cron job endpoints
 	r.Route("/cron", func(r chi.Router) {
 		r.Use(middleware.CronAuthMiddleware) // Apply cron auth middleware
 		r.Get("/leases/expire", leaseHandler.UpdateAllLeaseStatuses)
 		r.Post("/leases/notify-expiring", leaseHandler.NotifyExpiringLeases)
+		r.Post("/ledger/charges", leaseHandler.GenerateRentCharges)
+		r.Post("/deposits/dispositions", leaseHandler.SendDepositDispositions)
+		r.Post("/leases/vacate-notices", leaseHandler.ProcessVacateNotices)
+		r.Post("/leases/reconcile-documents", leaseHandler.ReconcileLeaseDocuments)
+		r.Post("/waitlist/offers", leaseHandler.ProcessWaitlistOffers)
 	})
 
 	// Application Routes
@@ -129,6 +168,12 @@ func main() {
 			})
 			// Documenso Configuration
 			r.Post("/config/documenso", leaseHandler.UpdateDocumensoConfig)
+			// Inbound webhook log
+			r.Route("/webhooks/events", func(r chi.Router) {
+				r.Get("/", webhookEventHandler.ListWebhookEvents)
+				r.Post("/replay-failed", webhookEventHandler.ReplayFailedWebhookEvents)
+				r.Post("/{eventID}/replay", webhookEventHandler.ReplayWebhookEvent)
+			})
 			// Tenants
 			r.Route("/tenants", func(r chi.Router) {
 				r.Get("/", userHandler.GetAllTenants)
@@ -203,6 +248,14 @@ func main() {
 				})
 			})
 
+			// Landlords buildings are managed by
+			r.Route("/landlords", func(r chi.Router) {
+				r.Get("/", landlordHandler.ListLandlords)
+				r.Post("/", landlordHandler.CreateLandlord)
+				r.Get("/{landlordID}", landlordHandler.GetLandlord)
+				r.Put("/{landlordID}", landlordHandler.UpdateLandlord)
+			})
+
 			// Complaint
 			r.Route("/complaints", func(r chi.Router) {
 				r.Get("/", complaintHandler.ListComplaintsHandler)
@@ -216,6 +269,33 @@ func main() {
 				})
 			})
 
+			// Rental application review queue
+			r.Route("/applications", func(r chi.Router) {
+				r.Get("/", leaseHandler.ListRentalApplications)
+				r.Get("/{applicationID}", leaseHandler.GetRentalApplication)
+				r.Post("/{applicationID}/decision", leaseHandler.DecideRentalApplication)
+				r.Get("/{applicationID}/documents/{documentID}/url", leaseHandler.GetRentalApplicationDocumentURL)
+				r.Post("/{applicationID}/screening", leaseHandler.ScreenRentalApplication)
+				r.Get("/{applicationID}/screenings", leaseHandler.ListRentalApplicationScreenings)
+			})
+
+			// Unit waitlist
+			r.Route("/waitlist", func(r chi.Router) {
+				r.Get("/", leaseHandler.ListWaitlist)
+				r.Get("/offers", leaseHandler.ListWaitlistOffers)
+				r.Delete("/{entryID}", leaseHandler.RemoveWaitlistEntry)
+				r.Post("/apartments/{apartmentID}/offer", leaseHandler.OfferApartment)
+			})
+
+			// Document vault
+			r.Route("/documents", func(r chi.Router) {
+				r.Get("/", leaseHandler.ListDocuments)
+				r.Post("/", leaseHandler.UploadDocument)
+				r.Delete("/{documentID}", leaseHandler.DeleteDocument)
+				r.Get("/{documentID}/url", leaseHandler.GetDocumentURL)
+				r.Get("/{documentID}/downloads", leaseHandler.ListDocumentDownloads)
+			})
+
 			// Leases
 			r.Route("/leases", func(r chi.Router) {
 				r.Get("/", leaseHandler.GetLeases)
@@ -224,12 +304,54 @@ func main() {
 				r.Post("/renew", leaseHandler.RenewLease)
 				r.Post("/amend", leaseHandler.AmendLease)
 				r.Post("/terminate/{leaseID}", leaseHandler.TerminateLease)
+				r.Post("/proration", leaseHandler.CalculateProration)
 				r.Get("/without-lease", leaseHandler.GetTenantsWithoutLease)
 				r.Get("/apartments-available", leaseHandler.GetApartmentsWithoutLease)
 				r.Get("/update-statuses", leaseHandler.UpdateAllLeaseStatuses)
 				r.Post("/notify-expiring", leaseHandler.NotifyExpiringLeases)
 
+				// Lease templates
+				r.Get("/templates", leaseHandler.ListLeaseTemplates)
+				r.Post("/templates", leaseHandler.CreateLeaseTemplate)
+				r.Post("/templates/preview", leaseHandler.PreviewLeaseTemplate)
+				r.Get("/templates/{templateID}", leaseHandler.GetLeaseTemplate)
+
+				// Addendum library
+				r.Get("/addenda", leaseHandler.ListLeaseAddenda)
+				r.Post("/addenda", leaseHandler.CreateLeaseAddendum)
+				r.Put("/addenda/{addendumID}", leaseHandler.UpdateLeaseAddendum)
+
 				r.Get("/{leaseID}/url", leaseHandler.DocumensoGetDocumentURL)
+				r.Get("/{leaseID}/pdf-url", leaseHandler.PdfS3GetDocumentURL)
+				r.Get("/{leaseID}/signers", leaseHandler.GetLeaseSigners)
+				r.Get("/{leaseID}/history", leaseHandler.GetLeaseHistory)
+				r.Get("/{leaseID}/status-history", leaseHandler.GetLeaseStatusHistory)
+				r.Get("/{leaseID}/deposit", leaseHandler.GetLeaseDeposit)
+				r.Put("/{leaseID}/deposit", leaseHandler.RecordLeaseDeposit)
+				r.Get("/{leaseID}/deposit/disposition", leaseHandler.GetDepositDisposition)
+				r.Post("/{leaseID}/deposit/disposition", leaseHandler.IssueDepositDisposition)
+
+				// Renewal offers
+				r.Get("/renewal-offers", leaseHandler.ListRenewalOffers)
+				r.Post("/renewal-offers/{offerID}/withdraw", leaseHandler.WithdrawRenewalOffer)
+				r.Post("/{leaseID}/renewal-offer", leaseHandler.CreateRenewalOffer)
+
+				// Notices to vacate
+				r.Get("/vacate-notices", leaseHandler.ListVacateNotices)
+				r.Post("/vacate-notices/{noticeID}/cancel", leaseHandler.CancelVacateNotice)
+				r.Post("/{leaseID}/vacate-notice", leaseHandler.CreateVacateNotice)
+
+				// Month-to-month holdover
+				r.Put("/{leaseID}/holdover", leaseHandler.SetLeaseHoldoverPolicy)
+
+				// Rent ledger
+				r.Get("/ledger/balances", leaseHandler.GetLedgerBalances)
+				r.Post("/ledger/charges", leaseHandler.GenerateRentCharges)
+				r.Route("/{leaseID}/ledger", func(r chi.Router) {
+					r.Get("/", leaseHandler.GetLeaseLedger)
+					r.Post("/payments", leaseHandler.RecordLeasePayment)
+					r.Post("/entries", leaseHandler.CreateLeaseLedgerEntry)
+				})
 			})
 		})
 		// End Admin
@@ -238,11 +360,13 @@ func main() {
 		r.Route("/tenant", func(r chi.Router) {
 			r.Get("/", userHandler.GetUserByClerkId)
 			r.Get("/apartment", userHandler.TenantGetApartment)
-			r.Get("/documents", userHandler.TenantGetDocuments)
+			r.Get("/documents", leaseHandler.TenantGetDocuments)
+			r.Get("/documents/{documentID}/url", leaseHandler.TenantGetDocumentURL)
 			r.Get("/work_orders", userHandler.TenantGetWorkOrders)
 			r.Post("/work_orders", userHandler.TenantCreateWorkOrder)
 			r.Get("/complaints", userHandler.TenantGetComplaints)
 			r.Post("/complaints", userHandler.TenantCreateComplaint)
+			r.Get("/ledger", leaseHandler.TenantGetLedger)
 
 			// Locker Endpoints
 			r.Get("/lockers", lockerHandler.GetLockersByUserId)
@@ -255,6 +379,8 @@ func main() {
 				r.Get("/{permit_id}", parkingPermitHandler.GetParkingPermit)
 			})
 			r.Route("/leases", func(r chi.Router) {
+				r.Get("/notice-to-vacate", leaseHandler.TenantGetVacateNotices)
+				r.Post("/notice-to-vacate", leaseHandler.TenantGiveVacateNotice)
 				r.Get("/{user_id}/signing-url", func(w http.ResponseWriter, r *http.Request) {
 					leaseHandler.GetTenantLeaseStatusAndURLByUserID(w, r)
 				})